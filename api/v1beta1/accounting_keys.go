@@ -5,8 +5,11 @@ package v1beta1
 
 import (
 	"fmt"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/SlinkyProject/slurm-operator/internal/utils/domainname"
@@ -24,6 +27,21 @@ func (o *Accounting) PrimaryName() string {
 	return fmt.Sprintf("%s-0", key.Name)
 }
 
+// BackupName returns the name of the backup slurmdbd pod, when
+// HighAvailability is enabled.
+func (o *Accounting) BackupName() string {
+	key := o.Key()
+	return fmt.Sprintf("%s-1", key.Name)
+}
+
+// Replicas returns the number of slurmdbd pods.
+func (o *Accounting) Replicas() int32 {
+	if o.Spec.HighAvailability {
+		return 2
+	}
+	return 1
+}
+
 func (o *Accounting) ServiceKey() types.NamespacedName {
 	key := o.Key()
 	return types.NamespacedName{
@@ -59,6 +77,31 @@ func (o *Accounting) AuthStorageRef() *corev1.SecretKeySelector {
 	}
 }
 
+// StorageTLSEnabled reports if slurmdbd connects to the database with TLS.
+func (o *Accounting) StorageTLSEnabled() bool {
+	tls := o.Spec.StorageConfig.TLS
+	return tls != nil && tls.Mode != StorageTLSModeDisabled
+}
+
+// StorageTLSRefs returns the secret references used for the TLS connection to
+// the database, or nil if TLS is disabled.
+func (o *Accounting) StorageTLSRefs() []*corev1.SecretKeySelector {
+	if !o.StorageTLSEnabled() {
+		return nil
+	}
+	tls := o.Spec.StorageConfig.TLS
+	out := []*corev1.SecretKeySelector{
+		tls.CaKeyRef.DeepCopy(),
+	}
+	if tls.CertKeyRef != nil {
+		out = append(out, tls.CertKeyRef.DeepCopy())
+	}
+	if tls.KeyKeyRef != nil {
+		out = append(out, tls.KeyKeyRef.DeepCopy())
+	}
+	return out
+}
+
 func (o *Accounting) AuthSlurmKey() types.NamespacedName {
 	return types.NamespacedName{
 		Name:      o.Spec.SlurmKeyRef.Name,
@@ -116,3 +159,53 @@ func (o *Accounting) ConfigKey() types.NamespacedName {
 		Namespace: o.Namespace,
 	}
 }
+
+// NamespaceSelector returns the selector of the namespaces mapped to
+// accounts, which selects nothing without NamespaceAccounts.
+func (o *Accounting) NamespaceSelector() (labels.Selector, error) {
+	if o.Spec.NamespaceAccounts == nil {
+		return labels.Nothing(), nil
+	}
+	return metav1.LabelSelectorAsSelector(&o.Spec.NamespaceAccounts.NamespaceSelector)
+}
+
+// NamespaceAccountName returns the name of the account the namespace is
+// mapped to. Slurm account names are lowercase.
+func (o *Accounting) NamespaceAccountName(namespace *corev1.Namespace) string {
+	name := namespace.Name
+	if o.Spec.NamespaceAccounts != nil && o.Spec.NamespaceAccounts.AccountLabel != "" {
+		if value := namespace.Labels[o.Spec.NamespaceAccounts.AccountLabel]; value != "" {
+			name = value
+		}
+	}
+	return strings.ToLower(name)
+}
+
+func (o *Accounting) ArchiveKey() types.NamespacedName {
+	key := o.Key()
+	return types.NamespacedName{
+		Name:      fmt.Sprintf("%s-archive", key.Name),
+		Namespace: o.Namespace,
+	}
+}
+
+// ArchiveClaimName returns the name of the `PersistentVolumeClaim` mounted at
+// ArchiveDir, or empty if there is none.
+func (o *Accounting) ArchiveClaimName() string {
+	archive := o.Spec.Archive
+	switch {
+	case archive.ExistingClaim != "":
+		return archive.ExistingClaim
+	case archive.VolumeClaim != nil:
+		return o.ArchiveKey().Name
+	default:
+		return ""
+	}
+}
+
+// ArchiveEnabled reports if any records are archived when they are purged.
+func (o *Accounting) ArchiveEnabled() bool {
+	archive := o.Spec.Archive
+	return archive.Jobs || archive.Steps || archive.Events || archive.Resvs ||
+		archive.Suspend || archive.TXN || archive.Usage
+}
@@ -56,6 +56,13 @@ type AccountingSpec struct {
 	// +optional
 	Template PodTemplate `json:"template,omitempty"`
 
+	// HighAvailability runs a backup slurmdbd, which takes over when the
+	// primary slurmdbd is unavailable. The two pods are spread across nodes.
+	// Ref: https://slurm.schedmd.com/slurmdbd.conf.html#OPT_DbdBackupHost
+	// +optional
+	// +default:=false
+	HighAvailability bool `json:"highAvailability,omitzero"`
+
 	// StorageConfig is the configuration for mysql/mariadb access.
 	// +optional
 	StorageConfig StorageConfig `json:"storageConfig,omitzero"`
@@ -68,6 +75,174 @@ type AccountingSpec struct {
 	// Service defines a template for a Kubernetes Service object.
 	// +optional
 	Service ServiceSpec `json:"service,omitzero"`
+
+	// NamespaceAccounts maps namespaces to Slurm accounts, whose GrpTRES
+	// limits follow the ResourceQuotas of the namespaces.
+	// +optional
+	NamespaceAccounts *NamespaceAccounts `json:"namespaceAccounts,omitempty"`
+
+	// Purge defines how long records are kept in the database.
+	// Ref: https://slurm.schedmd.com/slurmdbd.conf.html#OPT_PurgeJobAfter
+	// +optional
+	Purge AccountingPurge `json:"purge,omitzero"`
+
+	// Archive defines which records are archived to ArchiveDir when they are
+	// purged, and the volume mounted there.
+	// Ref: https://slurm.schedmd.com/slurmdbd.conf.html#OPT_ArchiveDir
+	// +optional
+	Archive AccountingArchive `json:"archive,omitzero"`
+
+	// ClusterRegistration manages the cluster records of the Controllers
+	// using this Accounting in slurmdbd.
+	// +optional
+	ClusterRegistration AccountingClusterRegistration `json:"clusterRegistration,omitzero"`
+}
+
+// AccountingPurge defines how long records are kept in the database before
+// they are purged. Each is a number followed by `hours`, `days`, or `months`
+// (e.g. `12months`). When omitted, records are never purged.
+type AccountingPurge struct {
+	// JobAfter is how long job records are kept.
+	// Ref: https://slurm.schedmd.com/slurmdbd.conf.html#OPT_PurgeJobAfter
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[1-9][0-9]*(hour|day|month)s?$`
+	JobAfter string `json:"jobAfter,omitempty"`
+
+	// StepAfter is how long step records are kept.
+	// Ref: https://slurm.schedmd.com/slurmdbd.conf.html#OPT_PurgeStepAfter
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[1-9][0-9]*(hour|day|month)s?$`
+	StepAfter string `json:"stepAfter,omitempty"`
+
+	// EventAfter is how long node and cluster event records are kept.
+	// Ref: https://slurm.schedmd.com/slurmdbd.conf.html#OPT_PurgeEventAfter
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[1-9][0-9]*(hour|day|month)s?$`
+	EventAfter string `json:"eventAfter,omitempty"`
+
+	// ResvAfter is how long reservation records are kept.
+	// Ref: https://slurm.schedmd.com/slurmdbd.conf.html#OPT_PurgeResvAfter
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[1-9][0-9]*(hour|day|month)s?$`
+	ResvAfter string `json:"resvAfter,omitempty"`
+
+	// SuspendAfter is how long job suspend records are kept.
+	// Ref: https://slurm.schedmd.com/slurmdbd.conf.html#OPT_PurgeSuspendAfter
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[1-9][0-9]*(hour|day|month)s?$`
+	SuspendAfter string `json:"suspendAfter,omitempty"`
+
+	// TXNAfter is how long transaction records are kept.
+	// Ref: https://slurm.schedmd.com/slurmdbd.conf.html#OPT_PurgeTXNAfter
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[1-9][0-9]*(hour|day|month)s?$`
+	TXNAfter string `json:"txnAfter,omitempty"`
+
+	// UsageAfter is how long usage records are kept.
+	// Ref: https://slurm.schedmd.com/slurmdbd.conf.html#OPT_PurgeUsageAfter
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[1-9][0-9]*(hour|day|month)s?$`
+	UsageAfter string `json:"usageAfter,omitempty"`
+}
+
+// AccountingArchive defines the archiving of purged records.
+type AccountingArchive struct {
+	// Jobs archives job records when they are purged.
+	// Ref: https://slurm.schedmd.com/slurmdbd.conf.html#OPT_ArchiveJobs
+	// +optional
+	Jobs bool `json:"jobs,omitzero"`
+
+	// Steps archives step records when they are purged.
+	// Ref: https://slurm.schedmd.com/slurmdbd.conf.html#OPT_ArchiveSteps
+	// +optional
+	Steps bool `json:"steps,omitzero"`
+
+	// Events archives event records when they are purged.
+	// Ref: https://slurm.schedmd.com/slurmdbd.conf.html#OPT_ArchiveEvents
+	// +optional
+	Events bool `json:"events,omitzero"`
+
+	// Resvs archives reservation records when they are purged.
+	// Ref: https://slurm.schedmd.com/slurmdbd.conf.html#OPT_ArchiveResvs
+	// +optional
+	Resvs bool `json:"resvs,omitzero"`
+
+	// Suspend archives job suspend records when they are purged.
+	// Ref: https://slurm.schedmd.com/slurmdbd.conf.html#OPT_ArchiveSuspend
+	// +optional
+	Suspend bool `json:"suspend,omitzero"`
+
+	// TXN archives transaction records when they are purged.
+	// Ref: https://slurm.schedmd.com/slurmdbd.conf.html#OPT_ArchiveTXN
+	// +optional
+	TXN bool `json:"txn,omitzero"`
+
+	// Usage archives usage records when they are purged.
+	// Ref: https://slurm.schedmd.com/slurmdbd.conf.html#OPT_ArchiveUsage
+	// +optional
+	Usage bool `json:"usage,omitzero"`
+
+	// ExistingClaim is the name of an existing `PersistentVolumeClaim` that is
+	// mounted at ArchiveDir.
+	// +optional
+	ExistingClaim string `json:"existingClaim,omitempty"`
+
+	// VolumeClaim is the spec of the `PersistentVolumeClaim` that is created
+	// and mounted at ArchiveDir, unless ExistingClaim is set. The claim is kept
+	// when the Accounting is deleted.
+	// +optional
+	VolumeClaim *corev1.PersistentVolumeClaimSpec `json:"volumeClaim,omitempty"`
+}
+
+// AccountingClusterRegistration defines the lifecycle of the cluster records of
+// the Controllers in slurmdbd.
+type AccountingClusterRegistration struct {
+	// Enabled registers the cluster of each Controller in slurmdbd, and holds
+	// the deletion of the Controller with a finalizer until its cluster record
+	// is handled according to the DeletionPolicy. The cluster is registered
+	// by name only, federations and cluster flags are not managed.
+	// +optional
+	// +default:=false
+	Enabled bool `json:"enabled,omitzero"`
+
+	// DeletionPolicy is what happens to the cluster record when its Controller
+	// is deleted.
+	// `Retain` keeps the cluster record, along with its usage and associations.
+	// `Delete` removes the cluster record from slurmdbd. The job records of the
+	// cluster are not archived first, they are kept by slurmdbd until purged
+	// according to Purge and Archive.
+	// +kubebuilder:validation:Enum=Retain;Delete
+	// +optional
+	// +default:="Retain"
+	DeletionPolicy ClusterDeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// ClusterDeletionPolicy is what happens to a cluster record in slurmdbd when
+// its Controller is deleted.
+type ClusterDeletionPolicy string
+
+const (
+	ClusterDeletionPolicyRetain ClusterDeletionPolicy = "Retain"
+	ClusterDeletionPolicyDelete ClusterDeletionPolicy = "Delete"
+)
+
+// NamespaceAccounts maps namespaces to Slurm accounts.
+type NamespaceAccounts struct {
+	// NamespaceSelector selects the namespaces that are mapped to accounts.
+	// +required
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector"`
+
+	// AccountLabel is the namespace label whose value is the account name.
+	// Namespaces without the label are mapped to the account named after the
+	// namespace.
+	// +optional
+	AccountLabel string `json:"accountLabel,omitempty"`
+
+	// ParentAccount is the parent of the accounts.
+	// Default is "root".
+	// +optional
+	// +default:="root"
+	ParentAccount string `json:"parentAccount,omitempty"`
 }
 
 // StorageConfig defines access to mysql/mariadb.
@@ -105,10 +280,98 @@ type StorageConfig struct {
 	// Ref: https://slurm.schedmd.com/slurmdbd.conf.html#OPT_StoragePass
 	// +required
 	PasswordKeyRef corev1.SecretKeySelector `json:"passwordKeyRef,omitzero"`
+
+	// TLS configures encrypted connections to the database.
+	// Ref: https://slurm.schedmd.com/slurmdbd.conf.html#OPT_StorageParameters
+	// +optional
+	TLS *StorageTLS `json:"tls,omitempty"`
+}
+
+// StorageTLS defines the TLS connection to mysql/mariadb.
+type StorageTLS struct {
+	// Mode determines if the connection to the database uses TLS.
+	// `Required` connects with TLS, using the given certificates.
+	// `Disabled` connects without TLS, even if certificates are given.
+	// +kubebuilder:validation:Enum=Disabled;Required
+	// +optional
+	// +default:="Required"
+	Mode StorageTLSMode `json:"mode,omitempty"`
+
+	// CaKeyRef is a reference to a secret containing the PEM encoded CA
+	// certificate used to verify the database server.
+	// Ref: https://slurm.schedmd.com/slurmdbd.conf.html#OPT_SSL_CA
+	// +required
+	CaKeyRef corev1.SecretKeySelector `json:"caKeyRef,omitzero"`
+
+	// CertKeyRef is a reference to a secret containing the PEM encoded client
+	// certificate. Requires KeyKeyRef.
+	// Ref: https://slurm.schedmd.com/slurmdbd.conf.html#OPT_SSL_CERT
+	// +optional
+	CertKeyRef *corev1.SecretKeySelector `json:"certKeyRef,omitempty"`
+
+	// KeyKeyRef is a reference to a secret containing the PEM encoded client
+	// private key. Requires CertKeyRef.
+	// Ref: https://slurm.schedmd.com/slurmdbd.conf.html#OPT_SSL_KEY
+	// +optional
+	KeyKeyRef *corev1.SecretKeySelector `json:"keyKeyRef,omitempty"`
 }
 
+// StorageTLSMode is the TLS mode of the connection to the database.
+type StorageTLSMode string
+
+const (
+	StorageTLSModeDisabled StorageTLSMode = "Disabled"
+	StorageTLSModeRequired StorageTLSMode = "Required"
+)
+
+const (
+	// AccountingNamespaceAccountsSynced indicates whether the accounts of the
+	// namespaces in slurmdbd match the NamespaceAccounts.
+	AccountingNamespaceAccountsSynced = "NamespaceAccountsSynced"
+
+	// AccountingDatabaseReachable indicates whether slurmdbd can reach its
+	// database. Without a slurm client, it reports a probe of the database by
+	// the operator instead, with a reason ending in `FromOperator` or starting
+	// with `Operator`.
+	AccountingDatabaseReachable = "DatabaseReachable"
+
+	// AccountingClustersRegistered indicates whether the clusters of the
+	// Controllers using this Accounting are registered in slurmdbd.
+	AccountingClustersRegistered = "ClustersRegistered"
+)
+
 // AccountingStatus defines the observed state of Accounting
 type AccountingStatus struct {
+	// Retention lists how long each type of record is kept in the database.
+	// +optional
+	// +listType=map
+	// +listMapKey=records
+	Retention []AccountingRetention `json:"retention,omitempty"`
+
+	// LastStoragePasswordRotationTime is the last time slurmdbd was restarted
+	// because the password of the database user changed.
+	// +optional
+	LastStoragePasswordRotationTime *metav1.Time `json:"lastStoragePasswordRotationTime,omitempty"`
+
+	// Clusters are the clusters registered in slurmdbd, and of the Controllers
+	// using this Accounting.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Clusters []AccountingClusterStatus `json:"clusters,omitempty"`
+
+	// RemovedClusters are the most recent cluster records that were handled
+	// when their Controller was deleted, newest first.
+	// +optional
+	// +listType=atomic
+	RemovedClusters []AccountingRemovedCluster `json:"removedClusters,omitempty"`
+
+	// NamespaceAccounts are the namespaces mapped to accounts.
+	// +optional
+	// +listType=map
+	// +listMapKey=namespace
+	NamespaceAccounts []NamespaceAccountStatus `json:"namespaceAccounts,omitempty"`
+
 	// Represents the latest available observations of a Accounting's current state.
 	// +optional
 	// +patchMergeKey=type
@@ -118,6 +381,82 @@ type AccountingStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
 }
 
+// AccountingRetention is how long a type of record is kept in the database.
+type AccountingRetention struct {
+	// Records is the type of record (e.g. `Job`).
+	Records string `json:"records"`
+
+	// PurgeAfter is how long the records are kept before they are purged, or
+	// `Never`.
+	PurgeAfter string `json:"purgeAfter"`
+
+	// Archived indicates the records are archived when they are purged.
+	// +optional
+	Archived bool `json:"archived,omitzero"`
+}
+
+// AccountingClusterStatus is a cluster of the Accounting.
+type AccountingClusterStatus struct {
+	// Name is the name of the cluster.
+	Name string `json:"name"`
+
+	// ControllerRef is the Controller of the cluster, if it is managed.
+	// +optional
+	ControllerRef *ObjectReference `json:"controllerRef,omitempty"`
+
+	// Registered indicates the cluster is registered in slurmdbd.
+	// +optional
+	Registered bool `json:"registered,omitzero"`
+}
+
+// AccountingRemovedCluster is the result of handling the cluster record of a
+// deleted Controller.
+type AccountingRemovedCluster struct {
+	// Name is the name of the cluster.
+	Name string `json:"name"`
+
+	// ControllerRef is the deleted Controller of the cluster.
+	ControllerRef ObjectReference `json:"controllerRef"`
+
+	// Result is what happened to the cluster record.
+	Result ClusterRemovalResult `json:"result"`
+
+	// Message is a human readable message about the result.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Time is when the cluster record was handled.
+	Time metav1.Time `json:"time"`
+}
+
+// ClusterRemovalResult is what happened to the cluster record of a deleted
+// Controller.
+type ClusterRemovalResult string
+
+const (
+	// ClusterRemovalDeleted indicates the cluster record was removed.
+	ClusterRemovalDeleted ClusterRemovalResult = "Deleted"
+	// ClusterRemovalRetained indicates the cluster record was kept.
+	ClusterRemovalRetained ClusterRemovalResult = "Retained"
+	// ClusterRemovalFailed indicates the cluster record could not be removed,
+	// and was left in slurmdbd.
+	ClusterRemovalFailed ClusterRemovalResult = "Failed"
+)
+
+// NamespaceAccountStatus is the account of a namespace.
+type NamespaceAccountStatus struct {
+	// Namespace is the name of the namespace.
+	Namespace string `json:"namespace"`
+
+	// Account is the name of the account.
+	Account string `json:"account"`
+
+	// GrpTRES are the limits of the account from the ResourceQuotas of its
+	// namespaces, by TRES name.
+	// +optional
+	GrpTRES map[string]int64 `json:"grpTRES,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:shortName=slurmdbd
@@ -249,3 +249,32 @@ type ExternalConfig struct {
 	// +required
 	Port int `json:"port,omitzero"`
 }
+
+// Spank defines the SPANK plugins to load.
+type Spank struct {
+	// Plugins is the ordered list of SPANK plugins rendered into `plugstack.conf`.
+	// Ref: https://slurm.schedmd.com/spank.html#SECTION_CONFIGURATION
+	// +listType=atomic
+	// +optional
+	Plugins []SpankPlugin `json:"plugins,omitempty"`
+}
+
+// SpankPlugin defines a SPANK plugin line in `plugstack.conf`.
+type SpankPlugin struct {
+	// Path is the path to the plugin shared object, either absolute or
+	// relative to the Slurm PluginDir.
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Path string `json:"path"`
+
+	// Optional allows Slurm to continue if the plugin fails to load,
+	// otherwise the plugin is required.
+	// +optional
+	// +default:=false
+	Optional bool `json:"optional,omitzero"`
+
+	// Args are passed to the plugin.
+	// +listType=atomic
+	// +optional
+	Args []string `json:"args,omitempty"`
+}
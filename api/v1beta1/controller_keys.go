@@ -109,9 +109,33 @@ func (o *Controller) AuthJwksRef() *corev1.ConfigMapKeySelector {
 	}
 }
 
+func (o *Controller) JobSubmitScriptKey() types.NamespacedName {
+	return types.NamespacedName{
+		Name:      o.Spec.JobSubmitScriptRef.Name,
+		Namespace: o.Namespace,
+	}
+}
+
+func (o *Controller) JobSubmitScriptRef() *corev1.ConfigMapKeySelector {
+	ref := o.Spec.JobSubmitScriptRef
+	return &corev1.ConfigMapKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{
+			Name: ref.Name,
+		},
+		Key: ref.Key,
+	}
+}
+
 func (o *Controller) ConfigKey() types.NamespacedName {
 	return types.NamespacedName{
 		Name:      fmt.Sprintf("%s-config", o.Name),
 		Namespace: o.Namespace,
 	}
 }
+
+func (o *Controller) PendingConfigKey() types.NamespacedName {
+	return types.NamespacedName{
+		Name:      fmt.Sprintf("%s-config-pending", o.Name),
+		Namespace: o.Namespace,
+	}
+}
@@ -59,9 +59,22 @@ type ControllerSpec struct {
 	Slurmctld ContainerWrapper `json:"slurmctld,omitempty"`
 
 	// The reconfigure container configuration.
+	// Only used when reconfigureMode is `Sidecar`.
 	// +optional
 	Reconfigure ContainerWrapper `json:"reconfigure,omitzero"`
 
+	// ReconfigureMode determines how slurmctld is reconfigured after its
+	// configuration files change.
+	// `Sidecar` runs the reconfigure container, which polls the mounted files
+	// and issues `scontrol reconfigure` when they change.
+	// `Operator` has the operator request a reconfigure through slurmrestd
+	// when the rendered configuration changes; this requires a RestApi.
+	// Ref: https://slurm.schedmd.com/scontrol.html#OPT_reconfigure
+	// +kubebuilder:validation:Enum=Sidecar;Operator
+	// +optional
+	// +default:="Sidecar"
+	ReconfigureMode ReconfigureMode `json:"reconfigureMode,omitempty"`
+
 	// The logfile sidecar configuration.
 	// +optional
 	LogFile ContainerWrapper `json:"logfile,omitzero"`
@@ -77,6 +90,42 @@ type ControllerSpec struct {
 	// +optional
 	ExtraConf string `json:"extraConf,omitempty"`
 
+	// NodeCountHeadroom is the number of Slurm nodes allowed beyond the
+	// capacity of the NodeSets when computing `MaxNodeCount`. The capacity of
+	// a NodeSet is the greater of its replicas and the `maxReplicas` of any
+	// HorizontalPodAutoscaler that scales it. `MaxNodeCount` is rounded up to
+	// a power of two, and is never lower than 1024.
+	// Ref: https://slurm.schedmd.com/slurm.conf.html#OPT_MaxNodeCount
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	NodeCountHeadroom int32 `json:"nodeCountHeadroom,omitempty"`
+
+	// ConfigStrategy determines how changes to the rendered configuration
+	// files are applied.
+	// `Automatic` applies them as soon as they are rendered.
+	// `Manual` stages them in a pending ConfigMap, with a diff against the live
+	// configuration in `status.pendingConfig`, until they are approved by
+	// setting the `slinky.slurm.net/approve-config` annotation to the pending
+	// config hash.
+	// +kubebuilder:validation:Enum=Automatic;Manual
+	// +optional
+	// +default:="Automatic"
+	ConfigStrategy ConfigStrategy `json:"configStrategy,omitempty"`
+
+	// ConfigRevision pins the rendered configuration files to the
+	// ControllerRevision with this revision number, rolling back any later
+	// changes. Unset it to resume rendering from the current spec.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ConfigRevision *int64 `json:"configRevision,omitempty"`
+
+	// ConfigRevisionHistoryLimit is the maximum number of ControllerRevisions
+	// of the rendered configuration files that will be maintained.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	// +default:=10
+	ConfigRevisionHistoryLimit *int32 `json:"configRevisionHistoryLimit,omitempty"`
+
 	// ConfigFileRefs is a list of ConfigMap references containing files to be mounted in `/etc/slurm`.
 	// Ref: https://slurm.schedmd.com/slurm.conf.html
 	// +nullable
@@ -107,6 +156,56 @@ type ControllerSpec struct {
 	// +optional
 	EpilogSlurmctldScriptRefs []ObjectReference `json:"epilogSlurmctldScriptRefs,omitzero"`
 
+	// HealthCheckIntervalSeconds is how often the health checks of the
+	// NodeSets are run, in seconds. It is only used when a NodeSet has
+	// health checks.
+	// Ref: https://slurm.schedmd.com/slurm.conf.html#OPT_HealthCheckInterval
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	// +default:=300
+	HealthCheckIntervalSeconds int32 `json:"healthCheckIntervalSeconds,omitempty"`
+
+	// Cgroup defines the `cgroup.conf` settings. It is ignored when a
+	// `cgroup.conf` is supplied through ConfigFileRefs.
+	// Ref: https://slurm.schedmd.com/cgroup.conf.html
+	// +optional
+	Cgroup ControllerCgroup `json:"cgroup,omitzero"`
+
+	// JobSubmitScriptRef is a reference to a ConfigMap key containing a
+	// `job_submit.lua` script, which is mounted into `/etc/slurm` and enables
+	// the `lua` job submit plugin. The syntax of the script is checked when
+	// the Controller is created or updated, edits to the ConfigMap are not.
+	// Ref: https://slurm.schedmd.com/job_submit_plugins.html
+	// +optional
+	JobSubmitScriptRef *corev1.ConfigMapKeySelector `json:"jobSubmitScriptRef,omitempty"`
+
+	// Scheduling defines the scheduler, priority, and preemption settings
+	// rendered in `slurm.conf`. Settings that are not set use the Slurm
+	// defaults.
+	// Ref: https://slurm.schedmd.com/sched_config.html
+	// +optional
+	Scheduling ControllerScheduling `json:"scheduling,omitzero"`
+
+	// Licenses are the local licenses of the cluster, rendered as the
+	// `Licenses` line of `slurm.conf`.
+	// Ref: https://slurm.schedmd.com/licenses.html#local_licenses
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Licenses []License `json:"licenses,omitempty"`
+
+	// RemoteLicenses are licenses served by slurmdbd, which may be shared
+	// with other clusters of the Accounting. Requires accountingRef.
+	// Ref: https://slurm.schedmd.com/licenses.html#remote_licenses
+	// +optional
+	RemoteLicenses []RemoteLicense `json:"remoteLicenses,omitempty"`
+
+	// Spank defines the SPANK plugins loaded on all Slurm nodes and clients.
+	// It is ignored when a `plugstack.conf` is supplied through ConfigFileRefs.
+	// Ref: https://slurm.schedmd.com/spank.html
+	// +optional
+	Spank Spank `json:"spank,omitzero"`
+
 	// Persistence defines a persistent volume for the slurm controller to store its save-state.
 	// Used to recover from system failures or from pod upgrades.
 	// +optional
@@ -135,6 +234,367 @@ type ControllerPersistence struct {
 	corev1.PersistentVolumeClaimSpec `json:",inline"`
 }
 
+// ReconfigureMode is the method used to reconfigure slurmctld.
+type ReconfigureMode string
+
+const (
+	ReconfigureModeSidecar  ReconfigureMode = "Sidecar"
+	ReconfigureModeOperator ReconfigureMode = "Operator"
+)
+
+// CgroupPlugin is the cgroup plugin used by slurmd.
+type CgroupPlugin string
+
+const (
+	CgroupPluginV2         CgroupPlugin = "cgroup/v2"
+	CgroupPluginV1         CgroupPlugin = "cgroup/v1"
+	CgroupPluginAutodetect CgroupPlugin = "autodetect"
+	CgroupPluginDisabled   CgroupPlugin = "disabled"
+)
+
+// ControllerCgroup defines the `cgroup.conf` settings.
+type ControllerCgroup struct {
+	// Plugin is the cgroup plugin. When `disabled`, Slurm is configured to
+	// track and constrain jobs without cgroups.
+	// Ref: https://slurm.schedmd.com/cgroup.conf.html#OPT_CgroupPlugin
+	// +kubebuilder:validation:Enum=cgroup/v2;cgroup/v1;autodetect;disabled
+	// +optional
+	// +default:="cgroup/v2"
+	Plugin CgroupPlugin `json:"plugin,omitempty"`
+
+	// ConstrainCores constrains jobs to their allocated CPUs.
+	// Ref: https://slurm.schedmd.com/cgroup.conf.html#OPT_ConstrainCores
+	// +optional
+	ConstrainCores *bool `json:"constrainCores,omitempty"`
+
+	// ConstrainRAMSpace constrains jobs to their allocated memory.
+	// Ref: https://slurm.schedmd.com/cgroup.conf.html#OPT_ConstrainRAMSpace
+	// +optional
+	ConstrainRAMSpace *bool `json:"constrainRAMSpace,omitempty"`
+
+	// AllowedRAMSpace is the percent of allocated memory a job may use when
+	// ConstrainRAMSpace is enabled.
+	// Ref: https://slurm.schedmd.com/cgroup.conf.html#OPT_AllowedRAMSpace
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	AllowedRAMSpace *int32 `json:"allowedRAMSpace,omitempty"`
+
+	// ConstrainDevices constrains jobs to their allocated devices (e.g. GRES).
+	// Ref: https://slurm.schedmd.com/cgroup.conf.html#OPT_ConstrainDevices
+	// +optional
+	ConstrainDevices *bool `json:"constrainDevices,omitempty"`
+
+	// ConstrainSwapSpace constrains the swap space of jobs.
+	// Ref: https://slurm.schedmd.com/cgroup.conf.html#OPT_ConstrainSwapSpace
+	// +optional
+	ConstrainSwapSpace *bool `json:"constrainSwapSpace,omitempty"`
+
+	// EnableControllers enables the cgroup controllers needed by Slurm in the
+	// parent cgroups of slurmd.
+	// Ref: https://slurm.schedmd.com/cgroup.conf.html#OPT_EnableControllers
+	// +optional
+	EnableControllers *bool `json:"enableControllers,omitempty"`
+}
+
+// ControllerScheduling defines the scheduling settings of `slurm.conf`.
+type ControllerScheduling struct {
+	// SchedulerType is the scheduler plugin.
+	// Ref: https://slurm.schedmd.com/slurm.conf.html#OPT_SchedulerType
+	// +kubebuilder:validation:Enum=sched/backfill;sched/builtin
+	// +optional
+	SchedulerType SchedulerType `json:"schedulerType,omitempty"`
+
+	// SchedulerParameters are the scheduler plugin parameters
+	// (e.g. `bf_continue`, `bf_window=4320`).
+	// Ref: https://slurm.schedmd.com/slurm.conf.html#OPT_SchedulerParameters
+	// +optional
+	SchedulerParameters []string `json:"schedulerParameters,omitempty"`
+
+	// Priority defines how job priority is calculated.
+	// +optional
+	Priority ControllerPriority `json:"priority,omitzero"`
+
+	// Preempt defines how jobs are preempted.
+	// Ref: https://slurm.schedmd.com/preempt.html
+	// +optional
+	Preempt ControllerPreempt `json:"preempt,omitzero"`
+}
+
+// SchedulerType is a Slurm scheduler plugin.
+type SchedulerType string
+
+const (
+	SchedulerTypeBackfill SchedulerType = "sched/backfill"
+	SchedulerTypeBuiltin  SchedulerType = "sched/builtin"
+)
+
+// ControllerPriority defines the priority settings of `slurm.conf`.
+type ControllerPriority struct {
+	// Type is the priority plugin. The weights and decay half-life are only
+	// used by `priority/multifactor`.
+	// Ref: https://slurm.schedmd.com/slurm.conf.html#OPT_PriorityType
+	// +kubebuilder:validation:Enum=priority/basic;priority/multifactor
+	// +optional
+	Type PriorityType `json:"type,omitempty"`
+
+	// DecayHalfLife is the half-life of past usage in the fair-share factor,
+	// in Slurm time format (e.g. `7-0` for 7 days).
+	// Ref: https://slurm.schedmd.com/slurm.conf.html#OPT_PriorityDecayHalfLife
+	// +optional
+	DecayHalfLife string `json:"decayHalfLife,omitempty"`
+
+	// Weights are the weights of the multifactor priority factors.
+	// Ref: https://slurm.schedmd.com/priority_multifactor.html
+	// +optional
+	Weights PriorityWeights `json:"weights,omitzero"`
+}
+
+// PriorityType is a Slurm priority plugin.
+type PriorityType string
+
+const (
+	PriorityTypeBasic       PriorityType = "priority/basic"
+	PriorityTypeMultifactor PriorityType = "priority/multifactor"
+)
+
+// PriorityWeights are the weights of the multifactor priority factors.
+type PriorityWeights struct {
+	// Age is the weight of the job age factor.
+	// Ref: https://slurm.schedmd.com/slurm.conf.html#OPT_PriorityWeightAge
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=4294967295
+	// +optional
+	Age *int64 `json:"age,omitempty"`
+
+	// FairShare is the weight of the fair-share factor.
+	// Ref: https://slurm.schedmd.com/slurm.conf.html#OPT_PriorityWeightFairshare
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=4294967295
+	// +optional
+	FairShare *int64 `json:"fairShare,omitempty"`
+
+	// JobSize is the weight of the job size factor.
+	// Ref: https://slurm.schedmd.com/slurm.conf.html#OPT_PriorityWeightJobSize
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=4294967295
+	// +optional
+	JobSize *int64 `json:"jobSize,omitempty"`
+
+	// Partition is the weight of the partition factor.
+	// Ref: https://slurm.schedmd.com/slurm.conf.html#OPT_PriorityWeightPartition
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=4294967295
+	// +optional
+	Partition *int64 `json:"partition,omitempty"`
+
+	// QOS is the weight of the QOS factor.
+	// Ref: https://slurm.schedmd.com/slurm.conf.html#OPT_PriorityWeightQOS
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=4294967295
+	// +optional
+	QOS *int64 `json:"qos,omitempty"`
+
+	// TRES are the weights of the TRES factors, keyed by TRES type
+	// (e.g. `CPU`, `Mem`, `GRES/gpu`).
+	// Ref: https://slurm.schedmd.com/slurm.conf.html#OPT_PriorityWeightTRES
+	// +optional
+	TRES map[string]int64 `json:"tres,omitempty"`
+}
+
+// ControllerPreempt defines the preemption settings of `slurm.conf`.
+type ControllerPreempt struct {
+	// Type is the preemption plugin, which selects the jobs that can be
+	// preempted.
+	// Ref: https://slurm.schedmd.com/slurm.conf.html#OPT_PreemptType
+	// +kubebuilder:validation:Enum=preempt/none;preempt/partition_prio;preempt/qos
+	// +optional
+	Type PreemptType `json:"type,omitempty"`
+
+	// Mode is the list of preemption modes (e.g. `REQUEUE`, `SUSPEND,GANG`).
+	// Ref: https://slurm.schedmd.com/slurm.conf.html#OPT_PreemptMode
+	// +optional
+	Mode []PreemptMode `json:"mode,omitempty"`
+}
+
+// PreemptType is a Slurm preemption plugin.
+type PreemptType string
+
+const (
+	PreemptTypeNone          PreemptType = "preempt/none"
+	PreemptTypePartitionPrio PreemptType = "preempt/partition_prio"
+	PreemptTypeQOS           PreemptType = "preempt/qos"
+)
+
+// PreemptMode is a Slurm preemption mode.
+// +kubebuilder:validation:Enum=OFF;CANCEL;GANG;REQUEUE;SUSPEND;WITHIN
+type PreemptMode string
+
+const (
+	PreemptModeOff     PreemptMode = "OFF"
+	PreemptModeCancel  PreemptMode = "CANCEL"
+	PreemptModeGang    PreemptMode = "GANG"
+	PreemptModeRequeue PreemptMode = "REQUEUE"
+	PreemptModeSuspend PreemptMode = "SUSPEND"
+	PreemptModeWithin  PreemptMode = "WITHIN"
+)
+
+// License is a local license.
+type License struct {
+	// Name of the license, as requested by jobs (e.g. `sbatch -L matlab`).
+	// +kubebuilder:validation:Pattern=`^[^\s,:@]+$`
+	// +required
+	Name string `json:"name"`
+
+	// Count is the number of licenses available.
+	// +kubebuilder:validation:Minimum=0
+	// +required
+	Count int32 `json:"count"`
+}
+
+// RemoteLicense is a license served by slurmdbd.
+type RemoteLicense struct {
+	// Name of the license.
+	// +kubebuilder:validation:Pattern=`^[^\s,:@]+$`
+	// +required
+	Name string `json:"name"`
+
+	// Server is the license server, which jobs request with the name
+	// (e.g. `sbatch -L matlab@flexlm`).
+	// +kubebuilder:validation:Pattern=`^[^\s,:@]+$`
+	// +required
+	Server string `json:"server"`
+
+	// ServerType is the type of the license server (e.g. `flexlm`).
+	// slurmctld does not report it, so it is not verified.
+	// +optional
+	ServerType string `json:"serverType,omitempty"`
+
+	// Count is the number of licenses available on the server, across all
+	// clusters.
+	// +kubebuilder:validation:Minimum=0
+	// +required
+	Count int32 `json:"count"`
+
+	// AllowedPercent is the percent of Count allocated to this cluster.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	// +default:=100
+	AllowedPercent *int32 `json:"allowedPercent,omitempty"`
+}
+
+// LicenseName returns the name that jobs request the license with.
+func (l *RemoteLicense) LicenseName() string {
+	return l.Name + "@" + l.Server
+}
+
+// ClusterCount returns the number of licenses allocated to the cluster, which
+// slurmctld reports as the total of the license.
+func (l *RemoteLicense) ClusterCount() int32 {
+	allowed := int64(100)
+	if l.AllowedPercent != nil {
+		allowed = int64(*l.AllowedPercent)
+	}
+	return int32(int64(l.Count) * allowed / 100)
+}
+
+// LicenseStatus is the usage of a license reported by slurmctld.
+type LicenseStatus struct {
+	// Name of the license, including the server of remote licenses.
+	Name string `json:"name"`
+
+	// Remote indicates the license is served by slurmdbd.
+	// +optional
+	Remote bool `json:"remote,omitempty"`
+
+	// Total is the number of licenses available to the cluster.
+	Total int32 `json:"total"`
+
+	// Used is the number of licenses in use by jobs.
+	Used int32 `json:"used"`
+
+	// Free is the number of licenses that are not in use or reserved.
+	Free int32 `json:"free"`
+
+	// Reserved is the number of licenses held by reservations.
+	// +optional
+	Reserved int32 `json:"reserved,omitempty"`
+}
+
+// ConfigStrategy is the strategy used to apply configuration changes.
+type ConfigStrategy string
+
+const (
+	ConfigStrategyAutomatic ConfigStrategy = "Automatic"
+	ConfigStrategyManual    ConfigStrategy = "Manual"
+)
+
+// PendingConfig describes rendered configuration files awaiting approval.
+type PendingConfig struct {
+	// ConfigMapName is the name of the ConfigMap holding the pending
+	// configuration files.
+	// +optional
+	ConfigMapName string `json:"configMapName,omitempty"`
+
+	// Hash identifies the pending configuration files. Set the
+	// `slinky.slurm.net/approve-config` annotation to it to apply them.
+	// +optional
+	Hash string `json:"hash,omitempty"`
+
+	// Diff is a unified diff from the live to the pending configuration files.
+	// +optional
+	Diff string `json:"diff,omitempty"`
+
+	// Time is when the pending configuration files were first rendered.
+	// +optional
+	Time metav1.Time `json:"time,omitzero"`
+}
+
+// ConfigChangeAction is the action taken to apply a configuration change.
+type ConfigChangeAction string
+
+const (
+	// ConfigChangeActionReconfigure applies the change by reconfiguring the
+	// running daemons.
+	ConfigChangeActionReconfigure ConfigChangeAction = "Reconfigure"
+	// ConfigChangeActionRestartSlurmctld applies the change by a rolling
+	// restart of slurmctld.
+	ConfigChangeActionRestartSlurmctld ConfigChangeAction = "RestartSlurmctld"
+	// ConfigChangeActionRestartSlurmd applies the change by a rolling restart
+	// of slurmctld and a drain-aware rolling restart of all NodeSets.
+	ConfigChangeActionRestartSlurmd ConfigChangeAction = "RestartSlurmd"
+)
+
+// ConfigChange describes a change to the rendered `slurm.conf`.
+type ConfigChange struct {
+	// Action is the action taken to apply the change.
+	// +optional
+	Action ConfigChangeAction `json:"action,omitempty"`
+
+	// Keys are the changed configuration keys that require the action.
+	// +optional
+	Keys []string `json:"keys,omitempty"`
+
+	// Time is when the change was observed.
+	// +optional
+	Time metav1.Time `json:"time,omitzero"`
+}
+
+const (
+	// ControllerPartitionDrift indicates whether the cluster name and partitions
+	// running in slurmctld differ from those rendered by the operator.
+	ControllerPartitionDrift = "PartitionDrift"
+	// ControllerReconfigured indicates whether slurmctld was reconfigured with
+	// the current configuration.
+	ControllerReconfigured = "Reconfigured"
+	// ControllerRemoteLicensesRegistered indicates whether slurmctld serves
+	// every license in remoteLicenses.
+	ControllerRemoteLicensesRegistered = "RemoteLicensesRegistered"
+	// ControllerNodeCountExceeded indicates whether the NodeSets can scale
+	// beyond the `MaxNodeCount` of the rendered configuration.
+	ControllerNodeCountExceeded = "NodeCountExceeded"
+)
+
 // ControllerStatus defines the observed state of Controller
 type ControllerStatus struct {
 	// Represents the latest available observations of a Controller's current state.
@@ -144,6 +604,46 @@ type ControllerStatus struct {
 	// +listType=map
 	// +listMapKey=type
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// ConfigHash is the hash of the configuration files mounted into slurmctld.
+	// +optional
+	ConfigHash string `json:"configHash,omitempty"`
+
+	// ReconfiguredHash is the ConfigHash that slurmctld was last reconfigured
+	// with by the operator.
+	// +optional
+	ReconfiguredHash string `json:"reconfiguredHash,omitempty"`
+
+	// LastConfigChange describes the most recent change to the rendered
+	// `slurm.conf` and the action taken to apply it.
+	// +optional
+	LastConfigChange *ConfigChange `json:"lastConfigChange,omitempty"`
+
+	// PendingConfig describes the rendered configuration files awaiting
+	// approval, when configStrategy is `Manual`.
+	// +optional
+	PendingConfig *PendingConfig `json:"pendingConfig,omitempty"`
+
+	// ConfigRevision is the revision number of the active ControllerRevision
+	// of the rendered configuration files.
+	// +optional
+	ConfigRevision int64 `json:"configRevision,omitempty"`
+
+	// ConfigRevisionName is the name of the active ControllerRevision of the
+	// rendered configuration files.
+	// +optional
+	ConfigRevisionName string `json:"configRevisionName,omitempty"`
+
+	// CollisionCount is the count of hash collisions for the Controller. The
+	// Controller uses this field as a collision avoidance mechanism when it
+	// needs to create the name for the newest ControllerRevision.
+	// +optional
+	CollisionCount *int32 `json:"collisionCount,omitempty"`
+
+	// Licenses is the usage of the licenses known to slurmctld, polled
+	// through slurmrestd.
+	// +optional
+	Licenses []LicenseStatus `json:"licenses,omitempty"`
 }
 
 // +kubebuilder:object:root=true
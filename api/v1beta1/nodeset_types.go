@@ -5,6 +5,7 @@ package v1beta1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
@@ -56,10 +57,51 @@ type NodeSetSpec struct {
 	// +optional
 	ExtraConf string `json:"extraConf,omitzero"`
 
+	// ResourceOverhead reserves part of the slurmd container's resource limits
+	// for system use. The Slurm node definition is derived from the limits.
+	// +optional
+	ResourceOverhead NodeSetResourceOverhead `json:"resourceOverhead,omitzero"`
+
 	// Partition defines the Slurm partition configuration for this NodeSet.
 	// +optional
 	Partition NodeSetPartition `json:"partition,omitzero"`
 
+	// Gres is the list of generic resources (GRES) on the nodes of this
+	// NodeSet, rendered into `gres.conf` scoped to the NodeSet's nodes.
+	// Ref: https://slurm.schedmd.com/gres.conf.html
+	// +listType=atomic
+	// +optional
+	Gres []NodeSetGres `json:"gres,omitempty"`
+
+	// Spank defines the SPANK plugins loaded only on the nodes of this NodeSet,
+	// in addition to those of the Controller.
+	// Ref: https://slurm.schedmd.com/spank.html
+	// +optional
+	Spank Spank `json:"spank,omitzero"`
+
+	// PrologScriptRefs is a list of prolog scripts run only on the nodes of
+	// this NodeSet, after those of the Controller. Every key of the
+	// ConfigMaps is a script, run in name order.
+	// Ref: https://slurm.schedmd.com/prolog_epilog.html
+	// +nullable
+	// +optional
+	PrologScriptRefs []ObjectReference `json:"prologScriptRefs,omitzero"`
+
+	// EpilogScriptRefs is a list of epilog scripts run only on the nodes of
+	// this NodeSet, after those of the Controller. Every key of the
+	// ConfigMaps is a script, run in name order.
+	// Ref: https://slurm.schedmd.com/prolog_epilog.html
+	// +nullable
+	// +optional
+	EpilogScriptRefs []ObjectReference `json:"epilogScriptRefs,omitzero"`
+
+	// HealthCheck defines the health checks run periodically on the nodes of
+	// this NodeSet. A failing check drains the node. The interval is set by
+	// `HealthCheckIntervalSeconds` of the Controller.
+	// Ref: https://slurm.schedmd.com/slurm.conf.html#OPT_HealthCheckProgram
+	// +optional
+	HealthCheck NodeSetHealthCheck `json:"healthCheck,omitzero"`
+
 	// volumeClaimTemplates is a list of claims that pods are allowed to reference.
 	// The NodeSet controller is responsible for mapping network identities to
 	// claims in a way that maintains the identity of a pod. Every claim in
@@ -128,6 +170,65 @@ type NodeSetPartition struct {
 	Config string `json:"config,omitzero"`
 }
 
+// NodeSetResourceOverhead defines the resources of a Slurm node reserved for
+// system use, which are not available to jobs.
+type NodeSetResourceOverhead struct {
+	// CoreSpecCount is the number of cores reserved for system use.
+	// Ref: https://slurm.schedmd.com/slurm.conf.html#OPT_CoreSpecCount
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	CoreSpecCount int32 `json:"coreSpecCount,omitzero"`
+
+	// MemSpecLimit is the amount of memory reserved for system use.
+	// Ref: https://slurm.schedmd.com/slurm.conf.html#OPT_MemSpecLimit
+	// +optional
+	MemSpecLimit *resource.Quantity `json:"memSpecLimit,omitempty"`
+}
+
+// GresAutoDetect is the mechanism used to detect GRES on a node.
+type GresAutoDetect string
+
+const (
+	GresAutoDetectNvidia GresAutoDetect = "nvidia"
+	GresAutoDetectNvml   GresAutoDetect = "nvml"
+	GresAutoDetectRsmi   GresAutoDetect = "rsmi"
+	GresAutoDetectNrt    GresAutoDetect = "nrt"
+	GresAutoDetectOneapi GresAutoDetect = "oneapi"
+	GresAutoDetectOff    GresAutoDetect = "off"
+)
+
+// NodeSetGres defines a generic resource (GRES) on the nodes of a NodeSet.
+type NodeSetGres struct {
+	// Name of the generic resource (e.g. `gpu`, `shard`, `mps`, or a custom name).
+	// Ref: https://slurm.schedmd.com/gres.conf.html#OPT_Name
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_]+$`
+	// +required
+	Name string `json:"name"`
+
+	// Type is an arbitrary string identifying the type of the resource (e.g. `a100`).
+	// Ref: https://slurm.schedmd.com/gres.conf.html#OPT_Type
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// Count is the number of resources on each node, with an optional suffix
+	// of `K`, `M`, `G`, `T`, or `P`.
+	// Ref: https://slurm.schedmd.com/gres.conf.html#OPT_Count
+	// +optional
+	Count string `json:"count,omitempty"`
+
+	// File is the fully qualified path of the device files associated with
+	// the resource, which may be a hostlist expression (e.g. `/dev/nvidia[0-3]`).
+	// Ref: https://slurm.schedmd.com/gres.conf.html#OPT_File
+	// +optional
+	File string `json:"file,omitempty"`
+
+	// AutoDetect is the mechanism used to detect the resource.
+	// Ref: https://slurm.schedmd.com/gres.conf.html#OPT_AutoDetect
+	// +kubebuilder:validation:Enum=nvidia;nvml;rsmi;nrt;oneapi;off
+	// +optional
+	AutoDetect GresAutoDetect `json:"autoDetect,omitempty"`
+}
+
 // NodeSetSsh defines SSH configuration for NodeSet worker pods.
 type NodeSetSsh struct {
 	// Enabled controls whether SSH access is enabled for this NodeSet.
@@ -222,6 +323,15 @@ type RollingUpdateNodeSetStrategy struct {
 	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
 }
 
+// NodeSetHealthCheck defines the health checks of a NodeSet.
+type NodeSetHealthCheck struct {
+	// ScriptRefs is a list of health check scripts. Every key of the
+	// ConfigMaps is a script, run in name order until one fails.
+	// +nullable
+	// +optional
+	ScriptRefs []ObjectReference `json:"scriptRefs,omitzero"`
+}
+
 // NodeSetStatus defines the observed state of NodeSet
 type NodeSetStatus struct {
 	// Total number of non-terminated pods targeted by this NodeSet (their labels match the Selector).
@@ -6,6 +6,7 @@ package v1beta1
 import (
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/SlinkyProject/slurm-operator/internal/utils/domainname"
@@ -35,3 +36,22 @@ func (o *RestApi) ServiceFQDNShort() string {
 	s := o.ServiceKey()
 	return domainname.FqdnShort(s.Name, s.Namespace)
 }
+
+// TLSKey is the secret with the operator-generated certificates.
+func (o *RestApi) TLSKey() types.NamespacedName {
+	key := o.Key()
+	return types.NamespacedName{
+		Name:      fmt.Sprintf("%s-tls", key.Name),
+		Namespace: o.Namespace,
+	}
+}
+
+// TLSRef is the secret with the certificates that the RestApi serves.
+func (o *RestApi) TLSRef() *corev1.LocalObjectReference {
+	if ref := o.Spec.TLS.SecretRef; ref != nil {
+		return ref
+	}
+	return &corev1.LocalObjectReference{
+		Name: o.TLSKey().Name,
+	}
+}
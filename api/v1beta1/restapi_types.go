@@ -4,6 +4,7 @@
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -43,8 +44,41 @@ type RestApiSpec struct {
 	// Service defines a template for a Kubernetes Service object.
 	// +optional
 	Service ServiceSpec `json:"service,omitzero"`
+
+	// TLS configures HTTPS for the RestApi.
+	// +optional
+	TLS RestApiTLS `json:"tls,omitzero"`
 }
 
+// RestApiTLS defines how the RestApi serves HTTPS.
+type RestApiTLS struct {
+	// Enabled serves the RestApi over HTTPS. slurmrestd listens on localhost,
+	// and the proxy container terminates TLS on the slurmrestd port.
+	// +optional
+	// +default:=false
+	Enabled bool `json:"enabled,omitzero"`
+
+	// SecretRef is a reference to a secret with the serving certificate
+	// (`tls.crt`), its private key (`tls.key`), and the CA certificate
+	// (`ca.crt`) the operator uses to verify it.
+	// When omitted, the operator generates a CA and a serving certificate, and
+	// renews them before they expire.
+	// +optional
+	SecretRef *corev1.LocalObjectReference `json:"secretRef,omitempty"`
+
+	// The proxy container configuration.
+	// The image must provide ghostunnel.
+	// Ref: https://github.com/ghostunnel/ghostunnel
+	// +optional
+	Proxy ContainerWrapper `json:"proxy,omitzero"`
+}
+
+const (
+	// RestApiTLSReady indicates whether the secret with the certificates
+	// served by the TLS proxy exists and has all the keys it needs.
+	RestApiTLSReady = "TLSReady"
+)
+
 // RestApiStatus defines the observed state of Restapi
 type RestApiStatus struct {
 	// Represents the latest available observations of a Restapi's current state.
@@ -0,0 +1,30 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func (o *SlurmAccount) Key() types.NamespacedName {
+	return types.NamespacedName{
+		Name:      o.Name,
+		Namespace: o.Namespace,
+	}
+}
+
+func (o *SlurmAccount) AccountingRef() ObjectReference {
+	ref := o.Spec.AccountingRef
+	if ref.Namespace == "" {
+		ref.Namespace = o.Namespace
+	}
+	return ref
+}
+
+func (o *SlurmAccount) AccountName() string {
+	if o.Spec.AccountName != "" {
+		return o.Spec.AccountName
+	}
+	return o.Name
+}
@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	SlurmAccountKind = "SlurmAccount"
+)
+
+var (
+	SlurmAccountGVK        = GroupVersion.WithKind(SlurmAccountKind)
+	SlurmAccountAPIVersion = GroupVersion.String()
+)
+
+// SlurmAccountSpec defines the desired state of SlurmAccount
+type SlurmAccountSpec struct {
+	// accountingRef is a reference to the Accounting CR whose slurmdbd stores
+	// the account.
+	// +required
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="accountingRef is immutable"
+	AccountingRef ObjectReference `json:"accountingRef"`
+
+	// The Slurm account name. Defaults to the name of the SlurmAccount.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="accountName is immutable"
+	AccountName string `json:"accountName,omitempty"`
+
+	// Description of the account.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Organization the account belongs to.
+	// +optional
+	Organization string `json:"organization,omitempty"`
+
+	// The parent account in the account hierarchy.
+	// Ref: https://slurm.schedmd.com/sacctmgr.html#OPT_Parent
+	// +optional
+	// +default:="root"
+	ParentAccount string `json:"parentAccount,omitempty"`
+
+	// The fairshare of the account associations.
+	// When omitted, the Slurm default is used.
+	// Ref: https://slurm.schedmd.com/sacctmgr.html#OPT_Fairshare
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Fairshare int32 `json:"fairshare,omitempty"`
+
+	// The clusters the account is associated with.
+	// Defaults to the clusters of every Controller using the Accounting.
+	// +optional
+	// +listType=set
+	Clusters []string `json:"clusters,omitempty"`
+}
+
+const (
+	// SlurmAccountSynced indicates whether slurmdbd matches the spec.
+	SlurmAccountSynced = "Synced"
+)
+
+// SlurmAccountStatus defines the observed state of SlurmAccount
+type SlurmAccountStatus struct {
+	// Clusters the account is associated with in slurmdbd.
+	// +optional
+	Clusters []string `json:"clusters,omitempty"`
+
+	// Drift lists the changes made to the account in slurmdbd, outside of the
+	// SlurmAccount, that were reverted by the last sync.
+	// +optional
+	Drift []string `json:"drift,omitempty"`
+
+	// LastSyncTime is when slurmdbd last matched the spec.
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// Represents the latest available observations of a SlurmAccount's current state.
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=slurmaccounts;sacc
+// +kubebuilder:printcolumn:name="PARENT",type="string",JSONPath=".spec.parentAccount",description="The parent account."
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type==\"Synced\")].status",description="Whether slurmdbd matches the spec."
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+
+// SlurmAccount is the Schema for the slurmaccounts API
+type SlurmAccount struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SlurmAccountSpec   `json:"spec,omitempty"`
+	Status SlurmAccountStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SlurmAccountList contains a list of SlurmAccount
+type SlurmAccountList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SlurmAccount `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SlurmAccount{}, &SlurmAccountList{})
+}
@@ -0,0 +1,30 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func (o *SlurmQOS) Key() types.NamespacedName {
+	return types.NamespacedName{
+		Name:      o.Name,
+		Namespace: o.Namespace,
+	}
+}
+
+func (o *SlurmQOS) AccountingRef() ObjectReference {
+	ref := o.Spec.AccountingRef
+	if ref.Namespace == "" {
+		ref.Namespace = o.Namespace
+	}
+	return ref
+}
+
+func (o *SlurmQOS) QOSName() string {
+	if o.Spec.QOSName != "" {
+		return o.Spec.QOSName
+	}
+	return o.Name
+}
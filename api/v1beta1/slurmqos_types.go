@@ -0,0 +1,172 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	SlurmQOSKind = "SlurmQOS"
+)
+
+var (
+	SlurmQOSGVK        = GroupVersion.WithKind(SlurmQOSKind)
+	SlurmQOSAPIVersion = GroupVersion.String()
+)
+
+// SlurmQOSSpec defines the desired state of SlurmQOS
+type SlurmQOSSpec struct {
+	// accountingRef is a reference to the Accounting CR whose slurmdbd stores
+	// the QOS.
+	// +required
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="accountingRef is immutable"
+	AccountingRef ObjectReference `json:"accountingRef"`
+
+	// The Slurm QOS name. Defaults to the name of the SlurmQOS.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="qosName is immutable"
+	QOSName string `json:"qosName,omitempty"`
+
+	// Description of the QOS.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// The priority of jobs using the QOS.
+	// Ref: https://slurm.schedmd.com/sacctmgr.html#OPT_Priority
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Priority int32 `json:"priority,omitempty"`
+
+	// The QOS whose jobs can be preempted by jobs using this QOS.
+	// Ref: https://slurm.schedmd.com/sacctmgr.html#OPT_Preempt
+	// +optional
+	// +listType=set
+	Preempt []string `json:"preempt,omitempty"`
+
+	// The mechanism used to preempt jobs of this QOS, which overrides the
+	// cluster-wide PreemptMode.
+	// Ref: https://slurm.schedmd.com/sacctmgr.html#OPT_PreemptMode
+	// +optional
+	// +default:="Cluster"
+	PreemptMode SlurmQOSPreemptMode `json:"preemptMode,omitempty"`
+
+	// The total TRES that can be used by all jobs using the QOS, by TRES name
+	// (e.g. `cpu`, `mem` in megabytes, `gres/gpu`).
+	// Ref: https://slurm.schedmd.com/sacctmgr.html#OPT_GrpTRES
+	// +optional
+	GrpTRES map[string]int64 `json:"grpTRES,omitempty"`
+
+	// The TRES that can be used by all jobs of each user using the QOS, by
+	// TRES name (e.g. `cpu`, `mem` in megabytes, `gres/gpu`).
+	// Ref: https://slurm.schedmd.com/sacctmgr.html#OPT_MaxTRESPerUser
+	// +optional
+	MaxTRESPerUser map[string]int64 `json:"maxTRESPerUser,omitempty"`
+
+	// The maximum wall clock time of jobs using the QOS, rounded down to the
+	// minute. When omitted, there is no limit.
+	// Ref: https://slurm.schedmd.com/sacctmgr.html#OPT_MaxWall
+	// +optional
+	MaxWall *metav1.Duration `json:"maxWall,omitempty"`
+
+	// The factor by which the usage of jobs using the QOS is multiplied.
+	// When omitted, usage is not scaled.
+	// Ref: https://slurm.schedmd.com/sacctmgr.html#OPT_UsageFactor
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	UsageFactor string `json:"usageFactor,omitempty"`
+
+	// Flags of the QOS.
+	// Ref: https://slurm.schedmd.com/sacctmgr.html#OPT_Flags
+	// +optional
+	// +listType=set
+	Flags []SlurmQOSFlag `json:"flags,omitempty"`
+}
+
+// SlurmQOSPreemptMode is the preemption mechanism of a QOS.
+// +kubebuilder:validation:Enum=Cluster;Cancel;Requeue;Suspend
+type SlurmQOSPreemptMode string
+
+const (
+	SlurmQOSPreemptModeCluster SlurmQOSPreemptMode = "Cluster"
+	SlurmQOSPreemptModeCancel  SlurmQOSPreemptMode = "Cancel"
+	SlurmQOSPreemptModeRequeue SlurmQOSPreemptMode = "Requeue"
+	SlurmQOSPreemptModeSuspend SlurmQOSPreemptMode = "Suspend"
+)
+
+// SlurmQOSFlag is a flag of a QOS.
+// +kubebuilder:validation:Enum=DenyOnLimit;EnforceUsageThreshold;NoDecay;NoReserve;OverPartQOS;PartitionMaxNodes;PartitionMinNodes;PartitionTimeLimit;RequiresReservation;UsageFactorSafe
+type SlurmQOSFlag string
+
+const (
+	SlurmQOSFlagDenyOnLimit           SlurmQOSFlag = "DenyOnLimit"
+	SlurmQOSFlagEnforceUsageThreshold SlurmQOSFlag = "EnforceUsageThreshold"
+	SlurmQOSFlagNoDecay               SlurmQOSFlag = "NoDecay"
+	SlurmQOSFlagNoReserve             SlurmQOSFlag = "NoReserve"
+	SlurmQOSFlagOverPartQOS           SlurmQOSFlag = "OverPartQOS"
+	SlurmQOSFlagPartitionMaxNodes     SlurmQOSFlag = "PartitionMaxNodes"
+	SlurmQOSFlagPartitionMinNodes     SlurmQOSFlag = "PartitionMinNodes"
+	SlurmQOSFlagPartitionTimeLimit    SlurmQOSFlag = "PartitionTimeLimit"
+	SlurmQOSFlagRequiresReservation   SlurmQOSFlag = "RequiresReservation"
+	SlurmQOSFlagUsageFactorSafe       SlurmQOSFlag = "UsageFactorSafe"
+)
+
+const (
+	// SlurmQOSSynced indicates whether slurmdbd matches the spec.
+	SlurmQOSSynced = "Synced"
+)
+
+// SlurmQOSStatus defines the observed state of SlurmQOS
+type SlurmQOSStatus struct {
+	// Drift lists the changes made to the QOS in slurmdbd, outside of the
+	// SlurmQOS, that were reverted by the last sync.
+	// +optional
+	Drift []string `json:"drift,omitempty"`
+
+	// UnmanagedQOS lists the QOS in slurmdbd that no SlurmQOS of the
+	// Accounting manages.
+	// +optional
+	UnmanagedQOS []string `json:"unmanagedQOS,omitempty"`
+
+	// LastSyncTime is when slurmdbd last matched the spec.
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// Represents the latest available observations of a SlurmQOS's current state.
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=slurmqoses,singular=slurmqos,shortName=sqos
+// +kubebuilder:printcolumn:name="PRIORITY",type="integer",JSONPath=".spec.priority",description="The priority of jobs using the QOS."
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type==\"Synced\")].status",description="Whether slurmdbd matches the spec."
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+
+// SlurmQOS is the Schema for the slurmqoses API
+type SlurmQOS struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SlurmQOSSpec   `json:"spec,omitempty"`
+	Status SlurmQOSStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SlurmQOSList contains a list of SlurmQOS
+type SlurmQOSList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SlurmQOS `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SlurmQOS{}, &SlurmQOSList{})
+}
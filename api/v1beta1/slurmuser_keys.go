@@ -0,0 +1,30 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func (o *SlurmUser) Key() types.NamespacedName {
+	return types.NamespacedName{
+		Name:      o.Name,
+		Namespace: o.Namespace,
+	}
+}
+
+func (o *SlurmUser) AccountingRef() ObjectReference {
+	ref := o.Spec.AccountingRef
+	if ref.Namespace == "" {
+		ref.Namespace = o.Namespace
+	}
+	return ref
+}
+
+func (o *SlurmUser) UserName() string {
+	if o.Spec.UserName != "" {
+		return o.Spec.UserName
+	}
+	return o.Name
+}
@@ -0,0 +1,142 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	SlurmUserKind = "SlurmUser"
+)
+
+var (
+	SlurmUserGVK        = GroupVersion.WithKind(SlurmUserKind)
+	SlurmUserAPIVersion = GroupVersion.String()
+)
+
+// SlurmUserSpec defines the desired state of SlurmUser
+// +kubebuilder:validation:XValidation:rule="!has(self.associations) || self.associations.exists(a, a.account == self.defaultAccount)",message="defaultAccount must be one of the association accounts"
+type SlurmUserSpec struct {
+	// accountingRef is a reference to the Accounting CR whose slurmdbd stores
+	// the user.
+	// +required
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="accountingRef is immutable"
+	AccountingRef ObjectReference `json:"accountingRef"`
+
+	// The Slurm user name. Defaults to the name of the SlurmUser.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="userName is immutable"
+	UserName string `json:"userName,omitempty"`
+
+	// The account used when a job does not request one.
+	// Ref: https://slurm.schedmd.com/sacctmgr.html#OPT_DefaultAccount
+	// +required
+	DefaultAccount string `json:"defaultAccount"`
+
+	// The administrative privileges of the user.
+	// Ref: https://slurm.schedmd.com/sacctmgr.html#OPT_AdminLevel
+	// +optional
+	// +default:="None"
+	AdminLevel SlurmAdminLevel `json:"adminLevel,omitempty"`
+
+	// The fairshare of the user associations.
+	// When omitted, the Slurm default is used.
+	// Ref: https://slurm.schedmd.com/sacctmgr.html#OPT_Fairshare
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Fairshare int32 `json:"fairshare,omitempty"`
+
+	// The associations of the user.
+	// Defaults to the default account on every cluster using the Accounting.
+	// +optional
+	Associations []SlurmUserAssociation `json:"associations,omitempty"`
+}
+
+// SlurmAdminLevel is the administrative privilege level of a Slurm user.
+// +kubebuilder:validation:Enum=None;Operator;Administrator
+type SlurmAdminLevel string
+
+const (
+	SlurmAdminLevelNone          SlurmAdminLevel = "None"
+	SlurmAdminLevelOperator      SlurmAdminLevel = "Operator"
+	SlurmAdminLevelAdministrator SlurmAdminLevel = "Administrator"
+)
+
+// SlurmUserAssociation associates a user with an account.
+type SlurmUserAssociation struct {
+	// The account of the association.
+	// +required
+	Account string `json:"account"`
+
+	// The cluster of the association.
+	// When omitted, the association is made on every cluster using the
+	// Accounting.
+	// +optional
+	Cluster string `json:"cluster,omitempty"`
+
+	// The partition of the association.
+	// When omitted, the association applies to every partition.
+	// +optional
+	Partition string `json:"partition,omitempty"`
+}
+
+const (
+	// SlurmUserSynced indicates whether slurmdbd matches the spec.
+	SlurmUserSynced = "Synced"
+)
+
+// SlurmUserStatus defines the observed state of SlurmUser
+type SlurmUserStatus struct {
+	// Associations of the user in slurmdbd.
+	// +optional
+	Associations []SlurmUserAssociation `json:"associations,omitempty"`
+
+	// Drift lists the changes made to the user in slurmdbd, outside of the
+	// SlurmUser, that were reverted by the last sync.
+	// +optional
+	Drift []string `json:"drift,omitempty"`
+
+	// LastSyncTime is when slurmdbd last matched the spec.
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// Represents the latest available observations of a SlurmUser's current state.
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=slurmusers;suser
+// +kubebuilder:printcolumn:name="DEFAULT ACCOUNT",type="string",JSONPath=".spec.defaultAccount",description="The default account."
+// +kubebuilder:printcolumn:name="ADMIN",type="string",JSONPath=".spec.adminLevel",description="The administrative privileges."
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type==\"Synced\")].status",description="Whether slurmdbd matches the spec."
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+
+// SlurmUser is the Schema for the slurmusers API
+type SlurmUser struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SlurmUserSpec   `json:"spec,omitempty"`
+	Status SlurmUserStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SlurmUserList contains a list of SlurmUser
+type SlurmUserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SlurmUser `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SlurmUser{}, &SlurmUserList{})
+}
@@ -0,0 +1,23 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func (o *Upgrade) Key() types.NamespacedName {
+	return types.NamespacedName{
+		Name:      o.Name,
+		Namespace: o.Namespace,
+	}
+}
+
+func (o *Upgrade) ControllerRef() ObjectReference {
+	ref := o.Spec.ControllerRef
+	if ref.Namespace == "" {
+		ref.Namespace = o.Namespace
+	}
+	return ref
+}
@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	UpgradeKind = "Upgrade"
+)
+
+var (
+	UpgradeGVK        = GroupVersion.WithKind(UpgradeKind)
+	UpgradeAPIVersion = GroupVersion.String()
+)
+
+// UpgradeSpec defines the desired state of Upgrade
+type UpgradeSpec struct {
+	// controllerRef is a reference to the Controller CR of the Slurm cluster
+	// to upgrade. The Accounting, NodeSets, LoginSets, and RestApis of that
+	// Controller are upgraded with it.
+	// +required
+	ControllerRef ObjectReference `json:"controllerRef"`
+
+	// The Slurm version to upgrade to (e.g. `25.11` or `25.11.1`).
+	// Upgrades may advance at most two major Slurm releases, and downgrades
+	// are refused. Create a new Upgrade for each version change.
+	// +required
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="slurmVersion is immutable"
+	// +kubebuilder:validation:Pattern=`^[0-9]{2}\.[0-9]{2}(\.[0-9]+)?$`
+	SlurmVersion string `json:"slurmVersion,omitzero"`
+
+	// Images overrides the image used for each component.
+	// When a component image is omitted, it is derived from the component's
+	// current image by replacing the Slurm version at the start of its tag
+	// with `slurmVersion` (e.g. `slurmctld:25.05-ubuntu24.04` becomes
+	// `slurmctld:25.11-ubuntu24.04`).
+	// +optional
+	Images UpgradeImages `json:"images,omitzero"`
+}
+
+// UpgradeImages are the container images to roll out for each component.
+type UpgradeImages struct {
+	// Slurmdbd is the image for the Accounting slurmdbd container.
+	// +optional
+	Slurmdbd string `json:"slurmdbd,omitempty"`
+
+	// Slurmctld is the image for the Controller slurmctld container.
+	// +optional
+	Slurmctld string `json:"slurmctld,omitempty"`
+
+	// Slurmd is the image for the NodeSet slurmd containers.
+	// +optional
+	Slurmd string `json:"slurmd,omitempty"`
+
+	// Login is the image for the LoginSet login containers.
+	// +optional
+	Login string `json:"login,omitempty"`
+
+	// Slurmrestd is the image for the RestApi slurmrestd containers.
+	// +optional
+	Slurmrestd string `json:"slurmrestd,omitempty"`
+}
+
+// UpgradePhase is the tier of the Slurm cluster being upgraded.
+// +enum
+type UpgradePhase string
+
+const (
+	// UpgradePhaseAccounting means slurmdbd is being upgraded.
+	UpgradePhaseAccounting UpgradePhase = "Accounting"
+	// UpgradePhaseController means slurmctld is being upgraded.
+	UpgradePhaseController UpgradePhase = "Controller"
+	// UpgradePhaseNodes means slurmd and the clients (login, slurmrestd) are
+	// being upgraded.
+	UpgradePhaseNodes UpgradePhase = "Nodes"
+	// UpgradePhaseComplete means every component runs the requested version.
+	UpgradePhaseComplete UpgradePhase = "Complete"
+	// UpgradePhaseFailed means the upgrade was refused or cannot proceed
+	// without user intervention.
+	UpgradePhaseFailed UpgradePhase = "Failed"
+)
+
+// UpgradeComponentStatus is the upgrade progress of one component.
+type UpgradeComponentStatus struct {
+	// Kind of the component CR (e.g. `Accounting`, `NodeSet`).
+	Kind string `json:"kind"`
+
+	// Name of the component CR.
+	Name string `json:"name"`
+
+	// Image the component is being upgraded to.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Ready indicates the component rolled out the image and is healthy.
+	// +optional
+	Ready bool `json:"ready,omitempty"`
+
+	// Message explains why the component is not ready.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+const (
+	// UpgradeProgressing indicates whether the upgrade is rolling out.
+	UpgradeProgressing = "Progressing"
+	// UpgradeSupported indicates whether the version change is supported
+	// by Slurm.
+	UpgradeSupported = "Supported"
+)
+
+// UpgradeStatus defines the observed state of Upgrade
+type UpgradeStatus struct {
+	// Phase is the tier currently being upgraded.
+	// +optional
+	Phase UpgradePhase `json:"phase,omitempty"`
+
+	// FromVersion is the Slurm version the cluster ran when the upgrade
+	// started.
+	// +optional
+	FromVersion string `json:"fromVersion,omitempty"`
+
+	// Components is the upgrade progress of each component, in upgrade order.
+	// +optional
+	Components []UpgradeComponentStatus `json:"components,omitempty"`
+
+	// Represents the latest available observations of an Upgrade's current state.
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=upg
+// +kubebuilder:printcolumn:name="FROM",type="string",JSONPath=".status.fromVersion",description="The Slurm version before the upgrade."
+// +kubebuilder:printcolumn:name="VERSION",type="string",JSONPath=".spec.slurmVersion",description="The Slurm version to upgrade to."
+// +kubebuilder:printcolumn:name="PHASE",type="string",JSONPath=".status.phase",description="The tier being upgraded."
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+
+// Upgrade is the Schema for the upgrades API
+type Upgrade struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   UpgradeSpec   `json:"spec,omitempty"`
+	Status UpgradeStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// UpgradeList contains a list of Upgrade
+type UpgradeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Upgrade `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Upgrade{}, &UpgradeList{})
+}
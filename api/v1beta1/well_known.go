@@ -30,6 +30,26 @@ const (
 	AnnotationPodDeadline = NodeSetPrefix + "pod-deadline"
 )
 
+// Well Known Annotations for Objects of type Controller
+const (
+	// AnnotationApproveConfig approves the pending configuration files of a
+	// Controller whose configStrategy is `Manual`, when set to their hash.
+	AnnotationApproveConfig = SlinkyPrefix + "approve-config"
+)
+
+// Well Known Finalizers
+const (
+	// FinalizerSlurmdb is added to the objects stored in slurmdbd (e.g.
+	// SlurmAccount, SlurmUser), which are removed from slurmdbd before the
+	// finalizer is removed.
+	FinalizerSlurmdb = SlinkyPrefix + "slurmdb"
+
+	// FinalizerSlurmdbCluster is added to the Controllers of an Accounting
+	// with ClusterRegistration enabled, which hold their deletion until the
+	// cluster record in slurmdbd is handled.
+	FinalizerSlurmdbCluster = SlinkyPrefix + "slurmdb-cluster"
+)
+
 // Well Known Annotations for Objects of type corev1.Node
 const (
 	// AnnotationNodeCordonReason indicates a custom reason for the Slurm DRAIN action taken when the Kube node on which
@@ -41,6 +41,61 @@ func (in *Accounting) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountingArchive) DeepCopyInto(out *AccountingArchive) {
+	*out = *in
+	if in.VolumeClaim != nil {
+		in, out := &in.VolumeClaim, &out.VolumeClaim
+		*out = new(v1.PersistentVolumeClaimSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountingArchive.
+func (in *AccountingArchive) DeepCopy() *AccountingArchive {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountingArchive)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountingClusterRegistration) DeepCopyInto(out *AccountingClusterRegistration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountingClusterRegistration.
+func (in *AccountingClusterRegistration) DeepCopy() *AccountingClusterRegistration {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountingClusterRegistration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountingClusterStatus) DeepCopyInto(out *AccountingClusterStatus) {
+	*out = *in
+	if in.ControllerRef != nil {
+		in, out := &in.ControllerRef, &out.ControllerRef
+		*out = new(ObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountingClusterStatus.
+func (in *AccountingClusterStatus) DeepCopy() *AccountingClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountingClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AccountingList) DeepCopyInto(out *AccountingList) {
 	*out = *in
@@ -73,6 +128,53 @@ func (in *AccountingList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountingPurge) DeepCopyInto(out *AccountingPurge) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountingPurge.
+func (in *AccountingPurge) DeepCopy() *AccountingPurge {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountingPurge)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountingRemovedCluster) DeepCopyInto(out *AccountingRemovedCluster) {
+	*out = *in
+	out.ControllerRef = in.ControllerRef
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountingRemovedCluster.
+func (in *AccountingRemovedCluster) DeepCopy() *AccountingRemovedCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountingRemovedCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountingRetention) DeepCopyInto(out *AccountingRetention) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountingRetention.
+func (in *AccountingRetention) DeepCopy() *AccountingRetention {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountingRetention)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AccountingSpec) DeepCopyInto(out *AccountingSpec) {
 	*out = *in
@@ -88,6 +190,14 @@ func (in *AccountingSpec) DeepCopyInto(out *AccountingSpec) {
 	in.Template.DeepCopyInto(&out.Template)
 	in.StorageConfig.DeepCopyInto(&out.StorageConfig)
 	in.Service.DeepCopyInto(&out.Service)
+	if in.NamespaceAccounts != nil {
+		in, out := &in.NamespaceAccounts, &out.NamespaceAccounts
+		*out = new(NamespaceAccounts)
+		(*in).DeepCopyInto(*out)
+	}
+	out.Purge = in.Purge
+	in.Archive.DeepCopyInto(&out.Archive)
+	out.ClusterRegistration = in.ClusterRegistration
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountingSpec.
@@ -103,6 +213,36 @@ func (in *AccountingSpec) DeepCopy() *AccountingSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AccountingStatus) DeepCopyInto(out *AccountingStatus) {
 	*out = *in
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = make([]AccountingRetention, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastStoragePasswordRotationTime != nil {
+		in, out := &in.LastStoragePasswordRotationTime, &out.LastStoragePasswordRotationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]AccountingClusterStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RemovedClusters != nil {
+		in, out := &in.RemovedClusters, &out.RemovedClusters
+		*out = make([]AccountingRemovedCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NamespaceAccounts != nil {
+		in, out := &in.NamespaceAccounts, &out.NamespaceAccounts
+		*out = make([]NamespaceAccountStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]metav1.Condition, len(*in))
@@ -122,6 +262,27 @@ func (in *AccountingStatus) DeepCopy() *AccountingStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigChange) DeepCopyInto(out *ConfigChange) {
+	*out = *in
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigChange.
+func (in *ConfigChange) DeepCopy() *ConfigChange {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ContainerWrapper) DeepCopyInto(out *ContainerWrapper) {
 	clone := in.DeepCopy()
@@ -155,6 +316,51 @@ func (in *Controller) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControllerCgroup) DeepCopyInto(out *ControllerCgroup) {
+	*out = *in
+	if in.ConstrainCores != nil {
+		in, out := &in.ConstrainCores, &out.ConstrainCores
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ConstrainRAMSpace != nil {
+		in, out := &in.ConstrainRAMSpace, &out.ConstrainRAMSpace
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AllowedRAMSpace != nil {
+		in, out := &in.AllowedRAMSpace, &out.AllowedRAMSpace
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ConstrainDevices != nil {
+		in, out := &in.ConstrainDevices, &out.ConstrainDevices
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ConstrainSwapSpace != nil {
+		in, out := &in.ConstrainSwapSpace, &out.ConstrainSwapSpace
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EnableControllers != nil {
+		in, out := &in.EnableControllers, &out.EnableControllers
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControllerCgroup.
+func (in *ControllerCgroup) DeepCopy() *ControllerCgroup {
+	if in == nil {
+		return nil
+	}
+	out := new(ControllerCgroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ControllerList) DeepCopyInto(out *ControllerList) {
 	*out = *in
@@ -203,6 +409,64 @@ func (in *ControllerPersistence) DeepCopy() *ControllerPersistence {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControllerPreempt) DeepCopyInto(out *ControllerPreempt) {
+	*out = *in
+	if in.Mode != nil {
+		in, out := &in.Mode, &out.Mode
+		*out = make([]PreemptMode, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControllerPreempt.
+func (in *ControllerPreempt) DeepCopy() *ControllerPreempt {
+	if in == nil {
+		return nil
+	}
+	out := new(ControllerPreempt)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControllerPriority) DeepCopyInto(out *ControllerPriority) {
+	*out = *in
+	in.Weights.DeepCopyInto(&out.Weights)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControllerPriority.
+func (in *ControllerPriority) DeepCopy() *ControllerPriority {
+	if in == nil {
+		return nil
+	}
+	out := new(ControllerPriority)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControllerScheduling) DeepCopyInto(out *ControllerScheduling) {
+	*out = *in
+	if in.SchedulerParameters != nil {
+		in, out := &in.SchedulerParameters, &out.SchedulerParameters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Priority.DeepCopyInto(&out.Priority)
+	in.Preempt.DeepCopyInto(&out.Preempt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControllerScheduling.
+func (in *ControllerScheduling) DeepCopy() *ControllerScheduling {
+	if in == nil {
+		return nil
+	}
+	out := new(ControllerScheduling)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ControllerSpec) DeepCopyInto(out *ControllerSpec) {
 	*out = *in
@@ -219,6 +483,16 @@ func (in *ControllerSpec) DeepCopyInto(out *ControllerSpec) {
 	in.Reconfigure.DeepCopyInto(&out.Reconfigure)
 	in.LogFile.DeepCopyInto(&out.LogFile)
 	in.Template.DeepCopyInto(&out.Template)
+	if in.ConfigRevision != nil {
+		in, out := &in.ConfigRevision, &out.ConfigRevision
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ConfigRevisionHistoryLimit != nil {
+		in, out := &in.ConfigRevisionHistoryLimit, &out.ConfigRevisionHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
 	if in.ConfigFileRefs != nil {
 		in, out := &in.ConfigFileRefs, &out.ConfigFileRefs
 		*out = make([]ObjectReference, len(*in))
@@ -244,6 +518,26 @@ func (in *ControllerSpec) DeepCopyInto(out *ControllerSpec) {
 		*out = make([]ObjectReference, len(*in))
 		copy(*out, *in)
 	}
+	in.Cgroup.DeepCopyInto(&out.Cgroup)
+	if in.JobSubmitScriptRef != nil {
+		in, out := &in.JobSubmitScriptRef, &out.JobSubmitScriptRef
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Scheduling.DeepCopyInto(&out.Scheduling)
+	if in.Licenses != nil {
+		in, out := &in.Licenses, &out.Licenses
+		*out = make([]License, len(*in))
+		copy(*out, *in)
+	}
+	if in.RemoteLicenses != nil {
+		in, out := &in.RemoteLicenses, &out.RemoteLicenses
+		*out = make([]RemoteLicense, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Spank.DeepCopyInto(&out.Spank)
 	in.Persistence.DeepCopyInto(&out.Persistence)
 	in.Service.DeepCopyInto(&out.Service)
 	in.Metrics.DeepCopyInto(&out.Metrics)
@@ -269,6 +563,26 @@ func (in *ControllerStatus) DeepCopyInto(out *ControllerStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LastConfigChange != nil {
+		in, out := &in.LastConfigChange, &out.LastConfigChange
+		*out = new(ConfigChange)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PendingConfig != nil {
+		in, out := &in.PendingConfig, &out.PendingConfig
+		*out = new(PendingConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CollisionCount != nil {
+		in, out := &in.CollisionCount, &out.CollisionCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Licenses != nil {
+		in, out := &in.Licenses, &out.Licenses
+		*out = make([]LicenseStatus, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControllerStatus.
@@ -312,6 +626,36 @@ func (in *JwtSecretKeySelector) DeepCopy() *JwtSecretKeySelector {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *License) DeepCopyInto(out *License) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new License.
+func (in *License) DeepCopy() *License {
+	if in == nil {
+		return nil
+	}
+	out := new(License)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LicenseStatus) DeepCopyInto(out *LicenseStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LicenseStatus.
+func (in *LicenseStatus) DeepCopy() *LicenseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LicenseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LoginSet) DeepCopyInto(out *LoginSet) {
 	*out = *in
@@ -479,6 +823,44 @@ func (in *Metrics) DeepCopy() *Metrics {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceAccountStatus) DeepCopyInto(out *NamespaceAccountStatus) {
+	*out = *in
+	if in.GrpTRES != nil {
+		in, out := &in.GrpTRES, &out.GrpTRES
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceAccountStatus.
+func (in *NamespaceAccountStatus) DeepCopy() *NamespaceAccountStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceAccountStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceAccounts) DeepCopyInto(out *NamespaceAccounts) {
+	*out = *in
+	in.NamespaceSelector.DeepCopyInto(&out.NamespaceSelector)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceAccounts.
+func (in *NamespaceAccounts) DeepCopy() *NamespaceAccounts {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceAccounts)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeSet) DeepCopyInto(out *NodeSet) {
 	*out = *in
@@ -507,36 +889,71 @@ func (in *NodeSet) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeSetList) DeepCopyInto(out *NodeSetList) {
+func (in *NodeSetGres) DeepCopyInto(out *NodeSetGres) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]NodeSet, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSetList.
-func (in *NodeSetList) DeepCopy() *NodeSetList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSetGres.
+func (in *NodeSetGres) DeepCopy() *NodeSetGres {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeSetList)
+	out := new(NodeSetGres)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *NodeSetList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeSetHealthCheck) DeepCopyInto(out *NodeSetHealthCheck) {
+	*out = *in
+	if in.ScriptRefs != nil {
+		in, out := &in.ScriptRefs, &out.ScriptRefs
+		*out = make([]ObjectReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSetHealthCheck.
+func (in *NodeSetHealthCheck) DeepCopy() *NodeSetHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeSetHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeSetList) DeepCopyInto(out *NodeSetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NodeSet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSetList.
+func (in *NodeSetList) DeepCopy() *NodeSetList {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeSetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodeSetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeSetPartition) DeepCopyInto(out *NodeSetPartition) {
@@ -568,6 +985,26 @@ func (in *NodeSetPersistentVolumeClaimRetentionPolicy) DeepCopy() *NodeSetPersis
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeSetResourceOverhead) DeepCopyInto(out *NodeSetResourceOverhead) {
+	*out = *in
+	if in.MemSpecLimit != nil {
+		in, out := &in.MemSpecLimit, &out.MemSpecLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSetResourceOverhead.
+func (in *NodeSetResourceOverhead) DeepCopy() *NodeSetResourceOverhead {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeSetResourceOverhead)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeSetSpec) DeepCopyInto(out *NodeSetSpec) {
 	*out = *in
@@ -581,7 +1018,25 @@ func (in *NodeSetSpec) DeepCopyInto(out *NodeSetSpec) {
 	in.Ssh.DeepCopyInto(&out.Ssh)
 	in.LogFile.DeepCopyInto(&out.LogFile)
 	in.Template.DeepCopyInto(&out.Template)
+	in.ResourceOverhead.DeepCopyInto(&out.ResourceOverhead)
 	out.Partition = in.Partition
+	if in.Gres != nil {
+		in, out := &in.Gres, &out.Gres
+		*out = make([]NodeSetGres, len(*in))
+		copy(*out, *in)
+	}
+	in.Spank.DeepCopyInto(&out.Spank)
+	if in.PrologScriptRefs != nil {
+		in, out := &in.PrologScriptRefs, &out.PrologScriptRefs
+		*out = make([]ObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.EpilogScriptRefs != nil {
+		in, out := &in.EpilogScriptRefs, &out.EpilogScriptRefs
+		*out = make([]ObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	in.HealthCheck.DeepCopyInto(&out.HealthCheck)
 	if in.VolumeClaimTemplates != nil {
 		in, out := &in.VolumeClaimTemplates, &out.VolumeClaimTemplates
 		*out = make([]v1.PersistentVolumeClaim, len(*in))
@@ -690,6 +1145,22 @@ func (in *ObjectReference) DeepCopy() *ObjectReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PendingConfig) DeepCopyInto(out *PendingConfig) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PendingConfig.
+func (in *PendingConfig) DeepCopy() *PendingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PendingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PodSpecWrapper) DeepCopyInto(out *PodSpecWrapper) {
 	clone := in.DeepCopy()
@@ -713,6 +1184,73 @@ func (in *PodTemplate) DeepCopy() *PodTemplate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PriorityWeights) DeepCopyInto(out *PriorityWeights) {
+	*out = *in
+	if in.Age != nil {
+		in, out := &in.Age, &out.Age
+		*out = new(int64)
+		**out = **in
+	}
+	if in.FairShare != nil {
+		in, out := &in.FairShare, &out.FairShare
+		*out = new(int64)
+		**out = **in
+	}
+	if in.JobSize != nil {
+		in, out := &in.JobSize, &out.JobSize
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Partition != nil {
+		in, out := &in.Partition, &out.Partition
+		*out = new(int64)
+		**out = **in
+	}
+	if in.QOS != nil {
+		in, out := &in.QOS, &out.QOS
+		*out = new(int64)
+		**out = **in
+	}
+	if in.TRES != nil {
+		in, out := &in.TRES, &out.TRES
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PriorityWeights.
+func (in *PriorityWeights) DeepCopy() *PriorityWeights {
+	if in == nil {
+		return nil
+	}
+	out := new(PriorityWeights)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteLicense) DeepCopyInto(out *RemoteLicense) {
+	*out = *in
+	if in.AllowedPercent != nil {
+		in, out := &in.AllowedPercent, &out.AllowedPercent
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteLicense.
+func (in *RemoteLicense) DeepCopy() *RemoteLicense {
+	if in == nil {
+		return nil
+	}
+	out := new(RemoteLicense)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RestApi) DeepCopyInto(out *RestApi) {
 	*out = *in
@@ -784,6 +1322,7 @@ func (in *RestApiSpec) DeepCopyInto(out *RestApiSpec) {
 	in.Slurmrestd.DeepCopyInto(&out.Slurmrestd)
 	in.Template.DeepCopyInto(&out.Template)
 	in.Service.DeepCopyInto(&out.Service)
+	in.TLS.DeepCopyInto(&out.TLS)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestApiSpec.
@@ -818,6 +1357,27 @@ func (in *RestApiStatus) DeepCopy() *RestApiStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RestApiTLS) DeepCopyInto(out *RestApiTLS) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	in.Proxy.DeepCopyInto(&out.Proxy)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RestApiTLS.
+func (in *RestApiTLS) DeepCopy() *RestApiTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(RestApiTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RollingUpdateNodeSetStrategy) DeepCopyInto(out *RollingUpdateNodeSetStrategy) {
 	*out = *in
@@ -883,23 +1443,123 @@ func (in *ServiceSpecWrapper) DeepCopyInto(out *ServiceSpecWrapper) {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StorageConfig) DeepCopyInto(out *StorageConfig) {
+func (in *SlurmAccount) DeepCopyInto(out *SlurmAccount) {
 	*out = *in
-	in.PasswordKeyRef.DeepCopyInto(&out.PasswordKeyRef)
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageConfig.
-func (in *StorageConfig) DeepCopy() *StorageConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlurmAccount.
+func (in *SlurmAccount) DeepCopy() *SlurmAccount {
 	if in == nil {
 		return nil
 	}
-	out := new(StorageConfig)
+	out := new(SlurmAccount)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SlurmAccount) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Token) DeepCopyInto(out *Token) {
+func (in *SlurmAccountList) DeepCopyInto(out *SlurmAccountList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SlurmAccount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlurmAccountList.
+func (in *SlurmAccountList) DeepCopy() *SlurmAccountList {
+	if in == nil {
+		return nil
+	}
+	out := new(SlurmAccountList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SlurmAccountList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlurmAccountSpec) DeepCopyInto(out *SlurmAccountSpec) {
+	*out = *in
+	out.AccountingRef = in.AccountingRef
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlurmAccountSpec.
+func (in *SlurmAccountSpec) DeepCopy() *SlurmAccountSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SlurmAccountSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlurmAccountStatus) DeepCopyInto(out *SlurmAccountStatus) {
+	*out = *in
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlurmAccountStatus.
+func (in *SlurmAccountStatus) DeepCopy() *SlurmAccountStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SlurmAccountStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlurmQOS) DeepCopyInto(out *SlurmQOS) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -907,18 +1567,18 @@ func (in *Token) DeepCopyInto(out *Token) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Token.
-func (in *Token) DeepCopy() *Token {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlurmQOS.
+func (in *SlurmQOS) DeepCopy() *SlurmQOS {
 	if in == nil {
 		return nil
 	}
-	out := new(Token)
+	out := new(SlurmQOS)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Token) DeepCopyObject() runtime.Object {
+func (in *SlurmQOS) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -926,31 +1586,31 @@ func (in *Token) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TokenList) DeepCopyInto(out *TokenList) {
+func (in *SlurmQOSList) DeepCopyInto(out *SlurmQOSList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Token, len(*in))
+		*out = make([]SlurmQOS, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenList.
-func (in *TokenList) DeepCopy() *TokenList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlurmQOSList.
+func (in *SlurmQOSList) DeepCopy() *SlurmQOSList {
 	if in == nil {
 		return nil
 	}
-	out := new(TokenList)
+	out := new(SlurmQOSList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *TokenList) DeepCopyObject() runtime.Object {
+func (in *SlurmQOSList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -958,36 +1618,65 @@ func (in *TokenList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TokenSpec) DeepCopyInto(out *TokenSpec) {
+func (in *SlurmQOSSpec) DeepCopyInto(out *SlurmQOSSpec) {
 	*out = *in
-	in.JwtHs256KeyRef.DeepCopyInto(&out.JwtHs256KeyRef)
-	if in.Lifetime != nil {
-		in, out := &in.Lifetime, &out.Lifetime
+	out.AccountingRef = in.AccountingRef
+	if in.Preempt != nil {
+		in, out := &in.Preempt, &out.Preempt
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.GrpTRES != nil {
+		in, out := &in.GrpTRES, &out.GrpTRES
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.MaxTRESPerUser != nil {
+		in, out := &in.MaxTRESPerUser, &out.MaxTRESPerUser
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.MaxWall != nil {
+		in, out := &in.MaxWall, &out.MaxWall
 		*out = new(metav1.Duration)
 		**out = **in
 	}
-	if in.SecretRef != nil {
-		in, out := &in.SecretRef, &out.SecretRef
-		*out = new(v1.SecretKeySelector)
-		(*in).DeepCopyInto(*out)
+	if in.Flags != nil {
+		in, out := &in.Flags, &out.Flags
+		*out = make([]SlurmQOSFlag, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenSpec.
-func (in *TokenSpec) DeepCopy() *TokenSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlurmQOSSpec.
+func (in *SlurmQOSSpec) DeepCopy() *SlurmQOSSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(TokenSpec)
+	out := new(SlurmQOSSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TokenStatus) DeepCopyInto(out *TokenStatus) {
+func (in *SlurmQOSStatus) DeepCopyInto(out *SlurmQOSStatus) {
 	*out = *in
-	if in.IssuedAt != nil {
-		in, out := &in.IssuedAt, &out.IssuedAt
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UnmanagedQOS != nil {
+		in, out := &in.UnmanagedQOS, &out.UnmanagedQOS
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
 		*out = (*in).DeepCopy()
 	}
 	if in.Conditions != nil {
@@ -999,7 +1688,338 @@ func (in *TokenStatus) DeepCopyInto(out *TokenStatus) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenStatus.
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlurmQOSStatus.
+func (in *SlurmQOSStatus) DeepCopy() *SlurmQOSStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SlurmQOSStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlurmUser) DeepCopyInto(out *SlurmUser) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlurmUser.
+func (in *SlurmUser) DeepCopy() *SlurmUser {
+	if in == nil {
+		return nil
+	}
+	out := new(SlurmUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SlurmUser) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlurmUserAssociation) DeepCopyInto(out *SlurmUserAssociation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlurmUserAssociation.
+func (in *SlurmUserAssociation) DeepCopy() *SlurmUserAssociation {
+	if in == nil {
+		return nil
+	}
+	out := new(SlurmUserAssociation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlurmUserList) DeepCopyInto(out *SlurmUserList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SlurmUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlurmUserList.
+func (in *SlurmUserList) DeepCopy() *SlurmUserList {
+	if in == nil {
+		return nil
+	}
+	out := new(SlurmUserList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SlurmUserList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlurmUserSpec) DeepCopyInto(out *SlurmUserSpec) {
+	*out = *in
+	out.AccountingRef = in.AccountingRef
+	if in.Associations != nil {
+		in, out := &in.Associations, &out.Associations
+		*out = make([]SlurmUserAssociation, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlurmUserSpec.
+func (in *SlurmUserSpec) DeepCopy() *SlurmUserSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SlurmUserSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SlurmUserStatus) DeepCopyInto(out *SlurmUserStatus) {
+	*out = *in
+	if in.Associations != nil {
+		in, out := &in.Associations, &out.Associations
+		*out = make([]SlurmUserAssociation, len(*in))
+		copy(*out, *in)
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SlurmUserStatus.
+func (in *SlurmUserStatus) DeepCopy() *SlurmUserStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SlurmUserStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Spank) DeepCopyInto(out *Spank) {
+	*out = *in
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = make([]SpankPlugin, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Spank.
+func (in *Spank) DeepCopy() *Spank {
+	if in == nil {
+		return nil
+	}
+	out := new(Spank)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpankPlugin) DeepCopyInto(out *SpankPlugin) {
+	*out = *in
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpankPlugin.
+func (in *SpankPlugin) DeepCopy() *SpankPlugin {
+	if in == nil {
+		return nil
+	}
+	out := new(SpankPlugin)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageConfig) DeepCopyInto(out *StorageConfig) {
+	*out = *in
+	in.PasswordKeyRef.DeepCopyInto(&out.PasswordKeyRef)
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(StorageTLS)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageConfig.
+func (in *StorageConfig) DeepCopy() *StorageConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageTLS) DeepCopyInto(out *StorageTLS) {
+	*out = *in
+	in.CaKeyRef.DeepCopyInto(&out.CaKeyRef)
+	if in.CertKeyRef != nil {
+		in, out := &in.CertKeyRef, &out.CertKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KeyKeyRef != nil {
+		in, out := &in.KeyKeyRef, &out.KeyKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageTLS.
+func (in *StorageTLS) DeepCopy() *StorageTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Token) DeepCopyInto(out *Token) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Token.
+func (in *Token) DeepCopy() *Token {
+	if in == nil {
+		return nil
+	}
+	out := new(Token)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Token) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TokenList) DeepCopyInto(out *TokenList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Token, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenList.
+func (in *TokenList) DeepCopy() *TokenList {
+	if in == nil {
+		return nil
+	}
+	out := new(TokenList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TokenList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TokenSpec) DeepCopyInto(out *TokenSpec) {
+	*out = *in
+	in.JwtHs256KeyRef.DeepCopyInto(&out.JwtHs256KeyRef)
+	if in.Lifetime != nil {
+		in, out := &in.Lifetime, &out.Lifetime
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenSpec.
+func (in *TokenSpec) DeepCopy() *TokenSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TokenSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TokenStatus) DeepCopyInto(out *TokenStatus) {
+	*out = *in
+	if in.IssuedAt != nil {
+		in, out := &in.IssuedAt, &out.IssuedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenStatus.
 func (in *TokenStatus) DeepCopy() *TokenStatus {
 	if in == nil {
 		return nil
@@ -1008,3 +2028,136 @@ func (in *TokenStatus) DeepCopy() *TokenStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Upgrade) DeepCopyInto(out *Upgrade) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Upgrade.
+func (in *Upgrade) DeepCopy() *Upgrade {
+	if in == nil {
+		return nil
+	}
+	out := new(Upgrade)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Upgrade) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeComponentStatus) DeepCopyInto(out *UpgradeComponentStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeComponentStatus.
+func (in *UpgradeComponentStatus) DeepCopy() *UpgradeComponentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeComponentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeImages) DeepCopyInto(out *UpgradeImages) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeImages.
+func (in *UpgradeImages) DeepCopy() *UpgradeImages {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeImages)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeList) DeepCopyInto(out *UpgradeList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Upgrade, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeList.
+func (in *UpgradeList) DeepCopy() *UpgradeList {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *UpgradeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeSpec) DeepCopyInto(out *UpgradeSpec) {
+	*out = *in
+	out.ControllerRef = in.ControllerRef
+	out.Images = in.Images
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeSpec.
+func (in *UpgradeSpec) DeepCopy() *UpgradeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeStatus) DeepCopyInto(out *UpgradeStatus) {
+	*out = *in
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]UpgradeComponentStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeStatus.
+func (in *UpgradeStatus) DeepCopy() *UpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -30,7 +30,9 @@ import (
 	"github.com/SlinkyProject/slurm-operator/internal/controller/nodeset"
 	"github.com/SlinkyProject/slurm-operator/internal/controller/restapi"
 	"github.com/SlinkyProject/slurm-operator/internal/controller/slurmclient"
+	"github.com/SlinkyProject/slurm-operator/internal/controller/slurmdb"
 	"github.com/SlinkyProject/slurm-operator/internal/controller/token"
+	"github.com/SlinkyProject/slurm-operator/internal/controller/upgrade"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -142,7 +144,7 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "Restapi")
 		os.Exit(1)
 	}
-	if err := accounting.NewReconciler(mgr.GetClient()).SetupWithManager(mgr); err != nil {
+	if err := accounting.NewReconciler(mgr.GetClient(), clientMap).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Accounting")
 		os.Exit(1)
 	}
@@ -162,6 +164,26 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "Token")
 		os.Exit(1)
 	}
+	if err := upgrade.NewReconciler(mgr.GetClient(), clientMap).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "Upgrade")
+		os.Exit(1)
+	}
+	if err := slurmdb.NewSlurmAccountReconciler(mgr.GetClient(), clientMap).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SlurmAccount")
+		os.Exit(1)
+	}
+	if err := slurmdb.NewSlurmUserReconciler(mgr.GetClient(), clientMap).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SlurmUser")
+		os.Exit(1)
+	}
+	if err := slurmdb.NewSlurmQOSReconciler(mgr.GetClient(), clientMap).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "SlurmQOS")
+		os.Exit(1)
+	}
+	if err := slurmdb.NewNamespaceAccountReconciler(mgr.GetClient(), clientMap).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "NamespaceAccount")
+		os.Exit(1)
+	}
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
@@ -105,3 +105,10 @@ Worker epilog slurmctld scripts.
 {{- define "slurm.controller.epilogSlurmctldName" -}}
 {{- printf "%s-epilog-slurmctld-scripts" (include "slurm.fullname" .) -}}
 {{- end }}
+
+{{/*
+Controller job submit script.
+*/}}
+{{- define "slurm.controller.jobSubmitName" -}}
+{{- printf "%s-job-submit" (include "slurm.fullname" .) -}}
+{{- end }}
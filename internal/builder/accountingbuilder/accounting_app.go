@@ -12,6 +12,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -28,6 +29,11 @@ func (b *AccountingBuilder) BuildAccounting(accounting *slinkyv1beta1.Accounting
 	key := accounting.Key()
 	serviceKey := accounting.ServiceKey()
 
+	storagePassHash, err := b.getStoragePassHash(context.TODO(), accounting)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get storage password: %w", err)
+	}
+
 	selectorLabels := labels.NewBuilder().
 		WithAccountingSelectorLabels(accounting).
 		Build()
@@ -36,6 +42,9 @@ func (b *AccountingBuilder) BuildAccounting(accounting *slinkyv1beta1.Accounting
 		WithLabels(accounting.Labels).
 		WithMetadata(accounting.Spec.Template.Metadata).
 		WithLabels(labels.NewBuilder().WithAccountingLabels(accounting).Build()).
+		WithAnnotations(map[string]string{
+			common.AnnotationStoragePassHash: storagePassHash,
+		}).
 		Build()
 
 	podTemplate, err := b.accountingPodTemplate(accounting)
@@ -47,7 +56,7 @@ func (b *AccountingBuilder) BuildAccounting(accounting *slinkyv1beta1.Accounting
 		ObjectMeta: objectMeta,
 		Spec: appsv1.StatefulSetSpec{
 			PodManagementPolicy:  appsv1.ParallelPodManagement,
-			Replicas:             ptr.To[int32](1),
+			Replicas:             ptr.To(accounting.Replicas()),
 			RevisionHistoryLimit: ptr.To[int32](0),
 			Selector: &metav1.LabelSelector{
 				MatchLabels: selectorLabels,
@@ -95,7 +104,7 @@ func (b *AccountingBuilder) accountingPodTemplate(accounting *slinkyv1beta1.Acco
 		Base: corev1.PodSpec{
 			AutomountServiceAccountToken: ptr.To(false),
 			Containers: []corev1.Container{
-				b.slurmdbdContainer(spec.Slurmdbd.Container),
+				b.slurmdbdContainer(accounting, spec.Slurmdbd.Container),
 			},
 			SecurityContext: &corev1.PodSecurityContext{
 				RunAsNonRoot: ptr.To(true),
@@ -108,9 +117,32 @@ func (b *AccountingBuilder) accountingPodTemplate(accounting *slinkyv1beta1.Acco
 		Merge: template.PodSpec,
 	}
 
+	if spec.HighAvailability {
+		opts.Base.Affinity = accountingAntiAffinity(accounting)
+	}
+
 	return b.CommonBuilder.BuildPodTemplate(opts), nil
 }
 
+// accountingAntiAffinity spreads the primary and backup slurmdbd across nodes.
+func accountingAntiAffinity(accounting *slinkyv1beta1.Accounting) *corev1.Affinity {
+	return &corev1.Affinity{
+		PodAntiAffinity: &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+				{
+					Weight: 100,
+					PodAffinityTerm: corev1.PodAffinityTerm{
+						LabelSelector: &metav1.LabelSelector{
+							MatchLabels: labels.NewBuilder().WithAccountingSelectorLabels(accounting).Build(),
+						},
+						TopologyKey: corev1.LabelHostname,
+					},
+				},
+			},
+		},
+	}
+}
+
 func accountingVolumes(accounting *slinkyv1beta1.Accounting) []corev1.Volume {
 	out := []corev1.Volume{
 		{
@@ -164,10 +196,59 @@ func accountingVolumes(accounting *slinkyv1beta1.Accounting) []corev1.Volume {
 		out[0].Projected.Sources = append(out[0].Projected.Sources, volumeProjection)
 	}
 
+	if accounting.StorageTLSEnabled() {
+		tls := accounting.Spec.StorageConfig.TLS
+		out[0].Projected.Sources = append(out[0].Projected.Sources,
+			storageSecretProjection(&tls.CaKeyRef, common.StorageCaFile))
+		if tls.CertKeyRef != nil {
+			out[0].Projected.Sources = append(out[0].Projected.Sources,
+				storageSecretProjection(tls.CertKeyRef, common.StorageCertFile))
+		}
+		if tls.KeyKeyRef != nil {
+			out[0].Projected.Sources = append(out[0].Projected.Sources,
+				storageSecretProjection(tls.KeyKeyRef, common.StorageKeyFile))
+		}
+	}
+
+	if claimName := accounting.ArchiveClaimName(); claimName != "" {
+		volume := corev1.Volume{
+			Name: common.SlurmdbdArchiveVolume,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: claimName,
+				},
+			},
+		}
+		out = append(out, volume)
+	}
+
 	return out
 }
 
-func (b *AccountingBuilder) slurmdbdContainer(merge corev1.Container) corev1.Container {
+func storageSecretProjection(ref *corev1.SecretKeySelector, path string) corev1.VolumeProjection {
+	return corev1.VolumeProjection{
+		Secret: &corev1.SecretProjection{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: ref.Name,
+			},
+			Items: []corev1.KeyToPath{
+				{Key: ref.Key, Path: path},
+			},
+		},
+	}
+}
+
+func (b *AccountingBuilder) slurmdbdContainer(accounting *slinkyv1beta1.Accounting, merge corev1.Container) corev1.Container {
+	volumeMounts := []corev1.VolumeMount{
+		{Name: common.SlurmEtcVolume, MountPath: common.SlurmEtcDir, ReadOnly: true},
+		{Name: common.SlurmPidFileVolume, MountPath: common.SlurmPidFileDir},
+	}
+	if accounting.ArchiveClaimName() != "" {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name: common.SlurmdbdArchiveVolume, MountPath: common.SlurmdbdArchiveDir,
+		})
+	}
+
 	opts := common.ContainerOpts{
 		Base: corev1.Container{
 			Name: labels.AccountingApp,
@@ -190,10 +271,7 @@ func (b *AccountingBuilder) slurmdbdContainer(merge corev1.Container) corev1.Con
 				RunAsUser:    ptr.To(common.SlurmUserUid),
 				RunAsGroup:   ptr.To(common.SlurmUserGid),
 			},
-			VolumeMounts: []corev1.VolumeMount{
-				{Name: common.SlurmEtcVolume, MountPath: common.SlurmEtcDir, ReadOnly: true},
-				{Name: common.SlurmPidFileVolume, MountPath: common.SlurmPidFileDir},
-			},
+			VolumeMounts: volumeMounts,
 		},
 		Merge: merge,
 	}
@@ -203,30 +281,85 @@ func (b *AccountingBuilder) slurmdbdContainer(merge corev1.Container) corev1.Con
 
 const (
 	annotationSlurmdbdConfHash = slinkyv1beta1.SlinkyPrefix + "slurmdbd-conf-hash"
+	annotationStorageTLSHash   = slinkyv1beta1.SlinkyPrefix + "storage-tls-hash"
 )
 
+// getStoragePass returns the password of the database user.
+func (b *AccountingBuilder) getStoragePass(ctx context.Context, accounting *slinkyv1beta1.Accounting) ([]byte, error) {
+	storagePass := &corev1.Secret{}
+	storagePassKey := accounting.AuthStorageKey()
+	if err := b.client.Get(ctx, storagePassKey, storagePass); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+	}
+	return storagePass.Data[accounting.AuthStorageRef().Key], nil
+}
+
+// getStoragePassHash returns the hash of the password of the database user,
+// which is recorded on the StatefulSet to detect password rotations.
+func (b *AccountingBuilder) getStoragePassHash(ctx context.Context, accounting *slinkyv1beta1.Accounting) (string, error) {
+	pass, err := b.getStoragePass(ctx, accounting)
+	if err != nil {
+		return "", err
+	}
+	if len(pass) == 0 {
+		return "", nil
+	}
+	return crypto.CheckSum(pass), nil
+}
+
 func (b *AccountingBuilder) getAccountingHashes(ctx context.Context, accounting *slinkyv1beta1.Accounting) (map[string]string, error) {
 	hashMap, err := b.getAuthHashesFromAccounting(ctx, accounting)
 	if err != nil {
 		return nil, err
 	}
 
-	dbdConfig := &corev1.Secret{}
-	dbdConfigKey := accounting.ConfigKey()
-	if err := b.client.Get(ctx, dbdConfigKey, dbdConfig); err != nil {
-		if !apierrors.IsNotFound(err) {
-			return nil, err
-		}
+	pass, err := b.getStoragePass(ctx, accounting)
+	if err != nil {
+		return nil, err
 	}
-	slurmdbdConfHash := crypto.CheckSumFromMap(dbdConfig.Data)
+
+	// NOTE: hash the rendered config instead of the config secret, which may
+	// not be updated in the cache yet, so that a rotated password rolls out
+	// slurmdbd only once. The hash is the same as the one of the config
+	// secret, so existing slurmdbd pods are not restarted.
+	slurmdbdConfHash := crypto.CheckSumFromMap(map[string]string{
+		common.SlurmdbdConfFile: buildSlurmdbdConf(accounting, string(pass)),
+	})
 
 	hashMap = structutils.MergeMaps(hashMap, map[string]string{
 		annotationSlurmdbdConfHash: slurmdbdConfHash,
 	})
 
+	if accounting.StorageTLSEnabled() {
+		storageTLSHash, err := b.getStorageTLSHash(ctx, accounting)
+		if err != nil {
+			return nil, err
+		}
+		hashMap[annotationStorageTLSHash] = storageTLSHash
+	}
+
 	return hashMap, nil
 }
 
+// getStorageTLSHash returns a checksum of the TLS certificates, so that
+// slurmdbd is restarted when they rotate.
+func (b *AccountingBuilder) getStorageTLSHash(ctx context.Context, accounting *slinkyv1beta1.Accounting) (string, error) {
+	data := make(map[string][]byte)
+	for _, ref := range accounting.StorageTLSRefs() {
+		secret := &corev1.Secret{}
+		secretKey := types.NamespacedName{Namespace: accounting.Namespace, Name: ref.Name}
+		if err := b.client.Get(ctx, secretKey, secret); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return "", err
+			}
+		}
+		data[ref.Name+"/"+ref.Key] = secret.Data[ref.Key]
+	}
+	return crypto.CheckSumFromMap(data), nil
+}
+
 func (b *AccountingBuilder) getAuthHashesFromAccounting(ctx context.Context, accounting *slinkyv1beta1.Accounting) (map[string]string, error) {
 	authSlurm := &corev1.Secret{}
 	authSlurmKey := accounting.AuthSlurmKey()
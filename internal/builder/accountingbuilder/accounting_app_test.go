@@ -10,6 +10,9 @@ import (
 	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
 	common "github.com/SlinkyProject/slurm-operator/internal/builder/common"
 	"github.com/SlinkyProject/slurm-operator/internal/builder/labels"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/crypto"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 	"k8s.io/utils/set"
@@ -87,3 +90,104 @@ func TestBuilder_BuildAccounting(t *testing.T) {
 		})
 	}
 }
+
+func TestBuilder_BuildAccounting_storagePassHash(t *testing.T) {
+	accounting := &slinkyv1beta1.Accounting{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "slurm",
+		},
+		Spec: slinkyv1beta1.AccountingSpec{
+			StorageConfig: slinkyv1beta1.StorageConfig{
+				PasswordKeyRef: corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: "mariadb",
+					},
+					Key: "password",
+				},
+			},
+		},
+	}
+	newSecret := func(password string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "mariadb",
+			},
+			Data: map[string][]byte{
+				"password": []byte(password),
+			},
+		}
+	}
+	build := func(password string) *appsv1.StatefulSet {
+		b := New(fake.NewFakeClient(newSecret(password)))
+		got, err := b.BuildAccounting(accounting)
+		if err != nil {
+			t.Fatalf("Builder.BuildAccounting() error = %v", err)
+		}
+		return got
+	}
+
+	old := build("foo")
+	cur := build("bar")
+	if key := common.AnnotationStoragePassHash; old.Annotations[key] == "" || old.Annotations[key] == cur.Annotations[key] {
+		t.Errorf("Annotations[%s] = %v, want changed from %v", key, cur.Annotations[key], old.Annotations[key])
+	}
+	if _, ok := cur.Spec.Template.Annotations[common.AnnotationStoragePassHash]; ok {
+		t.Errorf("Template.Annotations[%s] is set, want it only on the StatefulSet", common.AnnotationStoragePassHash)
+	}
+	if key := annotationSlurmdbdConfHash; old.Spec.Template.Annotations[key] == "" || old.Spec.Template.Annotations[key] == cur.Spec.Template.Annotations[key] {
+		t.Errorf("Template.Annotations[%s] = %v, want changed from %v", key, cur.Spec.Template.Annotations[key], old.Spec.Template.Annotations[key])
+	}
+
+	// The config hash must match the hash of the config secret, as it was
+	// computed before, so that upgrades do not restart slurmdbd.
+	b := New(fake.NewFakeClient(newSecret("bar")))
+	config, err := b.BuildAccountingConfig(accounting)
+	if err != nil {
+		t.Fatalf("Builder.BuildAccountingConfig() error = %v", err)
+	}
+	if got, want := cur.Spec.Template.Annotations[annotationSlurmdbdConfHash], crypto.CheckSumFromMap(config.StringData); got != want {
+		t.Errorf("Template.Annotations[%s] = %v, want %v", annotationSlurmdbdConfHash, got, want)
+	}
+}
+
+func TestBuilder_BuildAccounting_highAvailability(t *testing.T) {
+	accounting := &slinkyv1beta1.Accounting{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "slurm",
+		},
+	}
+
+	b := New(fake.NewFakeClient())
+	got, err := b.BuildAccounting(accounting)
+	if err != nil {
+		t.Fatalf("Builder.BuildAccounting() error = %v", err)
+	}
+	if ptr.Deref(got.Spec.Replicas, 0) != 1 {
+		t.Errorf("Replicas = %v, want = %v", ptr.Deref(got.Spec.Replicas, 0), 1)
+	}
+	if got.Spec.Template.Spec.Affinity != nil {
+		t.Errorf("Affinity = %v, want = nil", got.Spec.Template.Spec.Affinity)
+	}
+
+	accounting.Spec.HighAvailability = true
+	got, err = b.BuildAccounting(accounting)
+	if err != nil {
+		t.Fatalf("Builder.BuildAccounting() error = %v", err)
+	}
+	if ptr.Deref(got.Spec.Replicas, 0) != 2 {
+		t.Errorf("Replicas = %v, want = %v", ptr.Deref(got.Spec.Replicas, 0), 2)
+	}
+	affinity := got.Spec.Template.Spec.Affinity
+	if affinity == nil || affinity.PodAntiAffinity == nil ||
+		len(affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution) != 1 {
+		t.Fatalf("Affinity = %v, want pod anti-affinity", affinity)
+	}
+	term := affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution[0].PodAffinityTerm
+	if term.TopologyKey != corev1.LabelHostname {
+		t.Errorf("TopologyKey = %v, want = %v", term.TopologyKey, corev1.LabelHostname)
+	}
+	if !set.KeySet(got.Spec.Template.Labels).HasAll(set.KeySet(term.LabelSelector.MatchLabels).UnsortedList()...) {
+		t.Errorf("Template.Labels = %v , LabelSelector.MatchLabels = %v",
+			got.Spec.Template.Labels, term.LabelSelector.MatchLabels)
+	}
+}
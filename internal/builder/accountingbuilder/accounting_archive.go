@@ -0,0 +1,36 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package accountingbuilder
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder/labels"
+	"github.com/SlinkyProject/slurm-operator/internal/builder/metadata"
+)
+
+// BuildAccountingArchiveClaim builds the `PersistentVolumeClaim` mounted at
+// ArchiveDir. It has no owner so that archives outlive the Accounting.
+func (b *AccountingBuilder) BuildAccountingArchiveClaim(accounting *slinkyv1beta1.Accounting) (*corev1.PersistentVolumeClaim, error) {
+	spec := accounting.Spec.Archive.VolumeClaim
+	if spec == nil {
+		return nil, fmt.Errorf("failed to specify an archive volume claim")
+	}
+
+	objectMeta := metadata.NewBuilder(accounting.ArchiveKey()).
+		WithAnnotations(accounting.Annotations).
+		WithLabels(accounting.Labels).
+		WithLabels(labels.NewBuilder().WithAccountingLabels(accounting).Build()).
+		Build()
+
+	o := &corev1.PersistentVolumeClaim{
+		ObjectMeta: objectMeta,
+		Spec:       *spec.DeepCopy(),
+	}
+
+	return o, nil
+}
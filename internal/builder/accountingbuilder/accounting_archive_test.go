@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package accountingbuilder
+
+import (
+	"testing"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	common "github.com/SlinkyProject/slurm-operator/internal/builder/common"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestBuilder_BuildAccountingArchiveClaim(t *testing.T) {
+	tests := []struct {
+		name       string
+		accounting *slinkyv1beta1.Accounting
+		wantErr    bool
+	}{
+		{
+			name: "volumeClaim",
+			accounting: &slinkyv1beta1.Accounting{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "slurm",
+				},
+				Spec: slinkyv1beta1.AccountingSpec{
+					Archive: slinkyv1beta1.AccountingArchive{
+						Jobs: true,
+						VolumeClaim: &corev1.PersistentVolumeClaimSpec{
+							AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+							Resources: corev1.VolumeResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceStorage: resource.MustParse("10Gi"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "existingClaim",
+			accounting: &slinkyv1beta1.Accounting{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "slurm",
+				},
+				Spec: slinkyv1beta1.AccountingSpec{
+					Archive: slinkyv1beta1.AccountingArchive{
+						Jobs:          true,
+						ExistingClaim: "archive",
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(fake.NewFakeClient())
+			got, err := b.BuildAccountingArchiveClaim(tt.accounting)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Builder.BuildAccountingArchiveClaim() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			switch {
+			case got.Name != tt.accounting.ArchiveClaimName():
+				t.Errorf("Name = %v, ArchiveClaimName() = %v", got.Name, tt.accounting.ArchiveClaimName())
+
+			case len(got.OwnerReferences) != 0:
+				t.Errorf("OwnerReferences = %v, want none", got.OwnerReferences)
+			}
+
+			sts, err := b.BuildAccounting(tt.accounting)
+			if err != nil {
+				t.Fatalf("Builder.BuildAccounting() error = %v", err)
+			}
+			found := false
+			for _, volume := range sts.Spec.Template.Spec.Volumes {
+				if volume.Name == common.SlurmdbdArchiveVolume && volume.PersistentVolumeClaim.ClaimName == got.Name {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("Volumes = %v, want claim %v", sts.Spec.Template.Spec.Volumes, got.Name)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ package accountingbuilder
 
 import (
 	"context"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -48,6 +49,9 @@ func buildSlurmdbdConf(accounting *slinkyv1beta1.Accounting, storagePass string)
 	conf.AddProperty(config.NewPropertyRaw("#"))
 	conf.AddProperty(config.NewPropertyRaw("### GENERAL ###"))
 	conf.AddProperty(config.NewProperty("DbdHost", dbdHost))
+	if accounting.Spec.HighAvailability {
+		conf.AddProperty(config.NewProperty("DbdBackupHost", accounting.BackupName()))
+	}
 	conf.AddProperty(config.NewProperty("DbdPort", common.SlurmdbdPort))
 	conf.AddProperty(config.NewProperty("SlurmUser", common.SlurmUser))
 
@@ -72,12 +76,67 @@ func buildSlurmdbdConf(accounting *slinkyv1beta1.Accounting, storagePass string)
 	conf.AddProperty(config.NewProperty("StorageUser", storageUser))
 	conf.AddProperty(config.NewProperty("StorageLoc", storageLoc))
 	conf.AddProperty(config.NewProperty("StoragePass", storagePass))
+	if accounting.StorageTLSEnabled() {
+		conf.AddProperty(config.NewProperty("StorageParameters", storageParameters(accounting)))
+	}
 
 	conf.AddProperty(config.NewPropertyRaw("#"))
 	conf.AddProperty(config.NewPropertyRaw("### LOGGING ###"))
 	conf.AddProperty(config.NewProperty("LogFile", common.DevNull))
 	conf.AddProperty(config.NewProperty("LogTimeFormat", common.LogTimeFormat))
 
+	// The section is omitted when empty, so the rendered config, and the
+	// hash that restarts slurmdbd, do not change for existing Accountings.
+	purge := accounting.Spec.Purge
+	archive := accounting.Spec.Archive
+	type setting struct {
+		key string
+		val any
+	}
+	archivePurge := []setting{}
+	if accounting.ArchiveClaimName() != "" {
+		archivePurge = append(archivePurge, setting{"ArchiveDir", common.SlurmdbdArchiveDir})
+	}
+	for _, item := range []struct {
+		name    string
+		enabled bool
+	}{
+		{"ArchiveEvents", archive.Events},
+		{"ArchiveJobs", archive.Jobs},
+		{"ArchiveResvs", archive.Resvs},
+		{"ArchiveSteps", archive.Steps},
+		{"ArchiveSuspend", archive.Suspend},
+		{"ArchiveTXN", archive.TXN},
+		{"ArchiveUsage", archive.Usage},
+	} {
+		if item.enabled {
+			archivePurge = append(archivePurge, setting{item.name, "yes"})
+		}
+	}
+	for _, item := range []struct {
+		name  string
+		after string
+	}{
+		{"PurgeEventAfter", purge.EventAfter},
+		{"PurgeJobAfter", purge.JobAfter},
+		{"PurgeResvAfter", purge.ResvAfter},
+		{"PurgeStepAfter", purge.StepAfter},
+		{"PurgeSuspendAfter", purge.SuspendAfter},
+		{"PurgeTXNAfter", purge.TXNAfter},
+		{"PurgeUsageAfter", purge.UsageAfter},
+	} {
+		if item.after != "" {
+			archivePurge = append(archivePurge, setting{item.name, item.after})
+		}
+	}
+	if len(archivePurge) > 0 {
+		conf.AddProperty(config.NewPropertyRaw("#"))
+		conf.AddProperty(config.NewPropertyRaw("### ARCHIVE & PURGE ###"))
+		for _, item := range archivePurge {
+			conf.AddProperty(config.NewProperty(item.key, item.val))
+		}
+	}
+
 	extraConf := accounting.Spec.ExtraConf
 	conf.AddProperty(config.NewPropertyRaw("#"))
 	conf.AddProperty(config.NewPropertyRaw("### EXTRA CONFIG ###"))
@@ -85,3 +144,19 @@ func buildSlurmdbdConf(accounting *slinkyv1beta1.Accounting, storagePass string)
 
 	return conf.Build()
 }
+
+// storageParameters returns the TLS options of the database connection.
+// Ref: https://slurm.schedmd.com/slurmdbd.conf.html#OPT_StorageParameters
+func storageParameters(accounting *slinkyv1beta1.Accounting) string {
+	tls := accounting.Spec.StorageConfig.TLS
+	params := []string{
+		"SSL_CA=" + common.StorageCaPath,
+	}
+	if tls.CertKeyRef != nil {
+		params = append(params, "SSL_CERT="+common.StorageCertPath)
+	}
+	if tls.KeyKeyRef != nil {
+		params = append(params, "SSL_KEY="+common.StorageKeyPath)
+	}
+	return strings.Join(params, ",")
+}
@@ -11,6 +11,7 @@ import (
 	common "github.com/SlinkyProject/slurm-operator/internal/builder/common"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -82,3 +83,133 @@ func TestBuilder_BuildAccountingConfig(t *testing.T) {
 		})
 	}
 }
+
+func Test_buildSlurmdbdConf_archive(t *testing.T) {
+	tests := []struct {
+		name       string
+		accounting *slinkyv1beta1.Accounting
+		want       []string
+		notWant    []string
+	}{
+		{
+			name:       "default",
+			accounting: &slinkyv1beta1.Accounting{},
+			notWant:    []string{"### ARCHIVE & PURGE ###", "ArchiveDir=", "ArchiveJobs=", "PurgeJobAfter="},
+		},
+		{
+			name: "purge only",
+			accounting: &slinkyv1beta1.Accounting{
+				Spec: slinkyv1beta1.AccountingSpec{
+					Purge: slinkyv1beta1.AccountingPurge{
+						JobAfter:   "12months",
+						UsageAfter: "24months",
+					},
+				},
+			},
+			want:    []string{"### ARCHIVE & PURGE ###", "PurgeJobAfter=12months", "PurgeUsageAfter=24months"},
+			notWant: []string{"ArchiveDir=", "PurgeStepAfter="},
+		},
+		{
+			name: "archive",
+			accounting: &slinkyv1beta1.Accounting{
+				Spec: slinkyv1beta1.AccountingSpec{
+					Purge: slinkyv1beta1.AccountingPurge{
+						JobAfter: "6months",
+					},
+					Archive: slinkyv1beta1.AccountingArchive{
+						Jobs:          true,
+						ExistingClaim: "archive",
+					},
+				},
+			},
+			want:    []string{"ArchiveDir=" + common.SlurmdbdArchiveDir, "ArchiveJobs=yes", "PurgeJobAfter=6months"},
+			notWant: []string{"ArchiveSteps="},
+		},
+		{
+			name: "high availability",
+			accounting: &slinkyv1beta1.Accounting{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "slurm",
+				},
+				Spec: slinkyv1beta1.AccountingSpec{
+					HighAvailability: true,
+				},
+			},
+			want: []string{"DbdBackupHost=slurm-accounting-1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildSlurmdbdConf(tt.accounting, "")
+			for _, want := range tt.want {
+				if !strings.Contains(got, want+"\n") {
+					t.Errorf("buildSlurmdbdConf() = %v, want %v", got, want)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(got, notWant) {
+					t.Errorf("buildSlurmdbdConf() = %v, not want %v", got, notWant)
+				}
+			}
+		})
+	}
+}
+
+func Test_storageParameters(t *testing.T) {
+	secretRef := func(name, key string) corev1.SecretKeySelector {
+		return corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: name},
+			Key:                  key,
+		}
+	}
+	tests := []struct {
+		name string
+		tls  *slinkyv1beta1.StorageTLS
+		want string
+	}{
+		{
+			name: "disabled",
+			tls: &slinkyv1beta1.StorageTLS{
+				Mode:     slinkyv1beta1.StorageTLSModeDisabled,
+				CaKeyRef: secretRef("mariadb-ca", "ca.crt"),
+			},
+		},
+		{
+			name: "ca",
+			tls: &slinkyv1beta1.StorageTLS{
+				CaKeyRef: secretRef("mariadb-ca", "ca.crt"),
+			},
+			want: "StorageParameters=SSL_CA=" + common.StorageCaPath,
+		},
+		{
+			name: "client certificate",
+			tls: &slinkyv1beta1.StorageTLS{
+				Mode:       slinkyv1beta1.StorageTLSModeRequired,
+				CaKeyRef:   secretRef("mariadb-ca", "ca.crt"),
+				CertKeyRef: ptr.To(secretRef("mariadb-client", "tls.crt")),
+				KeyKeyRef:  ptr.To(secretRef("mariadb-client", "tls.key")),
+			},
+			want: "StorageParameters=SSL_CA=" + common.StorageCaPath +
+				",SSL_CERT=" + common.StorageCertPath +
+				",SSL_KEY=" + common.StorageKeyPath,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accounting := &slinkyv1beta1.Accounting{
+				Spec: slinkyv1beta1.AccountingSpec{
+					StorageConfig: slinkyv1beta1.StorageConfig{
+						TLS: tt.tls,
+					},
+				},
+			}
+			got := buildSlurmdbdConf(accounting, "")
+			switch {
+			case tt.want == "" && strings.Contains(got, "StorageParameters="):
+				t.Errorf("buildSlurmdbdConf() = %v, not want StorageParameters", got)
+			case tt.want != "" && !strings.Contains(got, tt.want+"\n"):
+				t.Errorf("buildSlurmdbdConf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
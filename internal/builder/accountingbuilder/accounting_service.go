@@ -4,7 +4,9 @@
 package accountingbuilder
 
 import (
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
@@ -27,6 +29,11 @@ func (b *AccountingBuilder) BuildAccountingService(accounting *slinkyv1beta1.Acc
 			Build(),
 	}
 
+	// The backup slurmdbd is addressed by its own service.
+	if accounting.Spec.HighAvailability {
+		opts.Selector[appsv1.StatefulSetPodNameLabel] = accounting.PrimaryName()
+	}
+
 	port := corev1.ServicePort{
 		Name:       labels.AccountingApp,
 		Protocol:   corev1.ProtocolTCP,
@@ -37,3 +44,33 @@ func (b *AccountingBuilder) BuildAccountingService(accounting *slinkyv1beta1.Acc
 
 	return b.CommonBuilder.BuildService(opts, accounting)
 }
+
+// BuildAccountingHostService builds a headless service for a single slurmdbd
+// pod, so that slurmctld can address the primary and backup slurmdbd.
+func (b *AccountingBuilder) BuildAccountingHostService(accounting *slinkyv1beta1.Accounting, podName string) (*corev1.Service, error) {
+	opts := common.ServiceOpts{
+		Key: types.NamespacedName{
+			Namespace: accounting.Namespace,
+			Name:      podName,
+		},
+		Metadata: slinkyv1beta1.Metadata{
+			Annotations: accounting.Annotations,
+			Labels:      structutils.MergeMaps(accounting.Labels, labels.NewBuilder().WithAccountingLabels(accounting).Build()),
+		},
+		Selector: structutils.MergeMaps(
+			labels.NewBuilder().WithAccountingSelectorLabels(accounting).Build(),
+			map[string]string{appsv1.StatefulSetPodNameLabel: podName},
+		),
+		Headless: true,
+	}
+
+	port := corev1.ServicePort{
+		Name:       labels.AccountingApp,
+		Protocol:   corev1.ProtocolTCP,
+		Port:       common.SlurmdbdPort,
+		TargetPort: intstr.FromString(labels.AccountingApp),
+	}
+	opts.Ports = append(opts.Ports, port)
+
+	return b.CommonBuilder.BuildService(opts, accounting)
+}
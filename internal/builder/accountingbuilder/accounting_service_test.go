@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/set"
@@ -90,3 +91,52 @@ func TestBuilder_BuildAccountingService(t *testing.T) {
 		})
 	}
 }
+
+func TestBuilder_BuildAccountingHostService(t *testing.T) {
+	accounting := &slinkyv1beta1.Accounting{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "slurm",
+		},
+		Spec: slinkyv1beta1.AccountingSpec{
+			HighAvailability: true,
+		},
+	}
+
+	b := New(fake.NewFakeClient())
+	for _, name := range []string{accounting.PrimaryName(), accounting.BackupName()} {
+		got, err := b.BuildAccountingHostService(accounting, name)
+		if err != nil {
+			t.Fatalf("Builder.BuildAccountingHostService() error = %v", err)
+		}
+		switch {
+		case got.Name != name:
+			t.Errorf("Name = %v, want = %v", got.Name, name)
+
+		case got.Spec.ClusterIP != corev1.ClusterIPNone:
+			t.Errorf("Spec.ClusterIP = %v, want = %v", got.Spec.ClusterIP, corev1.ClusterIPNone)
+
+		case got.Spec.Selector[appsv1.StatefulSetPodNameLabel] != name:
+			t.Errorf("Spec.Selector = %v, want pod %v", got.Spec.Selector, name)
+		}
+	}
+}
+
+func TestBuilder_BuildAccountingService_HighAvailability(t *testing.T) {
+	accounting := &slinkyv1beta1.Accounting{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "slurm",
+		},
+		Spec: slinkyv1beta1.AccountingSpec{
+			HighAvailability: true,
+		},
+	}
+
+	b := New(fake.NewFakeClient())
+	got, err := b.BuildAccountingService(accounting)
+	if err != nil {
+		t.Fatalf("Builder.BuildAccountingService() error = %v", err)
+	}
+	if got.Spec.Selector[appsv1.StatefulSetPodNameLabel] != accounting.PrimaryName() {
+		t.Errorf("Spec.Selector = %v, want pod %v", got.Spec.Selector, accounting.PrimaryName())
+	}
+}
@@ -76,11 +76,22 @@ const (
 	SlurmdbdPort = 6819
 
 	SlurmdbdConfFile = "slurmdbd.conf"
+
+	SlurmdbdArchiveVolume = "archive"
+	SlurmdbdArchiveDir    = "/var/spool/slurmdbd/archive"
+
+	StorageCaFile   = "storage-ca.crt"
+	StorageCaPath   = SlurmEtcDir + "/" + StorageCaFile
+	StorageCertFile = "storage-tls.crt"
+	StorageCertPath = SlurmEtcDir + "/" + StorageCertFile
+	StorageKeyFile  = "storage-tls.key"
+	StorageKeyPath  = SlurmEtcDir + "/" + StorageKeyFile
 )
 
 const (
 	AnnotationAuthSlurmKeyHash    = slinkyv1beta1.SlinkyPrefix + "slurm-key-hash"
 	AnnotationAuthJwtHs256KeyHash = slinkyv1beta1.SlinkyPrefix + "jwt-hs256-key-hash"
+	AnnotationStoragePassHash     = slinkyv1beta1.SlinkyPrefix + "storage-pass-hash"
 )
 
 const (
@@ -88,3 +99,9 @@ const (
 	AnnotationSssdConfHash    = slinkyv1beta1.SlinkyPrefix + "sssd-conf-hash"
 	AnnotationSshHostKeysHash = slinkyv1beta1.SlinkyPrefix + "ssh-host-keys-hash"
 )
+
+const (
+	AnnotationSlurmctldRestartHash = slinkyv1beta1.SlinkyPrefix + "slurmctld-restart-hash"
+	AnnotationSlurmdRestartHash    = slinkyv1beta1.SlinkyPrefix + "slurmd-restart-hash"
+	AnnotationHealthCheckHash      = slinkyv1beta1.SlinkyPrefix + "healthcheck-hash"
+)
@@ -4,6 +4,7 @@
 package controllerbuilder
 
 import (
+	"context"
 	_ "embed"
 	"fmt"
 	"path"
@@ -11,8 +12,10 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
@@ -114,13 +117,19 @@ func (b *ControllerBuilder) controllerPodTemplate(controller *slinkyv1beta1.Cont
 		extraConfigMapNames = append(extraConfigMapNames, ref.Name)
 	}
 
+	restartHash, err := b.slurmctldRestartHash(controller)
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
+
 	objectMeta := metadata.NewBuilder(key).
 		WithAnnotations(controller.Annotations).
 		WithLabels(controller.Labels).
 		WithMetadata(controller.Spec.Template.Metadata).
 		WithLabels(labels.NewBuilder().WithControllerLabels(controller).Build()).
 		WithAnnotations(map[string]string{
-			annotationDefaultContainer: labels.ControllerApp,
+			annotationDefaultContainer:            labels.ControllerApp,
+			common.AnnotationSlurmctldRestartHash: restartHash,
 		}).
 		Build()
 
@@ -138,10 +147,7 @@ func (b *ControllerBuilder) controllerPodTemplate(controller *slinkyv1beta1.Cont
 			Containers: []corev1.Container{
 				b.slurmctldContainer(spec.Slurmctld.Container, controller.ClusterName()),
 			},
-			InitContainers: []corev1.Container{
-				b.reconfigureContainer(spec.Reconfigure),
-				b.CommonBuilder.LogfileContainer(spec.LogFile, common.SlurmctldLogFilePath),
-			},
+			InitContainers: b.controllerInitContainers(controller),
 			SecurityContext: &corev1.PodSecurityContext{
 				RunAsNonRoot: ptr.To(true),
 				RunAsUser:    ptr.To(common.SlurmUserUid),
@@ -156,6 +162,20 @@ func (b *ControllerBuilder) controllerPodTemplate(controller *slinkyv1beta1.Cont
 	return b.CommonBuilder.BuildPodTemplate(opts), nil
 }
 
+// slurmctldRestartHash returns the hash of the rendered `slurm.conf` settings
+// which require slurmctld to be restarted when changed.
+func (b *ControllerBuilder) slurmctldRestartHash(controller *slinkyv1beta1.Controller) (string, error) {
+	ctx := context.TODO()
+	configMap := &corev1.ConfigMap{}
+	if err := b.client.Get(ctx, controller.ConfigKey(), configMap); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return "", fmt.Errorf("failed to get object (%s): %w", klog.KObj(configMap), err)
+		}
+	}
+	conf := configMap.Data[SlurmConfFile]
+	return RestartHash(conf, slinkyv1beta1.ConfigChangeActionRestartSlurmctld), nil
+}
+
 func controllerVolumes(controller *slinkyv1beta1.Controller, extra []string) []corev1.Volume {
 	out := []corev1.Volume{
 		{
@@ -224,6 +244,20 @@ func controllerVolumes(controller *slinkyv1beta1.Controller, extra []string) []c
 		out[0].Projected.Sources = append(out[0].Projected.Sources, volumeProjection)
 	}
 
+	jobSubmitEnabled := controller.Spec.JobSubmitScriptRef != nil
+	if jobSubmitEnabled {
+		ref := controller.JobSubmitScriptRef()
+		volumeProjection := corev1.VolumeProjection{
+			ConfigMap: &corev1.ConfigMapProjection{
+				LocalObjectReference: ref.LocalObjectReference,
+				Items: []corev1.KeyToPath{
+					{Key: ref.Key, Path: JobSubmitLuaFile},
+				},
+			},
+		}
+		out[0].Projected.Sources = append(out[0].Projected.Sources, volumeProjection)
+	}
+
 	return out
 }
 
@@ -292,6 +326,16 @@ func (b *ControllerBuilder) slurmctldContainer(merge corev1.Container, clusterNa
 //go:embed scripts/reconfigure.sh
 var reconfigureScript string
 
+func (b *ControllerBuilder) controllerInitContainers(controller *slinkyv1beta1.Controller) []corev1.Container {
+	spec := controller.Spec
+	out := []corev1.Container{}
+	if spec.ReconfigureMode != slinkyv1beta1.ReconfigureModeOperator {
+		out = append(out, b.reconfigureContainer(spec.Reconfigure))
+	}
+	out = append(out, b.CommonBuilder.LogfileContainer(spec.LogFile, common.SlurmctldLogFilePath))
+	return out
+}
+
 func (b *ControllerBuilder) reconfigureContainer(container slinkyv1beta1.ContainerWrapper) corev1.Container {
 	opts := common.ContainerOpts{
 		Base: corev1.Container{
@@ -10,6 +10,8 @@ import (
 	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
 	"github.com/SlinkyProject/slurm-operator/internal/builder/common"
 	"github.com/SlinkyProject/slurm-operator/internal/builder/labels"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 	"k8s.io/utils/set"
@@ -124,3 +126,84 @@ func TestBuilder_BuildController(t *testing.T) {
 		})
 	}
 }
+
+func TestBuilder_controllerInitContainers(t *testing.T) {
+	tests := []struct {
+		name       string
+		controller *slinkyv1beta1.Controller
+		want       []string
+	}{
+		{
+			name: "default",
+			controller: &slinkyv1beta1.Controller{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "slurm",
+				},
+			},
+			want: []string{"reconfigure", "logfile"},
+		},
+		{
+			name: "sidecar",
+			controller: &slinkyv1beta1.Controller{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "slurm",
+				},
+				Spec: slinkyv1beta1.ControllerSpec{
+					ReconfigureMode: slinkyv1beta1.ReconfigureModeSidecar,
+				},
+			},
+			want: []string{"reconfigure", "logfile"},
+		},
+		{
+			name: "operator",
+			controller: &slinkyv1beta1.Controller{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "slurm",
+				},
+				Spec: slinkyv1beta1.ControllerSpec{
+					ReconfigureMode: slinkyv1beta1.ReconfigureModeOperator,
+				},
+			},
+			want: []string{"logfile"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(fake.NewFakeClient())
+			got := []string{}
+			for _, container := range b.controllerInitContainers(tt.controller) {
+				got = append(got, container.Name)
+			}
+			if !apiequality.Semantic.DeepEqual(got, tt.want) {
+				t.Errorf("Builder.controllerInitContainers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_controllerVolumes_jobSubmit(t *testing.T) {
+	controller := &slinkyv1beta1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "slurm",
+		},
+		Spec: slinkyv1beta1.ControllerSpec{
+			JobSubmitScriptRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "job-submit"},
+				Key:                  "policy.lua",
+			},
+		},
+	}
+	got := controllerVolumes(controller, nil)
+	want := corev1.VolumeProjection{
+		ConfigMap: &corev1.ConfigMapProjection{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "job-submit"},
+			Items: []corev1.KeyToPath{
+				{Key: "policy.lua", Path: JobSubmitLuaFile},
+			},
+		},
+	}
+	sources := got[0].Projected.Sources
+	if !apiequality.Semantic.DeepEqual(sources[len(sources)-1], want) {
+		t.Errorf("controllerVolumes() Sources = %v, want %v", sources, want)
+	}
+}
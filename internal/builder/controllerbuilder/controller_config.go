@@ -5,20 +5,25 @@ package controllerbuilder
 
 import (
 	"context"
+	_ "embed"
 	"fmt"
+	"math"
 	"path"
-	"regexp"
+	"slices"
 	"sort"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"k8s.io/utils/set"
 
 	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
 	"github.com/SlinkyProject/slurm-operator/internal/builder/common"
 	"github.com/SlinkyProject/slurm-operator/internal/builder/labels"
 	"github.com/SlinkyProject/slurm-operator/internal/utils/config"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/refresolver"
 	"github.com/SlinkyProject/slurm-operator/internal/utils/structutils"
 )
 
@@ -26,8 +31,32 @@ const (
 	SlurmConfFile  = "slurm.conf"
 	CgroupConfFile = "cgroup.conf"
 	GresConfFile   = "gres.conf"
+
+	JobSubmitLuaFile = "job_submit.lua"
+
+	PlugstackConfFile = "plugstack.conf"
+	PlugstackConfDir  = "plugstack.conf.d"
+
+	// MinMaxNodeCount is the lowest `MaxNodeCount` rendered in `slurm.conf`.
+	MinMaxNodeCount = 1024
+
+	// The NodeSet scripts are mounted into the NodeSet's pods under these
+	// directories, relative to `/etc/slurm`, and run by the files below.
+	NodeSetPrologDir       = "nodeset.d/prolog.d"
+	NodeSetEpilogDir       = "nodeset.d/epilog.d"
+	NodeSetHealthCheckDir  = "nodeset.d/healthcheck.d"
+	NodeSetPrologFile      = "nodeset-prolog.sh"
+	NodeSetEpilogFile      = "nodeset-epilog.sh"
+	NodeSetHealthCheckFile = "nodeset-healthcheck.sh"
+
+	// DefaultHealthCheckInterval is the `HealthCheckInterval` used when the
+	// Controller does not set one.
+	DefaultHealthCheckInterval = 300
 )
 
+// nvidiaGpuResource is the extended resource of the NVIDIA device plugin.
+const nvidiaGpuResource corev1.ResourceName = "nvidia.com/gpu"
+
 func (b *ControllerBuilder) BuildControllerConfig(controller *slinkyv1beta1.Controller) (*corev1.ConfigMap, error) {
 	ctx := context.TODO()
 
@@ -42,6 +71,10 @@ func (b *ControllerBuilder) BuildControllerConfig(controller *slinkyv1beta1.Cont
 	if err != nil {
 		return nil, err
 	}
+	nodeCapacity, err := NodeCapacity(ctx, b.refResolver, nodesetList)
+	if err != nil {
+		return nil, err
+	}
 
 	configFilesList := &corev1.ConfigMapList{
 		Items: make([]corev1.ConfigMap, 0, len(controller.Spec.ConfigFileRefs)),
@@ -57,9 +90,10 @@ func (b *ControllerBuilder) BuildControllerConfig(controller *slinkyv1beta1.Cont
 		}
 		configFilesList.Items = append(configFilesList.Items, *cm)
 	}
-	cgroupEnabled := true
+	cgroupEnabled := controller.Spec.Cgroup.Plugin != slinkyv1beta1.CgroupPluginDisabled
 	hasCgroupConfFile := false
 	hasGresConfFile := false
+	hasPlugstackConfFile := false
 	for _, configMap := range configFilesList.Items {
 		if contents, ok := configMap.Data[CgroupConfFile]; ok {
 			hasCgroupConfFile = true
@@ -68,6 +102,9 @@ func (b *ControllerBuilder) BuildControllerConfig(controller *slinkyv1beta1.Cont
 		if _, ok := configMap.Data[GresConfFile]; ok {
 			hasGresConfFile = true
 		}
+		if _, ok := configMap.Data[PlugstackConfFile]; ok {
+			hasPlugstackConfFile = true
+		}
 	}
 
 	prologScripts := []string{}
@@ -130,6 +167,8 @@ func (b *ControllerBuilder) BuildControllerConfig(controller *slinkyv1beta1.Cont
 		epilogSlurmctldScripts = append(epilogSlurmctldScripts, filenames...)
 	}
 
+	maxNodeCount := MaxNodeCount(controller, nodeCapacity)
+
 	opts := common.ConfigMapOpts{
 		Key: controller.ConfigKey(),
 		Metadata: slinkyv1beta1.Metadata{
@@ -141,19 +180,73 @@ func (b *ControllerBuilder) BuildControllerConfig(controller *slinkyv1beta1.Cont
 				controller, accounting, nodesetList,
 				prologScripts, epilogScripts,
 				prologSlurmctldScripts, epilogSlurmctldScripts,
-				cgroupEnabled),
+				cgroupEnabled, maxNodeCount),
 		},
 	}
 	if !hasCgroupConfFile {
-		opts.Data[CgroupConfFile] = buildCgroupConf()
+		opts.Data[CgroupConfFile] = buildCgroupConf(controller)
 	}
 	if !hasGresConfFile {
-		opts.Data[GresConfFile] = buildGresConf()
+		opts.Data[GresConfFile] = buildGresConf(nodesetList, maxNodeCount)
+	}
+	if !hasPlugstackConfFile {
+		if plugstackConf := buildPlugstackConf(controller, nodesetList); plugstackConf != "" {
+			opts.Data[PlugstackConfFile] = plugstackConf
+		}
+	}
+	for _, nodeset := range nodesetList.Items {
+		if len(nodeset.Spec.Spank.Plugins) > 0 {
+			opts.Data[PlugstackNodeSetConfKey(&nodeset)] = buildSpankConf(nodeset.Spec.Spank)
+		}
+	}
+	if hasNodeSetPrologs(nodesetList) {
+		opts.Data[NodeSetPrologFile] = buildNodeSetScriptRunner(NodeSetPrologDir)
+	}
+	if hasNodeSetEpilogs(nodesetList) {
+		opts.Data[NodeSetEpilogFile] = buildNodeSetScriptRunner(NodeSetEpilogDir)
+	}
+	if healthCheckInterval(controller, nodesetList) > 0 {
+		opts.Data[NodeSetHealthCheckFile] = nodesetHealthCheckScript
 	}
 
 	return b.CommonBuilder.BuildConfigMap(opts, controller)
 }
 
+// NodeCapacity returns the number of Slurm nodes the NodeSets can scale to.
+// Each NodeSet counts the greater of its replicas and the maxReplicas of the
+// HorizontalPodAutoscalers that scale it.
+func NodeCapacity(
+	ctx context.Context,
+	refResolver *refresolver.RefResolver,
+	nodesetList *slinkyv1beta1.NodeSetList,
+) (int32, error) {
+	var capacity int32
+	for _, nodeset := range nodesetList.Items {
+		replicas := ptr.Deref(nodeset.Spec.Replicas, 0)
+		hpaList, err := refResolver.GetHorizontalPodAutoscalersForNodeSet(ctx, &nodeset)
+		if err != nil {
+			return 0, err
+		}
+		for _, hpa := range hpaList.Items {
+			replicas = max(replicas, hpa.Spec.MaxReplicas)
+		}
+		capacity += replicas
+	}
+	return capacity, nil
+}
+
+// MaxNodeCount returns the `MaxNodeCount` for the node capacity, including the
+// Controller's headroom. It is rounded up to a power of two, so that scaling
+// the NodeSets rarely changes slurm.conf, which restarts slurmctld.
+// Ref: https://slurm.schedmd.com/slurm.conf.html#OPT_MaxNodeCount
+func MaxNodeCount(controller *slinkyv1beta1.Controller, capacity int32) int32 {
+	count := int32(MinMaxNodeCount)
+	for count < capacity+controller.Spec.NodeCountHeadroom && count <= math.MaxInt32/2 {
+		count *= 2
+	}
+	return count
+}
+
 // https://slurm.schedmd.com/slurm.conf.html
 func buildSlurmConf(
 	controller *slinkyv1beta1.Controller,
@@ -162,6 +255,7 @@ func buildSlurmConf(
 	prologScripts, epilogScripts []string,
 	prologSlurmctldScripts, epilogSlurmctldScripts []string,
 	cgroupEnabled bool,
+	maxNodeCount int32,
 ) string {
 	controllerHost := fmt.Sprintf("%s(%s)", controller.PrimaryName(), controller.ServiceFQDNShort())
 
@@ -178,8 +272,8 @@ func buildSlurmConf(
 	conf.AddProperty(config.NewProperty("SlurmdPort", common.SlurmdPort))
 	conf.AddProperty(config.NewProperty("SlurmdSpoolDir", common.SlurmdSpoolDir))
 	conf.AddProperty(config.NewProperty("ReturnToService", 2))
-	conf.AddProperty(config.NewProperty("MaxNodeCount", 1024))
-	conf.AddProperty(config.NewProperty("GresTypes", "gpu"))
+	conf.AddProperty(config.NewProperty("MaxNodeCount", maxNodeCount))
+	conf.AddProperty(config.NewProperty("GresTypes", strings.Join(gresTypes(nodesetList), ",")))
 
 	conf.AddProperty(config.NewPropertyRaw("#"))
 	conf.AddProperty(config.NewPropertyRaw("### LOGGING ###"))
@@ -219,13 +313,35 @@ func buildSlurmConf(
 		conf.AddProperty(config.NewProperty("MetricsType", "metrics/openmetrics"))
 	}
 
+	jobSubmitEnabled := controller.Spec.JobSubmitScriptRef != nil
+	if jobSubmitEnabled {
+		conf.AddProperty(config.NewProperty("JobSubmitPlugins", "lua"))
+	}
+
+	if len(controller.Spec.Licenses) > 0 {
+		conf.AddProperty(config.NewProperty("Licenses", buildLicenses(controller.Spec.Licenses)))
+	}
+
+	if snippet := buildSchedulingConf(controller.Spec.Scheduling); snippet != "" {
+		conf.AddProperty(config.NewPropertyRaw(snippet))
+	}
+
 	conf.AddProperty(config.NewPropertyRaw("#"))
 	conf.AddProperty(config.NewPropertyRaw("### ACCOUNTING ###"))
 	if accounting != nil {
 		conf.AddProperty(config.NewProperty("AccountingStorageType", "accounting_storage/slurmdbd"))
-		conf.AddProperty(config.NewProperty("AccountingStorageHost", accounting.ServiceKey().Name))
+		if accounting.Spec.HighAvailability && !accounting.Spec.External {
+			conf.AddProperty(config.NewProperty("AccountingStorageHost", accounting.PrimaryName()))
+			conf.AddProperty(config.NewProperty("AccountingStorageBackupHost", accounting.BackupName()))
+		} else {
+			conf.AddProperty(config.NewProperty("AccountingStorageHost", accounting.ServiceKey().Name))
+		}
 		conf.AddProperty(config.NewProperty("AccountingStoragePort", common.SlurmdbdPort))
-		conf.AddProperty(config.NewProperty("AccountingStorageTRES", "gres/gpu"))
+		accountingTres := []string{}
+		for _, name := range gresTypes(nodesetList) {
+			accountingTres = append(accountingTres, "gres/"+name)
+		}
+		conf.AddProperty(config.NewProperty("AccountingStorageTRES", strings.Join(accountingTres, ",")))
 		if cgroupEnabled {
 			conf.AddProperty(config.NewProperty("JobAcctGatherType", "jobacct_gather/cgroup"))
 		} else {
@@ -244,6 +360,10 @@ func buildSlurmConf(
 		conf.AddProperty(config.NewPropertyRaw(snippet))
 	}
 
+	if snippet := buildNodeSetScriptConf(controller, nodesetList); snippet != "" {
+		conf.AddProperty(config.NewPropertyRaw(snippet))
+	}
+
 	if snippet := buildNodeSetConf(nodesetList); snippet != "" {
 		conf.AddProperty(config.NewPropertyRaw(snippet))
 	}
@@ -256,6 +376,82 @@ func buildSlurmConf(
 	return conf.Build()
 }
 
+// buildLicenses() returns the value of the Licenses option.
+//
+// https://slurm.schedmd.com/slurm.conf.html#OPT_Licenses
+func buildLicenses(licenses []slinkyv1beta1.License) string {
+	items := make([]string, 0, len(licenses))
+	for _, license := range licenses {
+		items = append(items, fmt.Sprintf("%s:%d", license.Name, license.Count))
+	}
+	return strings.Join(items, ",")
+}
+
+// buildSchedulingConf() returns a slurm.conf snippet containing the scheduler, priority, and preemption config.
+//
+// https://slurm.schedmd.com/slurm.conf.html#OPT_SchedulerType
+// https://slurm.schedmd.com/slurm.conf.html#OPT_PriorityType
+// https://slurm.schedmd.com/slurm.conf.html#OPT_PreemptType
+func buildSchedulingConf(scheduling slinkyv1beta1.ControllerScheduling) string {
+	conf := config.NewBuilder()
+
+	if scheduling.SchedulerType != "" {
+		conf.AddProperty(config.NewProperty("SchedulerType", scheduling.SchedulerType))
+	}
+	if len(scheduling.SchedulerParameters) > 0 {
+		conf.AddProperty(config.NewProperty("SchedulerParameters", strings.Join(scheduling.SchedulerParameters, ",")))
+	}
+
+	priority := scheduling.Priority
+	if priority.Type != "" {
+		conf.AddProperty(config.NewProperty("PriorityType", priority.Type))
+	}
+	if priority.DecayHalfLife != "" {
+		conf.AddProperty(config.NewProperty("PriorityDecayHalfLife", priority.DecayHalfLife))
+	}
+	weights := []struct {
+		key    string
+		weight *int64
+	}{
+		{key: "PriorityWeightAge", weight: priority.Weights.Age},
+		{key: "PriorityWeightFairshare", weight: priority.Weights.FairShare},
+		{key: "PriorityWeightJobSize", weight: priority.Weights.JobSize},
+		{key: "PriorityWeightPartition", weight: priority.Weights.Partition},
+		{key: "PriorityWeightQOS", weight: priority.Weights.QOS},
+	}
+	for _, w := range weights {
+		if w.weight != nil {
+			conf.AddProperty(config.NewProperty(w.key, *w.weight))
+		}
+	}
+	if len(priority.Weights.TRES) > 0 {
+		tres := make([]string, 0, len(priority.Weights.TRES))
+		for name, weight := range priority.Weights.TRES {
+			tres = append(tres, fmt.Sprintf("%s=%d", name, weight))
+		}
+		sort.Strings(tres)
+		conf.AddProperty(config.NewProperty("PriorityWeightTRES", strings.Join(tres, ",")))
+	}
+
+	preempt := scheduling.Preempt
+	if preempt.Type != "" {
+		conf.AddProperty(config.NewProperty("PreemptType", preempt.Type))
+	}
+	if len(preempt.Mode) > 0 {
+		modes := make([]string, 0, len(preempt.Mode))
+		for _, mode := range preempt.Mode {
+			modes = append(modes, string(mode))
+		}
+		conf.AddProperty(config.NewProperty("PreemptMode", strings.Join(modes, ",")))
+	}
+
+	settings := conf.WithFinalNewline(false).Build()
+	if settings == "" {
+		return ""
+	}
+	return strings.Join([]string{"#", "### SCHEDULING ###", settings}, "\n")
+}
+
 // buildPrologEpilogConf() returns a slurm.conf snippet containing PrologSlurmctld and EpilogSlurmctld config.
 //
 // https://slurm.schedmd.com/slurm.conf.html#OPT_PrologSlurmctld
@@ -306,6 +502,85 @@ func buildPrologEpilogConf(prologScripts, epilogScripts []string) string {
 	return conf.WithFinalNewline(false).Build()
 }
 
+// buildNodeSetScriptConf() returns a slurm.conf snippet running the prolog,
+// epilog, and health check scripts of the NodeSets.
+//
+// The runners are configured for every node, but the scripts are only mounted
+// into the pods of their NodeSet, so each node only runs its own scripts.
+//
+// https://slurm.schedmd.com/slurm.conf.html#OPT_Prolog
+// https://slurm.schedmd.com/slurm.conf.html#OPT_Epilog
+// https://slurm.schedmd.com/slurm.conf.html#OPT_HealthCheckProgram
+func buildNodeSetScriptConf(controller *slinkyv1beta1.Controller, nodesetList *slinkyv1beta1.NodeSetList) string {
+	conf := config.NewBuilder()
+
+	hasPrologs := hasNodeSetPrologs(nodesetList)
+	hasEpilogs := hasNodeSetEpilogs(nodesetList)
+	interval := healthCheckInterval(controller, nodesetList)
+	if !hasPrologs && !hasEpilogs && interval == 0 {
+		return ""
+	}
+
+	conf.AddProperty(config.NewPropertyRaw("#"))
+	conf.AddProperty(config.NewPropertyRaw("### NODESET PROLOG, EPILOG & HEALTH CHECK ###"))
+	if hasPrologs {
+		conf.AddProperty(config.NewProperty("Prolog", NodeSetPrologFile))
+	}
+	if hasEpilogs {
+		conf.AddProperty(config.NewProperty("Epilog", NodeSetEpilogFile))
+	}
+	if interval > 0 {
+		// Unlike prolog and epilog scripts, the health check program is not
+		// sent to the nodes by slurmctld; it is mounted into the NodeSet pods.
+		conf.AddProperty(config.NewProperty("HealthCheckProgram", path.Join(common.SlurmEtcDir, NodeSetHealthCheckFile)))
+		conf.AddProperty(config.NewProperty("HealthCheckInterval", interval))
+	}
+
+	return conf.WithFinalNewline(false).Build()
+}
+
+func hasNodeSetPrologs(nodesetList *slinkyv1beta1.NodeSetList) bool {
+	return slices.ContainsFunc(nodesetList.Items, func(nodeset slinkyv1beta1.NodeSet) bool {
+		return len(nodeset.Spec.PrologScriptRefs) > 0
+	})
+}
+
+func hasNodeSetEpilogs(nodesetList *slinkyv1beta1.NodeSetList) bool {
+	return slices.ContainsFunc(nodesetList.Items, func(nodeset slinkyv1beta1.NodeSet) bool {
+		return len(nodeset.Spec.EpilogScriptRefs) > 0
+	})
+}
+
+// healthCheckInterval returns the health check interval of the Controller, or
+// zero when none of its NodeSets have health checks.
+func healthCheckInterval(controller *slinkyv1beta1.Controller, nodesetList *slinkyv1beta1.NodeSetList) int32 {
+	hasHealthChecks := slices.ContainsFunc(nodesetList.Items, func(nodeset slinkyv1beta1.NodeSet) bool {
+		return len(nodeset.Spec.HealthCheck.ScriptRefs) > 0
+	})
+	if !hasHealthChecks {
+		return 0
+	}
+	if controller.Spec.HealthCheckIntervalSeconds > 0 {
+		return controller.Spec.HealthCheckIntervalSeconds
+	}
+	return DefaultHealthCheckInterval
+}
+
+// buildNodeSetScriptRunner returns a script which runs the scripts mounted in
+// the directory, relative to `/etc/slurm`, in name order.
+func buildNodeSetScriptRunner(dir string) string {
+	return fmt.Sprintf(`#!/usr/bin/env sh
+set -e
+for script in %s/*; do
+	[ -f "$script" ] || continue
+	"$script"
+done
+`, path.Join(common.SlurmEtcDir, dir))
+}
+
+//go:embed scripts/nodeset-healthcheck.sh
+var nodesetHealthCheckScript string
+
 // buildNodeSetConf() returns a slurm.conf snippet containing NodeSets and their Partitions.
 //
 // https://slurm.schedmd.com/slurm.conf.html#SECTION_NODESET-CONFIGURATION
@@ -349,30 +624,215 @@ func buildNodeSetConf(nodesetList *slinkyv1beta1.NodeSetList) string {
 }
 
 // https://slurm.schedmd.com/cgroup.conf.html
-func buildCgroupConf() string {
+func buildCgroupConf(controller *slinkyv1beta1.Controller) string {
 	conf := config.NewBuilder()
 
-	conf.AddProperty(config.NewProperty("CgroupPlugin", "cgroup/v2"))
+	cgroup := controller.Spec.Cgroup
+	plugin := cgroup.Plugin
+	if plugin == "" {
+		plugin = slinkyv1beta1.CgroupPluginV2
+	}
+	conf.AddProperty(config.NewProperty("CgroupPlugin", plugin))
 	conf.AddProperty(config.NewProperty("IgnoreSystemd", "yes"))
+	if cgroup.EnableControllers != nil {
+		conf.AddProperty(config.NewProperty("EnableControllers", yesNo(*cgroup.EnableControllers)))
+	}
+	if cgroup.ConstrainCores != nil {
+		conf.AddProperty(config.NewProperty("ConstrainCores", yesNo(*cgroup.ConstrainCores)))
+	}
+	if cgroup.ConstrainRAMSpace != nil {
+		conf.AddProperty(config.NewProperty("ConstrainRAMSpace", yesNo(*cgroup.ConstrainRAMSpace)))
+	}
+	if cgroup.AllowedRAMSpace != nil {
+		conf.AddProperty(config.NewProperty("AllowedRAMSpace", *cgroup.AllowedRAMSpace))
+	}
+	if cgroup.ConstrainSwapSpace != nil {
+		conf.AddProperty(config.NewProperty("ConstrainSwapSpace", yesNo(*cgroup.ConstrainSwapSpace)))
+	}
+	if cgroup.ConstrainDevices != nil {
+		conf.AddProperty(config.NewProperty("ConstrainDevices", yesNo(*cgroup.ConstrainDevices)))
+	}
 
 	return conf.Build()
 }
 
+// yesNo formats a boolean as a Slurm `yes` or `no` value.
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// isCgroupEnabled returns false if the `cgroup.conf` disables cgroups.
 func isCgroupEnabled(cgroupConf string) bool {
-	r := regexp.MustCompile(`(?im)^CgroupPlugin=disabled`)
-	found := r.FindStringSubmatch(cgroupConf)
-	return len(found) == 0
+	for _, line := range config.Parse(cgroupConf) {
+		if plugin, ok := line.Get("CgroupPlugin"); ok && strings.EqualFold(plugin, string(slinkyv1beta1.CgroupPluginDisabled)) {
+			return false
+		}
+	}
+	return true
+}
+
+// gresTypes returns the sorted union of GRES names of all NodeSets. The `gpu`
+// GRES is always included.
+func gresTypes(nodesetList *slinkyv1beta1.NodeSetList) []string {
+	names := set.New("gpu")
+	for _, nodeset := range nodesetList.Items {
+		for _, gres := range nodeset.Spec.Gres {
+			names.Insert(gres.Name)
+		}
+	}
+	return names.SortedList()
+}
+
+// nodesetNodeNames returns a hostlist expression matching the Slurm node names
+// of the NodeSet's pods. Slurm registers no more than MaxNodeCount nodes, so
+// the ordinals stay below it; it rarely changes, unlike the replicas.
+func nodesetNodeNames(nodeset *slinkyv1beta1.NodeSet, maxNodeCount int32) string {
+	prefix := nodeset.Name + "-"
+	if hostname := nodeset.Spec.Template.PodSpecWrapper.Hostname; hostname != "" {
+		prefix = hostname
+	}
+	return hostlistRange(prefix, int(nodeset.Spec.OrdinalPadding), maxNodeCount-1)
+}
+
+// hostlistRange returns a hostlist expression of the names made of the prefix
+// and the ordinals from zero to last, zero padded to the width. Ordinals with
+// more digits than the width are in their own range, so that the padding of
+// each range is unambiguous.
+// Ref: https://slurm.schedmd.com/slurm.conf.html#SECTION_NODE-CONFIGURATION
+func hostlistRange(prefix string, width int, last int32) string {
+	if last <= 0 {
+		return fmt.Sprintf("%s%0*d", prefix, width, 0)
+	}
+
+	ranges := []string{}
+	lo, next := int64(0), int64(math.Pow10(max(width, 1)))
+	for lo <= int64(last) {
+		hi := min(next-1, int64(last))
+		if lo == hi {
+			ranges = append(ranges, fmt.Sprintf("%0*d", width, lo))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%0*d-%0*d", width, lo, width, hi))
+		}
+		lo, next = next, next*10
+	}
+	return fmt.Sprintf("%s[%s]", prefix, strings.Join(ranges, ","))
 }
 
 // https://slurm.schedmd.com/gres.conf.html
-func buildGresConf() string {
+func buildGresConf(nodesetList *slinkyv1beta1.NodeSetList, maxNodeCount int32) string {
+	conf := config.NewBuilder()
+
+	// Without any NodeSet GRES, keep the cluster-wide default. Otherwise it
+	// would apply to every node, including nodes without GPUs, so NVIDIA
+	// detection is scoped to the NodeSets that request NVIDIA GPUs instead.
+	hasNodeSetGres := slices.ContainsFunc(nodesetList.Items, func(nodeset slinkyv1beta1.NodeSet) bool {
+		return len(nodeset.Spec.Gres) > 0
+	})
+	if !hasNodeSetGres {
+		conf.AddProperty(config.NewProperty("AutoDetect", slinkyv1beta1.GresAutoDetectNvidia))
+		return conf.Build()
+	}
+
+	sort.Slice(nodesetList.Items, func(i, j int) bool {
+		return nodesetList.Items[i].Name < nodesetList.Items[j].Name
+	})
+	for _, nodeset := range nodesetList.Items {
+		nodeNames := nodesetNodeNames(&nodeset, maxNodeCount)
+		if len(nodeset.Spec.Gres) == 0 {
+			if _, ok := nodeset.Spec.Slurmd.Resources.Limits[nvidiaGpuResource]; ok {
+				conf.AddProperty(config.NewPropertyRaw("#"))
+				conf.AddProperty(config.NewPropertyRaw(fmt.Sprintf("### NODESET: %s ###", nodeset.Name)))
+				conf.AddProperty(config.NewPropertyRaw(fmt.Sprintf("NodeName=%v AutoDetect=%v", nodeNames, slinkyv1beta1.GresAutoDetectNvidia)))
+			}
+			continue
+		}
+		conf.AddProperty(config.NewPropertyRaw("#"))
+		conf.AddProperty(config.NewPropertyRaw(fmt.Sprintf("### NODESET: %s ###", nodeset.Name)))
+		for _, gres := range nodeset.Spec.Gres {
+			gresLine := []string{
+				fmt.Sprintf("NodeName=%v", nodeNames),
+			}
+			if gres.AutoDetect != "" {
+				gresLine = append(gresLine, fmt.Sprintf("AutoDetect=%v", gres.AutoDetect))
+			}
+			gresLine = append(gresLine, fmt.Sprintf("Name=%v", gres.Name))
+			if gres.Type != "" {
+				gresLine = append(gresLine, fmt.Sprintf("Type=%v", gres.Type))
+			}
+			if gres.Count != "" {
+				gresLine = append(gresLine, fmt.Sprintf("Count=%v", gres.Count))
+			}
+			if gres.File != "" {
+				gresLine = append(gresLine, fmt.Sprintf("File=%v", gres.File))
+			}
+			conf.AddProperty(config.NewPropertyRaw(strings.Join(gresLine, " ")))
+		}
+	}
+
+	return conf.Build()
+}
+
+// PlugstackNodeSetConfKey returns the config file holding the SPANK plugins of
+// the NodeSet, which is only mounted into the NodeSet's pods.
+func PlugstackNodeSetConfKey(nodeset *slinkyv1beta1.NodeSet) string {
+	return fmt.Sprintf("plugstack-%s.conf", nodeset.Name)
+}
+
+// PlugstackNodeSetConfPath returns the path, relative to `/etc/slurm`, where
+// the SPANK plugins of the NodeSet are mounted.
+func PlugstackNodeSetConfPath(nodeset *slinkyv1beta1.NodeSet) string {
+	return path.Join(PlugstackConfDir, nodeset.Name+".conf")
+}
+
+// buildPlugstackConf returns the `plugstack.conf` with the Controller's SPANK
+// plugins, and an include of the NodeSet plugins mounted into their pods. An
+// empty string is returned when there are no plugins.
+//
+// The include uses a glob so that it resolves to nothing where no NodeSet
+// plugins are mounted (e.g. slurmctld).
+// https://slurm.schedmd.com/spank.html#SECTION_CONFIGURATION
+func buildPlugstackConf(controller *slinkyv1beta1.Controller, nodesetList *slinkyv1beta1.NodeSetList) string {
+	hasNodeSetPlugins := slices.ContainsFunc(nodesetList.Items, func(nodeset slinkyv1beta1.NodeSet) bool {
+		return len(nodeset.Spec.Spank.Plugins) > 0
+	})
+	if len(controller.Spec.Spank.Plugins) == 0 && !hasNodeSetPlugins {
+		return ""
+	}
+
 	conf := config.NewBuilder()
 
-	conf.AddProperty(config.NewProperty("AutoDetect", "nvidia"))
+	if len(controller.Spec.Spank.Plugins) > 0 {
+		conf.AddProperty(config.NewPropertyRaw(buildSpankConf(controller.Spec.Spank)))
+	}
+	if hasNodeSetPlugins {
+		conf.AddProperty(config.NewPropertyRaw("#"))
+		conf.AddProperty(config.NewPropertyRaw("### NODESET PLUGINS ###"))
+		conf.AddProperty(config.NewPropertyRaw("include " + path.Join(common.SlurmEtcDir, PlugstackConfDir, "*.conf")))
+	}
 
 	return conf.Build()
 }
 
+// buildSpankConf returns the `plugstack.conf` lines of the SPANK plugins.
+// https://slurm.schedmd.com/spank.html#SECTION_CONFIGURATION
+func buildSpankConf(spank slinkyv1beta1.Spank) string {
+	conf := config.NewBuilder()
+
+	for _, plugin := range spank.Plugins {
+		pluginLine := []string{"required", plugin.Path}
+		if plugin.Optional {
+			pluginLine[0] = "optional"
+		}
+		pluginLine = append(pluginLine, plugin.Args...)
+		conf.AddProperty(config.NewPropertyRaw(strings.Join(pluginLine, " ")))
+	}
+
+	return conf.WithFinalNewline(false).Build()
+}
+
 // BuildControllerConfigExternal returns a minimal slurm.conf for slurmrestd (lacks configless).
 func (b *ControllerBuilder) BuildControllerConfigExternal(controller *slinkyv1beta1.Controller) (*corev1.ConfigMap, error) {
 	ctx := context.TODO()
@@ -421,7 +881,12 @@ func buildSlurmConfMinimal(
 	conf.AddProperty(config.NewPropertyRaw("### ACCOUNTING ###"))
 	if accounting != nil {
 		conf.AddProperty(config.NewProperty("AccountingStorageType", "accounting_storage/slurmdbd"))
-		conf.AddProperty(config.NewProperty("AccountingStorageHost", accounting.ServiceKey().Name))
+		if accounting.Spec.HighAvailability && !accounting.Spec.External {
+			conf.AddProperty(config.NewProperty("AccountingStorageHost", accounting.PrimaryName()))
+			conf.AddProperty(config.NewProperty("AccountingStorageBackupHost", accounting.BackupName()))
+		} else {
+			conf.AddProperty(config.NewProperty("AccountingStorageHost", accounting.ServiceKey().Name))
+		}
 		conf.AddProperty(config.NewProperty("AccountingStoragePort", common.SlurmdbdPort))
 	} else {
 		conf.AddProperty(config.NewProperty("AccountingStorageType", "accounting_storage/none"))
@@ -0,0 +1,160 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllerbuilder
+
+import (
+	"sort"
+	"strings"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/config"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/crypto"
+)
+
+// restartKeys maps `slurm.conf` keys (lowercase) to the action required to
+// apply a change to them. Keys not listed are applied by a reconfigure.
+//
+// Since Slurm 23.11, `scontrol reconfigure` re-executes the daemons, but
+// changes to listening ports and authentication cannot be applied to running
+// daemons and may break communication with nodes still using the old values.
+// Plugin selections and state locations are only read on startup.
+// Ref: https://slurm.schedmd.com/scontrol.html#OPT_reconfigure
+var restartKeys = map[string]slinkyv1beta1.ConfigChangeAction{
+	// Require all daemons to agree.
+	"authaltparameters":       slinkyv1beta1.ConfigChangeActionRestartSlurmd,
+	"authalttypes":            slinkyv1beta1.ConfigChangeActionRestartSlurmd,
+	"authinfo":                slinkyv1beta1.ConfigChangeActionRestartSlurmd,
+	"authtype":                slinkyv1beta1.ConfigChangeActionRestartSlurmd,
+	"communicationparameters": slinkyv1beta1.ConfigChangeActionRestartSlurmd,
+	"credtype":                slinkyv1beta1.ConfigChangeActionRestartSlurmd,
+	"grestypes":               slinkyv1beta1.ConfigChangeActionRestartSlurmd,
+	"hashplugin":              slinkyv1beta1.ConfigChangeActionRestartSlurmd,
+	"jobacctgathertype":       slinkyv1beta1.ConfigChangeActionRestartSlurmd,
+	"launchtype":              slinkyv1beta1.ConfigChangeActionRestartSlurmd,
+	"nodefeaturesplugins":     slinkyv1beta1.ConfigChangeActionRestartSlurmd,
+	"plugindir":               slinkyv1beta1.ConfigChangeActionRestartSlurmd,
+	"proctracktype":           slinkyv1beta1.ConfigChangeActionRestartSlurmd,
+	"prologflags":             slinkyv1beta1.ConfigChangeActionRestartSlurmd,
+	"selecttype":              slinkyv1beta1.ConfigChangeActionRestartSlurmd,
+	"selecttypeparameters":    slinkyv1beta1.ConfigChangeActionRestartSlurmd,
+	"slurmctldport":           slinkyv1beta1.ConfigChangeActionRestartSlurmd,
+	"slurmdport":              slinkyv1beta1.ConfigChangeActionRestartSlurmd,
+	"slurmdspooldir":          slinkyv1beta1.ConfigChangeActionRestartSlurmd,
+	"slurmduser":              slinkyv1beta1.ConfigChangeActionRestartSlurmd,
+	"slurmuser":               slinkyv1beta1.ConfigChangeActionRestartSlurmd,
+	"switchtype":              slinkyv1beta1.ConfigChangeActionRestartSlurmd,
+	"taskplugin":              slinkyv1beta1.ConfigChangeActionRestartSlurmd,
+	"topologyparam":           slinkyv1beta1.ConfigChangeActionRestartSlurmd,
+	"topologyplugin":          slinkyv1beta1.ConfigChangeActionRestartSlurmd,
+	"treewidth":               slinkyv1beta1.ConfigChangeActionRestartSlurmd,
+	// Only read by slurmctld on startup.
+	"accountingstoragehost": slinkyv1beta1.ConfigChangeActionRestartSlurmctld,
+	"accountingstorageport": slinkyv1beta1.ConfigChangeActionRestartSlurmctld,
+	"accountingstoragetype": slinkyv1beta1.ConfigChangeActionRestartSlurmctld,
+	"clustername":           slinkyv1beta1.ConfigChangeActionRestartSlurmctld,
+	"jobcomptype":           slinkyv1beta1.ConfigChangeActionRestartSlurmctld,
+	"maxnodecount":          slinkyv1beta1.ConfigChangeActionRestartSlurmctld,
+	"metricstype":           slinkyv1beta1.ConfigChangeActionRestartSlurmctld,
+	"preempttype":           slinkyv1beta1.ConfigChangeActionRestartSlurmctld,
+	"prioritytype":          slinkyv1beta1.ConfigChangeActionRestartSlurmctld,
+	"schedulertype":         slinkyv1beta1.ConfigChangeActionRestartSlurmctld,
+	"statesavelocation":     slinkyv1beta1.ConfigChangeActionRestartSlurmctld,
+}
+
+// confEntry is a single `slurm.conf` setting.
+type confEntry struct {
+	name  string
+	value string
+}
+
+// confEntries indexes `slurm.conf` settings by lowercase key. Lines defining
+// an entity (e.g. `PartitionName=foo ...`) are keyed by their first parameter.
+func confEntries(conf string) map[string]confEntry {
+	entries := make(map[string]confEntry)
+	for _, line := range config.Parse(conf) {
+		name := line[0].Key
+		value := line[0].Value
+		if len(line) > 1 {
+			name = line[0].Key + "=" + line[0].Value
+			values := make([]string, 0, len(line)-1)
+			for _, param := range line[1:] {
+				values = append(values, param.Key+"="+param.Value)
+			}
+			value = strings.Join(values, " ")
+		}
+		entries[strings.ToLower(name)] = confEntry{name: name, value: value}
+	}
+	return entries
+}
+
+// configChangeAction returns the action required to apply a change to the key.
+func configChangeAction(key string) slinkyv1beta1.ConfigChangeAction {
+	if action, ok := restartKeys[key]; ok {
+		return action
+	}
+	return slinkyv1beta1.ConfigChangeActionReconfigure
+}
+
+// actionSeverity orders actions from least to most disruptive.
+func actionSeverity(action slinkyv1beta1.ConfigChangeAction) int {
+	switch action {
+	case slinkyv1beta1.ConfigChangeActionReconfigure:
+		return 1
+	case slinkyv1beta1.ConfigChangeActionRestartSlurmctld:
+		return 2
+	case slinkyv1beta1.ConfigChangeActionRestartSlurmd:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// ClassifyConfigChange compares two renderings of `slurm.conf` and returns the
+// most disruptive action needed to apply the change, along with the changed
+// keys that require it. An empty action is returned when nothing changed.
+func ClassifyConfigChange(oldConf, newConf string) (slinkyv1beta1.ConfigChangeAction, []string) {
+	oldEntries := confEntries(oldConf)
+	newEntries := confEntries(newConf)
+
+	changed := make(map[string]string)
+	for key, entry := range newEntries {
+		if old, ok := oldEntries[key]; !ok || old.value != entry.value {
+			changed[key] = entry.name
+		}
+	}
+	for key, entry := range oldEntries {
+		if _, ok := newEntries[key]; !ok {
+			changed[key] = entry.name
+		}
+	}
+
+	var action slinkyv1beta1.ConfigChangeAction
+	var keys []string
+	for key, name := range changed {
+		keyAction := configChangeAction(key)
+		switch {
+		case actionSeverity(keyAction) > actionSeverity(action):
+			action = keyAction
+			keys = []string{name}
+		case keyAction == action:
+			keys = append(keys, name)
+		}
+	}
+	sort.Strings(keys)
+
+	return action, keys
+}
+
+// RestartHash returns a hash of the `slurm.conf` settings which require at
+// least the given action to be applied. It is used to restart the daemons
+// when those settings change.
+func RestartHash(conf string, action slinkyv1beta1.ConfigChangeAction) string {
+	data := make(map[string]string)
+	for key, entry := range confEntries(conf) {
+		if actionSeverity(configChangeAction(key)) >= actionSeverity(action) {
+			data[key] = entry.value
+		}
+	}
+	return crypto.CheckSumFromMap(data)
+}
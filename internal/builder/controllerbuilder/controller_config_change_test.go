@@ -0,0 +1,135 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllerbuilder
+
+import (
+	"testing"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+)
+
+func TestClassifyConfigChange(t *testing.T) {
+	const base = `ClusterName=slurm
+AuthType=auth/slurm
+SchedulerParameters=bf_continue
+PartitionName=all Nodes=ALL Default=YES
+`
+	type args struct {
+		oldConf string
+		newConf string
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantAction slinkyv1beta1.ConfigChangeAction
+		wantKeys   []string
+	}{
+		{
+			name: "no change",
+			args: args{
+				oldConf: base,
+				newConf: base + "# comment\n",
+			},
+			wantAction: "",
+		},
+		{
+			name: "reconfigure",
+			args: args{
+				oldConf: base,
+				newConf: base + "SchedulerParameters=bf_continue,bf_interval=60\nPartitionName=foo Nodes=foo\n",
+			},
+			wantAction: slinkyv1beta1.ConfigChangeActionReconfigure,
+			wantKeys:   []string{"PartitionName=foo", "SchedulerParameters"},
+		},
+		{
+			name: "partition removed",
+			args: args{
+				oldConf: base,
+				newConf: "ClusterName=slurm\nAuthType=auth/slurm\nSchedulerParameters=bf_continue\n",
+			},
+			wantAction: slinkyv1beta1.ConfigChangeActionReconfigure,
+			wantKeys:   []string{"PartitionName=all"},
+		},
+		{
+			name: "restart slurmctld",
+			args: args{
+				oldConf: base,
+				newConf: base + "MaxNodeCount=2048\nPartitionName=foo Nodes=foo\n",
+			},
+			wantAction: slinkyv1beta1.ConfigChangeActionRestartSlurmctld,
+			wantKeys:   []string{"MaxNodeCount"},
+		},
+		{
+			name: "restart slurmd",
+			args: args{
+				oldConf: base,
+				newConf: base + "authtype=auth/munge\nMaxNodeCount=2048\nSlurmdPort=6820\n",
+			},
+			wantAction: slinkyv1beta1.ConfigChangeActionRestartSlurmd,
+			wantKeys:   []string{"SlurmdPort", "authtype"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotAction, gotKeys := ClassifyConfigChange(tt.args.oldConf, tt.args.newConf)
+			if gotAction != tt.wantAction {
+				t.Errorf("ClassifyConfigChange() action = %v, want %v", gotAction, tt.wantAction)
+			}
+			if !apiequality.Semantic.DeepEqual(gotKeys, tt.wantKeys) {
+				t.Errorf("ClassifyConfigChange() keys = %v, want %v", gotKeys, tt.wantKeys)
+			}
+		})
+	}
+}
+
+func TestRestartHash(t *testing.T) {
+	const base = "ClusterName=slurm\nAuthType=auth/slurm\nPartitionName=all Nodes=ALL\n"
+	tests := []struct {
+		name     string
+		newConf  string
+		action   slinkyv1beta1.ConfigChangeAction
+		wantSame bool
+	}{
+		{
+			name:     "slurmctld, reconfigure key",
+			newConf:  base + "SchedulerParameters=bf_continue\n",
+			action:   slinkyv1beta1.ConfigChangeActionRestartSlurmctld,
+			wantSame: true,
+		},
+		{
+			name:     "slurmctld, slurmctld key",
+			newConf:  base + "MaxNodeCount=2048\n",
+			action:   slinkyv1beta1.ConfigChangeActionRestartSlurmctld,
+			wantSame: false,
+		},
+		{
+			name:     "slurmctld, slurmd key",
+			newConf:  base + "SlurmdPort=6820\n",
+			action:   slinkyv1beta1.ConfigChangeActionRestartSlurmctld,
+			wantSame: false,
+		},
+		{
+			name:     "slurmd, slurmctld key",
+			newConf:  base + "MaxNodeCount=2048\n",
+			action:   slinkyv1beta1.ConfigChangeActionRestartSlurmd,
+			wantSame: true,
+		},
+		{
+			name:     "slurmd, slurmd key",
+			newConf:  base + "SlurmdPort=6820\n",
+			action:   slinkyv1beta1.ConfigChangeActionRestartSlurmd,
+			wantSame: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RestartHash(tt.newConf, tt.action) == RestartHash(base, tt.action)
+			if got != tt.wantSame {
+				t.Errorf("RestartHash() unchanged = %v, want %v", got, tt.wantSame)
+			}
+		})
+	}
+}
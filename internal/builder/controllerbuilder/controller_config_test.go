@@ -4,15 +4,24 @@
 package controllerbuilder
 
 import (
+	"context"
+	"fmt"
 	"math/rand/v2"
 	"strings"
 	"testing"
 
 	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	"github.com/puttsk/hostlist"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/SlinkyProject/slurm-operator/internal/utils/refresolver"
 )
 
 func TestBuilder_BuildControllerConfig(t *testing.T) {
@@ -146,6 +155,37 @@ func TestBuilder_BuildControllerConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "with accounting high availability",
+			fields: fields{
+				client: fake.NewClientBuilder().
+					WithObjects(&slinkyv1beta1.Accounting{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "slurm",
+						},
+						Spec: slinkyv1beta1.AccountingSpec{
+							HighAvailability: true,
+						},
+					}).
+					Build(),
+			},
+			args: args{
+				controller: &slinkyv1beta1.Controller{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "slurm",
+					},
+					Spec: slinkyv1beta1.ControllerSpec{
+						AccountingRef: slinkyv1beta1.ObjectReference{
+							Name: "slurm",
+						},
+					},
+				},
+			},
+			wantScripts: []string{
+				"AccountingStorageHost=slurm-accounting-0\n",
+				"AccountingStorageBackupHost=slurm-accounting-1\n",
+			},
+		},
 		{
 			name: "multiple prolog configmaps",
 			fields: fields{
@@ -220,6 +260,24 @@ func TestBuilder_BuildControllerConfig(t *testing.T) {
 			},
 			wantScripts: []string{"00-cleanup.sh", "90-finalize.sh"},
 		},
+		{
+			name: "with job submit script",
+			fields: fields{
+				client: fake.NewFakeClient(),
+			},
+			args: args{
+				controller: &slinkyv1beta1.Controller{
+					ObjectMeta: metav1.ObjectMeta{Name: "slurm"},
+					Spec: slinkyv1beta1.ControllerSpec{
+						JobSubmitScriptRef: &corev1.ConfigMapKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "job-submit"},
+							Key:                  "job_submit.lua",
+						},
+					},
+				},
+			},
+			wantScripts: []string{"JobSubmitPlugins=lua"},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -247,6 +305,59 @@ func TestBuilder_BuildControllerConfig(t *testing.T) {
 	}
 }
 
+func Test_buildCgroupConf(t *testing.T) {
+	tests := []struct {
+		name   string
+		cgroup slinkyv1beta1.ControllerCgroup
+		want   string
+	}{
+		{
+			name: "default",
+			want: "CgroupPlugin=cgroup/v2\nIgnoreSystemd=yes\n",
+		},
+		{
+			name: "disabled",
+			cgroup: slinkyv1beta1.ControllerCgroup{
+				Plugin: slinkyv1beta1.CgroupPluginDisabled,
+			},
+			want: "CgroupPlugin=disabled\nIgnoreSystemd=yes\n",
+		},
+		{
+			name: "constrained",
+			cgroup: slinkyv1beta1.ControllerCgroup{
+				ConstrainCores:     ptr.To(true),
+				ConstrainRAMSpace:  ptr.To(true),
+				AllowedRAMSpace:    ptr.To[int32](95),
+				ConstrainDevices:   ptr.To(true),
+				ConstrainSwapSpace: ptr.To(false),
+				EnableControllers:  ptr.To(true),
+			},
+			want: strings.Join([]string{
+				"CgroupPlugin=cgroup/v2",
+				"IgnoreSystemd=yes",
+				"EnableControllers=yes",
+				"ConstrainCores=yes",
+				"ConstrainRAMSpace=yes",
+				"AllowedRAMSpace=95",
+				"ConstrainSwapSpace=no",
+				"ConstrainDevices=yes",
+			}, "\n") + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := &slinkyv1beta1.Controller{
+				Spec: slinkyv1beta1.ControllerSpec{
+					Cgroup: tt.cgroup,
+				},
+			}
+			if got := buildCgroupConf(controller); got != tt.want {
+				t.Errorf("buildCgroupConf() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_isCgroupEnabled(t *testing.T) {
 	type args struct {
 		cgroupConf string
@@ -661,3 +772,658 @@ EpilogSlurmctld=/etc/slurm/epilog-slurmctld-2.sh`,
 		})
 	}
 }
+
+func Test_buildGresConf(t *testing.T) {
+	tests := []struct {
+		name        string
+		nodesetList *slinkyv1beta1.NodeSetList
+		want        string
+	}{
+		{
+			name: "empty",
+			nodesetList: &slinkyv1beta1.NodeSetList{
+				Items: []slinkyv1beta1.NodeSet{},
+			},
+			want: "AutoDetect=nvidia\n",
+		},
+		{
+			name: "non-empty",
+			nodesetList: &slinkyv1beta1.NodeSetList{
+				Items: []slinkyv1beta1.NodeSet{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: metav1.NamespaceDefault,
+							Name:      "gpu",
+						},
+						Spec: slinkyv1beta1.NodeSetSpec{
+							OrdinalPadding: 2,
+							Gres: []slinkyv1beta1.NodeSetGres{
+								{Name: "gpu", Type: "a100", AutoDetect: slinkyv1beta1.GresAutoDetectNvml},
+								{Name: "shard", Count: "32"},
+							},
+						},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: metav1.NamespaceDefault,
+							Name:      "cpu",
+						},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: metav1.NamespaceDefault,
+							Name:      "fpga",
+						},
+						Spec: slinkyv1beta1.NodeSetSpec{
+							Template: slinkyv1beta1.PodTemplate{
+								PodSpecWrapper: slinkyv1beta1.PodSpecWrapper{
+									PodSpec: corev1.PodSpec{
+										Hostname: "fpga-node",
+									},
+								},
+							},
+							Gres: []slinkyv1beta1.NodeSetGres{
+								{Name: "fpga", Count: "2", File: "/dev/fpga[0-1]", AutoDetect: slinkyv1beta1.GresAutoDetectOff},
+							},
+						},
+					},
+				},
+			},
+			want: `#
+### NODESET: fpga ###
+NodeName=fpga-node[0-9,10-99,100-999,1000-1023] AutoDetect=off Name=fpga Count=2 File=/dev/fpga[0-1]
+#
+### NODESET: gpu ###
+NodeName=gpu-[00-99,100-999,1000-1023] AutoDetect=nvml Name=gpu Type=a100
+NodeName=gpu-[00-99,100-999,1000-1023] Name=shard Count=32
+`,
+		},
+		{
+			name: "no gpus",
+			nodesetList: &slinkyv1beta1.NodeSetList{
+				Items: []slinkyv1beta1.NodeSet{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: metav1.NamespaceDefault,
+							Name:      "cpu",
+						},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: metav1.NamespaceDefault,
+							Name:      "fpga",
+						},
+						Spec: slinkyv1beta1.NodeSetSpec{
+							Gres: []slinkyv1beta1.NodeSetGres{
+								{Name: "fpga", Count: "2"},
+							},
+						},
+					},
+				},
+			},
+			want: `#
+### NODESET: fpga ###
+NodeName=fpga-[0-9,10-99,100-999,1000-1023] Name=fpga Count=2
+`,
+		},
+		{
+			name: "nvidia gpus without gres",
+			nodesetList: &slinkyv1beta1.NodeSetList{
+				Items: []slinkyv1beta1.NodeSet{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: metav1.NamespaceDefault,
+							Name:      "gpu",
+						},
+						Spec: slinkyv1beta1.NodeSetSpec{
+							Slurmd: slinkyv1beta1.ContainerWrapper{
+								Container: corev1.Container{
+									Resources: corev1.ResourceRequirements{
+										Limits: corev1.ResourceList{
+											"nvidia.com/gpu": resource.MustParse("8"),
+										},
+									},
+								},
+							},
+						},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Namespace: metav1.NamespaceDefault,
+							Name:      "shard",
+						},
+						Spec: slinkyv1beta1.NodeSetSpec{
+							Gres: []slinkyv1beta1.NodeSetGres{
+								{Name: "gpu"},
+								{Name: "shard", Count: "8"},
+							},
+						},
+					},
+				},
+			},
+			want: `#
+### NODESET: gpu ###
+NodeName=gpu-[0-9,10-99,100-999,1000-1023] AutoDetect=nvidia
+#
+### NODESET: shard ###
+NodeName=shard-[0-9,10-99,100-999,1000-1023] Name=gpu
+NodeName=shard-[0-9,10-99,100-999,1000-1023] Name=shard Count=8
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildGresConf(tt.nodesetList, MinMaxNodeCount); got != tt.want {
+				t.Errorf("buildGresConf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_hostlistRange(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix string
+		width  int
+		last   int32
+		want   string
+	}{
+		{
+			name:   "single",
+			prefix: "cpu-",
+			last:   0,
+			want:   "cpu-0",
+		},
+		{
+			name:   "range",
+			prefix: "cpu-",
+			last:   3,
+			want:   "cpu-[0-3]",
+		},
+		{
+			name:   "digits",
+			prefix: "cpu-",
+			last:   10,
+			want:   "cpu-[0-9,10]",
+		},
+		{
+			name:   "padded",
+			prefix: "gpu-",
+			width:  2,
+			last:   11,
+			want:   "gpu-[00-11]",
+		},
+		{
+			name:   "hostname",
+			prefix: "fpga-node",
+			width:  3,
+			last:   1023,
+			want:   "fpga-node[000-999,1000-1023]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := hostlistRange(tt.prefix, tt.width, tt.last)
+			if got != tt.want {
+				t.Fatalf("hostlistRange() = %v, want %v", got, tt.want)
+			}
+			names, err := hostlist.Expand(got)
+			if err != nil {
+				t.Fatalf("hostlist.Expand() error = %v", err)
+			}
+			if len(names) != int(tt.last)+1 {
+				t.Fatalf("hostlist.Expand() = %v names, want %v", len(names), tt.last+1)
+			}
+			for i, name := range names {
+				if want := fmt.Sprintf("%s%0*d", tt.prefix, tt.width, i); name != want {
+					t.Errorf("hostlist.Expand()[%d] = %v, want %v", i, name, want)
+				}
+			}
+		})
+	}
+}
+
+func Test_gresTypes(t *testing.T) {
+	tests := []struct {
+		name        string
+		nodesetList *slinkyv1beta1.NodeSetList
+		want        []string
+	}{
+		{
+			name:        "empty",
+			nodesetList: &slinkyv1beta1.NodeSetList{},
+			want:        []string{"gpu"},
+		},
+		{
+			name: "union",
+			nodesetList: &slinkyv1beta1.NodeSetList{
+				Items: []slinkyv1beta1.NodeSet{
+					{Spec: slinkyv1beta1.NodeSetSpec{Gres: []slinkyv1beta1.NodeSetGres{{Name: "shard"}, {Name: "gpu"}}}},
+					{Spec: slinkyv1beta1.NodeSetSpec{Gres: []slinkyv1beta1.NodeSetGres{{Name: "mps"}, {Name: "shard"}}}},
+				},
+			},
+			want: []string{"gpu", "mps", "shard"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := gresTypes(tt.nodesetList); !apiequality.Semantic.DeepEqual(got, tt.want) {
+				t.Errorf("gresTypes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_buildPlugstackConf(t *testing.T) {
+	pyxis := slinkyv1beta1.Spank{
+		Plugins: []slinkyv1beta1.SpankPlugin{
+			{Path: "/usr/lib/x86_64-linux-gnu/slurm/spank_pyxis.so", Args: []string{"runtime_path=/run/pyxis", "sbatch_support=1"}},
+		},
+	}
+	tests := []struct {
+		name        string
+		controller  *slinkyv1beta1.Controller
+		nodesetList *slinkyv1beta1.NodeSetList
+		want        string
+	}{
+		{
+			name:        "empty",
+			controller:  &slinkyv1beta1.Controller{},
+			nodesetList: &slinkyv1beta1.NodeSetList{},
+			want:        "",
+		},
+		{
+			name: "controller plugins",
+			controller: &slinkyv1beta1.Controller{
+				Spec: slinkyv1beta1.ControllerSpec{
+					Spank: slinkyv1beta1.Spank{
+						Plugins: []slinkyv1beta1.SpankPlugin{
+							{Path: "spank_foo.so"},
+							{Path: "spank_bar.so", Optional: true, Args: []string{"debug"}},
+						},
+					},
+				},
+			},
+			nodesetList: &slinkyv1beta1.NodeSetList{},
+			want: strings.Join([]string{
+				"required spank_foo.so",
+				"optional spank_bar.so debug",
+			}, "\n") + "\n",
+		},
+		{
+			name:       "nodeset plugins",
+			controller: &slinkyv1beta1.Controller{},
+			nodesetList: &slinkyv1beta1.NodeSetList{
+				Items: []slinkyv1beta1.NodeSet{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "cpu"},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "gpu"},
+						Spec: slinkyv1beta1.NodeSetSpec{
+							Spank: pyxis,
+						},
+					},
+				},
+			},
+			want: strings.Join([]string{
+				"#",
+				"### NODESET PLUGINS ###",
+				"include /etc/slurm/plugstack.conf.d/*.conf",
+			}, "\n") + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildPlugstackConf(tt.controller, tt.nodesetList); got != tt.want {
+				t.Errorf("buildPlugstackConf() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_buildSpankConf(t *testing.T) {
+	spank := slinkyv1beta1.Spank{
+		Plugins: []slinkyv1beta1.SpankPlugin{
+			{Path: "/usr/lib/x86_64-linux-gnu/slurm/spank_pyxis.so", Args: []string{"runtime_path=/run/pyxis", "sbatch_support=1"}},
+			{Path: "spank_foo.so", Optional: true},
+		},
+	}
+	want := strings.Join([]string{
+		"required /usr/lib/x86_64-linux-gnu/slurm/spank_pyxis.so runtime_path=/run/pyxis sbatch_support=1",
+		"optional spank_foo.so",
+	}, "\n")
+	if got := buildSpankConf(spank); got != want {
+		t.Errorf("buildSpankConf() = %q, want %q", got, want)
+	}
+}
+
+func TestNodeCapacity(t *testing.T) {
+	newNodeSet := func(name string, replicas int32) *slinkyv1beta1.NodeSet {
+		return &slinkyv1beta1.NodeSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: corev1.NamespaceDefault,
+			},
+			Spec: slinkyv1beta1.NodeSetSpec{
+				Replicas: ptr.To(replicas),
+			},
+		}
+	}
+	newHPA := func(name, target string, maxReplicas int32) *autoscalingv2.HorizontalPodAutoscaler {
+		return &autoscalingv2.HorizontalPodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: corev1.NamespaceDefault,
+			},
+			Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+				ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+					APIVersion: slinkyv1beta1.NodeSetAPIVersion,
+					Kind:       slinkyv1beta1.NodeSetKind,
+					Name:       target,
+				},
+				MaxReplicas: maxReplicas,
+			},
+		}
+	}
+	tests := []struct {
+		name     string
+		objs     []client.Object
+		nodesets []*slinkyv1beta1.NodeSet
+		want     int32
+	}{
+		{
+			name: "empty",
+			want: 0,
+		},
+		{
+			name:     "replicas",
+			nodesets: []*slinkyv1beta1.NodeSet{newNodeSet("foo", 600), newNodeSet("bar", 500)},
+			want:     1100,
+		},
+		{
+			name:     "autoscaled",
+			objs:     []client.Object{newHPA("foo", "foo", 2000), newHPA("bar", "bar", 100)},
+			nodesets: []*slinkyv1beta1.NodeSet{newNodeSet("foo", 10), newNodeSet("bar", 500)},
+			want:     2500,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithObjects(tt.objs...).Build()
+			nodesetList := &slinkyv1beta1.NodeSetList{}
+			for _, nodeset := range tt.nodesets {
+				nodesetList.Items = append(nodesetList.Items, *nodeset)
+			}
+			got, err := NodeCapacity(context.TODO(), refresolver.New(c), nodesetList)
+			if err != nil {
+				t.Fatalf("NodeCapacity() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("NodeCapacity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaxNodeCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		headroom int32
+		capacity int32
+		want     int32
+	}{
+		{
+			name:     "minimum",
+			capacity: 10,
+			want:     MinMaxNodeCount,
+		},
+		{
+			name:     "capacity",
+			capacity: 1500,
+			want:     2048,
+		},
+		{
+			name:     "power of two",
+			capacity: 2048,
+			want:     2048,
+		},
+		{
+			name:     "headroom",
+			headroom: 100,
+			capacity: 2000,
+			want:     4096,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := &slinkyv1beta1.Controller{
+				Spec: slinkyv1beta1.ControllerSpec{
+					NodeCountHeadroom: tt.headroom,
+				},
+			}
+			if got := MaxNodeCount(controller, tt.capacity); got != tt.want {
+				t.Errorf("MaxNodeCount() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_buildSchedulingConf(t *testing.T) {
+	tests := []struct {
+		name       string
+		scheduling slinkyv1beta1.ControllerScheduling
+		want       string
+	}{
+		{
+			name: "empty",
+			want: "",
+		},
+		{
+			name: "scheduler",
+			scheduling: slinkyv1beta1.ControllerScheduling{
+				SchedulerType:       slinkyv1beta1.SchedulerTypeBackfill,
+				SchedulerParameters: []string{"bf_continue", "bf_window=4320"},
+			},
+			want: strings.Join([]string{
+				"#",
+				"### SCHEDULING ###",
+				"SchedulerType=sched/backfill",
+				"SchedulerParameters=bf_continue,bf_window=4320",
+			}, "\n"),
+		},
+		{
+			name: "multifactor and preemption",
+			scheduling: slinkyv1beta1.ControllerScheduling{
+				Priority: slinkyv1beta1.ControllerPriority{
+					Type:          slinkyv1beta1.PriorityTypeMultifactor,
+					DecayHalfLife: "7-0",
+					Weights: slinkyv1beta1.PriorityWeights{
+						Age:       ptr.To[int64](1000),
+						FairShare: ptr.To[int64](10000),
+						QOS:       ptr.To[int64](0),
+						TRES: map[string]int64{
+							"GRES/gpu": 3000,
+							"CPU":      1000,
+							"Mem":      2000,
+						},
+					},
+				},
+				Preempt: slinkyv1beta1.ControllerPreempt{
+					Type: slinkyv1beta1.PreemptTypeQOS,
+					Mode: []slinkyv1beta1.PreemptMode{slinkyv1beta1.PreemptModeSuspend, slinkyv1beta1.PreemptModeGang},
+				},
+			},
+			want: strings.Join([]string{
+				"#",
+				"### SCHEDULING ###",
+				"PriorityType=priority/multifactor",
+				"PriorityDecayHalfLife=7-0",
+				"PriorityWeightAge=1000",
+				"PriorityWeightFairshare=10000",
+				"PriorityWeightQOS=0",
+				"PriorityWeightTRES=CPU=1000,GRES/gpu=3000,Mem=2000",
+				"PreemptType=preempt/qos",
+				"PreemptMode=SUSPEND,GANG",
+			}, "\n"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildSchedulingConf(tt.scheduling); got != tt.want {
+				t.Errorf("buildSchedulingConf() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_buildLicenses(t *testing.T) {
+	licenses := []slinkyv1beta1.License{
+		{Name: "matlab", Count: 10},
+		{Name: "fluent", Count: 0},
+	}
+	want := "matlab:10,fluent:0"
+	if got := buildLicenses(licenses); got != want {
+		t.Errorf("buildLicenses() = %q, want %q", got, want)
+	}
+}
+
+func Test_buildNodeSetScriptConf(t *testing.T) {
+	tests := []struct {
+		name        string
+		controller  *slinkyv1beta1.Controller
+		nodesetList *slinkyv1beta1.NodeSetList
+		want        string
+	}{
+		{
+			name:        "empty",
+			nodesetList: &slinkyv1beta1.NodeSetList{},
+			want:        "",
+		},
+		{
+			name: "prolog and epilog",
+			nodesetList: &slinkyv1beta1.NodeSetList{
+				Items: []slinkyv1beta1.NodeSet{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "cpu"},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "gpu"},
+						Spec: slinkyv1beta1.NodeSetSpec{
+							PrologScriptRefs: []slinkyv1beta1.ObjectReference{{Name: "gpu-prolog"}},
+							EpilogScriptRefs: []slinkyv1beta1.ObjectReference{{Name: "gpu-epilog"}},
+						},
+					},
+				},
+			},
+			want: strings.Join([]string{
+				"#",
+				"### NODESET PROLOG, EPILOG & HEALTH CHECK ###",
+				"Prolog=nodeset-prolog.sh",
+				"Epilog=nodeset-epilog.sh",
+			}, "\n"),
+		},
+		{
+			name: "health check",
+			controller: &slinkyv1beta1.Controller{
+				Spec: slinkyv1beta1.ControllerSpec{
+					HealthCheckIntervalSeconds: 60,
+				},
+			},
+			nodesetList: &slinkyv1beta1.NodeSetList{
+				Items: []slinkyv1beta1.NodeSet{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "cpu"},
+						Spec: slinkyv1beta1.NodeSetSpec{
+							HealthCheck: slinkyv1beta1.NodeSetHealthCheck{
+								ScriptRefs: []slinkyv1beta1.ObjectReference{{Name: "cpu-healthcheck"}},
+							},
+						},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "gpu"},
+						Spec: slinkyv1beta1.NodeSetSpec{
+							HealthCheck: slinkyv1beta1.NodeSetHealthCheck{
+								ScriptRefs: []slinkyv1beta1.ObjectReference{{Name: "gpu-healthcheck"}},
+							},
+						},
+					},
+				},
+			},
+			want: strings.Join([]string{
+				"#",
+				"### NODESET PROLOG, EPILOG & HEALTH CHECK ###",
+				"HealthCheckProgram=/etc/slurm/nodeset-healthcheck.sh",
+				"HealthCheckInterval=60",
+			}, "\n"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := tt.controller
+			if controller == nil {
+				controller = &slinkyv1beta1.Controller{}
+			}
+			if got := buildNodeSetScriptConf(controller, tt.nodesetList); got != tt.want {
+				t.Errorf("buildNodeSetScriptConf() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_healthCheckInterval(t *testing.T) {
+	newNodeSet := func(scriptRefs ...slinkyv1beta1.ObjectReference) slinkyv1beta1.NodeSet {
+		return slinkyv1beta1.NodeSet{
+			Spec: slinkyv1beta1.NodeSetSpec{
+				HealthCheck: slinkyv1beta1.NodeSetHealthCheck{
+					ScriptRefs: scriptRefs,
+				},
+			},
+		}
+	}
+	ref := slinkyv1beta1.ObjectReference{Name: "healthcheck"}
+	tests := []struct {
+		name            string
+		intervalSeconds int32
+		nodesets        []slinkyv1beta1.NodeSet
+		want            int32
+	}{
+		{
+			name:            "no health checks",
+			intervalSeconds: 60,
+			nodesets: []slinkyv1beta1.NodeSet{
+				newNodeSet(),
+			},
+			want: 0,
+		},
+		{
+			name: "default",
+			nodesets: []slinkyv1beta1.NodeSet{
+				newNodeSet(ref),
+			},
+			want: DefaultHealthCheckInterval,
+		},
+		{
+			name:            "controller",
+			intervalSeconds: 120,
+			nodesets: []slinkyv1beta1.NodeSet{
+				newNodeSet(ref),
+				newNodeSet(),
+			},
+			want: 120,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := &slinkyv1beta1.Controller{
+				Spec: slinkyv1beta1.ControllerSpec{
+					HealthCheckIntervalSeconds: tt.intervalSeconds,
+				},
+			}
+			nodesetList := &slinkyv1beta1.NodeSetList{Items: tt.nodesets}
+			if got := healthCheckInterval(controller, nodesetList); got != tt.want {
+				t.Errorf("healthCheckInterval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
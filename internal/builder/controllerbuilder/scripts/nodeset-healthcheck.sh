@@ -0,0 +1,45 @@
+#!/usr/bin/env bash
+# SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+# SPDX-License-Identifier: Apache-2.0
+
+# Runs the NodeSet health checks, which are only mounted into the pods of their
+# NodeSet. A failing check drains the node, and the drain is lifted once all
+# checks pass again. The reason prefix is recognized by slurm-operator.
+
+set -uo pipefail
+
+HEALTHCHECK_DIR="/etc/slurm/nodeset.d/healthcheck.d"
+REASON_PREFIX="slurm-operator: HealthCheck"
+NODENAME="$(hostname)"
+
+function drain() {
+	local reason="$1"
+	echo "[$(date)] Draining node: $reason"
+	scontrol update nodename="$NODENAME" state=drain reason="$REASON_PREFIX $reason"
+}
+
+function undrain() {
+	if scontrol show node --oneliner "$NODENAME" | grep -q "Reason=$REASON_PREFIX"; then
+		echo "[$(date)] Health checks passed, undraining node"
+		scontrol update nodename="$NODENAME" state=undrain
+	fi
+}
+
+function main() {
+	local script=""
+	local output=""
+	local checks=0
+
+	for script in "$HEALTHCHECK_DIR"/*; do
+		[ -f "$script" ] || continue
+		checks=$((checks + 1))
+		if ! output="$("$script" 2>&1)"; then
+			drain "$(basename "$script") failed: $(echo "$output" | tail -n 1 | cut -c 1-128)"
+			exit 1
+		fi
+	done
+	if [ "$checks" -gt 0 ]; then
+		undrain
+	fi
+}
+main
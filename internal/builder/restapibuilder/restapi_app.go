@@ -27,6 +27,18 @@ import (
 const (
 	SlurmrestdPort = 6820
 
+	// slurmrestdLocalPort is where slurmrestd listens on localhost, behind the
+	// TLS proxy.
+	slurmrestdLocalPort = 6821
+	// tlsProxyStatusPort is where the TLS proxy serves its status, which
+	// checks that slurmrestd accepts connections.
+	tlsProxyStatusPort = 6822
+
+	tlsProxyName   = "tls-proxy"
+	tlsVolume      = "restapi-tls"
+	tlsDir         = "/etc/restapi/tls"
+	tlsReloadEvery = "1m"
+
 	slurmrestdUser    = "nobody"
 	slurmrestdUserUid = int64(65534)
 	slurmrestdUserGid = slurmrestdUserUid
@@ -92,6 +104,7 @@ func (b *RestapiBuilder) restapiPodTemplate(restapi *slinkyv1beta1.RestApi) (cor
 
 	spec := restapi.Spec
 	template := spec.Template.PodSpecWrapper
+	tlsEnabled := spec.TLS.Enabled
 
 	opts := common.PodTemplateOpts{
 		Key: key,
@@ -102,7 +115,7 @@ func (b *RestapiBuilder) restapiPodTemplate(restapi *slinkyv1beta1.RestApi) (cor
 		Base: corev1.PodSpec{
 			AutomountServiceAccountToken: ptr.To(false),
 			Containers: []corev1.Container{
-				b.slurmrestdContainer(spec.Slurmrestd.Container, hasAccounting),
+				b.slurmrestdContainer(spec.Slurmrestd.Container, hasAccounting, tlsEnabled),
 			},
 			SecurityContext: &corev1.PodSecurityContext{
 				RunAsNonRoot: ptr.To(true),
@@ -110,15 +123,18 @@ func (b *RestapiBuilder) restapiPodTemplate(restapi *slinkyv1beta1.RestApi) (cor
 				RunAsGroup:   ptr.To(slurmrestdUserGid),
 				FSGroup:      ptr.To(slurmrestdUserGid),
 			},
-			Volumes: restapiVolumes(controller),
+			Volumes: restapiVolumes(restapi, controller),
 		},
 		Merge: template.PodSpec,
 	}
+	if tlsEnabled {
+		opts.Base.InitContainers = append(opts.Base.InitContainers, b.tlsProxyContainer(spec.TLS.Proxy.Container))
+	}
 
 	return b.CommonBuilder.BuildPodTemplate(opts), nil
 }
 
-func restapiVolumes(controller *slinkyv1beta1.Controller) []corev1.Volume {
+func restapiVolumes(restapi *slinkyv1beta1.RestApi, controller *slinkyv1beta1.Controller) []corev1.Volume {
 	out := []corev1.Volume{
 		{
 			Name: common.SlurmEtcVolume,
@@ -151,10 +167,25 @@ func restapiVolumes(controller *slinkyv1beta1.Controller) []corev1.Volume {
 			},
 		},
 	}
+	if restapi.Spec.TLS.Enabled {
+		out = append(out, corev1.Volume{
+			Name: tlsVolume,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName:  restapi.TLSRef().Name,
+					DefaultMode: ptr.To[int32](0o600),
+					Items: []corev1.KeyToPath{
+						{Key: TLSCertFile, Path: TLSCertFile},
+						{Key: TLSKeyFile, Path: TLSKeyFile},
+					},
+				},
+			},
+		})
+	}
 	return out
 }
 
-func (b *RestapiBuilder) slurmrestdContainer(merge corev1.Container, hasAccounting bool) corev1.Container {
+func (b *RestapiBuilder) slurmrestdContainer(merge corev1.Container, hasAccounting, tlsEnabled bool) corev1.Container {
 	opts := common.ContainerOpts{
 		Base: corev1.Container{
 			Name: labels.RestapiApp,
@@ -210,14 +241,94 @@ func (b *RestapiBuilder) slurmrestdContainer(merge corev1.Container, hasAccounti
 		Merge: merge,
 	}
 
+	if tlsEnabled {
+		// The TLS proxy serves the slurmrestd port. Kubelet cannot reach
+		// slurmrestd on localhost, so it is probed through the status of the
+		// proxy, which connects to slurmrestd.
+		probe := corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path:   "/_status",
+				Port:   intstr.FromInt(tlsProxyStatusPort),
+				Scheme: corev1.URISchemeHTTPS,
+			},
+		}
+		opts.Base.Ports = nil
+		opts.Base.StartupProbe.ProbeHandler = probe
+		opts.Base.LivenessProbe.ProbeHandler = probe
+		opts.Base.ReadinessProbe.ProbeHandler = probe
+	}
+
 	out := b.CommonBuilder.BuildContainer(opts)
 
 	// Usage: slurmrestd [OPTIONS] [host:port]...
-	out.Args = append(out.Args, fmt.Sprintf("0.0.0.0:%d", SlurmrestdPort))
+	if tlsEnabled {
+		out.Args = append(out.Args, fmt.Sprintf("127.0.0.1:%d", slurmrestdLocalPort))
+	} else {
+		out.Args = append(out.Args, fmt.Sprintf("0.0.0.0:%d", SlurmrestdPort))
+	}
 
 	return out
 }
 
+// tlsProxyContainer terminates TLS on the slurmrestd port, and forwards to
+// slurmrestd on localhost. Renewed certificates are reloaded periodically.
+func (b *RestapiBuilder) tlsProxyContainer(merge corev1.Container) corev1.Container {
+	probe := corev1.ProbeHandler{
+		TCPSocket: &corev1.TCPSocketAction{
+			Port: intstr.FromInt(SlurmrestdPort),
+		},
+	}
+	opts := common.ContainerOpts{
+		Base: corev1.Container{
+			Name: tlsProxyName,
+			Args: []string{
+				"server",
+				fmt.Sprintf("--listen=0.0.0.0:%d", SlurmrestdPort),
+				fmt.Sprintf("--target=127.0.0.1:%d", slurmrestdLocalPort),
+				fmt.Sprintf("--cert=%s/%s", tlsDir, TLSCertFile),
+				fmt.Sprintf("--key=%s/%s", tlsDir, TLSKeyFile),
+				fmt.Sprintf("--timed-reload=%s", tlsReloadEvery),
+				// The status is served with the serving certificate.
+				fmt.Sprintf("--status=0.0.0.0:%d", tlsProxyStatusPort),
+				// Clients authenticate to slurmrestd with JWTs.
+				"--disable-authentication",
+			},
+			Ports: []corev1.ContainerPort{
+				{
+					Name:          labels.RestapiApp,
+					ContainerPort: SlurmrestdPort,
+					Protocol:      corev1.ProtocolTCP,
+				},
+			},
+			RestartPolicy: ptr.To(corev1.ContainerRestartPolicyAlways),
+			StartupProbe: &corev1.Probe{
+				ProbeHandler:     probe,
+				FailureThreshold: 6,
+				PeriodSeconds:    10,
+			},
+			LivenessProbe: &corev1.Probe{
+				ProbeHandler:     probe,
+				FailureThreshold: 6,
+				PeriodSeconds:    10,
+			},
+			ReadinessProbe: &corev1.Probe{
+				ProbeHandler: probe,
+			},
+			SecurityContext: &corev1.SecurityContext{
+				RunAsNonRoot: ptr.To(true),
+				RunAsUser:    ptr.To(slurmrestdUserUid),
+				RunAsGroup:   ptr.To(slurmrestdUserGid),
+			},
+			VolumeMounts: []corev1.VolumeMount{
+				{Name: tlsVolume, MountPath: tlsDir, ReadOnly: true},
+			},
+		},
+		Merge: merge,
+	}
+
+	return b.CommonBuilder.BuildContainer(opts)
+}
+
 func slurmrestdArgs(hasAccounting bool) []string {
 	args := []string{}
 	if !hasAccounting {
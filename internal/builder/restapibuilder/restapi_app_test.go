@@ -5,10 +5,12 @@ package restapibuilder
 
 import (
 	_ "embed"
+	"slices"
 	"testing"
 
 	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
 	"github.com/SlinkyProject/slurm-operator/internal/builder/labels"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 	"k8s.io/utils/set"
@@ -111,3 +113,76 @@ func TestBuilder_BuildRestapi(t *testing.T) {
 		})
 	}
 }
+
+func TestBuilder_BuildRestapi_TLS(t *testing.T) {
+	c := fake.NewClientBuilder().
+		WithObjects(&slinkyv1beta1.Controller{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "slurm",
+			},
+		}).
+		Build()
+	restapi := &slinkyv1beta1.RestApi{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "slurm",
+		},
+		Spec: slinkyv1beta1.RestApiSpec{
+			ControllerRef: slinkyv1beta1.ObjectReference{
+				Name: "slurm",
+			},
+			TLS: slinkyv1beta1.RestApiTLS{
+				Enabled: true,
+			},
+		},
+	}
+
+	b := New(c)
+	got, err := b.BuildRestapi(restapi)
+	if err != nil {
+		t.Fatalf("Builder.BuildRestapi() error = %v", err)
+	}
+	podSpec := got.Spec.Template.Spec
+
+	slurmrestd := podSpec.Containers[0]
+	if len(slurmrestd.Ports) != 0 {
+		t.Errorf("Containers[0].Ports = %v, want none", slurmrestd.Ports)
+	}
+	if want := "127.0.0.1:6821"; !slices.Contains(slurmrestd.Args, want) {
+		t.Errorf("Containers[0].Args = %v, want %v", slurmrestd.Args, want)
+	}
+	for _, probe := range []*corev1.Probe{slurmrestd.StartupProbe, slurmrestd.LivenessProbe, slurmrestd.ReadinessProbe} {
+		if probe == nil || probe.HTTPGet == nil || probe.HTTPGet.Port.IntValue() != tlsProxyStatusPort {
+			t.Errorf("Containers[0] probe = %v, want the proxy status on port %v", probe, tlsProxyStatusPort)
+		}
+	}
+
+	if len(podSpec.InitContainers) != 1 {
+		t.Fatalf("InitContainers = %v, want the TLS proxy", podSpec.InitContainers)
+	}
+	proxy := podSpec.InitContainers[0]
+	switch {
+	case proxy.Name != tlsProxyName:
+		t.Errorf("InitContainers[0].Name = %v, want %v", proxy.Name, tlsProxyName)
+	case ptr.Deref(proxy.RestartPolicy, "") != corev1.ContainerRestartPolicyAlways:
+		t.Errorf("InitContainers[0].RestartPolicy = %v, want %v", proxy.RestartPolicy, corev1.ContainerRestartPolicyAlways)
+	case len(proxy.Ports) != 1 || proxy.Ports[0].Name != labels.RestapiApp || proxy.Ports[0].ContainerPort != SlurmrestdPort:
+		t.Errorf("InitContainers[0].Ports = %v, want %v:%v", proxy.Ports, labels.RestapiApp, SlurmrestdPort)
+	case proxy.ReadinessProbe == nil:
+		t.Errorf("InitContainers[0].ReadinessProbe = nil")
+	case !slices.Contains(proxy.Args, "--status=0.0.0.0:6822"):
+		t.Errorf("InitContainers[0].Args = %v, want the status port", proxy.Args)
+	}
+
+	idx := slices.IndexFunc(podSpec.Volumes, func(v corev1.Volume) bool { return v.Name == tlsVolume })
+	if idx < 0 {
+		t.Fatalf("Volumes = %v, want %v", podSpec.Volumes, tlsVolume)
+	}
+	if name := podSpec.Volumes[idx].Secret.SecretName; name != restapi.TLSRef().Name {
+		t.Errorf("Volumes[%d].Secret.SecretName = %v, want %v", idx, name, restapi.TLSRef().Name)
+	}
+	for _, item := range podSpec.Volumes[idx].Secret.Items {
+		if item.Key == TLSCaKeyFile {
+			t.Errorf("Volumes[%d].Secret.Items = %v, must not mount %v", idx, podSpec.Volumes[idx].Secret.Items, TLSCaKeyFile)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package restapibuilder
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder/common"
+	"github.com/SlinkyProject/slurm-operator/internal/builder/labels"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/crypto"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/structutils"
+)
+
+const (
+	TLSCertFile   = corev1.TLSCertKey
+	TLSKeyFile    = corev1.TLSPrivateKeyKey
+	TLSCaCertFile = "ca.crt"
+	TLSCaKeyFile  = "ca.key"
+
+	// TLSCaValidity is the validity of a generated CA.
+	TLSCaValidity = 5 * 365 * 24 * time.Hour
+	// TLSCertValidity is the validity of a generated serving certificate.
+	TLSCertValidity = 90 * 24 * time.Hour
+)
+
+// BuildRestapiTLS builds the secret with the generated CA and serving
+// certificate of the restapi. The certificates of the existing secret are kept
+// until they are due for renewal.
+func (b *RestapiBuilder) BuildRestapiTLS(restapi *slinkyv1beta1.RestApi, existing *corev1.Secret) (*corev1.Secret, error) {
+	data, err := restapiTLSData(restapi, existing, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	opts := common.SecretOpts{
+		Key: restapi.TLSKey(),
+		Metadata: slinkyv1beta1.Metadata{
+			Annotations: restapi.Annotations,
+			Labels:      structutils.MergeMaps(restapi.Labels, labels.NewBuilder().WithRestapiLabels(restapi).Build()),
+		},
+		Data: data,
+	}
+
+	return b.CommonBuilder.BuildSecret(opts, restapi)
+}
+
+func restapiTLSData(restapi *slinkyv1beta1.RestApi, existing *corev1.Secret, now time.Time) (map[string][]byte, error) {
+	dnsNames := RestapiDNSNames(restapi)
+
+	var ca *crypto.Certificate
+	if existing != nil {
+		ca = &crypto.Certificate{
+			Cert: existing.Data[TLSCaCertFile],
+			Key:  existing.Data[TLSCaKeyFile],
+		}
+		cert := &crypto.Certificate{
+			Cert: existing.Data[TLSCertFile],
+			Key:  existing.Data[TLSKeyFile],
+		}
+		if err := crypto.VerifyServingCertificate(ca.Cert, cert, dnsNames, now); err == nil {
+			return existing.Data, nil
+		}
+		// Keep the CA while it is valid, so clients keep trusting the RestApi.
+		if err := crypto.VerifyCertificateAuthority(ca, now); err != nil {
+			ca = nil
+		}
+	}
+
+	if ca == nil {
+		var err error
+		ca, err = crypto.NewCertificateAuthority(restapi.Key().Name+"-ca", TLSCaValidity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create CA: %w", err)
+		}
+	}
+	cert, err := crypto.NewServingCertificate(ca, restapi.ServiceKey().Name, dnsNames, TLSCertValidity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create serving certificate: %w", err)
+	}
+
+	data := map[string][]byte{
+		TLSCaCertFile: ca.Cert,
+		TLSCaKeyFile:  ca.Key,
+		TLSCertFile:   cert.Cert,
+		TLSKeyFile:    cert.Key,
+	}
+	return data, nil
+}
+
+// RestapiDNSNames returns the names of the restapi service that a serving
+// certificate must cover.
+func RestapiDNSNames(restapi *slinkyv1beta1.RestApi) []string {
+	key := restapi.ServiceKey()
+	return []string{
+		key.Name,
+		restapi.ServiceFQDNShort(),
+		fmt.Sprintf("%s.%s.svc", key.Name, key.Namespace),
+		restapi.ServiceFQDN(),
+	}
+}
+
+// RestapiTLSRenewalTime returns when the serving certificate in the secret is
+// due for renewal.
+func RestapiTLSRenewalTime(secret *corev1.Secret) (time.Time, error) {
+	cert, err := crypto.ParseCertificate(secret.Data[TLSCertFile])
+	if err != nil {
+		return time.Time{}, err
+	}
+	return crypto.RenewalTime(cert), nil
+}
@@ -0,0 +1,142 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package restapibuilder
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/crypto"
+)
+
+func newTLSRestapi(name string) *slinkyv1beta1.RestApi {
+	return &slinkyv1beta1.RestApi{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "slurm",
+			Name:      name,
+		},
+		Spec: slinkyv1beta1.RestApiSpec{
+			TLS: slinkyv1beta1.RestApiTLS{
+				Enabled: true,
+			},
+		},
+	}
+}
+
+func TestBuilder_BuildRestapiTLS(t *testing.T) {
+	restapi := newTLSRestapi("slurm")
+	b := New(fake.NewFakeClient())
+	got, err := b.BuildRestapiTLS(restapi, nil)
+	if err != nil {
+		t.Fatalf("Builder.BuildRestapiTLS() error = %v", err)
+	}
+	if got.Name != restapi.TLSKey().Name {
+		t.Errorf("Name = %v, want %v", got.Name, restapi.TLSKey().Name)
+	}
+	cert := &crypto.Certificate{
+		Cert: got.Data[TLSCertFile],
+		Key:  got.Data[TLSKeyFile],
+	}
+	if err := crypto.VerifyServingCertificate(got.Data[TLSCaCertFile], cert, RestapiDNSNames(restapi), time.Now()); err != nil {
+		t.Errorf("VerifyServingCertificate() error = %v", err)
+	}
+}
+
+func Test_restapiTLSData(t *testing.T) {
+	restapi := newTLSRestapi("slurm")
+	now := time.Now()
+	current, err := restapiTLSData(restapi, nil, now)
+	if err != nil {
+		t.Fatalf("restapiTLSData() error = %v", err)
+	}
+	existing := &corev1.Secret{Data: current}
+
+	type args struct {
+		restapi  *slinkyv1beta1.RestApi
+		existing *corev1.Secret
+		now      time.Time
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantCert bool
+		wantCa   bool
+	}{
+		{
+			name: "Keep",
+			args: args{
+				restapi:  restapi,
+				existing: existing,
+				now:      now,
+			},
+			wantCert: true,
+			wantCa:   true,
+		},
+		{
+			name: "Renew certificate",
+			args: args{
+				restapi:  restapi,
+				existing: existing,
+				now:      now.Add(TLSCertValidity),
+			},
+			wantCa: true,
+		},
+		{
+			name: "Renew CA",
+			args: args{
+				restapi:  restapi,
+				existing: existing,
+				now:      now.Add(TLSCaValidity),
+			},
+		},
+		{
+			name: "Renamed",
+			args: args{
+				restapi:  newTLSRestapi("other"),
+				existing: existing,
+				now:      now,
+			},
+			wantCa: true,
+		},
+		{
+			name: "Invalid",
+			args: args{
+				restapi: restapi,
+				existing: &corev1.Secret{Data: map[string][]byte{
+					TLSCaCertFile: []byte("foo"),
+				}},
+				now: now,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := restapiTLSData(tt.args.restapi, tt.args.existing, tt.args.now)
+			if err != nil {
+				t.Fatalf("restapiTLSData() error = %v", err)
+			}
+			if sameCert := bytes.Equal(got[TLSCertFile], current[TLSCertFile]); sameCert != tt.wantCert {
+				t.Errorf("restapiTLSData() kept certificate = %v, want %v", sameCert, tt.wantCert)
+			}
+			if sameCa := bytes.Equal(got[TLSCaCertFile], current[TLSCaCertFile]); sameCa != tt.wantCa {
+				t.Errorf("restapiTLSData() kept CA = %v, want %v", sameCa, tt.wantCa)
+			}
+			cert := &crypto.Certificate{
+				Cert: got[TLSCertFile],
+				Key:  got[TLSKeyFile],
+			}
+			if !tt.wantCert {
+				if err := crypto.VerifyServingCertificate(got[TLSCaCertFile], cert, RestapiDNSNames(tt.args.restapi), time.Now()); err != nil {
+					t.Errorf("VerifyServingCertificate() error = %v", err)
+				}
+			}
+		})
+	}
+}
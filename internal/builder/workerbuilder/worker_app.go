@@ -7,19 +7,24 @@ import (
 	"context"
 	_ "embed"
 	"fmt"
+	"path"
 	"sort"
+	"strconv"
 	"strings"
 
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
+	"k8s.io/utils/set"
 
 	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
 	"github.com/SlinkyProject/slurm-operator/internal/builder/common"
+	"github.com/SlinkyProject/slurm-operator/internal/builder/controllerbuilder"
 	"github.com/SlinkyProject/slurm-operator/internal/builder/labels"
 	loginbuilder "github.com/SlinkyProject/slurm-operator/internal/builder/loginbuilder"
 	"github.com/SlinkyProject/slurm-operator/internal/builder/metadata"
@@ -37,6 +42,17 @@ func (b *WorkerBuilder) BuildWorkerPodTemplate(nodeset *slinkyv1beta1.NodeSet, c
 		return corev1.PodTemplateSpec{}
 	}
 
+	scriptProjections, err := b.nodesetScriptProjections(ctx, nodeset, controller)
+	if err != nil {
+		return corev1.PodTemplateSpec{}
+	}
+	volumes := nodesetVolumes(nodeset, controller)
+	for i := range volumes {
+		if volumes[i].Name == common.SlurmEtcVolume {
+			volumes[i].Projected.Sources = append(volumes[i].Projected.Sources, scriptProjections...)
+		}
+	}
+
 	objectMeta := metadata.NewBuilder(key).
 		WithAnnotations(nodeset.Annotations).
 		WithLabels(nodeset.Labels).
@@ -72,7 +88,7 @@ func (b *WorkerBuilder) BuildWorkerPodTemplate(nodeset *slinkyv1beta1.NodeSet, c
 			InitContainers: []corev1.Container{
 				b.CommonBuilder.LogfileContainer(spec.LogFile, common.SlurmdLogFilePath),
 			},
-			Volumes: nodesetVolumes(nodeset, controller),
+			Volumes: volumes,
 			Tolerations: []corev1.Toleration{
 				slurmtaints.TolerationWorkerNode,
 			},
@@ -108,6 +124,21 @@ func nodesetVolumes(nodeset *slinkyv1beta1.NodeSet, controller *slinkyv1beta1.Co
 		common.LogFileVolume(),
 	}
 
+	// Add the NodeSet SPANK plugins, included by `plugstack.conf`
+	if len(nodeset.Spec.Spank.Plugins) > 0 {
+		volumeProjection := corev1.VolumeProjection{
+			ConfigMap: &corev1.ConfigMapProjection{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: controller.ConfigKey().Name,
+				},
+				Items: []corev1.KeyToPath{
+					{Key: controllerbuilder.PlugstackNodeSetConfKey(nodeset), Path: controllerbuilder.PlugstackNodeSetConfPath(nodeset)},
+				},
+			},
+		}
+		out[0].Projected.Sources = append(out[0].Projected.Sources, volumeProjection)
+	}
+
 	// Add SSH host keys volume if SSH is enabled
 	if nodeset.Spec.Ssh.Enabled {
 		out = structutils.MergeList(out, []corev1.Volume{
@@ -157,6 +188,74 @@ func nodesetVolumes(nodeset *slinkyv1beta1.NodeSet, controller *slinkyv1beta1.Co
 	return out
 }
 
+// nodesetScriptProjections returns the projections of the prolog, epilog, and
+// health check scripts of the NodeSet into `/etc/slurm`. The health check
+// runner is projected into the pods of every NodeSet when the Controller
+// configures one, as `HealthCheckProgram` applies to all nodes. Its hash is
+// part of the NodeSet revision (see BuildWorkerRevisionHashes).
+func (b *WorkerBuilder) nodesetScriptProjections(ctx context.Context, nodeset *slinkyv1beta1.NodeSet, controller *slinkyv1beta1.Controller) ([]corev1.VolumeProjection, error) {
+	out := []corev1.VolumeProjection{}
+
+	scriptDirs := []struct {
+		dir  string
+		refs []slinkyv1beta1.ObjectReference
+	}{
+		{dir: controllerbuilder.NodeSetPrologDir, refs: nodeset.Spec.PrologScriptRefs},
+		{dir: controllerbuilder.NodeSetEpilogDir, refs: nodeset.Spec.EpilogScriptRefs},
+		{dir: controllerbuilder.NodeSetHealthCheckDir, refs: nodeset.Spec.HealthCheck.ScriptRefs},
+	}
+	for _, scriptDir := range scriptDirs {
+		for _, ref := range scriptDir.refs {
+			configMap := &corev1.ConfigMap{}
+			key := types.NamespacedName{
+				Namespace: nodeset.Namespace,
+				Name:      ref.Name,
+			}
+			if err := b.client.Get(ctx, key, configMap); err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return nil, fmt.Errorf("failed to get object (%s): %w", klog.KObj(configMap), err)
+			}
+			filenames := structutils.Keys(configMap.Data)
+			sort.Strings(filenames)
+			items := make([]corev1.KeyToPath, 0, len(filenames))
+			for _, filename := range filenames {
+				items = append(items, corev1.KeyToPath{
+					Key:  filename,
+					Path: path.Join(scriptDir.dir, filename),
+					Mode: ptr.To[int32](0o700),
+				})
+			}
+			out = append(out, corev1.VolumeProjection{
+				ConfigMap: &corev1.ConfigMapProjection{
+					LocalObjectReference: corev1.LocalObjectReference{Name: ref.Name},
+					Items:                items,
+				},
+			})
+		}
+	}
+
+	slurmConfig := &corev1.ConfigMap{}
+	if err := b.client.Get(ctx, controller.ConfigKey(), slurmConfig); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get object (%s): %w", klog.KObj(slurmConfig), err)
+		}
+	}
+	if _, ok := slurmConfig.Data[controllerbuilder.NodeSetHealthCheckFile]; ok {
+		out = append(out, corev1.VolumeProjection{
+			ConfigMap: &corev1.ConfigMapProjection{
+				LocalObjectReference: corev1.LocalObjectReference{Name: controller.ConfigKey().Name},
+				Items: []corev1.KeyToPath{
+					{Key: controllerbuilder.NodeSetHealthCheckFile, Path: controllerbuilder.NodeSetHealthCheckFile, Mode: ptr.To[int32](0o700)},
+				},
+			},
+		})
+	}
+
+	return out, nil
+}
+
 func (b *WorkerBuilder) slurmdContainer(nodeset *slinkyv1beta1.NodeSet, controller *slinkyv1beta1.Controller) corev1.Container {
 	merge := nodeset.Spec.Slurmd.Container
 
@@ -307,6 +406,18 @@ func slurmdConfArgs(nodeset *slinkyv1beta1.NodeSet) []string {
 		}
 	}
 
+	// Do not mix a derived CPU topology with a user provided one.
+	userTopology := hasConfKey(confMap, cpuTopologyKeys.UnsortedList()...)
+	for _, item := range slurmdResourceConf(nodeset) {
+		if hasConfKey(confMap, item.key) {
+			continue
+		}
+		if userTopology && cpuTopologyKeys.Has(strings.ToLower(item.key)) {
+			continue
+		}
+		confMap[item.key] = item.value
+	}
+
 	confList := []string{}
 	for key, val := range confMap {
 		confList = append(confList, fmt.Sprintf("%s=%s", key, val))
@@ -321,6 +432,74 @@ func slurmdConfArgs(nodeset *slinkyv1beta1.NodeSet) []string {
 	return args
 }
 
+// cpuTopologyKeys are the node parameters (lowercase) that define the CPU topology.
+var cpuTopologyKeys = set.New(
+	"cpus",
+	"boards",
+	"sockets",
+	"socketsperboard",
+	"corespersocket",
+	"threadspercore",
+)
+
+// confItem is a single slurmd node parameter.
+type confItem struct {
+	key   string
+	value string
+}
+
+// hasConfKey returns true if any of the keys are in confMap, ignoring case.
+func hasConfKey(confMap map[string]string, keys ...string) bool {
+	for k := range confMap {
+		for _, key := range keys {
+			if strings.EqualFold(k, key) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// slurmdResourceConf derives the Slurm node definition from the slurmd
+// container's resource limits, so that Slurm does not allocate more than the
+// pod cgroup allows. Without limits, slurmd reports the hardware of the
+// Kubernetes node.
+//
+// The CPU topology is only derived for a whole number of CPUs, which is
+// presented as a single socket of single threaded cores.
+// Ref: https://slurm.schedmd.com/slurm.conf.html#SECTION_NODE-CONFIGURATION
+func slurmdResourceConf(nodeset *slinkyv1beta1.NodeSet) []confItem {
+	items := []confItem{}
+
+	limits := nodeset.Spec.Slurmd.Resources.Limits
+	if cpu, ok := limits[corev1.ResourceCPU]; ok && cpu.MilliValue() >= 1000 && cpu.MilliValue()%1000 == 0 {
+		cpus := strconv.FormatInt(cpu.Value(), 10)
+		items = append(items,
+			confItem{key: "CPUs", value: cpus},
+			confItem{key: "Boards", value: "1"},
+			confItem{key: "SocketsPerBoard", value: "1"},
+			confItem{key: "CoresPerSocket", value: cpus},
+			confItem{key: "ThreadsPerCore", value: "1"},
+		)
+	}
+	if memory, ok := limits[corev1.ResourceMemory]; ok && memory.Value() >= mebibyte {
+		items = append(items, confItem{key: "RealMemory", value: strconv.FormatInt(memory.Value()/mebibyte, 10)})
+	}
+
+	overhead := nodeset.Spec.ResourceOverhead
+	if overhead.CoreSpecCount > 0 {
+		items = append(items, confItem{key: "CoreSpecCount", value: strconv.FormatInt(int64(overhead.CoreSpecCount), 10)})
+	}
+	if overhead.MemSpecLimit != nil && overhead.MemSpecLimit.Value() >= mebibyte {
+		items = append(items, confItem{key: "MemSpecLimit", value: strconv.FormatInt(overhead.MemSpecLimit.Value()/mebibyte, 10)})
+	}
+
+	return items
+}
+
+// mebibyte is the unit of memory in Slurm node parameters.
+const mebibyte = 1024 * 1024
+
 func (b *WorkerBuilder) getWorkerHashes(ctx context.Context, nodeset *slinkyv1beta1.NodeSet) (map[string]string, error) {
 	sshConfig := &corev1.ConfigMap{}
 	sshConfigKey := nodeset.SshConfigKey()
@@ -346,3 +525,28 @@ func (b *WorkerBuilder) getWorkerHashes(ctx context.Context, nodeset *slinkyv1be
 
 	return hashMap, nil
 }
+
+// BuildWorkerRevisionHashes returns the hashes of the worker pod inputs that
+// come from the Controller rather than the NodeSet. The NodeSet controller
+// records them in the NodeSet revision, so that a change rolls the pods.
+func (b *WorkerBuilder) BuildWorkerRevisionHashes(ctx context.Context, controller *slinkyv1beta1.Controller) (map[string]string, error) {
+	slurmConfig := &corev1.ConfigMap{}
+	if err := b.client.Get(ctx, controller.ConfigKey(), slurmConfig); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get object (%s): %w", klog.KObj(slurmConfig), err)
+		}
+	}
+	slurmConf := slurmConfig.Data[controllerbuilder.SlurmConfFile]
+
+	hashMap := map[string]string{
+		common.AnnotationSlurmdRestartHash: controllerbuilder.RestartHash(slurmConf, slinkyv1beta1.ConfigChangeActionRestartSlurmd),
+	}
+	// The health check runner is projected into the pods of every NodeSet, so
+	// that adding or removing it must roll the pods of NodeSets without any
+	// health check scripts of their own.
+	if healthCheck, ok := slurmConfig.Data[controllerbuilder.NodeSetHealthCheckFile]; ok {
+		hashMap[common.AnnotationHealthCheckHash] = crypto.CheckSum([]byte(healthCheck))
+	}
+
+	return hashMap, nil
+}
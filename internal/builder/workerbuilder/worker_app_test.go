@@ -4,6 +4,7 @@
 package workerbuilder
 
 import (
+	"context"
 	_ "embed"
 	"strings"
 	"testing"
@@ -12,8 +13,11 @@ import (
 	"github.com/SlinkyProject/slurm-operator/internal/builder/common"
 	"github.com/SlinkyProject/slurm-operator/internal/builder/labels"
 	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/ptr"
 	"k8s.io/utils/set"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -106,3 +110,267 @@ func TestBuilder_BuildWorkerPodTemplate(t *testing.T) {
 		})
 	}
 }
+
+func Test_slurmdConfArgs(t *testing.T) {
+	newNodeSet := func(limits corev1.ResourceList, extraConf string, overhead slinkyv1beta1.NodeSetResourceOverhead) *slinkyv1beta1.NodeSet {
+		return &slinkyv1beta1.NodeSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo",
+			},
+			Spec: slinkyv1beta1.NodeSetSpec{
+				Slurmd: slinkyv1beta1.ContainerWrapper{
+					Container: corev1.Container{
+						Resources: corev1.ResourceRequirements{
+							Limits: limits,
+						},
+					},
+				},
+				ExtraConf:        extraConf,
+				ResourceOverhead: overhead,
+			},
+		}
+	}
+	tests := []struct {
+		name    string
+		nodeset *slinkyv1beta1.NodeSet
+		want    []string
+	}{
+		{
+			name:    "No limits",
+			nodeset: newNodeSet(nil, "", slinkyv1beta1.NodeSetResourceOverhead{}),
+			want:    []string{"--conf", "'Features=foo'"},
+		},
+		{
+			name: "Limits",
+			nodeset: newNodeSet(corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("8Gi"),
+			}, "", slinkyv1beta1.NodeSetResourceOverhead{}),
+			want: []string{"--conf", "'Boards=1 CPUs=4 CoresPerSocket=4 Features=foo RealMemory=8192 SocketsPerBoard=1 ThreadsPerCore=1'"},
+		},
+		{
+			name: "Fractional CPU limit",
+			nodeset: newNodeSet(corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("1500m"),
+				corev1.ResourceMemory: resource.MustParse("1G"),
+			}, "", slinkyv1beta1.NodeSetResourceOverhead{}),
+			want: []string{"--conf", "'Features=foo RealMemory=953'"},
+		},
+		{
+			name: "Overhead",
+			nodeset: newNodeSet(corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("8Gi"),
+			}, "", slinkyv1beta1.NodeSetResourceOverhead{
+				CoreSpecCount: 1,
+				MemSpecLimit:  ptr.To(resource.MustParse("512Mi")),
+			}),
+			want: []string{"--conf", "'CoreSpecCount=1 Features=foo MemSpecLimit=512 RealMemory=8192'"},
+		},
+		{
+			name: "ExtraConf takes precedence",
+			nodeset: newNodeSet(corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("8Gi"),
+			}, "sockets=2 realmemory=4096", slinkyv1beta1.NodeSetResourceOverhead{}),
+			want: []string{"--conf", "'Features=foo Realmemory=4096 Sockets=2'"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := slurmdConfArgs(tt.nodeset); !apiequality.Semantic.DeepEqual(got, tt.want) {
+				t.Errorf("slurmdConfArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_nodesetVolumes_spank(t *testing.T) {
+	controller := &slinkyv1beta1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "slurm",
+		},
+	}
+	nodeset := &slinkyv1beta1.NodeSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "gpu",
+		},
+		Spec: slinkyv1beta1.NodeSetSpec{
+			Spank: slinkyv1beta1.Spank{
+				Plugins: []slinkyv1beta1.SpankPlugin{
+					{Path: "spank_pyxis.so"},
+				},
+			},
+		},
+	}
+	got := nodesetVolumes(nodeset, controller)
+	want := corev1.VolumeProjection{
+		ConfigMap: &corev1.ConfigMapProjection{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "slurm-config"},
+			Items: []corev1.KeyToPath{
+				{Key: "plugstack-gpu.conf", Path: "plugstack.conf.d/gpu.conf"},
+			},
+		},
+	}
+	sources := got[0].Projected.Sources
+	if !apiequality.Semantic.DeepEqual(sources[len(sources)-1], want) {
+		t.Errorf("nodesetVolumes() Sources = %v, want %v", sources, want)
+	}
+
+	nodeset.Spec.Spank = slinkyv1beta1.Spank{}
+	got = nodesetVolumes(nodeset, controller)
+	if len(got[0].Projected.Sources) != 1 {
+		t.Errorf("nodesetVolumes() Sources = %v, want only the auth key", got[0].Projected.Sources)
+	}
+}
+
+func TestBuilder_nodesetScriptProjections(t *testing.T) {
+	controller := &slinkyv1beta1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      "slurm",
+		},
+	}
+	gpuChecks := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      "gpu-checks",
+		},
+		Data: map[string]string{
+			"20-nvidia-smi.sh": "#!/bin/sh\nnvidia-smi\n",
+			"10-dcgm.sh":       "#!/bin/sh\ndcgmi diag -r 1\n",
+		},
+	}
+	slurmConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      controller.ConfigKey().Name,
+		},
+		Data: map[string]string{
+			"slurm.conf":             "",
+			"nodeset-healthcheck.sh": "",
+		},
+	}
+	healthCheckRunner := corev1.VolumeProjection{
+		ConfigMap: &corev1.ConfigMapProjection{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "slurm-config"},
+			Items: []corev1.KeyToPath{
+				{Key: "nodeset-healthcheck.sh", Path: "nodeset-healthcheck.sh", Mode: ptr.To[int32](0o700)},
+			},
+		},
+	}
+	tests := []struct {
+		name    string
+		client  client.Client
+		nodeset *slinkyv1beta1.NodeSet
+		want    []corev1.VolumeProjection
+	}{
+		{
+			name:   "no scripts",
+			client: fake.NewFakeClient(),
+			nodeset: &slinkyv1beta1.NodeSet{
+				ObjectMeta: metav1.ObjectMeta{Namespace: corev1.NamespaceDefault, Name: "cpu"},
+			},
+			want: []corev1.VolumeProjection{},
+		},
+		{
+			name:   "health check runner only",
+			client: fake.NewFakeClient(slurmConfig),
+			nodeset: &slinkyv1beta1.NodeSet{
+				ObjectMeta: metav1.ObjectMeta{Namespace: corev1.NamespaceDefault, Name: "cpu"},
+			},
+			want: []corev1.VolumeProjection{healthCheckRunner},
+		},
+		{
+			name:   "health checks",
+			client: fake.NewFakeClient(slurmConfig, gpuChecks),
+			nodeset: &slinkyv1beta1.NodeSet{
+				ObjectMeta: metav1.ObjectMeta{Namespace: corev1.NamespaceDefault, Name: "gpu"},
+				Spec: slinkyv1beta1.NodeSetSpec{
+					PrologScriptRefs: []slinkyv1beta1.ObjectReference{{Name: "missing"}},
+					HealthCheck: slinkyv1beta1.NodeSetHealthCheck{
+						ScriptRefs: []slinkyv1beta1.ObjectReference{{Name: "gpu-checks"}},
+					},
+				},
+			},
+			want: []corev1.VolumeProjection{
+				{
+					ConfigMap: &corev1.ConfigMapProjection{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "gpu-checks"},
+						Items: []corev1.KeyToPath{
+							{Key: "10-dcgm.sh", Path: "nodeset.d/healthcheck.d/10-dcgm.sh", Mode: ptr.To[int32](0o700)},
+							{Key: "20-nvidia-smi.sh", Path: "nodeset.d/healthcheck.d/20-nvidia-smi.sh", Mode: ptr.To[int32](0o700)},
+						},
+					},
+				},
+				healthCheckRunner,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New(tt.client)
+			got, err := b.nodesetScriptProjections(context.Background(), tt.nodeset, controller)
+			if err != nil {
+				t.Fatalf("nodesetScriptProjections() error = %v", err)
+			}
+			if !apiequality.Semantic.DeepEqual(got, tt.want) {
+				t.Errorf("nodesetScriptProjections() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuilder_BuildWorkerRevisionHashes(t *testing.T) {
+	controller := &slinkyv1beta1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      "slurm",
+		},
+	}
+	newSlurmConfig := func(slurmConf string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: corev1.NamespaceDefault,
+				Name:      controller.ConfigKey().Name,
+			},
+			Data: map[string]string{
+				"slurm.conf": slurmConf,
+			},
+		}
+	}
+	hash := func(c client.Client) string {
+		got, err := New(c).BuildWorkerRevisionHashes(context.Background(), controller)
+		if err != nil {
+			t.Fatalf("BuildWorkerRevisionHashes() error = %v", err)
+		}
+		return got[common.AnnotationSlurmdRestartHash]
+	}
+
+	base := hash(fake.NewFakeClient(newSlurmConfig("AuthType=auth/slurm\nPartitionName=all Nodes=ALL\n")))
+	if got := hash(fake.NewFakeClient()); got != "" {
+		t.Errorf("BuildWorkerRevisionHashes() without config = %q, want empty", got)
+	}
+	if got := hash(fake.NewFakeClient(newSlurmConfig("AuthType=auth/slurm\nPartitionName=debug Nodes=ALL\n"))); got != base {
+		t.Errorf("BuildWorkerRevisionHashes() after a reconfigure change = %v, want %v", got, base)
+	}
+	if got := hash(fake.NewFakeClient(newSlurmConfig("AuthType=auth/munge\nPartitionName=all Nodes=ALL\n"))); got == base {
+		t.Errorf("BuildWorkerRevisionHashes() after a restart change = %v, want it to change", got)
+	}
+
+	slurmConfig := newSlurmConfig("AuthType=auth/slurm\nPartitionName=all Nodes=ALL\n")
+	got, err := New(fake.NewFakeClient(slurmConfig)).BuildWorkerRevisionHashes(context.Background(), controller)
+	if err != nil {
+		t.Fatalf("BuildWorkerRevisionHashes() error = %v", err)
+	}
+	if _, ok := got[common.AnnotationHealthCheckHash]; ok {
+		t.Errorf("BuildWorkerRevisionHashes() without a health check runner = %v, want no %s", got, common.AnnotationHealthCheckHash)
+	}
+	slurmConfig.Data["nodeset-healthcheck.sh"] = "#!/bin/sh\n"
+	got, err = New(fake.NewFakeClient(slurmConfig)).BuildWorkerRevisionHashes(context.Background(), controller)
+	if err != nil {
+		t.Fatalf("BuildWorkerRevisionHashes() error = %v", err)
+	}
+	if got[common.AnnotationHealthCheckHash] == "" {
+		t.Errorf("BuildWorkerRevisionHashes() with a health check runner = %v, want %s", got, common.AnnotationHealthCheckHash)
+	}
+}
@@ -0,0 +1,303 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package accounting
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/set"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+)
+
+const (
+	// ClusterRemovalTimeout is how long the cluster record of a deleted
+	// Controller waits for a slurm client, before it is left in slurmdbd.
+	ClusterRemovalTimeout = 5 * time.Minute
+	// ClusterRemovalRetryInterval is how often the removal of a cluster record
+	// is retried.
+	ClusterRemovalRetryInterval = 10 * time.Second
+
+	// maxRemovedClusters is the number of RemovedClusters kept in the status.
+	maxRemovedClusters = 10
+
+	// ClusterRegisteredReason is the event reason when the cluster of a
+	// Controller is registered in slurmdbd.
+	ClusterRegisteredReason = "ClusterRegistered"
+	// ClusterRemovedReason is the event reason when the cluster record of a
+	// deleted Controller is handled.
+	ClusterRemovedReason = "ClusterRemoved"
+	// ClusterRemovalFailedReason is the event reason when the cluster record
+	// of a deleted Controller could not be removed.
+	ClusterRemovalFailedReason = "ClusterRemovalFailed"
+)
+
+// syncClusterRegistration registers the clusters of the Controllers using this
+// Accounting in slurmdbd, and adds a finalizer to the Controllers. When a
+// Controller is deleted, its cluster record is handled according to the
+// DeletionPolicy, and the result is recorded in the status, before the
+// finalizer is removed.
+func (r *AccountingReconciler) syncClusterRegistration(
+	ctx context.Context,
+	accounting *slinkyv1beta1.Accounting,
+) error {
+	controllers, orphans, err := r.getClusterControllers(ctx, client.ObjectKeyFromObject(accounting))
+	if err != nil {
+		return err
+	}
+	if !accounting.Spec.ClusterRegistration.Enabled || !accounting.DeletionTimestamp.IsZero() {
+		return r.releaseClusterFinalizers(ctx, append(controllers, orphans...))
+	}
+
+	var errs []error
+	if err := r.releaseClusterFinalizers(ctx, orphans); err != nil {
+		errs = append(errs, err)
+	}
+	active := []*slinkyv1beta1.Controller{}
+	for _, controller := range controllers {
+		if controller.DeletionTimestamp.IsZero() {
+			if err := r.addClusterFinalizer(ctx, controller); err != nil {
+				errs = append(errs, err)
+			}
+			active = append(active, controller)
+			continue
+		}
+		if !controllerutil.ContainsFinalizer(controller, slinkyv1beta1.FinalizerSlurmdbCluster) {
+			continue
+		}
+		removed, err := r.removeCluster(ctx, accounting, controllers, controller)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if removed == nil {
+			continue
+		}
+		// Recorded before the finalizer is removed, the status is synced even
+		// if a later step fails.
+		accounting.Status.RemovedClusters = appendRemovedCluster(accounting.Status.RemovedClusters, *removed)
+		if err := r.removeClusterFinalizer(ctx, controller); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := r.registerClusters(ctx, accounting, active); err != nil {
+		errs = append(errs, err)
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// getClusterControllers returns the Controllers using the Accounting, sorted by
+// key, and the Controllers holding the finalizer without using any Accounting.
+func (r *AccountingReconciler) getClusterControllers(
+	ctx context.Context,
+	key types.NamespacedName,
+) ([]*slinkyv1beta1.Controller, []*slinkyv1beta1.Controller, error) {
+	controllerList := &slinkyv1beta1.ControllerList{}
+	if err := r.List(ctx, controllerList); err != nil {
+		return nil, nil, err
+	}
+
+	controllers := []*slinkyv1beta1.Controller{}
+	orphans := []*slinkyv1beta1.Controller{}
+	for i := range controllerList.Items {
+		controller := &controllerList.Items[i]
+		switch {
+		case controller.Spec.AccountingRef.IsMatch(key):
+			controllers = append(controllers, controller)
+		case controller.Spec.AccountingRef.Name == "" &&
+			controllerutil.ContainsFinalizer(controller, slinkyv1beta1.FinalizerSlurmdbCluster):
+			orphans = append(orphans, controller)
+		}
+	}
+	slices.SortFunc(controllers, func(a, b *slinkyv1beta1.Controller) int {
+		return strings.Compare(a.Key().String(), b.Key().String())
+	})
+
+	return controllers, orphans, nil
+}
+
+// registerClusters adds the clusters of the controllers that are missing from
+// slurmdbd, through the slurmrestd of any of the controllers.
+func (r *AccountingReconciler) registerClusters(
+	ctx context.Context,
+	accounting *slinkyv1beta1.Accounting,
+	controllers []*slinkyv1beta1.Controller,
+) error {
+	via := r.clientController(nil, controllers)
+	if via == nil {
+		return nil
+	}
+
+	registered, err := r.slurmControl.GetClusters(ctx, via)
+	if err != nil {
+		return err
+	}
+	registeredSet := set.New(registered...)
+	for _, controller := range controllers {
+		name := controller.ClusterName()
+		if registeredSet.Has(name) {
+			continue
+		}
+		if err := r.slurmControl.RegisterCluster(ctx, via, name); err != nil {
+			return fmt.Errorf("failed to register cluster %s: %w", name, err)
+		}
+		registeredSet.Insert(name)
+		r.eventRecorder.Eventf(accounting, corev1.EventTypeNormal, ClusterRegisteredReason,
+			"Registered cluster %s of Controller %s in slurmdbd", name, klog.KObj(controller))
+	}
+
+	return nil
+}
+
+// removeCluster handles the cluster record of the deleted controller according
+// to the DeletionPolicy. It returns nil without an error while it waits for a
+// slurm client.
+func (r *AccountingReconciler) removeCluster(
+	ctx context.Context,
+	accounting *slinkyv1beta1.Accounting,
+	controllers []*slinkyv1beta1.Controller,
+	controller *slinkyv1beta1.Controller,
+) (*slinkyv1beta1.AccountingRemovedCluster, error) {
+	logger := log.FromContext(ctx)
+
+	name := controller.ClusterName()
+	removed := &slinkyv1beta1.AccountingRemovedCluster{
+		Name: name,
+		ControllerRef: slinkyv1beta1.ObjectReference{
+			Namespace: controller.Namespace,
+			Name:      controller.Name,
+		},
+		Result:  slinkyv1beta1.ClusterRemovalRetained,
+		Message: "The cluster record was kept in slurmdbd.",
+		Time:    metav1.Now(),
+	}
+
+	if accounting.Spec.ClusterRegistration.DeletionPolicy != slinkyv1beta1.ClusterDeletionPolicyDelete {
+		r.eventRecorder.Eventf(accounting, corev1.EventTypeNormal, ClusterRemovedReason,
+			"Kept cluster %s of deleted Controller %s in slurmdbd", name, klog.KObj(controller))
+		return removed, nil
+	}
+
+	timedOut := time.Since(controller.DeletionTimestamp.Time) > ClusterRemovalTimeout
+	via := r.clientController(controller, controllers)
+	if via == nil {
+		if !timedOut {
+			logger.Info("Waiting for a slurm client to remove the cluster from slurmdbd",
+				"cluster", name, "controller", klog.KObj(controller))
+			durationStore.Push(client.ObjectKeyFromObject(accounting).String(), ClusterRemovalRetryInterval)
+			return nil, nil
+		}
+		removed.Result = slinkyv1beta1.ClusterRemovalFailed
+		removed.Message = fmt.Sprintf("No Controller using this Accounting had a RestApi within %s.", ClusterRemovalTimeout)
+		r.eventRecorder.Eventf(accounting, corev1.EventTypeWarning, ClusterRemovalFailedReason,
+			"Failed to remove cluster %s of deleted Controller %s: %s", name, klog.KObj(controller), removed.Message)
+		return removed, nil
+	}
+
+	if err := r.slurmControl.DeleteCluster(ctx, via, name); err != nil {
+		if !timedOut {
+			durationStore.Push(client.ObjectKeyFromObject(accounting).String(), ClusterRemovalRetryInterval)
+			return nil, fmt.Errorf("failed to remove cluster %s: %w", name, err)
+		}
+		removed.Result = slinkyv1beta1.ClusterRemovalFailed
+		removed.Message = fmt.Sprintf("Failed to remove the cluster record: %v", err)
+		r.eventRecorder.Eventf(accounting, corev1.EventTypeWarning, ClusterRemovalFailedReason,
+			"Failed to remove cluster %s of deleted Controller %s: %v", name, klog.KObj(controller), err)
+		return removed, nil
+	}
+
+	removed.Result = slinkyv1beta1.ClusterRemovalDeleted
+	removed.Message = "The cluster record was removed from slurmdbd."
+	r.eventRecorder.Eventf(accounting, corev1.EventTypeNormal, ClusterRemovedReason,
+		"Removed cluster %s of deleted Controller %s from slurmdbd", name, klog.KObj(controller))
+	return removed, nil
+}
+
+// clientController returns the controller whose slurm client is used to reach
+// slurmdbd, preferring the given controller.
+func (r *AccountingReconciler) clientController(
+	preferred *slinkyv1beta1.Controller,
+	controllers []*slinkyv1beta1.Controller,
+) *slinkyv1beta1.Controller {
+	if preferred != nil && r.slurmControl.HasClient(preferred) {
+		return preferred
+	}
+	for _, controller := range controllers {
+		if r.slurmControl.HasClient(controller) {
+			return controller
+		}
+	}
+	return nil
+}
+
+// releaseClusterFinalizers removes the finalizer from the controllers. Their
+// cluster records are kept in slurmdbd.
+func (r *AccountingReconciler) releaseClusterFinalizers(
+	ctx context.Context,
+	controllers []*slinkyv1beta1.Controller,
+) error {
+	logger := log.FromContext(ctx)
+
+	var errs []error
+	for _, controller := range controllers {
+		if !controllerutil.ContainsFinalizer(controller, slinkyv1beta1.FinalizerSlurmdbCluster) {
+			continue
+		}
+		logger.Info("Releasing Controller, its cluster record is kept in slurmdbd",
+			"controller", klog.KObj(controller))
+		if err := r.removeClusterFinalizer(ctx, controller); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+func (r *AccountingReconciler) addClusterFinalizer(ctx context.Context, controller *slinkyv1beta1.Controller) error {
+	if controllerutil.ContainsFinalizer(controller, slinkyv1beta1.FinalizerSlurmdbCluster) {
+		return nil
+	}
+	patch := client.MergeFrom(controller.DeepCopy())
+	controllerutil.AddFinalizer(controller, slinkyv1beta1.FinalizerSlurmdbCluster)
+	return r.Patch(ctx, controller, patch)
+}
+
+func (r *AccountingReconciler) removeClusterFinalizer(ctx context.Context, controller *slinkyv1beta1.Controller) error {
+	patch := client.MergeFrom(controller.DeepCopy())
+	controllerutil.RemoveFinalizer(controller, slinkyv1beta1.FinalizerSlurmdbCluster)
+	if err := r.Patch(ctx, controller, patch); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// appendRemovedCluster adds the removed cluster to the front of the list,
+// keeping the most recent maxRemovedClusters.
+func appendRemovedCluster(
+	list []slinkyv1beta1.AccountingRemovedCluster,
+	removed slinkyv1beta1.AccountingRemovedCluster,
+) []slinkyv1beta1.AccountingRemovedCluster {
+	out := make([]slinkyv1beta1.AccountingRemovedCluster, 0, len(list)+1)
+	out = append(out, removed)
+	out = append(out, list...)
+	if len(out) > maxRemovedClusters {
+		out = out[:maxRemovedClusters]
+	}
+	return out
+}
@@ -0,0 +1,322 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package accounting
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/set"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	api "github.com/SlinkyProject/slurm-client/api/v0044"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	"github.com/SlinkyProject/slurm-operator/internal/controller/accounting/slurmcontrol"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/refresolver"
+)
+
+// fakeSlurmControl keeps the clusters of slurmdbd in memory.
+type fakeSlurmControl struct {
+	clients   set.Set[string]
+	clusters  set.Set[string]
+	deleteErr error
+}
+
+func (f *fakeSlurmControl) HasClient(controller *slinkyv1beta1.Controller) bool {
+	return f.clients.Has(controller.Name)
+}
+
+func (f *fakeSlurmControl) PingSlurmdbd(ctx context.Context, controller *slinkyv1beta1.Controller) ([]api.V0044SlurmdbdPing, error) {
+	return nil, nil
+}
+
+func (f *fakeSlurmControl) GetClusters(ctx context.Context, controller *slinkyv1beta1.Controller) ([]string, error) {
+	return f.clusters.SortedList(), nil
+}
+
+func (f *fakeSlurmControl) RegisterCluster(ctx context.Context, controller *slinkyv1beta1.Controller, name string) error {
+	f.clusters.Insert(name)
+	return nil
+}
+
+func (f *fakeSlurmControl) DeleteCluster(ctx context.Context, controller *slinkyv1beta1.Controller, name string) error {
+	if f.deleteErr != nil {
+		return f.deleteErr
+	}
+	f.clusters.Delete(name)
+	return nil
+}
+
+var _ slurmcontrol.SlurmControlInterface = &fakeSlurmControl{}
+
+func newClusterAccounting(policy slinkyv1beta1.ClusterDeletionPolicy) *slinkyv1beta1.Accounting {
+	return &slinkyv1beta1.Accounting{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "slurm",
+			Name:      "slurm",
+		},
+		Spec: slinkyv1beta1.AccountingSpec{
+			ClusterRegistration: slinkyv1beta1.AccountingClusterRegistration{
+				Enabled:        true,
+				DeletionPolicy: policy,
+			},
+		},
+	}
+}
+
+func newClusterController(name string, deletedAgo time.Duration) *slinkyv1beta1.Controller {
+	controller := &slinkyv1beta1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "slurm",
+			Name:      name,
+		},
+		Spec: slinkyv1beta1.ControllerSpec{
+			AccountingRef: slinkyv1beta1.ObjectReference{
+				Namespace: "slurm",
+				Name:      "slurm",
+			},
+		},
+	}
+	if deletedAgo > 0 {
+		controller.DeletionTimestamp = &metav1.Time{Time: time.Now().Add(-deletedAgo)}
+		controller.Finalizers = []string{slinkyv1beta1.FinalizerSlurmdbCluster}
+	}
+	return controller
+}
+
+func TestAccountingReconciler_syncClusterRegistration(t *testing.T) {
+	tests := []struct {
+		name             string
+		policy           slinkyv1beta1.ClusterDeletionPolicy
+		controller       *slinkyv1beta1.Controller
+		clients          []string
+		deleteErr        error
+		wantErr          bool
+		wantResult       slinkyv1beta1.ClusterRemovalResult
+		wantFinalizer    bool
+		wantClusterAfter bool
+	}{
+		{
+			name:             "register",
+			policy:           slinkyv1beta1.ClusterDeletionPolicyRetain,
+			controller:       newClusterController("b", 0),
+			clients:          []string{"a"},
+			wantFinalizer:    true,
+			wantClusterAfter: true,
+		},
+		{
+			name:             "retain",
+			policy:           slinkyv1beta1.ClusterDeletionPolicyRetain,
+			controller:       newClusterController("b", time.Second),
+			clients:          []string{"a", "b"},
+			wantResult:       slinkyv1beta1.ClusterRemovalRetained,
+			wantClusterAfter: true,
+		},
+		{
+			name:       "delete",
+			policy:     slinkyv1beta1.ClusterDeletionPolicyDelete,
+			controller: newClusterController("b", time.Second),
+			clients:    []string{"a"},
+			wantResult: slinkyv1beta1.ClusterRemovalDeleted,
+		},
+		{
+			name:             "delete waits for a client",
+			policy:           slinkyv1beta1.ClusterDeletionPolicyDelete,
+			controller:       newClusterController("b", time.Second),
+			wantFinalizer:    true,
+			wantClusterAfter: true,
+		},
+		{
+			name:             "delete without a client",
+			policy:           slinkyv1beta1.ClusterDeletionPolicyDelete,
+			controller:       newClusterController("b", 2*ClusterRemovalTimeout),
+			wantResult:       slinkyv1beta1.ClusterRemovalFailed,
+			wantClusterAfter: true,
+		},
+		{
+			name:             "delete error",
+			policy:           slinkyv1beta1.ClusterDeletionPolicyDelete,
+			controller:       newClusterController("b", time.Second),
+			clients:          []string{"b"},
+			deleteErr:        errors.New("unable to connect to database"),
+			wantErr:          true,
+			wantFinalizer:    true,
+			wantClusterAfter: true,
+		},
+		{
+			name:             "delete error after timeout",
+			policy:           slinkyv1beta1.ClusterDeletionPolicyDelete,
+			controller:       newClusterController("b", 2*ClusterRemovalTimeout),
+			clients:          []string{"b"},
+			deleteErr:        errors.New("unable to connect to database"),
+			wantResult:       slinkyv1beta1.ClusterRemovalFailed,
+			wantClusterAfter: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.TODO()
+			accounting := newClusterAccounting(tt.policy)
+			other := newClusterController("a", 0)
+			c := fake.NewFakeClient(accounting, other, tt.controller)
+
+			registered := set.New(other.ClusterName())
+			if !tt.controller.DeletionTimestamp.IsZero() {
+				registered.Insert(tt.controller.ClusterName())
+			}
+			slurmControl := &fakeSlurmControl{
+				clients:   set.New(tt.clients...),
+				clusters:  registered,
+				deleteErr: tt.deleteErr,
+			}
+			r := &AccountingReconciler{
+				Client:        c,
+				refResolver:   refresolver.New(c),
+				slurmControl:  slurmControl,
+				eventRecorder: record.NewFakeRecorder(10),
+			}
+
+			err := r.syncClusterRegistration(ctx, accounting)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("syncClusterRegistration() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			status := accounting.Status
+
+			switch {
+			case tt.wantResult == "" && len(status.RemovedClusters) != 0:
+				t.Errorf("RemovedClusters = %v, want none", status.RemovedClusters)
+			case tt.wantResult != "" && (len(status.RemovedClusters) != 1 ||
+				status.RemovedClusters[0].Result != tt.wantResult ||
+				status.RemovedClusters[0].Name != tt.controller.ClusterName()):
+				t.Errorf("RemovedClusters = %v, want %v", status.RemovedClusters, tt.wantResult)
+			}
+
+			got := &slinkyv1beta1.Controller{}
+			err = c.Get(ctx, client.ObjectKeyFromObject(tt.controller), got)
+			if err != nil && !apierrors.IsNotFound(err) {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if hasFinalizer := err == nil && controllerutil.ContainsFinalizer(got, slinkyv1beta1.FinalizerSlurmdbCluster); hasFinalizer != tt.wantFinalizer {
+				t.Errorf("Finalizers = %v, want finalizer %v", got.Finalizers, tt.wantFinalizer)
+			}
+			if hasCluster := slurmControl.clusters.Has(tt.controller.ClusterName()); hasCluster != tt.wantClusterAfter {
+				t.Errorf("clusters = %v, want cluster %v", slurmControl.clusters.SortedList(), tt.wantClusterAfter)
+			}
+		})
+	}
+}
+
+func TestAccountingReconciler_releaseClusterFinalizers(t *testing.T) {
+	newController := func(name, accountingName string) *slinkyv1beta1.Controller {
+		controller := newClusterController(name, 0)
+		controller.Spec.AccountingRef.Name = accountingName
+		controller.Finalizers = []string{slinkyv1beta1.FinalizerSlurmdbCluster}
+		return controller
+	}
+	disabled := newClusterAccounting(slinkyv1beta1.ClusterDeletionPolicyDelete)
+	disabled.Name = "disabled"
+	disabled.Spec.ClusterRegistration.Enabled = false
+	enabled := newClusterAccounting(slinkyv1beta1.ClusterDeletionPolicyDelete)
+	enabled.Name = "enabled"
+
+	tests := []struct {
+		name string
+		sync func(r *AccountingReconciler) error
+		want map[string]bool
+	}{
+		{
+			name: "Deleted",
+			sync: func(r *AccountingReconciler) error {
+				return r.Sync(context.TODO(), reconcile.Request{
+					NamespacedName: client.ObjectKey{Namespace: "slurm", Name: "gone"},
+				})
+			},
+			want: map[string]bool{
+				"gone":     false,
+				"disabled": true,
+				"enabled":  true,
+				"orphan":   false,
+			},
+		},
+		{
+			name: "Disabled",
+			sync: func(r *AccountingReconciler) error {
+				return r.syncClusterRegistration(context.TODO(), disabled.DeepCopy())
+			},
+			want: map[string]bool{
+				"gone":     true,
+				"disabled": false,
+				"enabled":  true,
+				"orphan":   false,
+			},
+		},
+		{
+			name: "Enabled",
+			sync: func(r *AccountingReconciler) error {
+				return r.syncClusterRegistration(context.TODO(), enabled.DeepCopy())
+			},
+			want: map[string]bool{
+				"gone":     true,
+				"disabled": true,
+				"enabled":  true,
+				"orphan":   false,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewFakeClient(
+				disabled.DeepCopy(),
+				enabled.DeepCopy(),
+				newController("gone", "gone"),
+				newController("disabled", "disabled"),
+				newController("enabled", "enabled"),
+				newController("orphan", ""),
+			)
+			r := &AccountingReconciler{
+				Client:        c,
+				refResolver:   refresolver.New(c),
+				slurmControl:  &fakeSlurmControl{clients: set.New[string](), clusters: set.New[string]()},
+				eventRecorder: record.NewFakeRecorder(10),
+			}
+			if err := tt.sync(r); err != nil {
+				t.Fatalf("sync error = %v", err)
+			}
+
+			for name, wantFinalizer := range tt.want {
+				got := &slinkyv1beta1.Controller{}
+				if err := c.Get(context.TODO(), client.ObjectKey{Namespace: "slurm", Name: name}, got); err != nil {
+					t.Fatalf("Get() error = %v", err)
+				}
+				if hasFinalizer := controllerutil.ContainsFinalizer(got, slinkyv1beta1.FinalizerSlurmdbCluster); hasFinalizer != wantFinalizer {
+					t.Errorf("Controller(%s).Finalizers = %v, want finalizer %v", name, got.Finalizers, wantFinalizer)
+				}
+			}
+		})
+	}
+}
+
+func Test_appendRemovedCluster(t *testing.T) {
+	var list []slinkyv1beta1.AccountingRemovedCluster
+	for i := range maxRemovedClusters + 2 {
+		list = appendRemovedCluster(list, slinkyv1beta1.AccountingRemovedCluster{
+			Name: string(rune('a' + i)),
+		})
+	}
+	if len(list) != maxRemovedClusters {
+		t.Fatalf("len(appendRemovedCluster()) = %v, want %v", len(list), maxRemovedClusters)
+	}
+	if got, want := list[0].Name, string(rune('a'+maxRemovedClusters+1)); got != want {
+		t.Errorf("appendRemovedCluster()[0].Name = %v, want %v", got, want)
+	}
+}
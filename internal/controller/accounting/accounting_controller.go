@@ -6,6 +6,7 @@ package accounting
 import (
 	"context"
 	"flag"
+	"net"
 	"sync"
 	"time"
 
@@ -23,7 +24,9 @@ import (
 
 	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
 	builder "github.com/SlinkyProject/slurm-operator/internal/builder/accountingbuilder"
+	"github.com/SlinkyProject/slurm-operator/internal/clientmap"
 	"github.com/SlinkyProject/slurm-operator/internal/controller/accounting/eventhandler"
+	"github.com/SlinkyProject/slurm-operator/internal/controller/accounting/slurmcontrol"
 	"github.com/SlinkyProject/slurm-operator/internal/utils/durationstore"
 	"github.com/SlinkyProject/slurm-operator/internal/utils/refresolver"
 )
@@ -54,18 +57,23 @@ type AccountingReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
 
+	ClientMap *clientmap.ClientMap
+
 	builder       *builder.AccountingBuilder
 	refResolver   *refresolver.RefResolver
+	slurmControl  slurmcontrol.SlurmControlInterface
 	eventRecorder record.EventRecorderLogger
+	dialContext   func(ctx context.Context, network, address string) (net.Conn, error)
 }
 
 // +kubebuilder:rbac:groups=slinky.slurm.net,resources=accountings,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=slinky.slurm.net,resources=accountings/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=slinky.slurm.net,resources=accountings/finalizers,verbs=update
-// +kubebuilder:rbac:groups=slinky.slurm.net,resources=controllers,verbs=get;list
+// +kubebuilder:rbac:groups=slinky.slurm.net,resources=controllers,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
@@ -104,6 +112,8 @@ func (r *AccountingReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 func (r *AccountingReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.builder = builder.New(r.Client)
 	r.refResolver = refresolver.New(r.Client)
+	r.slurmControl = slurmcontrol.NewSlurmControl(r.ClientMap)
+	r.dialContext = (&net.Dialer{}).DialContext
 	r.eventRecorder = record.NewBroadcaster().NewRecorder(r.Scheme, corev1.EventSource{Component: ControllerName})
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(ControllerName).
@@ -114,21 +124,25 @@ func (r *AccountingReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&corev1.Secret{}).
 		Watches(&slinkyv1beta1.Accounting{}, eventhandler.NewAccountingEventHandler(r.Client)).
 		Watches(&corev1.Secret{}, eventhandler.NewSecretEventHandler(r.Client)).
+		Watches(&slinkyv1beta1.Controller{}, eventhandler.NewControllerEventHandler(r.Client)).
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: maxConcurrentReconciles,
 		}).
 		Complete(r)
 }
 
-func NewReconciler(c client.Client) *AccountingReconciler {
+func NewReconciler(c client.Client, cm *clientmap.ClientMap) *AccountingReconciler {
 	s := c.Scheme()
 	es := corev1.EventSource{Component: ControllerName}
 	return &AccountingReconciler{
-		Client: c,
-		Scheme: s,
+		Client:    c,
+		Scheme:    s,
+		ClientMap: cm,
 
 		builder:       builder.New(c),
 		refResolver:   refresolver.New(c),
+		slurmControl:  slurmcontrol.NewSlurmControl(cm),
 		eventRecorder: record.NewBroadcaster().NewRecorder(s, es),
+		dialContext:   (&net.Dialer{}).DialContext,
 	}
 }
@@ -0,0 +1,278 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package accounting
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/set"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	api "github.com/SlinkyProject/slurm-client/api/v0044"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+)
+
+const (
+	// HealthCheckInterval is how often the health of slurmdbd is checked.
+	HealthCheckInterval = 1 * time.Minute
+
+	// databaseProbeTimeout is the timeout of the TCP probe of the database.
+	databaseProbeTimeout = 5 * time.Second
+
+	databaseReasonReachable             = "Reachable"
+	databaseReasonUnreachable           = "Unreachable"
+	databaseReasonNotResponding         = "SlurmdbdNotResponding"
+	databaseReasonReachableFromOperator = "ReachableFromOperator"
+	databaseReasonOperatorProbeFailed   = "OperatorProbeFailed"
+	databaseReasonUnknown               = "Unknown"
+
+	clustersReasonRegistered    = "Registered"
+	clustersReasonNotRegistered = "NotRegistered"
+	clustersReasonUnmanaged     = "UnmanagedClusters"
+	clustersReasonNoClient      = "NoClient"
+
+	// ClusterNotRegisteredReason is the event reason when the cluster of a
+	// Controller is not registered in slurmdbd.
+	ClusterNotRegisteredReason = "ClusterNotRegistered"
+	// UnmanagedClusterReason is the event reason when a cluster registered in
+	// slurmdbd has no Controller.
+	UnmanagedClusterReason = "UnmanagedCluster"
+)
+
+// syncHealth records whether slurmdbd can reach its database as the
+// DatabaseReachable condition, and the clusters registered in slurmdbd as the
+// ClustersRegistered condition.
+//
+// The checks go through the slurmrestd of a Controller using this Accounting.
+// Without one, the database is probed over TCP from the operator instead.
+func (r *AccountingReconciler) syncHealth(
+	ctx context.Context,
+	accounting *slinkyv1beta1.Accounting,
+	newStatus *slinkyv1beta1.AccountingStatus,
+) error {
+	logger := log.FromContext(ctx)
+
+	controllerList, err := r.refResolver.GetControllersForAccounting(ctx, accounting)
+	if err != nil {
+		return err
+	}
+	controllers := make([]*slinkyv1beta1.Controller, 0, len(controllerList.Items))
+	for i := range controllerList.Items {
+		controller := &controllerList.Items[i]
+		if !controller.DeletionTimestamp.IsZero() {
+			continue
+		}
+		controllers = append(controllers, controller)
+	}
+	slices.SortFunc(controllers, func(a, b *slinkyv1beta1.Controller) int {
+		return strings.Compare(a.Key().String(), b.Key().String())
+	})
+
+	var controller *slinkyv1beta1.Controller
+	for _, c := range controllers {
+		if r.slurmControl.HasClient(c) {
+			controller = c
+			break
+		}
+	}
+
+	if accounting.Spec.External && controller == nil {
+		meta.RemoveStatusCondition(&newStatus.Conditions, slinkyv1beta1.AccountingDatabaseReachable)
+		meta.RemoveStatusCondition(&newStatus.Conditions, slinkyv1beta1.AccountingClustersRegistered)
+		newStatus.Clusters = nil
+		return nil
+	}
+	durationStore.Push(client.ObjectKeyFromObject(accounting).String(), HealthCheckInterval)
+
+	if controller == nil {
+		meta.SetStatusCondition(&newStatus.Conditions, r.probeDatabase(ctx, accounting))
+		meta.SetStatusCondition(&newStatus.Conditions, metav1.Condition{
+			Type:               slinkyv1beta1.AccountingClustersRegistered,
+			Status:             metav1.ConditionUnknown,
+			ObservedGeneration: accounting.Generation,
+			Reason:             clustersReasonNoClient,
+			Message:            "No Controller using this Accounting has a RestApi.",
+		})
+		newStatus.Clusters = clusterStatuses(controllers, nil, false)
+		return nil
+	}
+
+	databaseCondition := metav1.Condition{
+		Type:               slinkyv1beta1.AccountingDatabaseReachable,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: accounting.Generation,
+		Reason:             databaseReasonReachable,
+		Message:            "slurmdbd is responding and can query the database.",
+	}
+	pings, err := r.slurmControl.PingSlurmdbd(ctx, controller)
+	switch {
+	case err != nil:
+		logger.Error(err, "failed to ping slurmdbd")
+		databaseCondition.Status = metav1.ConditionUnknown
+		databaseCondition.Reason = databaseReasonUnknown
+		databaseCondition.Message = fmt.Sprintf("Failed to ping slurmdbd through slurmrestd: %v", err)
+		meta.SetStatusCondition(&newStatus.Conditions, databaseCondition)
+		return nil
+	case !slices.ContainsFunc(pings, func(ping api.V0044SlurmdbdPing) bool { return ping.Responding }):
+		databaseCondition.Status = metav1.ConditionFalse
+		databaseCondition.Reason = databaseReasonNotResponding
+		databaseCondition.Message = "slurmdbd is not responding."
+		meta.SetStatusCondition(&newStatus.Conditions, databaseCondition)
+		return nil
+	}
+
+	registered, err := r.slurmControl.GetClusters(ctx, controller)
+	if err != nil {
+		databaseCondition.Status = metav1.ConditionFalse
+		databaseCondition.Reason = databaseReasonUnreachable
+		databaseCondition.Message = fmt.Sprintf("slurmdbd is responding but failed to query the database: %v", err)
+		meta.SetStatusCondition(&newStatus.Conditions, databaseCondition)
+		return nil
+	}
+	meta.SetStatusCondition(&newStatus.Conditions, databaseCondition)
+
+	newStatus.Clusters = clusterStatuses(controllers, registered, true)
+	oldCondition := meta.FindStatusCondition(newStatus.Conditions, slinkyv1beta1.AccountingClustersRegistered)
+	condition := clustersCondition(accounting.Generation, newStatus.Clusters)
+	if oldCondition == nil || oldCondition.Reason != condition.Reason || oldCondition.Message != condition.Message {
+		switch condition.Reason {
+		case clustersReasonNotRegistered:
+			r.eventRecorder.Event(accounting, corev1.EventTypeWarning, ClusterNotRegisteredReason, condition.Message)
+		case clustersReasonUnmanaged:
+			r.eventRecorder.Event(accounting, corev1.EventTypeWarning, UnmanagedClusterReason, condition.Message)
+		}
+	}
+	meta.SetStatusCondition(&newStatus.Conditions, condition)
+
+	return nil
+}
+
+// probeDatabase opens a TCP connection to the database from the operator. It
+// only tells that the database is up, not that slurmdbd can reach it.
+func (r *AccountingReconciler) probeDatabase(ctx context.Context, accounting *slinkyv1beta1.Accounting) metav1.Condition {
+	storage := accounting.Spec.StorageConfig
+	port := storage.Port
+	if port == 0 {
+		port = 3306
+	}
+	host := storage.Host
+	// slurmdbd resolves a short name in its own namespace, the operator does
+	// not.
+	if !strings.Contains(host, ".") && net.ParseIP(host) == nil {
+		host = host + "." + accounting.Namespace
+	}
+	address := net.JoinHostPort(host, strconv.Itoa(port))
+
+	condition := metav1.Condition{
+		Type:               slinkyv1beta1.AccountingDatabaseReachable,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: accounting.Generation,
+		Reason:             databaseReasonReachableFromOperator,
+		Message:            fmt.Sprintf("The database (%s) accepts TCP connections from the operator, slurmdbd was not checked.", address),
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, databaseProbeTimeout)
+	defer cancel()
+	conn, err := r.dialContext(ctx, "tcp", address)
+	if err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = databaseReasonOperatorProbeFailed
+		condition.Message = fmt.Sprintf("The operator failed to connect to the database (%s): %v", address, err)
+		return condition
+	}
+	_ = conn.Close()
+
+	return condition
+}
+
+// clusterStatuses returns the clusters of the controllers and the clusters
+// registered in slurmdbd, sorted by name.
+func clusterStatuses(
+	controllers []*slinkyv1beta1.Controller,
+	registered []string,
+	known bool,
+) []slinkyv1beta1.AccountingClusterStatus {
+	registeredSet := set.New(registered...)
+	statuses := map[string]slinkyv1beta1.AccountingClusterStatus{}
+	for _, controller := range controllers {
+		name := controller.ClusterName()
+		if _, ok := statuses[name]; ok {
+			continue
+		}
+		statuses[name] = slinkyv1beta1.AccountingClusterStatus{
+			Name: name,
+			ControllerRef: &slinkyv1beta1.ObjectReference{
+				Namespace: controller.Namespace,
+				Name:      controller.Name,
+			},
+			Registered: known && registeredSet.Has(name),
+		}
+	}
+	for _, name := range registered {
+		if _, ok := statuses[name]; ok {
+			continue
+		}
+		statuses[name] = slinkyv1beta1.AccountingClusterStatus{
+			Name:       name,
+			Registered: true,
+		}
+	}
+	if len(statuses) == 0 {
+		return nil
+	}
+
+	out := make([]slinkyv1beta1.AccountingClusterStatus, 0, len(statuses))
+	for _, status := range statuses {
+		out = append(out, status)
+	}
+	slices.SortFunc(out, func(a, b slinkyv1beta1.AccountingClusterStatus) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return out
+}
+
+// clustersCondition returns the ClustersRegistered condition of the clusters.
+// Clusters in slurmdbd without a Controller are only reported, since other
+// clusters may share slurmdbd.
+func clustersCondition(generation int64, clusters []slinkyv1beta1.AccountingClusterStatus) metav1.Condition {
+	notRegistered := []string{}
+	unmanaged := []string{}
+	for _, cluster := range clusters {
+		switch {
+		case cluster.ControllerRef != nil && !cluster.Registered:
+			notRegistered = append(notRegistered, cluster.Name)
+		case cluster.ControllerRef == nil:
+			unmanaged = append(unmanaged, cluster.Name)
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:               slinkyv1beta1.AccountingClustersRegistered,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             clustersReasonRegistered,
+		Message:            "All Controllers are registered as clusters in slurmdbd.",
+	}
+	switch {
+	case len(notRegistered) > 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = clustersReasonNotRegistered
+		condition.Message = fmt.Sprintf("Controllers are not registered as clusters in slurmdbd: %s.", strings.Join(notRegistered, ", "))
+	case len(unmanaged) > 0:
+		condition.Reason = clustersReasonUnmanaged
+		condition.Message = fmt.Sprintf("Clusters in slurmdbd have no Controller: %s.", strings.Join(unmanaged, ", "))
+	}
+	return condition
+}
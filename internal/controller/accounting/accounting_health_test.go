@@ -0,0 +1,184 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package accounting
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+)
+
+func newHealthController(name, clusterName string) *slinkyv1beta1.Controller {
+	return &slinkyv1beta1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "slurm",
+			Name:      name,
+		},
+		Spec: slinkyv1beta1.ControllerSpec{
+			ClusterName: clusterName,
+		},
+	}
+}
+
+func Test_clusterStatuses(t *testing.T) {
+	controllers := []*slinkyv1beta1.Controller{
+		newHealthController("a", "cluster_a"),
+		newHealthController("b", "cluster_b"),
+	}
+	tests := []struct {
+		name       string
+		registered []string
+		known      bool
+		want       []slinkyv1beta1.AccountingClusterStatus
+	}{
+		{
+			name:  "unknown",
+			known: false,
+			want: []slinkyv1beta1.AccountingClusterStatus{
+				{Name: "cluster_a", ControllerRef: &slinkyv1beta1.ObjectReference{Namespace: "slurm", Name: "a"}},
+				{Name: "cluster_b", ControllerRef: &slinkyv1beta1.ObjectReference{Namespace: "slurm", Name: "b"}},
+			},
+		},
+		{
+			name:       "mismatch",
+			registered: []string{"cluster_a", "cluster_c"},
+			known:      true,
+			want: []slinkyv1beta1.AccountingClusterStatus{
+				{Name: "cluster_a", ControllerRef: &slinkyv1beta1.ObjectReference{Namespace: "slurm", Name: "a"}, Registered: true},
+				{Name: "cluster_b", ControllerRef: &slinkyv1beta1.ObjectReference{Namespace: "slurm", Name: "b"}},
+				{Name: "cluster_c", Registered: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := clusterStatuses(controllers, tt.registered, tt.known)
+			if !apiequality.Semantic.DeepEqual(got, tt.want) {
+				t.Errorf("clusterStatuses() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_clustersCondition(t *testing.T) {
+	controllerRef := &slinkyv1beta1.ObjectReference{Namespace: "slurm", Name: "a"}
+	tests := []struct {
+		name       string
+		clusters   []slinkyv1beta1.AccountingClusterStatus
+		wantStatus metav1.ConditionStatus
+		wantReason string
+	}{
+		{
+			name: "registered",
+			clusters: []slinkyv1beta1.AccountingClusterStatus{
+				{Name: "cluster_a", ControllerRef: controllerRef, Registered: true},
+			},
+			wantStatus: metav1.ConditionTrue,
+			wantReason: clustersReasonRegistered,
+		},
+		{
+			name: "unmanaged",
+			clusters: []slinkyv1beta1.AccountingClusterStatus{
+				{Name: "cluster_a", ControllerRef: controllerRef, Registered: true},
+				{Name: "cluster_c", Registered: true},
+			},
+			wantStatus: metav1.ConditionTrue,
+			wantReason: clustersReasonUnmanaged,
+		},
+		{
+			name: "not registered",
+			clusters: []slinkyv1beta1.AccountingClusterStatus{
+				{Name: "cluster_a", ControllerRef: controllerRef},
+				{Name: "cluster_c", Registered: true},
+			},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: clustersReasonNotRegistered,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := clustersCondition(1, tt.clusters)
+			if got.Status != tt.wantStatus || got.Reason != tt.wantReason {
+				t.Errorf("clustersCondition() = %v, want status %v reason %v", got, tt.wantStatus, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestAccountingReconciler_probeDatabase(t *testing.T) {
+	newAccounting := func(host string) *slinkyv1beta1.Accounting {
+		return &slinkyv1beta1.Accounting{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "slurm",
+			},
+			Spec: slinkyv1beta1.AccountingSpec{
+				StorageConfig: slinkyv1beta1.StorageConfig{
+					Host: host,
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name        string
+		accounting  *slinkyv1beta1.Accounting
+		dialErr     error
+		wantStatus  metav1.ConditionStatus
+		wantAddress string
+	}{
+		{
+			name:        "reachable",
+			accounting:  newAccounting("mariadb"),
+			wantStatus:  metav1.ConditionTrue,
+			wantAddress: "mariadb.slurm:3306",
+		},
+		{
+			name:        "unreachable",
+			accounting:  newAccounting("mariadb"),
+			dialErr:     errors.New("connection refused"),
+			wantStatus:  metav1.ConditionFalse,
+			wantAddress: "mariadb.slurm:3306",
+		},
+		{
+			name:        "qualified",
+			accounting:  newAccounting("mariadb.database"),
+			wantStatus:  metav1.ConditionTrue,
+			wantAddress: "mariadb.database:3306",
+		},
+		{
+			name:        "IP",
+			accounting:  newAccounting("10.0.0.1"),
+			wantStatus:  metav1.ConditionTrue,
+			wantAddress: "10.0.0.1:3306",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotAddress string
+			r := &AccountingReconciler{
+				dialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+					gotAddress = address
+					if tt.dialErr != nil {
+						return nil, tt.dialErr
+					}
+					client, server := net.Pipe()
+					_ = server.Close()
+					return client, nil
+				},
+			}
+			got := r.probeDatabase(context.TODO(), tt.accounting)
+			if got.Status != tt.wantStatus {
+				t.Errorf("probeDatabase() = %v, want status %v", got, tt.wantStatus)
+			}
+			if gotAddress != tt.wantAddress {
+				t.Errorf("probeDatabase() address = %v, want %v", gotAddress, tt.wantAddress)
+			}
+		})
+	}
+}
@@ -7,16 +7,27 @@ import (
 	"context"
 	"fmt"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	common "github.com/SlinkyProject/slurm-operator/internal/builder/common"
 	"github.com/SlinkyProject/slurm-operator/internal/utils/objectutils"
 )
 
+const (
+	// StoragePasswordRotatedReason is the event reason when slurmdbd is
+	// restarted with a new storage password.
+	StoragePasswordRotatedReason = "StoragePasswordRotated"
+)
+
 type SyncStep struct {
 	Name string
 	Sync func(ctx context.Context, cluster *slinkyv1beta1.Accounting) error
@@ -30,7 +41,11 @@ func (r *AccountingReconciler) Sync(ctx context.Context, req reconcile.Request)
 	if err := r.Get(ctx, req.NamespacedName, cluster); err != nil {
 		if apierrors.IsNotFound(err) {
 			logger.Info("Accounting has been deleted", "request", req)
-			return nil
+			controllers, orphans, err := r.getClusterControllers(ctx, req.NamespacedName)
+			if err != nil {
+				return err
+			}
+			return r.releaseClusterFinalizers(ctx, append(controllers, orphans...))
 		}
 		return err
 	}
@@ -52,6 +67,29 @@ func (r *AccountingReconciler) Sync(ctx context.Context, req reconcile.Request)
 				return nil
 			},
 		},
+		{
+			Name: "HostServices",
+			Sync: func(ctx context.Context, accounting *slinkyv1beta1.Accounting) error {
+				for _, name := range []string{accounting.PrimaryName(), accounting.BackupName()} {
+					object, err := r.builder.BuildAccountingHostService(accounting, name)
+					if err != nil {
+						return fmt.Errorf("failed to build: %w", err)
+					}
+
+					if !accounting.Spec.HighAvailability || accounting.Spec.External {
+						if err := objectutils.DeleteObject(r.Client, ctx, object); err != nil {
+							return fmt.Errorf("failed to delete object (%s): %w", klog.KObj(object), err)
+						}
+						continue
+					}
+
+					if err := objectutils.SyncObject(r.Client, ctx, object, true); err != nil {
+						return fmt.Errorf("failed to sync object (%s): %w", klog.KObj(object), err)
+					}
+				}
+				return nil
+			},
+		},
 		{
 			Name: "Config",
 			Sync: func(ctx context.Context, accounting *slinkyv1beta1.Accounting) error {
@@ -68,6 +106,26 @@ func (r *AccountingReconciler) Sync(ctx context.Context, req reconcile.Request)
 				return nil
 			},
 		},
+		{
+			Name: "ArchiveClaim",
+			Sync: func(ctx context.Context, accounting *slinkyv1beta1.Accounting) error {
+				if accounting.Spec.External {
+					return nil
+				}
+				archive := accounting.Spec.Archive
+				if archive.ExistingClaim != "" || archive.VolumeClaim == nil {
+					return nil
+				}
+				object, err := r.builder.BuildAccountingArchiveClaim(accounting)
+				if err != nil {
+					return fmt.Errorf("failed to build: %w", err)
+				}
+				if err := objectutils.SyncObject(r.Client, ctx, object, true); err != nil {
+					return fmt.Errorf("failed to sync object (%s): %w", klog.KObj(object), err)
+				}
+				return nil
+			},
+		},
 		{
 			Name: "StatefulSet",
 			Sync: func(ctx context.Context, accounting *slinkyv1beta1.Accounting) error {
@@ -78,12 +136,28 @@ func (r *AccountingReconciler) Sync(ctx context.Context, req reconcile.Request)
 				if err != nil {
 					return fmt.Errorf("failed to build: %w", err)
 				}
+				rotated, err := r.storagePasswordRotated(ctx, object)
+				if err != nil {
+					return err
+				}
 				if err := objectutils.SyncObject(r.Client, ctx, object, true); err != nil {
 					return fmt.Errorf("failed to sync object (%s): %w", klog.KObj(object), err)
 				}
+				if rotated {
+					now := metav1.Now()
+					accounting.Status.LastStoragePasswordRotationTime = &now
+					r.eventRecorder.Eventf(accounting, corev1.EventTypeNormal, StoragePasswordRotatedReason,
+						"Restarting slurmdbd (%s) after storage password rotation", klog.KObj(object))
+				}
 				return nil
 			},
 		},
+		{
+			Name: "ClusterRegistration",
+			Sync: func(ctx context.Context, accounting *slinkyv1beta1.Accounting) error {
+				return r.syncClusterRegistration(ctx, accounting)
+			},
+		},
 	}
 
 	for _, s := range syncSteps {
@@ -100,3 +174,22 @@ func (r *AccountingReconciler) Sync(ctx context.Context, req reconcile.Request)
 
 	return r.syncStatus(ctx, cluster)
 }
+
+// storagePasswordRotated reports if the password of the database user
+// changed, which rolls out slurmdbd through its config hash.
+func (r *AccountingReconciler) storagePasswordRotated(
+	ctx context.Context,
+	newObj *appsv1.StatefulSet,
+) (bool, error) {
+	oldObj := &appsv1.StatefulSet{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(newObj), oldObj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	oldHash := oldObj.Annotations[common.AnnotationStoragePassHash]
+	newHash := newObj.Annotations[common.AnnotationStoragePassHash]
+	return oldHash != "" && newHash != "" && oldHash != newHash, nil
+}
@@ -9,6 +9,7 @@ import (
 
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/retry"
@@ -26,10 +27,19 @@ func (r *AccountingReconciler) syncStatus(
 	logger := log.FromContext(ctx)
 
 	newStatus := &slinkyv1beta1.AccountingStatus{
-		Conditions: []metav1.Condition{},
+		Retention:                       retention(accounting),
+		LastStoragePasswordRotationTime: accounting.Status.LastStoragePasswordRotationTime,
+		Clusters:                        accounting.Status.Clusters,
+		RemovedClusters:                 accounting.Status.RemovedClusters,
+		NamespaceAccounts:               accounting.Status.NamespaceAccounts,
+		Conditions:                      []metav1.Condition{},
 	}
 	newStatus.Conditions = append(newStatus.Conditions, accounting.Status.Conditions...)
 
+	if err := r.syncHealth(ctx, accounting, newStatus); err != nil {
+		return err
+	}
+
 	if apiequality.Semantic.DeepEqual(accounting.Status, newStatus) {
 		logger.V(2).Info("Accounting Status has not changed, skipping status update",
 			"accounting", klog.KObj(accounting), "status", accounting.Status)
@@ -44,6 +54,46 @@ func (r *AccountingReconciler) syncStatus(
 	return nil
 }
 
+// retention returns how long each type of record is kept in the database.
+func retention(accounting *slinkyv1beta1.Accounting) []slinkyv1beta1.AccountingRetention {
+	if accounting.Spec.External {
+		return nil
+	}
+
+	purge := accounting.Spec.Purge
+	archive := accounting.Spec.Archive
+	records := []struct {
+		records    string
+		purgeAfter string
+		archived   bool
+	}{
+		{"Event", purge.EventAfter, archive.Events},
+		{"Job", purge.JobAfter, archive.Jobs},
+		{"Resv", purge.ResvAfter, archive.Resvs},
+		{"Step", purge.StepAfter, archive.Steps},
+		{"Suspend", purge.SuspendAfter, archive.Suspend},
+		{"TXN", purge.TXNAfter, archive.TXN},
+		{"Usage", purge.UsageAfter, archive.Usage},
+	}
+
+	out := make([]slinkyv1beta1.AccountingRetention, 0, len(records))
+	for _, r := range records {
+		item := slinkyv1beta1.AccountingRetention{
+			Records:    r.records,
+			PurgeAfter: r.purgeAfter,
+			// Records are only archived when they are purged.
+			Archived: r.archived && r.purgeAfter != "",
+		}
+		if item.PurgeAfter == "" {
+			item.PurgeAfter = retentionNever
+		}
+		out = append(out, item)
+	}
+	return out
+}
+
+const retentionNever = "Never"
+
 func (r *AccountingReconciler) updateStatus(
 	ctx context.Context,
 	cluster *slinkyv1beta1.Accounting,
@@ -66,7 +116,16 @@ func (r *AccountingReconciler) updateStatus(
 			}
 			return err
 		}
+		// The namespace accounts are owned by the namespace account
+		// controller, so they are kept as they are.
+		namespaceAccounts := toUpdate.Status.NamespaceAccounts
+		namespaceAccountsSynced := meta.FindStatusCondition(toUpdate.Status.Conditions, slinkyv1beta1.AccountingNamespaceAccountsSynced)
 		toUpdate.Status = *newStatus
+		toUpdate.Status.NamespaceAccounts = namespaceAccounts
+		meta.RemoveStatusCondition(&toUpdate.Status.Conditions, slinkyv1beta1.AccountingNamespaceAccountsSynced)
+		if namespaceAccountsSynced != nil {
+			meta.SetStatusCondition(&toUpdate.Status.Conditions, *namespaceAccountsSynced)
+		}
 		return r.Status().Update(ctx, toUpdate)
 	})
 }
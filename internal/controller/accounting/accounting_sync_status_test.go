@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package accounting
+
+import (
+	"testing"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+)
+
+func Test_retention(t *testing.T) {
+	tests := []struct {
+		name       string
+		accounting *slinkyv1beta1.Accounting
+		want       map[string]slinkyv1beta1.AccountingRetention
+	}{
+		{
+			name: "external",
+			accounting: &slinkyv1beta1.Accounting{
+				Spec: slinkyv1beta1.AccountingSpec{
+					External: true,
+				},
+			},
+			want: map[string]slinkyv1beta1.AccountingRetention{},
+		},
+		{
+			name:       "default",
+			accounting: &slinkyv1beta1.Accounting{},
+			want: map[string]slinkyv1beta1.AccountingRetention{
+				"Job": {Records: "Job", PurgeAfter: retentionNever},
+			},
+		},
+		{
+			name: "purge and archive",
+			accounting: &slinkyv1beta1.Accounting{
+				Spec: slinkyv1beta1.AccountingSpec{
+					Purge: slinkyv1beta1.AccountingPurge{
+						JobAfter:  "12months",
+						StepAfter: "1month",
+					},
+					Archive: slinkyv1beta1.AccountingArchive{
+						Jobs:   true,
+						Events: true,
+					},
+				},
+			},
+			want: map[string]slinkyv1beta1.AccountingRetention{
+				"Job":   {Records: "Job", PurgeAfter: "12months", Archived: true},
+				"Step":  {Records: "Step", PurgeAfter: "1month"},
+				"Event": {Records: "Event", PurgeAfter: retentionNever},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := retention(tt.accounting)
+			if tt.accounting.Spec.External && got != nil {
+				t.Errorf("retention() = %v, want nil", got)
+			}
+			gotMap := make(map[string]slinkyv1beta1.AccountingRetention, len(got))
+			for _, item := range got {
+				gotMap[item.Records] = item
+			}
+			for records, want := range tt.want {
+				if !apiequality.Semantic.DeepEqual(gotMap[records], want) {
+					t.Errorf("retention()[%s] = %v, want %v", records, gotMap[records], want)
+				}
+			}
+		})
+	}
+}
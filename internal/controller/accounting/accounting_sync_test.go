@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package accounting
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	common "github.com/SlinkyProject/slurm-operator/internal/builder/common"
+)
+
+func TestAccountingReconciler_storagePasswordRotated(t *testing.T) {
+	newStatefulSet := func(hash string) *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: corev1.NamespaceDefault,
+				Name:      "slurm-accounting",
+				Annotations: map[string]string{
+					common.AnnotationStoragePassHash: hash,
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name        string
+		client      client.Client
+		newObj      *appsv1.StatefulSet
+		wantRotated bool
+	}{
+		{
+			name:   "create",
+			client: fake.NewFakeClient(),
+			newObj: newStatefulSet("foo"),
+		},
+		{
+			name:   "unchanged",
+			client: fake.NewFakeClient(newStatefulSet("foo")),
+			newObj: newStatefulSet("foo"),
+		},
+		{
+			name:   "upgrade",
+			client: fake.NewFakeClient(newStatefulSet("")),
+			newObj: newStatefulSet("foo"),
+		},
+		{
+			name:        "rotated",
+			client:      fake.NewFakeClient(newStatefulSet("foo")),
+			newObj:      newStatefulSet("bar"),
+			wantRotated: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &AccountingReconciler{
+				Client: tt.client,
+			}
+			got, err := r.storagePasswordRotated(context.TODO(), tt.newObj)
+			if err != nil {
+				t.Fatalf("storagePasswordRotated() error = %v", err)
+			}
+			if got != tt.wantRotated {
+				t.Errorf("storagePasswordRotated() = %v, want %v", got, tt.wantRotated)
+			}
+		})
+	}
+}
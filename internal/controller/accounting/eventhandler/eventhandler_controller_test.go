@@ -0,0 +1,190 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package eventhandler
+
+import (
+	"context"
+	"testing"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func newAccountingController(accountingName string) *slinkyv1beta1.Controller {
+	return &slinkyv1beta1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "slurm",
+			Name:      "slurm",
+		},
+		Spec: slinkyv1beta1.ControllerSpec{
+			AccountingRef: slinkyv1beta1.ObjectReference{
+				Namespace: "slurm",
+				Name:      accountingName,
+			},
+		},
+	}
+}
+
+func Test_ControllerEventHandler_Create(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		evt event.CreateEvent
+		q   workqueue.TypedRateLimitingInterface[reconcile.Request]
+	}
+	tests := []struct {
+		name string
+		args args
+		want int
+	}{
+		{
+			name: "empty",
+			args: args{
+				ctx: context.TODO(),
+				evt: event.CreateEvent{},
+				q:   newQueue(),
+			},
+			want: 0,
+		},
+		{
+			name: "no accounting",
+			args: args{
+				ctx: context.TODO(),
+				evt: event.CreateEvent{Object: newAccountingController("")},
+				q:   newQueue(),
+			},
+			want: 0,
+		},
+		{
+			name: "accounting",
+			args: args{
+				ctx: context.TODO(),
+				evt: event.CreateEvent{Object: newAccountingController("slurm")},
+				q:   newQueue(),
+			},
+			want: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewControllerEventHandler(fake.NewFakeClient())
+			e.Create(tt.args.ctx, tt.args.evt, tt.args.q)
+			if got := tt.args.q.Len(); got != tt.want {
+				t.Errorf("Create() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ControllerEventHandler_Update(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		evt event.UpdateEvent
+		q   workqueue.TypedRateLimitingInterface[reconcile.Request]
+	}
+	tests := []struct {
+		name string
+		args args
+		want int
+	}{
+		{
+			name: "empty",
+			args: args{
+				ctx: context.TODO(),
+				evt: event.UpdateEvent{},
+				q:   newQueue(),
+			},
+			want: 0,
+		},
+		{
+			name: "no accounting",
+			args: args{
+				ctx: context.TODO(),
+				evt: event.UpdateEvent{ObjectOld: newAccountingController(""), ObjectNew: newAccountingController("")},
+				q:   newQueue(),
+			},
+			want: 0,
+		},
+		{
+			name: "accounting",
+			args: args{
+				ctx: context.TODO(),
+				evt: event.UpdateEvent{ObjectOld: newAccountingController("slurm"), ObjectNew: newAccountingController("slurm")},
+				q:   newQueue(),
+			},
+			want: 1,
+		},
+		{
+			name: "accounting changed",
+			args: args{
+				ctx: context.TODO(),
+				evt: event.UpdateEvent{ObjectOld: newAccountingController("foo"), ObjectNew: newAccountingController("bar")},
+				q:   newQueue(),
+			},
+			want: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewControllerEventHandler(fake.NewFakeClient())
+			e.Update(tt.args.ctx, tt.args.evt, tt.args.q)
+			if got := tt.args.q.Len(); got != tt.want {
+				t.Errorf("Update() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_ControllerEventHandler_Delete(t *testing.T) {
+	type args struct {
+		ctx context.Context
+		evt event.DeleteEvent
+		q   workqueue.TypedRateLimitingInterface[reconcile.Request]
+	}
+	tests := []struct {
+		name string
+		args args
+		want int
+	}{
+		{
+			name: "empty",
+			args: args{
+				ctx: context.TODO(),
+				evt: event.DeleteEvent{},
+				q:   newQueue(),
+			},
+			want: 0,
+		},
+		{
+			name: "no accounting",
+			args: args{
+				ctx: context.TODO(),
+				evt: event.DeleteEvent{Object: newAccountingController("")},
+				q:   newQueue(),
+			},
+			want: 0,
+		},
+		{
+			name: "accounting",
+			args: args{
+				ctx: context.TODO(),
+				evt: event.DeleteEvent{Object: newAccountingController("slurm")},
+				q:   newQueue(),
+			},
+			want: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewControllerEventHandler(fake.NewFakeClient())
+			e.Delete(tt.args.ctx, tt.args.evt, tt.args.q)
+			if got := tt.args.q.Len(); got != tt.want {
+				t.Errorf("Delete() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -81,13 +81,28 @@ func (e *SecretEventHandler) enqueueRequest(
 	}
 
 	for _, accounting := range accountingList.Items {
-		slurmKeyKey := accounting.AuthSlurmKey()
-		jwtHs256KeyKey := accounting.AuthJwtHs256Key()
-		if secretKey.String() != slurmKeyKey.String() &&
-			secretKey.String() != jwtHs256KeyKey.String() {
+		if !isAccountingSecret(&accounting, secretKey) {
 			continue
 		}
 
 		objectutils.EnqueueRequest(q, &accounting)
 	}
 }
+
+// isAccountingSecret reports if the secret is referenced by the accounting.
+func isAccountingSecret(accounting *slinkyv1beta1.Accounting, secretKey client.ObjectKey) bool {
+	keys := []client.ObjectKey{
+		accounting.AuthSlurmKey(),
+		accounting.AuthJwtHs256Key(),
+		accounting.AuthStorageKey(),
+	}
+	for _, ref := range accounting.StorageTLSRefs() {
+		keys = append(keys, client.ObjectKey{Namespace: accounting.Namespace, Name: ref.Name})
+	}
+	for _, key := range keys {
+		if secretKey.String() == key.String() {
+			return true
+		}
+	}
+	return false
+}
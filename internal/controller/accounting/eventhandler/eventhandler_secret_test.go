@@ -7,12 +7,15 @@ import (
 	"context"
 	"testing"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
 	"github.com/SlinkyProject/slurm-operator/internal/utils/testutils"
 )
 
@@ -26,6 +29,19 @@ func Test_SecretEventHandler_Create(t *testing.T) {
 	passwordRef := testutils.NewPasswordRef(name)
 	passwordSecret := testutils.NewPasswordSecret(passwordRef)
 	accounting := testutils.NewAccounting(name, slurmKeyRef, jwtHs256KeyRef, passwordRef)
+	storageTLSSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: accounting.Namespace,
+			Name:      "mariadb-tls",
+		},
+	}
+	accountingTLS := accounting.DeepCopy()
+	accountingTLS.Spec.StorageConfig.TLS = &slinkyv1beta1.StorageTLS{
+		CaKeyRef: corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: storageTLSSecret.Name},
+			Key:                  "ca.crt",
+		},
+	}
 	type fields struct {
 		Reader client.Reader
 	}
@@ -80,6 +96,57 @@ func Test_SecretEventHandler_Create(t *testing.T) {
 			},
 			want: 1,
 		},
+		{
+			name: "storage password",
+			fields: fields{
+				Reader: fake.NewFakeClient(
+					passwordSecret,
+					accounting,
+				),
+			},
+			args: args{
+				ctx: context.TODO(),
+				evt: event.CreateEvent{
+					Object: passwordSecret,
+				},
+				q: newQueue(),
+			},
+			want: 1,
+		},
+		{
+			name: "storage tls",
+			fields: fields{
+				Reader: fake.NewFakeClient(
+					storageTLSSecret,
+					accountingTLS,
+				),
+			},
+			args: args{
+				ctx: context.TODO(),
+				evt: event.CreateEvent{
+					Object: storageTLSSecret,
+				},
+				q: newQueue(),
+			},
+			want: 1,
+		},
+		{
+			name: "storage tls disabled",
+			fields: fields{
+				Reader: fake.NewFakeClient(
+					storageTLSSecret,
+					accounting,
+				),
+			},
+			args: args{
+				ctx: context.TODO(),
+				evt: event.CreateEvent{
+					Object: storageTLSSecret,
+				},
+				q: newQueue(),
+			},
+			want: 0,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,166 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmcontrol
+
+import (
+	"context"
+	"net/http"
+
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	api "github.com/SlinkyProject/slurm-client/api/v0044"
+	slurmclient "github.com/SlinkyProject/slurm-client/pkg/client"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	"github.com/SlinkyProject/slurm-operator/internal/clientmap"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/slurmapi"
+)
+
+type SlurmControlInterface interface {
+	// HasClient reports if there is a slurm client for the controller.
+	HasClient(controller *slinkyv1beta1.Controller) bool
+	// PingSlurmdbd returns the ping results of slurmdbd, through the
+	// slurmrestd of the controller.
+	PingSlurmdbd(ctx context.Context, controller *slinkyv1beta1.Controller) ([]api.V0044SlurmdbdPing, error)
+	// GetClusters returns the names of the clusters registered in slurmdbd.
+	// This queries the database, so it fails when slurmdbd cannot reach it.
+	GetClusters(ctx context.Context, controller *slinkyv1beta1.Controller) ([]string, error)
+	// RegisterCluster adds the cluster to slurmdbd.
+	RegisterCluster(ctx context.Context, controller *slinkyv1beta1.Controller, name string) error
+	// DeleteCluster removes the cluster from slurmdbd.
+	DeleteCluster(ctx context.Context, controller *slinkyv1beta1.Controller, name string) error
+}
+
+// realSlurmControl is the default implementation of SlurmControlInterface.
+type realSlurmControl struct {
+	clientMap    *clientmap.ClientMap
+	newAPIClient slurmapi.ClientFunc
+}
+
+// HasClient implements SlurmControlInterface.
+func (r *realSlurmControl) HasClient(controller *slinkyv1beta1.Controller) bool {
+	return r.lookupClient(controller) != nil
+}
+
+// PingSlurmdbd implements SlurmControlInterface.
+func (r *realSlurmControl) PingSlurmdbd(ctx context.Context, controller *slinkyv1beta1.Controller) ([]api.V0044SlurmdbdPing, error) {
+	apiClient, err := r.lookupAPIClient(ctx, controller, "PingSlurmdbd")
+	if apiClient == nil || err != nil {
+		return nil, err
+	}
+	res, err := apiClient.SlurmdbV0044GetPingWithResponse(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if res.JSON200 == nil {
+		var oapiErrors *api.V0044OpenapiErrors
+		if res.JSONDefault != nil {
+			oapiErrors = res.JSONDefault.Errors
+		}
+		return nil, slurmapi.CheckResponse(res.StatusCode(), oapiErrors)
+	}
+	return res.JSON200.Pings, nil
+}
+
+// GetClusters implements SlurmControlInterface.
+func (r *realSlurmControl) GetClusters(ctx context.Context, controller *slinkyv1beta1.Controller) ([]string, error) {
+	apiClient, err := r.lookupAPIClient(ctx, controller, "GetClusters")
+	if apiClient == nil || err != nil {
+		return nil, err
+	}
+	res, err := apiClient.SlurmdbV0044GetClustersWithResponse(ctx, &api.SlurmdbV0044GetClustersParams{})
+	if err != nil {
+		return nil, err
+	}
+	if res.JSON200 == nil {
+		var oapiErrors *api.V0044OpenapiErrors
+		if res.JSONDefault != nil {
+			oapiErrors = res.JSONDefault.Errors
+		}
+		return nil, slurmapi.CheckResponse(res.StatusCode(), oapiErrors)
+	}
+	names := make([]string, 0, len(res.JSON200.Clusters))
+	for _, cluster := range res.JSON200.Clusters {
+		if name := ptr.Deref(cluster.Name, ""); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// RegisterCluster implements SlurmControlInterface.
+func (r *realSlurmControl) RegisterCluster(ctx context.Context, controller *slinkyv1beta1.Controller, name string) error {
+	apiClient, err := r.lookupAPIClient(ctx, controller, "RegisterCluster")
+	if apiClient == nil || err != nil {
+		return err
+	}
+	req := api.V0044OpenapiClustersResp{
+		Clusters: api.V0044ClusterRecList{
+			{Name: ptr.To(name)},
+		},
+	}
+	res, err := apiClient.SlurmdbV0044PostClustersWithResponse(ctx, &api.SlurmdbV0044PostClustersParams{}, req)
+	if err != nil {
+		return err
+	}
+	if res.JSON200 == nil {
+		var oapiErrors *api.V0044OpenapiErrors
+		if res.JSONDefault != nil {
+			oapiErrors = res.JSONDefault.Errors
+		}
+		return slurmapi.CheckResponse(res.StatusCode(), oapiErrors)
+	}
+	return nil
+}
+
+// DeleteCluster implements SlurmControlInterface.
+func (r *realSlurmControl) DeleteCluster(ctx context.Context, controller *slinkyv1beta1.Controller, name string) error {
+	apiClient, err := r.lookupAPIClient(ctx, controller, "DeleteCluster")
+	if apiClient == nil || err != nil {
+		return err
+	}
+	res, err := apiClient.SlurmdbV0044DeleteClusterWithResponse(ctx, name, &api.SlurmdbV0044DeleteClusterParams{})
+	if err != nil {
+		return err
+	}
+	if res.StatusCode() == http.StatusNotFound {
+		return nil
+	}
+	if res.JSON200 == nil {
+		var oapiErrors *api.V0044OpenapiErrors
+		if res.JSONDefault != nil {
+			oapiErrors = res.JSONDefault.Errors
+		}
+		return slurmapi.CheckResponse(res.StatusCode(), oapiErrors)
+	}
+	return nil
+}
+
+func (r *realSlurmControl) lookupClient(controller *slinkyv1beta1.Controller) slurmclient.Client {
+	return r.clientMap.Get(client.ObjectKeyFromObject(controller))
+}
+
+// lookupAPIClient returns an API client for the controller, or nil if there
+// is no slurm client for it.
+func (r *realSlurmControl) lookupAPIClient(ctx context.Context, controller *slinkyv1beta1.Controller, op string) (api.ClientWithResponsesInterface, error) {
+	logger := log.FromContext(ctx)
+
+	slurmClient := r.lookupClient(controller)
+	if slurmClient == nil {
+		logger.V(2).Info("no client for controller, cannot do " + op + "()")
+		return nil, nil
+	}
+	return r.newAPIClient(slurmClient)
+}
+
+var _ SlurmControlInterface = &realSlurmControl{}
+
+func NewSlurmControl(clientMap *clientmap.ClientMap) SlurmControlInterface {
+	return &realSlurmControl{
+		clientMap:    clientMap,
+		newAPIClient: slurmapi.NewClient,
+	}
+}
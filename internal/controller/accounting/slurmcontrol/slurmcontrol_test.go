@@ -0,0 +1,340 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmcontrol
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	api "github.com/SlinkyProject/slurm-client/api/v0044"
+	"github.com/SlinkyProject/slurm-client/pkg/client"
+	apifake "github.com/SlinkyProject/slurm-client/pkg/client/api/v0044/fake"
+	apiinterceptor "github.com/SlinkyProject/slurm-client/pkg/client/api/v0044/interceptor"
+	"github.com/SlinkyProject/slurm-client/pkg/client/fake"
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	"github.com/SlinkyProject/slurm-operator/internal/clientmap"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+func newController(name string) *slinkyv1beta1.Controller {
+	return &slinkyv1beta1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      name,
+		},
+	}
+}
+
+func newSlurmClientMap(controllerName string, client client.Client) *clientmap.ClientMap {
+	cm := clientmap.NewClientMap()
+	key := k8stypes.NamespacedName{
+		Namespace: corev1.NamespaceDefault,
+		Name:      controllerName,
+	}
+	cm.Add(key, client)
+	return cm
+}
+
+func newAPIClient(funcs apiinterceptor.Funcs) func(client.Client) (api.ClientWithResponsesInterface, error) {
+	return func(client.Client) (api.ClientWithResponsesInterface, error) {
+		return apifake.NewFakeClientBuilder().WithInterceptorFuncs(funcs).Build(), nil
+	}
+}
+
+func Test_realSlurmControl_HasClient(t *testing.T) {
+	controller := newController("slurm")
+	tests := []struct {
+		name      string
+		clientMap *clientmap.ClientMap
+		want      bool
+	}{
+		{
+			name:      "client",
+			clientMap: newSlurmClientMap(controller.Name, fake.NewFakeClient()),
+			want:      true,
+		},
+		{
+			name:      "no client",
+			clientMap: clientmap.NewClientMap(),
+			want:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewSlurmControl(tt.clientMap)
+			if got := r.HasClient(controller); got != tt.want {
+				t.Errorf("realSlurmControl.HasClient() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_realSlurmControl_PingSlurmdbd(t *testing.T) {
+	ctx := context.Background()
+	controller := newController("slurm")
+	tests := []struct {
+		name         string
+		clientMap    *clientmap.ClientMap
+		newAPIClient func(client.Client) (api.ClientWithResponsesInterface, error)
+		want         []api.V0044SlurmdbdPing
+		wantErr      bool
+	}{
+		{
+			name:      "smoke",
+			clientMap: newSlurmClientMap(controller.Name, fake.NewFakeClient()),
+			newAPIClient: newAPIClient(apiinterceptor.Funcs{
+				SlurmdbV0044GetPingWithResponse: func(ctx context.Context, reqEditors ...api.RequestEditorFn) (*api.SlurmdbV0044GetPingResponse, error) {
+					res := &api.SlurmdbV0044GetPingResponse{
+						HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+						JSON200: &api.V0044OpenapiSlurmdbdPingResp{
+							Pings: api.V0044SlurmdbdPingArray{
+								{Hostname: "slurm-accounting-0", Primary: true, Responding: true},
+							},
+						},
+					}
+					return res, nil
+				},
+			}),
+			want: []api.V0044SlurmdbdPing{
+				{Hostname: "slurm-accounting-0", Primary: true, Responding: true},
+			},
+		},
+		{
+			name:         "no client",
+			clientMap:    clientmap.NewClientMap(),
+			newAPIClient: newAPIClient(apiinterceptor.Funcs{}),
+		},
+		{
+			name:      "error",
+			clientMap: newSlurmClientMap(controller.Name, fake.NewFakeClient()),
+			newAPIClient: newAPIClient(apiinterceptor.Funcs{
+				SlurmdbV0044GetPingWithResponse: func(ctx context.Context, reqEditors ...api.RequestEditorFn) (*api.SlurmdbV0044GetPingResponse, error) {
+					return nil, errors.New(http.StatusText(http.StatusBadGateway))
+				},
+			}),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &realSlurmControl{
+				clientMap:    tt.clientMap,
+				newAPIClient: tt.newAPIClient,
+			}
+			got, err := r.PingSlurmdbd(ctx, controller)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("realSlurmControl.PingSlurmdbd() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !apiequality.Semantic.DeepEqual(got, tt.want) {
+				t.Errorf("realSlurmControl.PingSlurmdbd() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_realSlurmControl_GetClusters(t *testing.T) {
+	ctx := context.Background()
+	controller := newController("slurm")
+	tests := []struct {
+		name         string
+		clientMap    *clientmap.ClientMap
+		newAPIClient func(client.Client) (api.ClientWithResponsesInterface, error)
+		want         []string
+		wantErr      bool
+	}{
+		{
+			name:      "smoke",
+			clientMap: newSlurmClientMap(controller.Name, fake.NewFakeClient()),
+			newAPIClient: newAPIClient(apiinterceptor.Funcs{
+				SlurmdbV0044GetClustersWithResponse: func(ctx context.Context, params *api.SlurmdbV0044GetClustersParams, reqEditors ...api.RequestEditorFn) (*api.SlurmdbV0044GetClustersResponse, error) {
+					res := &api.SlurmdbV0044GetClustersResponse{
+						HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+						JSON200: &api.V0044OpenapiClustersResp{
+							Clusters: api.V0044ClusterRecList{
+								{Name: ptr.To("slurm_a")},
+								{Name: ptr.To("slurm_b")},
+								{},
+							},
+						},
+					}
+					return res, nil
+				},
+			}),
+			want: []string{"slurm_a", "slurm_b"},
+		},
+		{
+			name:         "no client",
+			clientMap:    clientmap.NewClientMap(),
+			newAPIClient: newAPIClient(apiinterceptor.Funcs{}),
+		},
+		{
+			name:      "error status",
+			clientMap: newSlurmClientMap(controller.Name, fake.NewFakeClient()),
+			newAPIClient: newAPIClient(apiinterceptor.Funcs{
+				SlurmdbV0044GetClustersWithResponse: func(ctx context.Context, params *api.SlurmdbV0044GetClustersParams, reqEditors ...api.RequestEditorFn) (*api.SlurmdbV0044GetClustersResponse, error) {
+					res := &api.SlurmdbV0044GetClustersResponse{
+						HTTPResponse: &http.Response{StatusCode: http.StatusInternalServerError},
+						JSONDefault: &api.V0044OpenapiClustersResp{
+							Errors: &api.V0044OpenapiErrors{
+								{Error: ptr.To("Unable to connect to database")},
+							},
+						},
+					}
+					return res, nil
+				},
+			}),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &realSlurmControl{
+				clientMap:    tt.clientMap,
+				newAPIClient: tt.newAPIClient,
+			}
+			got, err := r.GetClusters(ctx, controller)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("realSlurmControl.GetClusters() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if len(got) != len(tt.want) || (len(got) > 0 && !apiequality.Semantic.DeepEqual(got, tt.want)) {
+				t.Errorf("realSlurmControl.GetClusters() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_realSlurmControl_RegisterCluster(t *testing.T) {
+	ctx := context.Background()
+	controller := newController("slurm")
+	tests := []struct {
+		name         string
+		clientMap    *clientmap.ClientMap
+		newAPIClient func(client.Client) (api.ClientWithResponsesInterface, error)
+		wantErr      bool
+	}{
+		{
+			name:      "smoke",
+			clientMap: newSlurmClientMap(controller.Name, fake.NewFakeClient()),
+			newAPIClient: newAPIClient(apiinterceptor.Funcs{
+				SlurmdbV0044PostClustersWithResponse: func(ctx context.Context, params *api.SlurmdbV0044PostClustersParams, body api.V0044OpenapiClustersResp, reqEditors ...api.RequestEditorFn) (*api.SlurmdbV0044PostClustersResponse, error) {
+					if len(body.Clusters) != 1 || ptr.Deref(body.Clusters[0].Name, "") != "slurm_a" {
+						return nil, errors.New(http.StatusText(http.StatusBadRequest))
+					}
+					res := &api.SlurmdbV0044PostClustersResponse{
+						HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+						JSON200:      &api.V0044OpenapiResp{},
+					}
+					return res, nil
+				},
+			}),
+		},
+		{
+			name:         "no client",
+			clientMap:    clientmap.NewClientMap(),
+			newAPIClient: newAPIClient(apiinterceptor.Funcs{}),
+		},
+		{
+			name:      "error status",
+			clientMap: newSlurmClientMap(controller.Name, fake.NewFakeClient()),
+			newAPIClient: newAPIClient(apiinterceptor.Funcs{
+				SlurmdbV0044PostClustersWithResponse: func(ctx context.Context, params *api.SlurmdbV0044PostClustersParams, body api.V0044OpenapiClustersResp, reqEditors ...api.RequestEditorFn) (*api.SlurmdbV0044PostClustersResponse, error) {
+					res := &api.SlurmdbV0044PostClustersResponse{
+						HTTPResponse: &http.Response{StatusCode: http.StatusInternalServerError},
+						JSONDefault: &api.V0044OpenapiResp{
+							Errors: &api.V0044OpenapiErrors{
+								{Error: ptr.To("Unable to connect to database")},
+							},
+						},
+					}
+					return res, nil
+				},
+			}),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &realSlurmControl{
+				clientMap:    tt.clientMap,
+				newAPIClient: tt.newAPIClient,
+			}
+			if err := r.RegisterCluster(ctx, controller, "slurm_a"); (err != nil) != tt.wantErr {
+				t.Errorf("realSlurmControl.RegisterCluster() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_realSlurmControl_DeleteCluster(t *testing.T) {
+	ctx := context.Background()
+	controller := newController("slurm")
+	tests := []struct {
+		name         string
+		clientMap    *clientmap.ClientMap
+		newAPIClient func(client.Client) (api.ClientWithResponsesInterface, error)
+		wantErr      bool
+	}{
+		{
+			name:      "smoke",
+			clientMap: newSlurmClientMap(controller.Name, fake.NewFakeClient()),
+			newAPIClient: newAPIClient(apiinterceptor.Funcs{
+				SlurmdbV0044DeleteClusterWithResponse: func(ctx context.Context, clusterName string, params *api.SlurmdbV0044DeleteClusterParams, reqEditors ...api.RequestEditorFn) (*api.SlurmdbV0044DeleteClusterResponse, error) {
+					res := &api.SlurmdbV0044DeleteClusterResponse{
+						HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+						JSON200: &api.V0044OpenapiClustersRemovedResp{
+							DeletedClusters: api.V0044StringList{clusterName},
+						},
+					}
+					return res, nil
+				},
+			}),
+		},
+		{
+			name:      "not found",
+			clientMap: newSlurmClientMap(controller.Name, fake.NewFakeClient()),
+			newAPIClient: newAPIClient(apiinterceptor.Funcs{
+				SlurmdbV0044DeleteClusterWithResponse: func(ctx context.Context, clusterName string, params *api.SlurmdbV0044DeleteClusterParams, reqEditors ...api.RequestEditorFn) (*api.SlurmdbV0044DeleteClusterResponse, error) {
+					res := &api.SlurmdbV0044DeleteClusterResponse{
+						HTTPResponse: &http.Response{StatusCode: http.StatusNotFound},
+					}
+					return res, nil
+				},
+			}),
+		},
+		{
+			name:         "no client",
+			clientMap:    clientmap.NewClientMap(),
+			newAPIClient: newAPIClient(apiinterceptor.Funcs{}),
+		},
+		{
+			name:      "error",
+			clientMap: newSlurmClientMap(controller.Name, fake.NewFakeClient()),
+			newAPIClient: newAPIClient(apiinterceptor.Funcs{
+				SlurmdbV0044DeleteClusterWithResponse: func(ctx context.Context, clusterName string, params *api.SlurmdbV0044DeleteClusterParams, reqEditors ...api.RequestEditorFn) (*api.SlurmdbV0044DeleteClusterResponse, error) {
+					return nil, errors.New(http.StatusText(http.StatusBadGateway))
+				},
+			}),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &realSlurmControl{
+				clientMap:    tt.clientMap,
+				newAPIClient: tt.newAPIClient,
+			}
+			if err := r.DeleteCluster(ctx, controller, "slurm_a"); (err != nil) != tt.wantErr {
+				t.Errorf("realSlurmControl.DeleteCluster() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
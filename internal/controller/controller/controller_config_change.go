@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder/controllerbuilder"
+)
+
+// Reasons for Controller events
+const (
+	// ConfigChangedReason is added to an event when the rendered config changed.
+	ConfigChangedReason = "ConfigChanged"
+)
+
+// recordConfigChange classifies the difference between the stored and the
+// newly rendered `slurm.conf`, recording the action required to apply it on
+// the Controller status and as an event.
+func (r *ControllerReconciler) recordConfigChange(
+	ctx context.Context,
+	controller *slinkyv1beta1.Controller,
+	configMap *corev1.ConfigMap,
+) error {
+	logger := log.FromContext(ctx)
+
+	current := &corev1.ConfigMap{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(configMap), current); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get object (%s): %w", klog.KObj(current), err)
+	}
+
+	oldConf := current.Data[controllerbuilder.SlurmConfFile]
+	newConf := configMap.Data[controllerbuilder.SlurmConfFile]
+	action, keys := controllerbuilder.ClassifyConfigChange(oldConf, newConf)
+	if action == "" {
+		return nil
+	}
+
+	logger.Info("Rendered config changed", "action", action, "keys", keys)
+	r.eventRecorder.Eventf(controller, corev1.EventTypeNormal, ConfigChangedReason,
+		"Config changed (%s), applying with %s", strings.Join(keys, ", "), action)
+	controller.Status.LastConfigChange = &slinkyv1beta1.ConfigChange{
+		Action: action,
+		Keys:   keys,
+		Time:   metav1.Now(),
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder/controllerbuilder"
+)
+
+func TestControllerReconciler_recordConfigChange(t *testing.T) {
+	ctx := context.Background()
+	controller := &slinkyv1beta1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      "slurm",
+		},
+	}
+	newConfigMap := func(conf string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: corev1.NamespaceDefault,
+				Name:      controller.ConfigKey().Name,
+			},
+			Data: map[string]string{
+				controllerbuilder.SlurmConfFile: conf,
+			},
+		}
+	}
+	tests := []struct {
+		name       string
+		objects    []client.Object
+		configMap  *corev1.ConfigMap
+		wantAction slinkyv1beta1.ConfigChangeAction
+		wantKeys   []string
+	}{
+		{
+			name:      "new config",
+			configMap: newConfigMap("ClusterName=slurm\n"),
+		},
+		{
+			name:      "unchanged",
+			objects:   []client.Object{newConfigMap("ClusterName=slurm\n")},
+			configMap: newConfigMap("ClusterName=slurm\n"),
+		},
+		{
+			name:       "reconfigure",
+			objects:    []client.Object{newConfigMap("ClusterName=slurm\n")},
+			configMap:  newConfigMap("ClusterName=slurm\nSchedulerParameters=bf_continue\n"),
+			wantAction: slinkyv1beta1.ConfigChangeActionReconfigure,
+			wantKeys:   []string{"SchedulerParameters"},
+		},
+		{
+			name:       "restart slurmd",
+			objects:    []client.Object{newConfigMap("ClusterName=slurm\nAuthType=auth/munge\n")},
+			configMap:  newConfigMap("ClusterName=slurm\nAuthType=auth/slurm\n"),
+			wantAction: slinkyv1beta1.ConfigChangeActionRestartSlurmd,
+			wantKeys:   []string{"AuthType"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := controller.DeepCopy()
+			recorder := record.NewFakeRecorder(10)
+			r := &ControllerReconciler{
+				Client:        fake.NewClientBuilder().WithObjects(tt.objects...).Build(),
+				eventRecorder: recorder,
+			}
+			if err := r.recordConfigChange(ctx, controller, tt.configMap); err != nil {
+				t.Fatalf("recordConfigChange() error = %v", err)
+			}
+			change := controller.Status.LastConfigChange
+			if tt.wantAction == "" {
+				if change != nil {
+					t.Errorf("Status.LastConfigChange = %v, want nil", change)
+				}
+				if len(recorder.Events) != 0 {
+					t.Errorf("recordConfigChange() events = %v, want 0", len(recorder.Events))
+				}
+				return
+			}
+			if change == nil {
+				t.Fatalf("Status.LastConfigChange = nil, want %v", tt.wantAction)
+			}
+			if change.Action != tt.wantAction {
+				t.Errorf("Status.LastConfigChange.Action = %v, want %v", change.Action, tt.wantAction)
+			}
+			if !apiequality.Semantic.DeepEqual(change.Keys, tt.wantKeys) {
+				t.Errorf("Status.LastConfigChange.Keys = %v, want %v", change.Keys, tt.wantKeys)
+			}
+			if len(recorder.Events) != 1 {
+				t.Errorf("recordConfigChange() events = %v, want 1", len(recorder.Events))
+			}
+		})
+	}
+}
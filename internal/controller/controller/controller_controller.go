@@ -10,6 +10,7 @@ import (
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -25,7 +26,9 @@ import (
 	builder "github.com/SlinkyProject/slurm-operator/internal/builder/controllerbuilder"
 	"github.com/SlinkyProject/slurm-operator/internal/clientmap"
 	"github.com/SlinkyProject/slurm-operator/internal/controller/controller/eventhandler"
+	"github.com/SlinkyProject/slurm-operator/internal/controller/controller/slurmcontrol"
 	"github.com/SlinkyProject/slurm-operator/internal/utils/durationstore"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/historycontrol"
 	"github.com/SlinkyProject/slurm-operator/internal/utils/refresolver"
 )
 
@@ -44,7 +47,9 @@ var (
 	maxConcurrentReconciles = 1
 
 	// this is a short cut for any sub-functions to notify the reconcile how long to wait to requeue
-	durationStore = durationstore.NewDurationStore(durationstore.Greater)
+	// NOTE: the shortest duration is kept, so that the periodic partition drift
+	// and license syncs do not delay a pending reconfigure.
+	durationStore = durationstore.NewDurationStore(durationstore.Less)
 
 	onceBackoffGC     sync.Once
 	failedPodsBackoff = flowcontrol.NewBackOff(1*time.Second, 15*time.Minute)
@@ -57,9 +62,11 @@ type ControllerReconciler struct {
 
 	ClientMap *clientmap.ClientMap
 
-	builder       *builder.ControllerBuilder
-	refResolver   *refresolver.RefResolver
-	eventRecorder record.EventRecorderLogger
+	builder        *builder.ControllerBuilder
+	refResolver    *refresolver.RefResolver
+	slurmControl   slurmcontrol.SlurmControlInterface
+	historyControl historycontrol.HistoryControlInterface
+	eventRecorder  record.EventRecorderLogger
 }
 
 // +kubebuilder:rbac:groups=slinky.slurm.net,resources=controllers,verbs=get;list;watch;create;update;patch;delete
@@ -71,6 +78,8 @@ type ControllerReconciler struct {
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps,resources=controllerrevisions,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch
 // +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
@@ -116,6 +125,7 @@ func (r *ControllerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&corev1.Secret{}).
 		Watches(&slinkyv1beta1.Accounting{}, eventhandler.NewAccountingEventHandler(r.Client)).
 		Watches(&slinkyv1beta1.NodeSet{}, eventhandler.NewNodeSetEventHandler(r.Client)).
+		Watches(&autoscalingv2.HorizontalPodAutoscaler{}, eventhandler.NewHorizontalPodAutoscalerEventHandler(r.Client)).
 		Watches(&corev1.Secret{}, eventhandler.NewSecretEventHandler(r.Client)).
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: maxConcurrentReconciles,
@@ -132,8 +142,10 @@ func NewReconciler(c client.Client, cm *clientmap.ClientMap) *ControllerReconcil
 
 		ClientMap: cm,
 
-		builder:       builder.New(c),
-		refResolver:   refresolver.New(c),
-		eventRecorder: record.NewBroadcaster().NewRecorder(s, es),
+		builder:        builder.New(c),
+		refResolver:    refresolver.New(c),
+		slurmControl:   slurmcontrol.NewSlurmControl(cm),
+		historyControl: historycontrol.NewHistoryControl(c),
+		eventRecorder:  record.NewBroadcaster().NewRecorder(s, es),
 	}
 }
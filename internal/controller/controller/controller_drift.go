@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"k8s.io/utils/set"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	slurmtypes "github.com/SlinkyProject/slurm-client/pkg/types"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	builder "github.com/SlinkyProject/slurm-operator/internal/builder/controllerbuilder"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/config"
+)
+
+const (
+	// PartitionDriftInterval is how often the running partitions are compared
+	// against the rendered configuration.
+	PartitionDriftInterval = 5 * time.Minute
+
+	partitionDriftReasonInSync  = "InSync"
+	partitionDriftReasonDrifted = "Drifted"
+	partitionDriftReasonUnknown = "Unknown"
+)
+
+// syncPartitionDrift compares the cluster name and partitions of the rendered
+// slurm.conf against the running slurmctld and records the result as the
+// PartitionDrift condition. Other keys are not compared.
+func (r *ControllerReconciler) syncPartitionDrift(
+	ctx context.Context,
+	controller *slinkyv1beta1.Controller,
+	newStatus *slinkyv1beta1.ControllerStatus,
+) {
+	logger := log.FromContext(ctx)
+
+	if controller.Spec.External || !r.slurmControl.HasClient(controller) {
+		meta.RemoveStatusCondition(&newStatus.Conditions, slinkyv1beta1.ControllerPartitionDrift)
+		return
+	}
+	durationStore.Push(client.ObjectKeyFromObject(controller).String(), PartitionDriftInterval)
+
+	condition := metav1.Condition{
+		Type:               slinkyv1beta1.ControllerPartitionDrift,
+		Status:             metav1.ConditionUnknown,
+		ObservedGeneration: controller.Generation,
+		Reason:             partitionDriftReasonUnknown,
+	}
+	defer func() {
+		meta.SetStatusCondition(&newStatus.Conditions, condition)
+	}()
+
+	configMap := &corev1.ConfigMap{}
+	if err := r.Get(ctx, controller.ConfigKey(), configMap); err != nil {
+		if !apierrors.IsNotFound(err) {
+			logger.Error(err, "failed to get rendered config")
+		}
+		condition.Message = "Rendered config is not available."
+		return
+	}
+
+	partitions, err := r.slurmControl.GetPartitions(ctx, controller)
+	if err != nil {
+		logger.Error(err, "failed to get running partitions")
+		condition.Message = fmt.Sprintf("Failed to get running partitions: %v", err)
+		return
+	}
+
+	drift := partitionDrift(configMap.Data[builder.SlurmConfFile], partitions)
+	if len(drift) > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = partitionDriftReasonDrifted
+		condition.Message = strings.Join(drift, "; ")
+		logger.Info("Running partitions have drifted from the rendered config", "drift", drift)
+		return
+	}
+
+	condition.Status = metav1.ConditionFalse
+	condition.Reason = partitionDriftReasonInSync
+	condition.Message = "Running cluster name and partitions match the rendered config."
+}
+
+// partitionDrift returns the differences between the cluster and partitions
+// rendered in slurm.conf and those reported by slurmctld.
+func partitionDrift(slurmConf string, partitions []slurmtypes.V0044PartitionInfo) []string {
+	drift := []string{}
+
+	clusterName := ""
+	nodesets := set.New[string]()
+	expected := make(map[string]set.Set[string])
+	for _, line := range config.Parse(slurmConf) {
+		switch key := line[0].Key; {
+		case strings.EqualFold(key, "ClusterName"):
+			clusterName = line[0].Value
+		case strings.EqualFold(key, "NodeSet"):
+			nodesets.Insert(line[0].Value)
+		case strings.EqualFold(key, "PartitionName"):
+			name := line[0].Value
+			if strings.EqualFold(name, "DEFAULT") {
+				continue
+			}
+			nodes, _ := line.Get("Nodes")
+			expected[name] = set.New(splitList(nodes)...)
+		}
+	}
+
+	actual := make(map[string]set.Set[string])
+	clusters := set.New[string]()
+	for _, partition := range partitions {
+		name := ptr.Deref(partition.Name, "")
+		actual[name] = set.New(splitList(ptr.Deref(partition.NodeSets, ""))...)
+		if cluster := ptr.Deref(partition.Cluster, ""); cluster != "" {
+			clusters.Insert(cluster)
+		}
+	}
+
+	if clusterName != "" {
+		for _, cluster := range clusters.SortedList() {
+			if cluster != clusterName {
+				drift = append(drift, fmt.Sprintf("cluster is %q, expected %q", cluster, clusterName))
+			}
+		}
+	}
+
+	for _, name := range set.KeySet(expected).SortedList() {
+		live, ok := actual[name]
+		if !ok {
+			drift = append(drift, fmt.Sprintf("partition %q is missing", name))
+			continue
+		}
+		want := expected[name].Intersection(nodesets)
+		for _, nodeset := range want.Difference(live).SortedList() {
+			drift = append(drift, fmt.Sprintf("partition %q is missing nodeset %q", name, nodeset))
+		}
+		for _, nodeset := range live.Difference(want).SortedList() {
+			drift = append(drift, fmt.Sprintf("partition %q has unexpected nodeset %q", name, nodeset))
+		}
+	}
+	for _, name := range set.KeySet(actual).SortedList() {
+		if _, ok := expected[name]; !ok {
+			drift = append(drift, fmt.Sprintf("partition %q is unexpected", name))
+		}
+	}
+
+	return drift
+}
+
+func splitList(list string) []string {
+	items := []string{}
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
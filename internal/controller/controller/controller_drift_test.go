@@ -0,0 +1,127 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"testing"
+
+	api "github.com/SlinkyProject/slurm-client/api/v0044"
+	slurmtypes "github.com/SlinkyProject/slurm-client/pkg/types"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/utils/ptr"
+)
+
+func newPartition(name, cluster, nodesets string) slurmtypes.V0044PartitionInfo {
+	return slurmtypes.V0044PartitionInfo{
+		V0044PartitionInfo: api.V0044PartitionInfo{
+			Name:     ptr.To(name),
+			Cluster:  ptr.To(cluster),
+			NodeSets: ptr.To(nodesets),
+		},
+	}
+}
+
+func Test_partitionDrift(t *testing.T) {
+	slurmConf := `#
+### GENERAL ###
+ClusterName=slurm
+#
+### COMPUTE & PARTITION ###
+NodeSet=foo Feature=foo
+PartitionName=foo Nodes=foo MaxTime=UNLIMITED
+NodeSet=bar Feature=bar
+PartitionName=bar Nodes=bar
+#
+### EXTRA CONFIG ###
+PartitionName=DEFAULT MaxTime=1:00:00
+PartitionName=all Nodes=ALL Default=YES
+`
+	type args struct {
+		slurmConf  string
+		partitions []slurmtypes.V0044PartitionInfo
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{
+			name: "in sync",
+			args: args{
+				slurmConf: slurmConf,
+				partitions: []slurmtypes.V0044PartitionInfo{
+					newPartition("foo", "slurm", "foo"),
+					newPartition("bar", "slurm", "bar"),
+					newPartition("all", "slurm", ""),
+				},
+			},
+			want: []string{},
+		},
+		{
+			name: "missing partition",
+			args: args{
+				slurmConf: slurmConf,
+				partitions: []slurmtypes.V0044PartitionInfo{
+					newPartition("foo", "slurm", "foo"),
+					newPartition("all", "slurm", ""),
+				},
+			},
+			want: []string{
+				`partition "bar" is missing`,
+			},
+		},
+		{
+			name: "unexpected partition",
+			args: args{
+				slurmConf: slurmConf,
+				partitions: []slurmtypes.V0044PartitionInfo{
+					newPartition("foo", "slurm", "foo"),
+					newPartition("bar", "slurm", "bar"),
+					newPartition("all", "slurm", ""),
+					newPartition("baz", "slurm", "baz"),
+				},
+			},
+			want: []string{
+				`partition "baz" is unexpected`,
+			},
+		},
+		{
+			name: "nodeset mismatch",
+			args: args{
+				slurmConf: slurmConf,
+				partitions: []slurmtypes.V0044PartitionInfo{
+					newPartition("foo", "slurm", "baz"),
+					newPartition("bar", "slurm", "bar"),
+					newPartition("all", "slurm", ""),
+				},
+			},
+			want: []string{
+				`partition "foo" is missing nodeset "foo"`,
+				`partition "foo" has unexpected nodeset "baz"`,
+			},
+		},
+		{
+			name: "cluster mismatch",
+			args: args{
+				slurmConf: slurmConf,
+				partitions: []slurmtypes.V0044PartitionInfo{
+					newPartition("foo", "other", "foo"),
+					newPartition("bar", "other", "bar"),
+					newPartition("all", "other", ""),
+				},
+			},
+			want: []string{
+				`cluster is "other", expected "slurm"`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := partitionDrift(tt.args.slurmConf, tt.args.partitions)
+			if !apiequality.Semantic.DeepEqual(got, tt.want) {
+				t.Errorf("partitionDrift() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
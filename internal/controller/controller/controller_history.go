@@ -0,0 +1,199 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/kubernetes/pkg/controller/history"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder/labels"
+)
+
+// Reasons for Controller events
+const (
+	// ConfigRolledBackReason is added to an event when the rendered config was
+	// rolled back to a pinned revision.
+	ConfigRolledBackReason = "ConfigRolledBack"
+	// ConfigRevisionNotFoundReason is added to an event when the pinned config
+	// revision does not exist.
+	ConfigRevisionNotFoundReason = "ConfigRevisionNotFound"
+)
+
+// syncConfigRevision records the rendered config files in a ControllerRevision
+// and truncates the history. When the Controller pins a config revision, the
+// rendered config files are replaced with the ones saved in that revision.
+func (r *ControllerReconciler) syncConfigRevision(
+	ctx context.Context,
+	controller *slinkyv1beta1.Controller,
+	configMap *corev1.ConfigMap,
+) error {
+	logger := log.FromContext(ctx)
+
+	revisions, err := r.listConfigRevisions(controller)
+	if err != nil {
+		return err
+	}
+	history.SortControllerRevisions(revisions)
+
+	// Use a local copy of controller.Status.CollisionCount to avoid modifying controller.Status directly.
+	var collisionCount int32
+	if controller.Status.CollisionCount != nil {
+		collisionCount = *controller.Status.CollisionCount
+	}
+
+	var activeRevision *appsv1.ControllerRevision
+	if pin := controller.Spec.ConfigRevision; pin != nil {
+		for i := range revisions {
+			if revisions[i].Revision == *pin {
+				activeRevision = revisions[i]
+				break
+			}
+		}
+		if activeRevision == nil {
+			r.eventRecorder.Eventf(controller, corev1.EventTypeWarning, ConfigRevisionNotFoundReason,
+				"Config revision %d was not found", *pin)
+			return fmt.Errorf("config revision %d was not found", *pin)
+		}
+		data := map[string]string{}
+		if err := json.Unmarshal(activeRevision.Data.Raw, &data); err != nil {
+			return fmt.Errorf("failed to decode config revision (%s): %w", activeRevision.Name, err)
+		}
+		if controller.Status.ConfigRevisionName != activeRevision.Name {
+			logger.Info("Rolling back config", "revision", activeRevision.Revision)
+			r.eventRecorder.Eventf(controller, corev1.EventTypeNormal, ConfigRolledBackReason,
+				"Rolled back config to revision %d", activeRevision.Revision)
+		}
+		configMap.Data = data
+	} else {
+		activeRevision, err = r.getConfigRevision(controller, configMap, revisions, &collisionCount)
+		if err != nil {
+			return err
+		}
+	}
+
+	controller.Status.ConfigRevision = activeRevision.Revision
+	controller.Status.ConfigRevisionName = activeRevision.Name
+	controller.Status.CollisionCount = ptr.To(collisionCount)
+
+	return r.truncateConfigHistory(controller, revisions, activeRevision)
+}
+
+// getConfigRevision returns the ControllerRevision holding the rendered config
+// files, creating it or bumping the Revision of an equivalent one as needed.
+// This method expects that revisions is sorted when supplied.
+func (r *ControllerReconciler) getConfigRevision(
+	controller *slinkyv1beta1.Controller,
+	configMap *corev1.ConfigMap,
+	revisions []*appsv1.ControllerRevision,
+	collisionCount *int32,
+) (*appsv1.ControllerRevision, error) {
+	updateRevision, err := newConfigRevision(controller, configMap, nextConfigRevision(revisions), collisionCount)
+	if err != nil {
+		return nil, err
+	}
+
+	revisionCount := len(revisions)
+	equalRevisions := history.FindEqualRevisions(revisions, updateRevision)
+	equalCount := len(equalRevisions)
+
+	switch {
+	case equalCount > 0 && history.EqualRevision(revisions[revisionCount-1], equalRevisions[equalCount-1]):
+		// if the equivalent revision is immediately prior the config has not changed
+		return revisions[revisionCount-1], nil
+	case equalCount > 0:
+		// if the equivalent revision is not immediately prior we return to it by
+		// incrementing its Revision
+		return r.historyControl.UpdateControllerRevision(equalRevisions[equalCount-1], updateRevision.Revision)
+	default:
+		return r.historyControl.CreateControllerRevision(controller, updateRevision, collisionCount)
+	}
+}
+
+// truncateConfigHistory deletes the oldest config revisions, other than the
+// active one, until only ConfigRevisionHistoryLimit remain. This method expects
+// that revisions is sorted when supplied.
+func (r *ControllerReconciler) truncateConfigHistory(
+	controller *slinkyv1beta1.Controller,
+	revisions []*appsv1.ControllerRevision,
+	active *appsv1.ControllerRevision,
+) error {
+	history := make([]*appsv1.ControllerRevision, 0, len(revisions))
+	for i := range revisions {
+		if revisions[i].Name != active.Name {
+			history = append(history, revisions[i])
+		}
+	}
+	historyLen := len(history)
+	historyLimit := int(ptr.Deref(controller.Spec.ConfigRevisionHistoryLimit, 10))
+	if historyLen <= historyLimit {
+		return nil
+	}
+	history = history[:(historyLen - historyLimit)]
+	for i := range history {
+		if err := r.historyControl.DeleteControllerRevision(history[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listConfigRevisions returns the config revisions owned by the Controller.
+func (r *ControllerReconciler) listConfigRevisions(controller *slinkyv1beta1.Controller) ([]*appsv1.ControllerRevision, error) {
+	selectorLabels := labels.NewBuilder().WithControllerLabels(controller).Build()
+	selector := k8slabels.SelectorFromSet(k8slabels.Set(selectorLabels))
+	revisions, err := r.historyControl.ListControllerRevisions(controller, selector)
+	if err != nil {
+		return nil, err
+	}
+	// Only consider revisions created by this controller; the StatefulSet
+	// shares the selector labels.
+	owned := make([]*appsv1.ControllerRevision, 0, len(revisions))
+	for i := range revisions {
+		if metav1.IsControlledBy(revisions[i], controller) {
+			owned = append(owned, revisions[i])
+		}
+	}
+	return owned, nil
+}
+
+// nextConfigRevision finds the next valid revision number based on revisions.
+// This method assumes that revisions has been sorted by Revision.
+func nextConfigRevision(revisions []*appsv1.ControllerRevision) int64 {
+	count := len(revisions)
+	if count <= 0 {
+		return 1
+	}
+	return revisions[count-1].Revision + 1
+}
+
+// newConfigRevision creates a new ControllerRevision containing the rendered config files.
+func newConfigRevision(
+	controller *slinkyv1beta1.Controller,
+	configMap *corev1.ConfigMap,
+	revision int64,
+	collisionCount *int32,
+) (*appsv1.ControllerRevision, error) {
+	data, err := json.Marshal(configMap.Data)
+	if err != nil {
+		return nil, err
+	}
+	return history.NewControllerRevision(
+		controller,
+		slinkyv1beta1.ControllerGVK,
+		labels.NewBuilder().WithControllerLabels(controller).Build(),
+		runtime.RawExtension{Raw: data},
+		revision,
+		collisionCount)
+}
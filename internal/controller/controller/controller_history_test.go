@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder/controllerbuilder"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/historycontrol"
+)
+
+func TestControllerReconciler_syncConfigRevision(t *testing.T) {
+	ctx := context.Background()
+	newConfigMap := func(conf string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: corev1.NamespaceDefault,
+				Name:      "slurm-config",
+			},
+			Data: map[string]string{
+				controllerbuilder.SlurmConfFile: conf,
+			},
+		}
+	}
+	type step struct {
+		configRevision   *int64
+		historyLimit     *int32
+		conf             string
+		wantErr          bool
+		wantRevision     int64
+		wantConf         string
+		wantRevisionsLen int
+	}
+	steps := []struct {
+		name string
+		step
+	}{
+		{
+			name: "initial",
+			step: step{conf: "ClusterName=a\n", wantRevision: 1, wantConf: "ClusterName=a\n", wantRevisionsLen: 1},
+		},
+		{
+			name: "unchanged",
+			step: step{conf: "ClusterName=a\n", wantRevision: 1, wantConf: "ClusterName=a\n", wantRevisionsLen: 1},
+		},
+		{
+			name: "changed",
+			step: step{conf: "ClusterName=b\n", wantRevision: 2, wantConf: "ClusterName=b\n", wantRevisionsLen: 2},
+		},
+		{
+			name: "reverted",
+			step: step{conf: "ClusterName=a\n", wantRevision: 3, wantConf: "ClusterName=a\n", wantRevisionsLen: 2},
+		},
+		{
+			name: "changed again",
+			step: step{conf: "ClusterName=c\n", wantRevision: 4, wantConf: "ClusterName=c\n", wantRevisionsLen: 3},
+		},
+		{
+			name: "pinned",
+			step: step{configRevision: ptr.To[int64](2), conf: "ClusterName=d\n", wantRevision: 2, wantConf: "ClusterName=b\n", wantRevisionsLen: 3},
+		},
+		{
+			name: "pinned, not found",
+			step: step{configRevision: ptr.To[int64](9), conf: "ClusterName=d\n", wantErr: true, wantRevision: 2, wantConf: "ClusterName=d\n", wantRevisionsLen: 3},
+		},
+		{
+			name: "truncated",
+			step: step{historyLimit: ptr.To[int32](1), conf: "ClusterName=c\n", wantRevision: 4, wantConf: "ClusterName=c\n", wantRevisionsLen: 2},
+		},
+	}
+
+	controller := &slinkyv1beta1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      "slurm",
+			UID:       "uid",
+		},
+	}
+	c := fake.NewFakeClient()
+	r := &ControllerReconciler{
+		Client:         c,
+		historyControl: historycontrol.NewHistoryControl(c),
+		eventRecorder:  record.NewFakeRecorder(10),
+	}
+	for _, tt := range steps {
+		t.Run(tt.name, func(t *testing.T) {
+			controller.Spec.ConfigRevision = tt.configRevision
+			controller.Spec.ConfigRevisionHistoryLimit = tt.historyLimit
+			configMap := newConfigMap(tt.conf)
+			if err := r.syncConfigRevision(ctx, controller, configMap); (err != nil) != tt.wantErr {
+				t.Errorf("syncConfigRevision() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := controller.Status.ConfigRevision; got != tt.wantRevision {
+				t.Errorf("Status.ConfigRevision = %v, want %v", got, tt.wantRevision)
+			}
+			want := newConfigMap(tt.wantConf).Data
+			if !apiequality.Semantic.DeepEqual(configMap.Data, want) {
+				t.Errorf("ConfigMap.Data = %v, want %v", configMap.Data, want)
+			}
+			revisionList := &appsv1.ControllerRevisionList{}
+			if err := c.List(ctx, revisionList, client.InNamespace(controller.Namespace)); err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			if got := len(revisionList.Items); got != tt.wantRevisionsLen {
+				t.Errorf("ControllerRevisions = %v, want %v", got, tt.wantRevisionsLen)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"k8s.io/utils/set"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	api "github.com/SlinkyProject/slurm-client/api/v0044"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+)
+
+const (
+	// LicenseSyncInterval is how often license usage is refreshed from
+	// slurmctld.
+	LicenseSyncInterval = 1 * time.Minute
+
+	remoteLicensesReasonRegistered    = "Registered"
+	remoteLicensesReasonNotRegistered = "NotRegistered"
+	remoteLicensesReasonCountMismatch = "CountMismatch"
+)
+
+// syncLicenses records the licenses reported by slurmctld in the status, and
+// whether the remote licenses are registered with slurmdbd with the expected
+// count as the RemoteLicensesRegistered condition.
+func (r *ControllerReconciler) syncLicenses(
+	ctx context.Context,
+	controller *slinkyv1beta1.Controller,
+	newStatus *slinkyv1beta1.ControllerStatus,
+) {
+	logger := log.FromContext(ctx)
+
+	if controller.Spec.External || !r.slurmControl.HasClient(controller) {
+		newStatus.Licenses = nil
+		meta.RemoveStatusCondition(&newStatus.Conditions, slinkyv1beta1.ControllerRemoteLicensesRegistered)
+		return
+	}
+	if len(controller.Spec.Licenses) == 0 && len(controller.Spec.RemoteLicenses) == 0 && len(newStatus.Licenses) == 0 {
+		meta.RemoveStatusCondition(&newStatus.Conditions, slinkyv1beta1.ControllerRemoteLicensesRegistered)
+		return
+	}
+	durationStore.Push(client.ObjectKeyFromObject(controller).String(), LicenseSyncInterval)
+
+	licenses, err := r.slurmControl.GetLicenses(ctx, controller)
+	if err != nil {
+		// Keep the last known usage rather than reporting no licenses.
+		logger.Error(err, "failed to get licenses")
+		return
+	}
+	newStatus.Licenses = licenseStatuses(licenses)
+
+	if len(controller.Spec.RemoteLicenses) == 0 {
+		meta.RemoveStatusCondition(&newStatus.Conditions, slinkyv1beta1.ControllerRemoteLicensesRegistered)
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:               slinkyv1beta1.ControllerRemoteLicensesRegistered,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: controller.Generation,
+		Reason:             remoteLicensesReasonRegistered,
+		Message:            "All remote licenses are served by slurmdbd.",
+	}
+	if missing := missingRemoteLicenses(controller.Spec.RemoteLicenses, newStatus.Licenses); len(missing) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = remoteLicensesReasonNotRegistered
+		condition.Message = fmt.Sprintf("Remote licenses are not served by slurmdbd: %s.", strings.Join(missing, ", "))
+	} else if mismatched := mismatchedRemoteLicenses(controller.Spec.RemoteLicenses, newStatus.Licenses); len(mismatched) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = remoteLicensesReasonCountMismatch
+		condition.Message = fmt.Sprintf("Remote licenses do not match count and allowedPercent: %s.", strings.Join(mismatched, "; "))
+	}
+	meta.SetStatusCondition(&newStatus.Conditions, condition)
+}
+
+// licenseStatuses converts the licenses reported by slurmctld.
+func licenseStatuses(licenses []api.V0044License) []slinkyv1beta1.LicenseStatus {
+	if len(licenses) == 0 {
+		return nil
+	}
+	statuses := make([]slinkyv1beta1.LicenseStatus, 0, len(licenses))
+	for _, license := range licenses {
+		statuses = append(statuses, slinkyv1beta1.LicenseStatus{
+			Name:     ptr.Deref(license.LicenseName, ""),
+			Remote:   ptr.Deref(license.Remote, false),
+			Total:    ptr.Deref(license.Total, 0),
+			Used:     ptr.Deref(license.Used, 0),
+			Free:     ptr.Deref(license.Free, 0),
+			Reserved: ptr.Deref(license.Reserved, 0),
+		})
+	}
+	return statuses
+}
+
+// missingRemoteLicenses returns the names of the remote licenses that
+// slurmctld does not report as remote.
+func missingRemoteLicenses(remoteLicenses []slinkyv1beta1.RemoteLicense, statuses []slinkyv1beta1.LicenseStatus) []string {
+	served := set.New[string]()
+	for _, status := range statuses {
+		if status.Remote {
+			served.Insert(status.Name)
+		}
+	}
+	missing := []string{}
+	for _, license := range remoteLicenses {
+		if name := license.LicenseName(); !served.Has(name) {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// mismatchedRemoteLicenses describes the remote licenses whose total, as
+// reported by slurmctld, is not the share of Count that AllowedPercent
+// allocates to the cluster.
+func mismatchedRemoteLicenses(remoteLicenses []slinkyv1beta1.RemoteLicense, statuses []slinkyv1beta1.LicenseStatus) []string {
+	totals := make(map[string]int32, len(statuses))
+	for _, status := range statuses {
+		if status.Remote {
+			totals[status.Name] = status.Total
+		}
+	}
+	mismatched := []string{}
+	for _, license := range remoteLicenses {
+		name := license.LicenseName()
+		total, ok := totals[name]
+		if !ok {
+			continue
+		}
+		if want := license.ClusterCount(); total != want {
+			mismatched = append(mismatched, fmt.Sprintf("%s has %d, want %d", name, total, want))
+		}
+	}
+	return mismatched
+}
@@ -0,0 +1,143 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"testing"
+
+	api "github.com/SlinkyProject/slurm-client/api/v0044"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/utils/ptr"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+)
+
+func Test_licenseStatuses(t *testing.T) {
+	tests := []struct {
+		name     string
+		licenses []api.V0044License
+		want     []slinkyv1beta1.LicenseStatus
+	}{
+		{
+			name: "empty",
+			want: nil,
+		},
+		{
+			name: "local and remote",
+			licenses: []api.V0044License{
+				{
+					LicenseName: ptr.To("matlab"),
+					Total:       ptr.To[int32](10),
+					Used:        ptr.To[int32](3),
+					Free:        ptr.To[int32](5),
+					Reserved:    ptr.To[int32](2),
+				},
+				{
+					LicenseName: ptr.To("fluent@flexlm"),
+					Remote:      ptr.To(true),
+					Total:       ptr.To[int32](4),
+					Used:        ptr.To[int32](4),
+					Free:        ptr.To[int32](0),
+				},
+			},
+			want: []slinkyv1beta1.LicenseStatus{
+				{Name: "matlab", Total: 10, Used: 3, Free: 5, Reserved: 2},
+				{Name: "fluent@flexlm", Remote: true, Total: 4, Used: 4, Free: 0},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := licenseStatuses(tt.licenses); !apiequality.Semantic.DeepEqual(got, tt.want) {
+				t.Errorf("licenseStatuses() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_missingRemoteLicenses(t *testing.T) {
+	remoteLicenses := []slinkyv1beta1.RemoteLicense{
+		{Name: "fluent", Server: "flexlm", Count: 100},
+		{Name: "ansys", Server: "flexlm", Count: 10},
+	}
+	tests := []struct {
+		name     string
+		statuses []slinkyv1beta1.LicenseStatus
+		want     []string
+	}{
+		{
+			name: "registered",
+			statuses: []slinkyv1beta1.LicenseStatus{
+				{Name: "fluent@flexlm", Remote: true},
+				{Name: "ansys@flexlm", Remote: true},
+			},
+			want: []string{},
+		},
+		{
+			name: "missing",
+			statuses: []slinkyv1beta1.LicenseStatus{
+				{Name: "fluent@flexlm", Remote: true},
+			},
+			want: []string{"ansys@flexlm"},
+		},
+		{
+			name: "local license with the same name",
+			statuses: []slinkyv1beta1.LicenseStatus{
+				{Name: "fluent@flexlm", Remote: true},
+				{Name: "ansys@flexlm"},
+			},
+			want: []string{"ansys@flexlm"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := missingRemoteLicenses(remoteLicenses, tt.statuses); !apiequality.Semantic.DeepEqual(got, tt.want) {
+				t.Errorf("missingRemoteLicenses() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_mismatchedRemoteLicenses(t *testing.T) {
+	remoteLicenses := []slinkyv1beta1.RemoteLicense{
+		{Name: "fluent", Server: "flexlm", Count: 100, AllowedPercent: ptr.To[int32](50)},
+		{Name: "ansys", Server: "flexlm", Count: 10},
+	}
+	tests := []struct {
+		name     string
+		statuses []slinkyv1beta1.LicenseStatus
+		want     []string
+	}{
+		{
+			name: "matching",
+			statuses: []slinkyv1beta1.LicenseStatus{
+				{Name: "fluent@flexlm", Remote: true, Total: 50},
+				{Name: "ansys@flexlm", Remote: true, Total: 10},
+			},
+			want: []string{},
+		},
+		{
+			name: "mismatched",
+			statuses: []slinkyv1beta1.LicenseStatus{
+				{Name: "fluent@flexlm", Remote: true, Total: 100},
+				{Name: "ansys@flexlm", Remote: true, Total: 10},
+			},
+			want: []string{"fluent@flexlm has 100, want 50"},
+		},
+		{
+			name: "missing",
+			statuses: []slinkyv1beta1.LicenseStatus{
+				{Name: "ansys@flexlm", Remote: true, Total: 10},
+			},
+			want: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mismatchedRemoteLicenses(remoteLicenses, tt.statuses); !apiequality.Semantic.DeepEqual(got, tt.want) {
+				t.Errorf("mismatchedRemoteLicenses() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
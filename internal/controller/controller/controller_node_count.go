@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	builder "github.com/SlinkyProject/slurm-operator/internal/builder/controllerbuilder"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/config"
+)
+
+// Reasons for Controller events
+const (
+	// NodeCountExceededReason is added to an event when the NodeSets can scale
+	// beyond MaxNodeCount.
+	NodeCountExceededReason = "NodeCountExceeded"
+)
+
+const (
+	nodeCountReasonExceeded    = "Exceeded"
+	nodeCountReasonWithinLimit = "WithinLimit"
+)
+
+// syncNodeCount compares the capacity of the NodeSets against the
+// MaxNodeCount of the rendered slurm.conf and records the result as the
+// NodeCountExceeded condition.
+func (r *ControllerReconciler) syncNodeCount(
+	ctx context.Context,
+	controller *slinkyv1beta1.Controller,
+	newStatus *slinkyv1beta1.ControllerStatus,
+) error {
+	logger := log.FromContext(ctx)
+
+	configMap := &corev1.ConfigMap{}
+	if err := r.Get(ctx, controller.ConfigKey(), configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	maxNodeCount, ok := renderedMaxNodeCount(configMap.Data[builder.SlurmConfFile])
+	if !ok {
+		meta.RemoveStatusCondition(&newStatus.Conditions, slinkyv1beta1.ControllerNodeCountExceeded)
+		return nil
+	}
+
+	nodesetList, err := r.refResolver.GetNodeSetsForController(ctx, controller)
+	if err != nil {
+		return err
+	}
+	capacity, err := builder.NodeCapacity(ctx, r.refResolver, nodesetList)
+	if err != nil {
+		return err
+	}
+
+	condition := metav1.Condition{
+		Type:               slinkyv1beta1.ControllerNodeCountExceeded,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: controller.Generation,
+		Reason:             nodeCountReasonWithinLimit,
+		Message:            fmt.Sprintf("NodeSets can scale to %d nodes, within MaxNodeCount=%d.", capacity, maxNodeCount),
+	}
+	if int64(capacity) > maxNodeCount {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = nodeCountReasonExceeded
+		condition.Message = fmt.Sprintf("NodeSets can scale to %d nodes, exceeding MaxNodeCount=%d.", capacity, maxNodeCount)
+		if !meta.IsStatusConditionTrue(newStatus.Conditions, slinkyv1beta1.ControllerNodeCountExceeded) {
+			logger.Info("NodeSets can scale beyond MaxNodeCount", "capacity", capacity, "maxNodeCount", maxNodeCount)
+			r.eventRecorder.Eventf(controller, corev1.EventTypeWarning, NodeCountExceededReason,
+				"NodeSets can scale to %d nodes, exceeding MaxNodeCount=%d; nodes beyond the limit will fail to register",
+				capacity, maxNodeCount)
+		}
+	}
+	meta.SetStatusCondition(&newStatus.Conditions, condition)
+
+	return nil
+}
+
+// renderedMaxNodeCount returns the MaxNodeCount in effect for slurm.conf.
+// Later lines take precedence, so extraConf may override the rendered value.
+func renderedMaxNodeCount(slurmConf string) (int64, bool) {
+	value := ""
+	for _, line := range config.Parse(slurmConf) {
+		if strings.EqualFold(line[0].Key, "MaxNodeCount") {
+			value = line[0].Value
+		}
+	}
+	maxNodeCount, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return maxNodeCount, true
+}
@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/refresolver"
+)
+
+func Test_renderedMaxNodeCount(t *testing.T) {
+	tests := []struct {
+		name      string
+		slurmConf string
+		want      int64
+		wantOk    bool
+	}{
+		{
+			name:      "rendered",
+			slurmConf: "ClusterName=slurm\nMaxNodeCount=1024\n",
+			want:      1024,
+			wantOk:    true,
+		},
+		{
+			name:      "extraConf override",
+			slurmConf: "ClusterName=slurm\nMaxNodeCount=2048\n#\n### EXTRA CONFIG ###\nmaxnodecount=512\n",
+			want:      512,
+			wantOk:    true,
+		},
+		{
+			name:      "missing",
+			slurmConf: "ClusterName=slurm\n",
+			wantOk:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := renderedMaxNodeCount(tt.slurmConf)
+			if ok != tt.wantOk {
+				t.Fatalf("renderedMaxNodeCount() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if got != tt.want {
+				t.Errorf("renderedMaxNodeCount() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestControllerReconciler_syncNodeCount(t *testing.T) {
+	ctx := context.Background()
+	controller := &slinkyv1beta1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      "slurm",
+		},
+	}
+	newConfigMap := func(slurmConf string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: corev1.NamespaceDefault,
+				Name:      controller.ConfigKey().Name,
+			},
+			Data: map[string]string{
+				"slurm.conf": slurmConf,
+			},
+		}
+	}
+	nodeset := &slinkyv1beta1.NodeSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      "slurm-foo",
+		},
+		Spec: slinkyv1beta1.NodeSetSpec{
+			ControllerRef: slinkyv1beta1.ObjectReference{
+				Namespace: corev1.NamespaceDefault,
+				Name:      "slurm",
+			},
+			Replicas: ptr.To[int32](1500),
+		},
+	}
+	tests := []struct {
+		name       string
+		objs       []client.Object
+		wantStatus metav1.ConditionStatus
+		wantReason string
+		wantEvents int
+	}{
+		{
+			name:       "within limit",
+			objs:       []client.Object{newConfigMap("MaxNodeCount=2048\n"), nodeset.DeepCopy()},
+			wantStatus: metav1.ConditionFalse,
+			wantReason: nodeCountReasonWithinLimit,
+		},
+		{
+			name:       "exceeded",
+			objs:       []client.Object{newConfigMap("MaxNodeCount=1024\n"), nodeset.DeepCopy()},
+			wantStatus: metav1.ConditionTrue,
+			wantReason: nodeCountReasonExceeded,
+			wantEvents: 1,
+		},
+		{
+			name: "not rendered",
+			objs: []client.Object{nodeset.DeepCopy()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithObjects(tt.objs...).Build()
+			recorder := record.NewFakeRecorder(10)
+			r := &ControllerReconciler{
+				Client:        c,
+				refResolver:   refresolver.New(c),
+				eventRecorder: recorder,
+			}
+			newStatus := &slinkyv1beta1.ControllerStatus{}
+			if err := r.syncNodeCount(ctx, controller, newStatus); err != nil {
+				t.Fatalf("syncNodeCount() error = %v", err)
+			}
+			condition := meta.FindStatusCondition(newStatus.Conditions, slinkyv1beta1.ControllerNodeCountExceeded)
+			if tt.wantStatus == "" {
+				if condition != nil {
+					t.Errorf("syncNodeCount() condition = %v, want nil", condition)
+				}
+				return
+			}
+			if condition == nil {
+				t.Fatalf("syncNodeCount() condition = nil")
+			}
+			if condition.Status != tt.wantStatus || condition.Reason != tt.wantReason {
+				t.Errorf("syncNodeCount() condition = %v/%v, want %v/%v",
+					condition.Status, condition.Reason, tt.wantStatus, tt.wantReason)
+			}
+			if got := len(recorder.Events); got != tt.wantEvents {
+				t.Errorf("syncNodeCount() events = %v, want %v", got, tt.wantEvents)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/crypto"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/objectutils"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/structutils"
+)
+
+// MaxPendingConfigDiff bounds the size of the diff recorded in status.
+const MaxPendingConfigDiff = 64 * 1024
+
+// Reasons for Controller events
+const (
+	// ConfigPendingReason is added to an event when rendered config is awaiting approval.
+	ConfigPendingReason = "ConfigPending"
+	// ConfigApprovedReason is added to an event when pending config was approved.
+	ConfigApprovedReason = "ConfigApproved"
+)
+
+// syncPendingConfig stages the rendered config files when the Controller uses
+// the `Manual` config strategy. It returns true when the live config must not
+// be updated because the rendered config is awaiting approval.
+//
+// Rollbacks to a pinned config revision are not staged, so that a known-good
+// config can always be restored in one step.
+func (r *ControllerReconciler) syncPendingConfig(
+	ctx context.Context,
+	controller *slinkyv1beta1.Controller,
+	configMap *corev1.ConfigMap,
+) (bool, error) {
+	logger := log.FromContext(ctx)
+
+	if controller.Spec.ConfigStrategy != slinkyv1beta1.ConfigStrategyManual || controller.Spec.ConfigRevision != nil {
+		return false, r.deletePendingConfig(ctx, controller)
+	}
+
+	live := &corev1.ConfigMap{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(configMap), live); err != nil {
+		if apierrors.IsNotFound(err) {
+			// Nothing is running yet, the initial config is not staged.
+			return false, r.deletePendingConfig(ctx, controller)
+		}
+		return false, fmt.Errorf("failed to get object (%s): %w", klog.KObj(live), err)
+	}
+
+	diff, err := configDiff(live.Data, configMap.Data)
+	if err != nil {
+		return false, err
+	}
+	if diff == "" {
+		return false, r.deletePendingConfig(ctx, controller)
+	}
+
+	hash := crypto.CheckSumFromMap(configMap.Data)
+	if controller.Annotations[slinkyv1beta1.AnnotationApproveConfig] == hash {
+		logger.Info("Pending config was approved", "hash", hash)
+		r.eventRecorder.Eventf(controller, corev1.EventTypeNormal, ConfigApprovedReason,
+			"Approved pending config: %s", hash)
+		return false, r.deletePendingConfig(ctx, controller)
+	}
+
+	pending := configMap.DeepCopy()
+	pending.ObjectMeta = metav1.ObjectMeta{
+		Name:            controller.PendingConfigKey().Name,
+		Namespace:       controller.PendingConfigKey().Namespace,
+		Labels:          structutils.MergeMaps(configMap.Labels),
+		Annotations:     structutils.MergeMaps(configMap.Annotations),
+		OwnerReferences: configMap.OwnerReferences,
+	}
+	if err := objectutils.SyncObject(r.Client, ctx, pending, true); err != nil {
+		return true, fmt.Errorf("failed to sync object (%s): %w", klog.KObj(pending), err)
+	}
+
+	status := controller.Status.PendingConfig
+	if status == nil || status.Hash != hash {
+		logger.Info("Rendered config is awaiting approval", "hash", hash)
+		r.eventRecorder.Eventf(controller, corev1.EventTypeNormal, ConfigPendingReason,
+			"Config is awaiting approval, annotate with %s=%s", slinkyv1beta1.AnnotationApproveConfig, hash)
+		status = &slinkyv1beta1.PendingConfig{
+			Time: metav1.Now(),
+		}
+	}
+	controller.Status.PendingConfig = &slinkyv1beta1.PendingConfig{
+		ConfigMapName: pending.Name,
+		Hash:          hash,
+		Diff:          diff,
+		Time:          status.Time,
+	}
+
+	return true, nil
+}
+
+// deletePendingConfig removes any staged config files.
+func (r *ControllerReconciler) deletePendingConfig(
+	ctx context.Context,
+	controller *slinkyv1beta1.Controller,
+) error {
+	controller.Status.PendingConfig = nil
+	pending := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      controller.PendingConfigKey().Name,
+			Namespace: controller.PendingConfigKey().Namespace,
+		},
+	}
+	if err := objectutils.DeleteObject(r.Client, ctx, pending); err != nil {
+		return fmt.Errorf("failed to delete object (%s): %w", klog.KObj(pending), err)
+	}
+	return nil
+}
+
+// configDiff returns a unified diff of the config files, in file name order.
+func configDiff(oldData, newData map[string]string) (string, error) {
+	files := structutils.Keys(structutils.MergeMaps(oldData, newData))
+	sort.Strings(files)
+
+	var b strings.Builder
+	for _, file := range files {
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        splitLines(oldData[file]),
+			B:        splitLines(newData[file]),
+			FromFile: "a/" + file,
+			ToFile:   "b/" + file,
+			Context:  3,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to diff %s: %w", file, err)
+		}
+		b.WriteString(diff)
+	}
+
+	diff := b.String()
+	if len(diff) > MaxPendingConfigDiff {
+		diff = diff[:MaxPendingConfigDiff] + "\n... (truncated)\n"
+	}
+	return diff, nil
+}
+
+// splitLines splits text into newline terminated lines.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	lines := strings.SplitAfter(text, "\n")
+	return lines[:len(lines)-1]
+}
@@ -0,0 +1,232 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	"github.com/SlinkyProject/slurm-operator/internal/builder/controllerbuilder"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/crypto"
+)
+
+func TestControllerReconciler_syncPendingConfig(t *testing.T) {
+	ctx := context.Background()
+	newController := func(strategy slinkyv1beta1.ConfigStrategy) *slinkyv1beta1.Controller {
+		return &slinkyv1beta1.Controller{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: corev1.NamespaceDefault,
+				Name:      "slurm",
+			},
+			Spec: slinkyv1beta1.ControllerSpec{
+				ConfigStrategy: strategy,
+			},
+		}
+	}
+	newConfigMap := func(name, conf string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: corev1.NamespaceDefault,
+				Name:      name,
+			},
+			Data: map[string]string{
+				controllerbuilder.SlurmConfFile: conf,
+			},
+		}
+	}
+	controller := newController("")
+	liveName := controller.ConfigKey().Name
+	pendingName := controller.PendingConfigKey().Name
+	const (
+		oldConf = "ClusterName=slurm\n"
+		newConf = "ClusterName=slurm\nSchedulerParameters=bf_continue\n"
+	)
+	newHash := crypto.CheckSumFromMap(newConfigMap(liveName, newConf).Data)
+
+	tests := []struct {
+		name        string
+		controller  *slinkyv1beta1.Controller
+		objects     []client.Object
+		wantStaged  bool
+		wantPending bool
+	}{
+		{
+			name:       "automatic",
+			controller: newController(slinkyv1beta1.ConfigStrategyAutomatic),
+			objects: []client.Object{
+				newConfigMap(liveName, oldConf),
+				newConfigMap(pendingName, newConf),
+			},
+			wantStaged:  false,
+			wantPending: false,
+		},
+		{
+			name:        "manual, initial config",
+			controller:  newController(slinkyv1beta1.ConfigStrategyManual),
+			wantStaged:  false,
+			wantPending: false,
+		},
+		{
+			name:       "manual, unchanged",
+			controller: newController(slinkyv1beta1.ConfigStrategyManual),
+			objects: []client.Object{
+				newConfigMap(liveName, newConf),
+			},
+			wantStaged:  false,
+			wantPending: false,
+		},
+		{
+			name:       "manual, changed",
+			controller: newController(slinkyv1beta1.ConfigStrategyManual),
+			objects: []client.Object{
+				newConfigMap(liveName, oldConf),
+			},
+			wantStaged:  true,
+			wantPending: true,
+		},
+		{
+			name: "manual, approved",
+			controller: func() *slinkyv1beta1.Controller {
+				controller := newController(slinkyv1beta1.ConfigStrategyManual)
+				controller.Annotations = map[string]string{
+					slinkyv1beta1.AnnotationApproveConfig: newHash,
+				}
+				return controller
+			}(),
+			objects: []client.Object{
+				newConfigMap(liveName, oldConf),
+				newConfigMap(pendingName, newConf),
+			},
+			wantStaged:  false,
+			wantPending: false,
+		},
+		{
+			name: "manual, stale approval",
+			controller: func() *slinkyv1beta1.Controller {
+				controller := newController(slinkyv1beta1.ConfigStrategyManual)
+				controller.Annotations = map[string]string{
+					slinkyv1beta1.AnnotationApproveConfig: "stale",
+				}
+				return controller
+			}(),
+			objects: []client.Object{
+				newConfigMap(liveName, oldConf),
+			},
+			wantStaged:  true,
+			wantPending: true,
+		},
+		{
+			name: "manual, pinned revision",
+			controller: func() *slinkyv1beta1.Controller {
+				controller := newController(slinkyv1beta1.ConfigStrategyManual)
+				controller.Spec.ConfigRevision = ptr.To[int64](1)
+				return controller
+			}(),
+			objects: []client.Object{
+				newConfigMap(liveName, oldConf),
+				newConfigMap(pendingName, newConf),
+			},
+			wantStaged:  false,
+			wantPending: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithObjects(tt.objects...).Build()
+			r := &ControllerReconciler{
+				Client:        c,
+				eventRecorder: record.NewFakeRecorder(10),
+			}
+			staged, err := r.syncPendingConfig(ctx, tt.controller, newConfigMap(liveName, newConf))
+			if err != nil {
+				t.Fatalf("syncPendingConfig() error = %v", err)
+			}
+			if staged != tt.wantStaged {
+				t.Errorf("syncPendingConfig() = %v, want %v", staged, tt.wantStaged)
+			}
+
+			pending := &corev1.ConfigMap{}
+			err = c.Get(ctx, tt.controller.PendingConfigKey(), pending)
+			if err != nil && !apierrors.IsNotFound(err) {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if gotPending := err == nil; gotPending != tt.wantPending {
+				t.Errorf("pending ConfigMap exists = %v, want %v", gotPending, tt.wantPending)
+			}
+
+			status := tt.controller.Status.PendingConfig
+			if (status != nil) != tt.wantPending {
+				t.Fatalf("Status.PendingConfig = %v, want present %v", status, tt.wantPending)
+			}
+			if status == nil {
+				return
+			}
+			if status.Hash != newHash {
+				t.Errorf("Status.PendingConfig.Hash = %v, want %v", status.Hash, newHash)
+			}
+			if !strings.Contains(status.Diff, "+SchedulerParameters=bf_continue") {
+				t.Errorf("Status.PendingConfig.Diff = %v, want added SchedulerParameters", status.Diff)
+			}
+			if pending.Data[controllerbuilder.SlurmConfFile] != newConf {
+				t.Errorf("pending ConfigMap = %v, want %v", pending.Data, newConf)
+			}
+		})
+	}
+}
+
+func Test_configDiff(t *testing.T) {
+	tests := []struct {
+		name    string
+		oldData map[string]string
+		newData map[string]string
+		want    string
+	}{
+		{
+			name:    "unchanged",
+			oldData: map[string]string{"slurm.conf": "ClusterName=slurm\n"},
+			newData: map[string]string{"slurm.conf": "ClusterName=slurm\n"},
+			want:    "",
+		},
+		{
+			name:    "changed",
+			oldData: map[string]string{"slurm.conf": "ClusterName=slurm\n", "gres.conf": "AutoDetect=off\n"},
+			newData: map[string]string{"slurm.conf": "ClusterName=slurm\nMaxNodeCount=10\n", "cgroup.conf": "CgroupPlugin=autodetect\n"},
+			want: `--- a/cgroup.conf
++++ b/cgroup.conf
+@@ -0,0 +1 @@
++CgroupPlugin=autodetect
+--- a/gres.conf
++++ b/gres.conf
+@@ -1 +0,0 @@
+-AutoDetect=off
+--- a/slurm.conf
++++ b/slurm.conf
+@@ -1 +1,2 @@
+ ClusterName=slurm
++MaxNodeCount=10
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := configDiff(tt.oldData, tt.newData)
+			if err != nil {
+				t.Fatalf("configDiff() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("configDiff() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/crypto"
+)
+
+const (
+	// ReconfigureDelay is how long to wait after the rendered config changes
+	// before reconfiguring slurmctld, giving the kubelet time to update the
+	// files mounted into the pod.
+	ReconfigureDelay = 90 * time.Second
+
+	reconfiguredReasonPending   = "Pending"
+	reconfiguredReasonSucceeded = "Succeeded"
+	reconfiguredReasonFailed    = "Failed"
+	reconfiguredReasonNoClient  = "NoClient"
+)
+
+// Reasons for Controller events
+const (
+	// ReconfiguredReason is added to an event when slurmctld was reconfigured.
+	ReconfiguredReason = "Reconfigured"
+	// FailedReconfigureReason is added to an event when slurmctld could not be reconfigured.
+	FailedReconfigureReason = "FailedReconfigure"
+)
+
+// reconfigureBackoff bounds the reconfigure attempts made in one sync.
+var reconfigureBackoff = wait.Backoff{
+	Steps:    4,
+	Duration: 500 * time.Millisecond,
+	Factor:   2.0,
+	Jitter:   0.1,
+}
+
+// syncReconfigure tracks the hash of the configuration files mounted into
+// slurmctld and, when the operator is responsible for reconfiguring, requests
+// a reconfigure through slurmrestd after the configuration has changed.
+func (r *ControllerReconciler) syncReconfigure(
+	ctx context.Context,
+	controller *slinkyv1beta1.Controller,
+) error {
+	logger := log.FromContext(ctx)
+	key := client.ObjectKeyFromObject(controller).String()
+	status := &controller.Status
+
+	if controller.Spec.External {
+		return nil
+	}
+
+	hash, err := r.configHash(ctx, controller)
+	if err != nil {
+		return err
+	}
+	if hash != status.ConfigHash {
+		status.ConfigHash = hash
+		meta.RemoveStatusCondition(&status.Conditions, slinkyv1beta1.ControllerReconfigured)
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               slinkyv1beta1.ControllerReconfigured,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: controller.Generation,
+			Reason:             reconfiguredReasonPending,
+			Message:            "Waiting for the kubelet to update the mounted config files.",
+		})
+	}
+
+	if controller.Spec.ReconfigureMode != slinkyv1beta1.ReconfigureModeOperator {
+		status.ReconfiguredHash = ""
+		meta.RemoveStatusCondition(&status.Conditions, slinkyv1beta1.ControllerReconfigured)
+		return nil
+	}
+
+	if status.ReconfiguredHash == status.ConfigHash {
+		return nil
+	}
+
+	if !r.slurmControl.HasClient(controller) {
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               slinkyv1beta1.ControllerReconfigured,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: controller.Generation,
+			Reason:             reconfiguredReasonNoClient,
+			Message:            "No Slurm client is available, a RestApi is required.",
+		})
+		return nil
+	}
+
+	condition := meta.FindStatusCondition(status.Conditions, slinkyv1beta1.ControllerReconfigured)
+	if condition != nil && condition.Reason == reconfiguredReasonPending {
+		if wait := ReconfigureDelay - time.Since(condition.LastTransitionTime.Time); wait > 0 {
+			logger.V(1).Info("Delaying reconfigure until mounted config files are updated",
+				"configHash", hash, "wait", wait)
+			durationStore.Push(key, wait)
+			return nil
+		}
+	}
+
+	logger.Info("Reconfiguring slurmctld", "configHash", hash)
+	err = retry.OnError(reconfigureBackoff, func(error) bool { return true }, func() error {
+		return r.slurmControl.Reconfigure(ctx, controller)
+	})
+	if err != nil {
+		r.eventRecorder.Eventf(controller, corev1.EventTypeWarning, FailedReconfigureReason,
+			"Error reconfiguring: %v", err)
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               slinkyv1beta1.ControllerReconfigured,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: controller.Generation,
+			Reason:             reconfiguredReasonFailed,
+			Message:            fmt.Sprintf("Failed to reconfigure: %v", err),
+		})
+		return fmt.Errorf("failed to reconfigure: %w", err)
+	}
+
+	r.eventRecorder.Eventf(controller, corev1.EventTypeNormal, ReconfiguredReason,
+		"Reconfigured with config hash: %v", hash)
+	status.ReconfiguredHash = hash
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               slinkyv1beta1.ControllerReconfigured,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: controller.Generation,
+		Reason:             reconfiguredReasonSucceeded,
+		Message:            "Reconfigured with the current config.",
+	})
+
+	return nil
+}
+
+// configHash returns a hash of all ConfigMaps mounted into `/etc/slurm`.
+func (r *ControllerReconciler) configHash(
+	ctx context.Context,
+	controller *slinkyv1beta1.Controller,
+) (string, error) {
+	spec := controller.Spec
+	keys := []types.NamespacedName{controller.ConfigKey()}
+	refLists := [][]slinkyv1beta1.ObjectReference{
+		spec.ConfigFileRefs,
+		spec.PrologScriptRefs,
+		spec.EpilogScriptRefs,
+		spec.PrologSlurmctldScriptRefs,
+		spec.EpilogSlurmctldScriptRefs,
+	}
+	for _, refs := range refLists {
+		for _, ref := range refs {
+			keys = append(keys, types.NamespacedName{
+				Namespace: controller.Namespace,
+				Name:      ref.Name,
+			})
+		}
+	}
+	if spec.JobSubmitScriptRef != nil {
+		keys = append(keys, controller.JobSubmitScriptKey())
+	}
+
+	data := make(map[string]string)
+	for _, key := range keys {
+		configMap := &corev1.ConfigMap{}
+		if err := r.Get(ctx, key, configMap); err != nil {
+			return "", fmt.Errorf("failed to get ConfigMap (%s): %w", key, err)
+		}
+		for file, contents := range configMap.Data {
+			path := fmt.Sprintf("%s/%s", key.Name, file)
+			data[path] = path + "\n" + contents
+		}
+	}
+
+	return crypto.CheckSumFromMap(data), nil
+}
@@ -0,0 +1,200 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	slurmclient "github.com/SlinkyProject/slurm-client/pkg/client"
+	slurmfake "github.com/SlinkyProject/slurm-client/pkg/client/fake"
+	"github.com/SlinkyProject/slurm-client/pkg/client/interceptor"
+	slurmobject "github.com/SlinkyProject/slurm-client/pkg/object"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	"github.com/SlinkyProject/slurm-operator/internal/clientmap"
+	"github.com/SlinkyProject/slurm-operator/internal/controller/controller/slurmcontrol"
+)
+
+func newReconfigureSlurmClient(calls *int, err error) slurmclient.Client {
+	return slurmfake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+		Get: func(ctx context.Context, key slurmobject.ObjectKey, obj slurmobject.Object, opts ...slurmclient.GetOption) error {
+			*calls++
+			return err
+		},
+	}).Build()
+}
+
+func TestControllerReconciler_syncReconfigure(t *testing.T) {
+	ctx := context.Background()
+	newController := func(mode slinkyv1beta1.ReconfigureMode) *slinkyv1beta1.Controller {
+		return &slinkyv1beta1.Controller{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: corev1.NamespaceDefault,
+				Name:      "slurm",
+			},
+			Spec: slinkyv1beta1.ControllerSpec{
+				ReconfigureMode: mode,
+			},
+		}
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      newController("").ConfigKey().Name,
+		},
+		Data: map[string]string{
+			"slurm.conf": "ClusterName=slurm\n",
+		},
+	}
+	hash, err := (&ControllerReconciler{Client: fake.NewFakeClient(configMap.DeepCopy())}).
+		configHash(ctx, newController(""))
+	if err != nil {
+		t.Fatalf("configHash() error = %v", err)
+	}
+	withStatus := func(controller *slinkyv1beta1.Controller, reason string, since time.Duration) *slinkyv1beta1.Controller {
+		controller.Status.ConfigHash = hash
+		controller.Status.Conditions = []metav1.Condition{
+			{
+				Type:               slinkyv1beta1.ControllerReconfigured,
+				Status:             metav1.ConditionFalse,
+				Reason:             reason,
+				LastTransitionTime: metav1.NewTime(time.Now().Add(-since)),
+			},
+		}
+		return controller
+	}
+	tests := []struct {
+		name          string
+		controller    *slinkyv1beta1.Controller
+		noClient      bool
+		reconfigErr   error
+		wantErr       bool
+		wantCalls     int
+		wantReason    string
+		wantReconfig  string
+		wantCondition bool
+	}{
+		{
+			name:          "sidecar",
+			controller:    newController(slinkyv1beta1.ReconfigureModeSidecar),
+			wantCalls:     0,
+			wantCondition: false,
+		},
+		{
+			name:          "operator, config changed",
+			controller:    newController(slinkyv1beta1.ReconfigureModeOperator),
+			wantCalls:     0,
+			wantReason:    reconfiguredReasonPending,
+			wantCondition: true,
+		},
+		{
+			name:          "operator, pending",
+			controller:    withStatus(newController(slinkyv1beta1.ReconfigureModeOperator), reconfiguredReasonPending, 0),
+			wantCalls:     0,
+			wantReason:    reconfiguredReasonPending,
+			wantCondition: true,
+		},
+		{
+			name:          "operator, pending elapsed",
+			controller:    withStatus(newController(slinkyv1beta1.ReconfigureModeOperator), reconfiguredReasonPending, ReconfigureDelay),
+			wantCalls:     1,
+			wantReason:    reconfiguredReasonSucceeded,
+			wantReconfig:  hash,
+			wantCondition: true,
+		},
+		{
+			name:          "operator, failed",
+			controller:    withStatus(newController(slinkyv1beta1.ReconfigureModeOperator), reconfiguredReasonPending, ReconfigureDelay),
+			reconfigErr:   errors.New(http.StatusText(http.StatusInternalServerError)),
+			wantErr:       true,
+			wantCalls:     reconfigureBackoff.Steps,
+			wantReason:    reconfiguredReasonFailed,
+			wantCondition: true,
+		},
+		{
+			name:          "operator, retry after failure",
+			controller:    withStatus(newController(slinkyv1beta1.ReconfigureModeOperator), reconfiguredReasonFailed, 0),
+			wantCalls:     1,
+			wantReason:    reconfiguredReasonSucceeded,
+			wantReconfig:  hash,
+			wantCondition: true,
+		},
+		{
+			name:          "operator, no client",
+			controller:    withStatus(newController(slinkyv1beta1.ReconfigureModeOperator), reconfiguredReasonPending, ReconfigureDelay),
+			noClient:      true,
+			wantCalls:     0,
+			wantReason:    reconfiguredReasonNoClient,
+			wantCondition: true,
+		},
+		{
+			name: "operator, up to date",
+			controller: func() *slinkyv1beta1.Controller {
+				controller := withStatus(newController(slinkyv1beta1.ReconfigureModeOperator), reconfiguredReasonSucceeded, 0)
+				controller.Status.ReconfiguredHash = hash
+				return controller
+			}(),
+			wantCalls:     0,
+			wantReason:    reconfiguredReasonSucceeded,
+			wantReconfig:  hash,
+			wantCondition: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			cm := clientmap.NewClientMap()
+			if !tt.noClient {
+				cm.Add(client.ObjectKeyFromObject(tt.controller), newReconfigureSlurmClient(&calls, tt.reconfigErr))
+			}
+			r := &ControllerReconciler{
+				Client:        fake.NewFakeClient(configMap.DeepCopy()),
+				slurmControl:  slurmcontrol.NewSlurmControl(cm),
+				eventRecorder: record.NewFakeRecorder(10),
+			}
+			err := r.syncReconfigure(ctx, tt.controller)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("syncReconfigure() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("syncReconfigure() calls = %v, want %v", calls, tt.wantCalls)
+			}
+			status := tt.controller.Status
+			if status.ConfigHash != hash {
+				t.Errorf("Status.ConfigHash = %v, want %v", status.ConfigHash, hash)
+			}
+			if status.ReconfiguredHash != tt.wantReconfig {
+				t.Errorf("Status.ReconfiguredHash = %v, want %v", status.ReconfiguredHash, tt.wantReconfig)
+			}
+			condition := meta.FindStatusCondition(status.Conditions, slinkyv1beta1.ControllerReconfigured)
+			if (condition != nil) != tt.wantCondition {
+				t.Fatalf("Reconfigured condition = %v, want present %v", condition, tt.wantCondition)
+			}
+			if condition != nil && condition.Reason != tt.wantReason {
+				t.Errorf("Reconfigured condition reason = %v, want %v", condition.Reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func Test_durationStore_reconfigureDelay(t *testing.T) {
+	key := "slurm/test-reconfigure-delay"
+	wait := 10 * time.Second
+	durationStore.Push(key, wait)
+	durationStore.Push(key, LicenseSyncInterval)
+	durationStore.Push(key, PartitionDriftInterval)
+	if got := durationStore.Pop(key); got != wait {
+		t.Errorf("durationStore.Pop() = %v, want %v", got, wait)
+	}
+}
@@ -35,6 +35,8 @@ func (r *ControllerReconciler) Sync(ctx context.Context, req reconcile.Request)
 		}
 		return err
 	}
+	// Sync steps record their progress on the status of the controller.
+	storedStatus := controller.Status.DeepCopy()
 
 	syncSteps := []SyncStep{
 		{
@@ -66,6 +68,15 @@ func (r *ControllerReconciler) Sync(ctx context.Context, req reconcile.Request)
 				if err != nil {
 					return fmt.Errorf("failed to build: %w", err)
 				}
+				if staged, err := r.syncPendingConfig(ctx, controller, object); err != nil || staged {
+					return err
+				}
+				if err := r.syncConfigRevision(ctx, controller, object); err != nil {
+					return err
+				}
+				if err := r.recordConfigChange(ctx, controller, object); err != nil {
+					return err
+				}
 				if err := objectutils.SyncObject(r.Client, ctx, object, true); err != nil {
 					return fmt.Errorf("failed to sync object (%s): %w", klog.KObj(object), err)
 				}
@@ -88,6 +99,12 @@ func (r *ControllerReconciler) Sync(ctx context.Context, req reconcile.Request)
 				return nil
 			},
 		},
+		{
+			Name: "Reconfigure",
+			Sync: func(ctx context.Context, controller *slinkyv1beta1.Controller) error {
+				return r.syncReconfigure(ctx, controller)
+			},
+		},
 		{
 			Name: "ServiceMonitor",
 			Sync: func(ctx context.Context, controller *slinkyv1beta1.Controller) error {
@@ -115,7 +132,7 @@ func (r *ControllerReconciler) Sync(ctx context.Context, req reconcile.Request)
 		if err := s.Sync(ctx, controller); err != nil {
 			e := fmt.Errorf("[%s]: %w", s.Name, err)
 			errors := []error{e}
-			if err := r.syncStatus(ctx, controller); err != nil {
+			if err := r.syncStatus(ctx, controller, storedStatus); err != nil {
 				e := fmt.Errorf("[%s]: %w", s.Name, err)
 				errors = append(errors, e)
 			}
@@ -123,5 +140,5 @@ func (r *ControllerReconciler) Sync(ctx context.Context, req reconcile.Request)
 		}
 	}
 
-	return r.syncStatus(ctx, controller)
+	return r.syncStatus(ctx, controller, storedStatus)
 }
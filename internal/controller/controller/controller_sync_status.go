@@ -18,21 +18,37 @@ import (
 	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
 )
 
-// syncStatus handles determining and updating the status.
+// syncStatus handles determining and updating the status. The storedStatus is
+// the status before the sync steps recorded their progress on the controller.
 func (r *ControllerReconciler) syncStatus(
 	ctx context.Context,
 	controller *slinkyv1beta1.Controller,
+	storedStatus *slinkyv1beta1.ControllerStatus,
 ) error {
 	logger := log.FromContext(ctx)
 
 	newStatus := &slinkyv1beta1.ControllerStatus{
-		Conditions: []metav1.Condition{},
+		Conditions:         []metav1.Condition{},
+		ConfigHash:         controller.Status.ConfigHash,
+		ReconfiguredHash:   controller.Status.ReconfiguredHash,
+		LastConfigChange:   controller.Status.LastConfigChange,
+		PendingConfig:      controller.Status.PendingConfig,
+		ConfigRevision:     controller.Status.ConfigRevision,
+		ConfigRevisionName: controller.Status.ConfigRevisionName,
+		CollisionCount:     controller.Status.CollisionCount,
+		Licenses:           controller.Status.Licenses,
 	}
 	newStatus.Conditions = append(newStatus.Conditions, controller.Status.Conditions...)
 
-	if apiequality.Semantic.DeepEqual(controller.Status, newStatus) {
+	r.syncPartitionDrift(ctx, controller, newStatus)
+	if err := r.syncNodeCount(ctx, controller, newStatus); err != nil {
+		return err
+	}
+	r.syncLicenses(ctx, controller, newStatus)
+
+	if apiequality.Semantic.DeepEqual(*storedStatus, *newStatus) {
 		logger.V(2).Info("Controller Status has not changed, skipping status update",
-			"controller", klog.KObj(controller), "status", controller.Status)
+			"controller", klog.KObj(controller), "status", storedStatus)
 		return nil
 	}
 
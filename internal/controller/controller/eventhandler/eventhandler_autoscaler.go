@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package eventhandler
+
+import (
+	"context"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/SlinkyProject/slurm-operator/internal/utils/objectutils"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/refresolver"
+)
+
+func NewHorizontalPodAutoscalerEventHandler(reader client.Reader) *HorizontalPodAutoscalerEventHandler {
+	return &HorizontalPodAutoscalerEventHandler{
+		Reader:      reader,
+		refResolver: refresolver.New(reader),
+	}
+}
+
+var _ handler.EventHandler = &HorizontalPodAutoscalerEventHandler{}
+
+type HorizontalPodAutoscalerEventHandler struct {
+	client.Reader
+	refResolver *refresolver.RefResolver
+}
+
+// Create implements handler.TypedEventHandler.
+func (e *HorizontalPodAutoscalerEventHandler) Create(
+	ctx context.Context,
+	evt event.CreateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.Object, q)
+}
+
+// Delete implements handler.TypedEventHandler.
+func (e *HorizontalPodAutoscalerEventHandler) Delete(
+	ctx context.Context,
+	evt event.DeleteEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.Object, q)
+}
+
+// Generic implements handler.TypedEventHandler.
+func (e *HorizontalPodAutoscalerEventHandler) Generic(
+	ctx context.Context,
+	evt event.GenericEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	// Intentionally blank
+}
+
+// Update implements handler.TypedEventHandler.
+func (e *HorizontalPodAutoscalerEventHandler) Update(
+	ctx context.Context,
+	evt event.UpdateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.ObjectNew, q)
+}
+
+func (e *HorizontalPodAutoscalerEventHandler) enqueueRequest(ctx context.Context, obj client.Object, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	hpa, ok := obj.(*autoscalingv2.HorizontalPodAutoscaler)
+	if !ok {
+		return
+	}
+
+	nodeset, err := e.refResolver.GetNodeSetForHorizontalPodAutoscaler(ctx, hpa)
+	if err != nil {
+		return
+	}
+
+	controller, err := e.refResolver.GetController(ctx, nodeset.Spec.ControllerRef)
+	if err != nil {
+		return
+	}
+
+	objectutils.EnqueueRequest(q, controller)
+}
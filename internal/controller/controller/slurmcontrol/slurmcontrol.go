@@ -0,0 +1,135 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmcontrol
+
+import (
+	"context"
+	"net/http"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	api "github.com/SlinkyProject/slurm-client/api/v0044"
+	slurmclient "github.com/SlinkyProject/slurm-client/pkg/client"
+	slurmtypes "github.com/SlinkyProject/slurm-client/pkg/types"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	"github.com/SlinkyProject/slurm-operator/internal/clientmap"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/slurmapi"
+)
+
+type SlurmControlInterface interface {
+	// HasClient reports if there is a slurm client for the controller.
+	HasClient(controller *slinkyv1beta1.Controller) bool
+	// GetPartitions returns the partitions known to the running slurmctld.
+	GetPartitions(ctx context.Context, controller *slinkyv1beta1.Controller) ([]slurmtypes.V0044PartitionInfo, error)
+	// Reconfigure requests that slurmctld rereads its configuration files.
+	Reconfigure(ctx context.Context, controller *slinkyv1beta1.Controller) error
+	// GetLicenses returns the licenses known to the running slurmctld.
+	GetLicenses(ctx context.Context, controller *slinkyv1beta1.Controller) ([]api.V0044License, error)
+}
+
+// realSlurmControl is the default implementation of SlurmControlInterface.
+type realSlurmControl struct {
+	clientMap    *clientmap.ClientMap
+	newAPIClient slurmapi.ClientFunc
+}
+
+// HasClient implements SlurmControlInterface.
+func (r *realSlurmControl) HasClient(controller *slinkyv1beta1.Controller) bool {
+	return r.lookupClient(controller) != nil
+}
+
+// GetPartitions implements SlurmControlInterface.
+func (r *realSlurmControl) GetPartitions(ctx context.Context, controller *slinkyv1beta1.Controller) ([]slurmtypes.V0044PartitionInfo, error) {
+	logger := log.FromContext(ctx)
+
+	slurmClient := r.lookupClient(controller)
+	if slurmClient == nil {
+		logger.V(2).Info("no client for controller, cannot do GetPartitions()")
+		return nil, nil
+	}
+
+	partitionList := &slurmtypes.V0044PartitionInfoList{}
+	if err := slurmClient.List(ctx, partitionList); err != nil {
+		if tolerateError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return partitionList.Items, nil
+}
+
+// Reconfigure implements SlurmControlInterface.
+func (r *realSlurmControl) Reconfigure(ctx context.Context, controller *slinkyv1beta1.Controller) error {
+	logger := log.FromContext(ctx)
+
+	slurmClient := r.lookupClient(controller)
+	if slurmClient == nil {
+		logger.V(2).Info("no client for controller, cannot do Reconfigure()")
+		return nil
+	}
+
+	reconfigure := &slurmtypes.V0044Reconfigure{}
+	if err := slurmClient.Get(ctx, reconfigure.GetKey(), reconfigure); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetLicenses implements SlurmControlInterface.
+func (r *realSlurmControl) GetLicenses(ctx context.Context, controller *slinkyv1beta1.Controller) ([]api.V0044License, error) {
+	logger := log.FromContext(ctx)
+
+	slurmClient := r.lookupClient(controller)
+	if slurmClient == nil {
+		logger.V(2).Info("no client for controller, cannot do GetLicenses()")
+		return nil, nil
+	}
+
+	apiClient, err := r.newAPIClient(slurmClient)
+	if err != nil {
+		return nil, err
+	}
+	res, err := apiClient.SlurmV0044GetLicensesWithResponse(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if res.JSON200 == nil {
+		var oapiErrors *api.V0044OpenapiErrors
+		if res.JSONDefault != nil {
+			oapiErrors = res.JSONDefault.Errors
+		}
+		return nil, slurmapi.CheckResponse(res.StatusCode(), oapiErrors)
+	}
+
+	return res.JSON200.Licenses, nil
+}
+
+func (r *realSlurmControl) lookupClient(controller *slinkyv1beta1.Controller) slurmclient.Client {
+	return r.clientMap.Get(client.ObjectKeyFromObject(controller))
+}
+
+var _ SlurmControlInterface = &realSlurmControl{}
+
+func NewSlurmControl(clientMap *clientmap.ClientMap) SlurmControlInterface {
+	return &realSlurmControl{
+		clientMap:    clientMap,
+		newAPIClient: slurmapi.NewClient,
+	}
+}
+
+func tolerateError(err error) bool {
+	if err == nil {
+		return true
+	}
+	errText := err.Error()
+	if errText == http.StatusText(http.StatusNotFound) ||
+		errText == http.StatusText(http.StatusNoContent) {
+		return true
+	}
+	return false
+}
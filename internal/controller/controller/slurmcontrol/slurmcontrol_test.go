@@ -0,0 +1,266 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmcontrol
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	api "github.com/SlinkyProject/slurm-client/api/v0044"
+	"github.com/SlinkyProject/slurm-client/pkg/client"
+	apifake "github.com/SlinkyProject/slurm-client/pkg/client/api/v0044/fake"
+	apiinterceptor "github.com/SlinkyProject/slurm-client/pkg/client/api/v0044/interceptor"
+	"github.com/SlinkyProject/slurm-client/pkg/client/fake"
+	"github.com/SlinkyProject/slurm-client/pkg/client/interceptor"
+	"github.com/SlinkyProject/slurm-client/pkg/object"
+	"github.com/SlinkyProject/slurm-client/pkg/types"
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	"github.com/SlinkyProject/slurm-operator/internal/clientmap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+func newController(name string) *slinkyv1beta1.Controller {
+	return &slinkyv1beta1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      name,
+		},
+	}
+}
+
+func newSlurmClientMap(controllerName string, client client.Client) *clientmap.ClientMap {
+	cm := clientmap.NewClientMap()
+	key := k8stypes.NamespacedName{
+		Namespace: corev1.NamespaceDefault,
+		Name:      controllerName,
+	}
+	cm.Add(key, client)
+	return cm
+}
+
+func Test_realSlurmControl_HasClient(t *testing.T) {
+	controller := newController("slurm")
+	tests := []struct {
+		name      string
+		clientMap *clientmap.ClientMap
+		want      bool
+	}{
+		{
+			name:      "client",
+			clientMap: newSlurmClientMap(controller.Name, fake.NewFakeClient()),
+			want:      true,
+		},
+		{
+			name:      "no client",
+			clientMap: clientmap.NewClientMap(),
+			want:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewSlurmControl(tt.clientMap)
+			if got := r.HasClient(controller); got != tt.want {
+				t.Errorf("realSlurmControl.HasClient() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_realSlurmControl_GetPartitions(t *testing.T) {
+	ctx := context.Background()
+	controller := newController("slurm")
+	partitionList := &types.V0044PartitionInfoList{
+		Items: []types.V0044PartitionInfo{
+			{V0044PartitionInfo: api.V0044PartitionInfo{Name: ptr.To("foo")}},
+			{V0044PartitionInfo: api.V0044PartitionInfo{Name: ptr.To("bar")}},
+		},
+	}
+	tests := []struct {
+		name      string
+		clientMap *clientmap.ClientMap
+		wantCount int
+		wantErr   bool
+	}{
+		{
+			name: "smoke",
+			clientMap: newSlurmClientMap(controller.Name,
+				fake.NewClientBuilder().WithLists(partitionList).Build()),
+			wantCount: 2,
+		},
+		{
+			name:      "no client",
+			clientMap: clientmap.NewClientMap(),
+			wantCount: 0,
+		},
+		{
+			name: "not found",
+			clientMap: newSlurmClientMap(controller.Name,
+				fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+					List: func(ctx context.Context, list object.ObjectList, opts ...client.ListOption) error {
+						return errors.New(http.StatusText(http.StatusNotFound))
+					},
+				}).Build()),
+			wantCount: 0,
+		},
+		{
+			name: "error",
+			clientMap: newSlurmClientMap(controller.Name,
+				fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+					List: func(ctx context.Context, list object.ObjectList, opts ...client.ListOption) error {
+						return errors.New(http.StatusText(http.StatusInternalServerError))
+					},
+				}).Build()),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewSlurmControl(tt.clientMap)
+			got, err := r.GetPartitions(ctx, controller)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("realSlurmControl.GetPartitions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if len(got) != tt.wantCount {
+				t.Errorf("realSlurmControl.GetPartitions() = %v, want count %v", got, tt.wantCount)
+			}
+		})
+	}
+}
+
+func Test_realSlurmControl_Reconfigure(t *testing.T) {
+	ctx := context.Background()
+	controller := newController("slurm")
+	tests := []struct {
+		name      string
+		clientMap *clientmap.ClientMap
+		wantErr   bool
+	}{
+		{
+			name: "smoke",
+			clientMap: newSlurmClientMap(controller.Name,
+				fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+					Get: func(ctx context.Context, key object.ObjectKey, obj object.Object, opts ...client.GetOption) error {
+						if _, ok := obj.(*types.V0044Reconfigure); !ok {
+							return errors.New("unexpected object type")
+						}
+						return nil
+					},
+				}).Build()),
+		},
+		{
+			name:      "no client",
+			clientMap: clientmap.NewClientMap(),
+		},
+		{
+			name: "error",
+			clientMap: newSlurmClientMap(controller.Name,
+				fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+					Get: func(ctx context.Context, key object.ObjectKey, obj object.Object, opts ...client.GetOption) error {
+						return errors.New(http.StatusText(http.StatusInternalServerError))
+					},
+				}).Build()),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewSlurmControl(tt.clientMap)
+			if err := r.Reconfigure(ctx, controller); (err != nil) != tt.wantErr {
+				t.Errorf("realSlurmControl.Reconfigure() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_realSlurmControl_GetLicenses(t *testing.T) {
+	ctx := context.Background()
+	controller := newController("slurm")
+	newAPIClient := func(funcs apiinterceptor.Funcs) func(client.Client) (api.ClientWithResponsesInterface, error) {
+		return func(client.Client) (api.ClientWithResponsesInterface, error) {
+			return apifake.NewFakeClientBuilder().WithInterceptorFuncs(funcs).Build(), nil
+		}
+	}
+	tests := []struct {
+		name         string
+		clientMap    *clientmap.ClientMap
+		newAPIClient func(client.Client) (api.ClientWithResponsesInterface, error)
+		wantCount    int
+		wantErr      bool
+	}{
+		{
+			name:      "smoke",
+			clientMap: newSlurmClientMap(controller.Name, fake.NewFakeClient()),
+			newAPIClient: newAPIClient(apiinterceptor.Funcs{
+				SlurmV0044GetLicensesWithResponse: func(ctx context.Context, reqEditors ...api.RequestEditorFn) (*api.SlurmV0044GetLicensesResponse, error) {
+					res := &api.SlurmV0044GetLicensesResponse{
+						HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+						JSON200: &api.V0044OpenapiLicensesResp{
+							Licenses: api.V0044Licenses{
+								{LicenseName: ptr.To("matlab"), Total: ptr.To[int32](10)},
+								{LicenseName: ptr.To("fluent@db"), Total: ptr.To[int32](5), Remote: ptr.To(true)},
+							},
+						},
+					}
+					return res, nil
+				},
+			}),
+			wantCount: 2,
+		},
+		{
+			name:         "no client",
+			clientMap:    clientmap.NewClientMap(),
+			newAPIClient: newAPIClient(apiinterceptor.Funcs{}),
+		},
+		{
+			name:      "error status",
+			clientMap: newSlurmClientMap(controller.Name, fake.NewFakeClient()),
+			newAPIClient: newAPIClient(apiinterceptor.Funcs{
+				SlurmV0044GetLicensesWithResponse: func(ctx context.Context, reqEditors ...api.RequestEditorFn) (*api.SlurmV0044GetLicensesResponse, error) {
+					res := &api.SlurmV0044GetLicensesResponse{
+						HTTPResponse: &http.Response{StatusCode: http.StatusInternalServerError},
+						JSONDefault: &api.V0044OpenapiLicensesResp{
+							Errors: &api.V0044OpenapiErrors{
+								{Error: ptr.To("failed to query licenses")},
+							},
+						},
+					}
+					return res, nil
+				},
+			}),
+			wantErr: true,
+		},
+		{
+			name:      "error",
+			clientMap: newSlurmClientMap(controller.Name, fake.NewFakeClient()),
+			newAPIClient: newAPIClient(apiinterceptor.Funcs{
+				SlurmV0044GetLicensesWithResponse: func(ctx context.Context, reqEditors ...api.RequestEditorFn) (*api.SlurmV0044GetLicensesResponse, error) {
+					return nil, errors.New(http.StatusText(http.StatusBadGateway))
+				},
+			}),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &realSlurmControl{
+				clientMap:    tt.clientMap,
+				newAPIClient: tt.newAPIClient,
+			}
+			got, err := r.GetLicenses(ctx, controller)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("realSlurmControl.GetLicenses() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if len(got) != tt.wantCount {
+				t.Errorf("realSlurmControl.GetLicenses() = %v, want count %v", got, tt.wantCount)
+			}
+		})
+	}
+}
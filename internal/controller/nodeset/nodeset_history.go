@@ -10,6 +10,7 @@ import (
 	"maps"
 
 	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/kubernetes/pkg/controller/history"
 	"k8s.io/utils/ptr"
@@ -136,6 +137,32 @@ func (r *NodeSetReconciler) getNodeSetRevisions(
 	return currentRevision, updateRevision, collisionCount, nil
 }
 
+// setRevisionHashes records the hashes of the pod inputs that come from the
+// Controller, such as the `slurm.conf` settings that require a slurmd restart,
+// in the pod template of the nodeset. The nodeset must be a copy, as the hashes
+// are not part of the NodeSet spec. They become part of its revision, so that a
+// change rolls the pods like a change to the NodeSet does.
+func (r *NodeSetReconciler) setRevisionHashes(ctx context.Context, nodeset *slinkyv1beta1.NodeSet) error {
+	controller := &slinkyv1beta1.Controller{}
+	if err := r.Get(ctx, nodeset.Spec.ControllerRef.NamespacedName(), controller); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	hashes, err := r.builder.BuildWorkerRevisionHashes(ctx, controller)
+	if err != nil {
+		return err
+	}
+	if nodeset.Spec.Template.Metadata.Annotations == nil {
+		nodeset.Spec.Template.Metadata.Annotations = make(map[string]string, len(hashes))
+	}
+	maps.Copy(nodeset.Spec.Template.Metadata.Annotations, hashes)
+
+	return nil
+}
+
 // nextRevision finds the next valid revision number based on revisions. If the length of revisions
 // is 0 this is 1. Otherwise, it is 1 greater than the largest revision's Revision. This method
 // assumes that revisions has been sorted by Revision.
@@ -189,25 +216,35 @@ func getPatch(nodeset *slinkyv1beta1.NodeSet) ([]byte, error) {
 	objCopy := make(map[string]any)
 	specCopy := make(map[string]any)
 
-	// Create a patch of the NodeSet that replaces spec.template
+	// Create a patch of the NodeSet that replaces the pod defining fields of
+	// the spec. Objects are marked with `$patch: replace` so that their keys
+	// are replaced rather than merged; strings and lists without a merge key
+	// are already replaced as a whole. Fields omitted as zero are left out so
+	// that the patch, and the revision hash, of NodeSets that do not use them
+	// remain unchanged.
 	spec := raw["spec"].(map[string]any)
-	template := spec["template"].(map[string]any)
-	specCopy["template"] = template
-	template["$patch"] = "replace"
-	if slurmd, ok := spec["slurmd"].(map[string]any); ok {
-		slurmd["$patch"] = "replace"
-		specCopy["slurmd"] = slurmd
-	}
-	if extraConf, ok := spec["extraConf"].(string); ok {
-		specCopy["extraConf"] = extraConf
-	}
-	if logfile, ok := spec["logfile"].(map[string]any); ok {
-		logfile["$patch"] = "replace"
-		specCopy["logfile"] = logfile
-	}
-	if ssh, ok := spec["ssh"].(map[string]any); ok {
-		ssh["$patch"] = "replace"
-		specCopy["ssh"] = ssh
+	for _, field := range []string{
+		"template",
+		"slurmd",
+		"extraConf",
+		"resourceOverhead",
+		"gres",
+		"spank",
+		"prologScriptRefs",
+		"epilogScriptRefs",
+		"healthCheck",
+		"logfile",
+		"ssh",
+	} {
+		switch value := spec[field].(type) {
+		case nil:
+			continue
+		case map[string]any:
+			value["$patch"] = "replace"
+			specCopy[field] = value
+		default:
+			specCopy[field] = value
+		}
 	}
 	objCopy["spec"] = specCopy
 	patch, err := json.Marshal(objCopy)
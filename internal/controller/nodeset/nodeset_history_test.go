@@ -5,12 +5,15 @@ package nodeset
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"k8s.io/kubernetes/pkg/controller/history"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -268,3 +271,212 @@ func TestNodeSetReconciler_getNodeSetRevisions(t *testing.T) {
 		})
 	}
 }
+
+func Test_getPatch(t *testing.T) {
+	type args struct {
+		previous *slinkyv1beta1.NodeSet
+		current  *slinkyv1beta1.NodeSet
+	}
+	type testCaseFields struct {
+		name    string
+		args    args
+		changed bool
+	}
+	tests := []testCaseFields{
+		{
+			name: "unchanged",
+			args: args{
+				previous: newNodeSet("foo", "slurm", 2),
+				current:  newNodeSet("foo", "slurm", 2),
+			},
+		},
+		func() testCaseFields {
+			previous := newNodeSet("foo", "slurm", 2)
+			previous.Spec.ResourceOverhead = slinkyv1beta1.NodeSetResourceOverhead{
+				CoreSpecCount: 1,
+			}
+
+			current := previous.DeepCopy()
+			current.Spec.ResourceOverhead = slinkyv1beta1.NodeSetResourceOverhead{
+				MemSpecLimit: ptr.To(resource.MustParse("1Gi")),
+			}
+
+			return testCaseFields{
+				name: "resource overhead",
+				args: args{
+					previous: previous,
+					current:  current,
+				},
+				changed: true,
+			}
+		}(),
+		func() testCaseFields {
+			previous := newNodeSet("foo", "slurm", 2)
+			previous.Spec.ExtraConf = "Features=a"
+			previous.Spec.Gres = []slinkyv1beta1.NodeSetGres{
+				{Name: "gpu", Type: "a100", Count: "4"},
+			}
+			previous.Spec.Spank = slinkyv1beta1.Spank{
+				Plugins: []slinkyv1beta1.SpankPlugin{{Path: "a.so", Optional: true}},
+			}
+
+			current := previous.DeepCopy()
+			current.Spec.Replicas = ptr.To[int32](4)
+			current.Spec.Slurmd.Image = "slurmd:next"
+			current.Spec.Slurmd.Args = []string{"-v"}
+			current.Spec.Template.Metadata.Labels["baz"] = "qux"
+			current.Spec.ExtraConf = "Features=b"
+			current.Spec.Gres = []slinkyv1beta1.NodeSetGres{
+				{Name: "gpu", File: "/dev/nvidia[0-3]"},
+				{Name: "shard", Count: "8"},
+			}
+			current.Spec.Spank = slinkyv1beta1.Spank{
+				Plugins: []slinkyv1beta1.SpankPlugin{{Path: "b.so", Args: []string{"c"}}},
+			}
+
+			return testCaseFields{
+				name: "rollback",
+				args: args{
+					previous: previous,
+					current:  current,
+				},
+				changed: true,
+			}
+		}(),
+		func() testCaseFields {
+			previous := newNodeSet("foo", "slurm", 2)
+			previous.Spec.PrologScriptRefs = []slinkyv1beta1.ObjectReference{{Name: "prolog-a"}}
+
+			current := previous.DeepCopy()
+			current.Spec.PrologScriptRefs = []slinkyv1beta1.ObjectReference{{Name: "prolog-b"}, {Name: "prolog-c"}}
+
+			return testCaseFields{
+				name: "prolog scripts",
+				args: args{
+					previous: previous,
+					current:  current,
+				},
+				changed: true,
+			}
+		}(),
+		func() testCaseFields {
+			previous := newNodeSet("foo", "slurm", 2)
+			previous.Spec.EpilogScriptRefs = []slinkyv1beta1.ObjectReference{{Name: "epilog-a"}, {Name: "epilog-b"}}
+
+			current := previous.DeepCopy()
+			current.Spec.EpilogScriptRefs = []slinkyv1beta1.ObjectReference{{Name: "epilog-b"}}
+
+			return testCaseFields{
+				name: "epilog scripts",
+				args: args{
+					previous: previous,
+					current:  current,
+				},
+				changed: true,
+			}
+		}(),
+		func() testCaseFields {
+			previous := newNodeSet("foo", "slurm", 2)
+			previous.Spec.HealthCheck = slinkyv1beta1.NodeSetHealthCheck{
+				ScriptRefs: []slinkyv1beta1.ObjectReference{{Name: "gpu-checks"}},
+			}
+
+			current := previous.DeepCopy()
+			current.Spec.HealthCheck = slinkyv1beta1.NodeSetHealthCheck{
+				ScriptRefs: []slinkyv1beta1.ObjectReference{{Name: "node-checks", Namespace: "other"}},
+			}
+
+			return testCaseFields{
+				name: "health check",
+				args: args{
+					previous: previous,
+					current:  current,
+				},
+				changed: true,
+			}
+		}(),
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch, err := getPatch(tt.args.previous)
+			if err != nil {
+				t.Fatalf("getPatch() error = %v", err)
+			}
+			currentPatch, err := getPatch(tt.args.current)
+			if err != nil {
+				t.Fatalf("getPatch() error = %v", err)
+			}
+			if changed := string(currentPatch) != string(patch); changed != tt.changed {
+				t.Errorf("getPatch() changed = %v, want %v", changed, tt.changed)
+			}
+			currentBytes, err := json.Marshal(tt.args.current)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			gotBytes, err := strategicpatch.StrategicMergePatch(currentBytes, patch, &slinkyv1beta1.NodeSet{})
+			if err != nil {
+				t.Fatalf("StrategicMergePatch() error = %v", err)
+			}
+			got := &slinkyv1beta1.NodeSet{}
+			if err := json.Unmarshal(gotBytes, got); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+
+			// Fields outside of the patch are kept, the rest are rolled back.
+			want := tt.args.previous.DeepCopy()
+			want.Spec.Replicas = tt.args.current.Spec.Replicas
+			if !apiequality.Semantic.DeepEqual(got.Spec, want.Spec) {
+				t.Errorf("getPatch() rollback = %v, want %v", got.Spec, want.Spec)
+			}
+		})
+	}
+}
+
+func TestNodeSetReconciler_setRevisionHashes(t *testing.T) {
+	controller := &slinkyv1beta1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      "slurm",
+		},
+	}
+	newSlurmConfig := func(slurmConf string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: corev1.NamespaceDefault,
+				Name:      controller.ConfigKey().Name,
+			},
+			Data: map[string]string{
+				"slurm.conf": slurmConf,
+			},
+		}
+	}
+	revisionPatch := func(objs ...client.Object) string {
+		nodeset := newNodeSet("foo", controller.Name, 2)
+		r := newNodeSetController(fake.NewClientBuilder().WithObjects(objs...).Build(), nil)
+		if err := r.setRevisionHashes(context.Background(), nodeset); err != nil {
+			t.Fatalf("setRevisionHashes() error = %v", err)
+		}
+		patch, err := getPatch(nodeset)
+		if err != nil {
+			t.Fatalf("getPatch() error = %v", err)
+		}
+		return string(patch)
+	}
+
+	noController := revisionPatch()
+	want, err := getPatch(newNodeSet("foo", controller.Name, 2))
+	if err != nil {
+		t.Fatalf("getPatch() error = %v", err)
+	}
+	if noController != string(want) {
+		t.Errorf("setRevisionHashes() without a Controller changed the revision = %v, want %v", noController, string(want))
+	}
+
+	base := revisionPatch(controller, newSlurmConfig("AuthType=auth/slurm\nPartitionName=all Nodes=ALL\n"))
+	if got := revisionPatch(controller, newSlurmConfig("AuthType=auth/slurm\nPartitionName=debug Nodes=ALL\n")); got != base {
+		t.Errorf("setRevisionHashes() after a reconfigure change = %v, want %v", got, base)
+	}
+	if got := revisionPatch(controller, newSlurmConfig("AuthType=auth/munge\nPartitionName=all Nodes=ALL\n")); got == base {
+		t.Errorf("setRevisionHashes() after a slurmd restart change = %v, want a new revision", got)
+	}
+}
@@ -69,6 +69,10 @@ func (r *NodeSetReconciler) Sync(ctx context.Context, req reconcile.Request) err
 		return err
 	}
 
+	if err := r.setRevisionHashes(ctx, nodeset); err != nil {
+		return err
+	}
+
 	revisions, err := r.listRevisions(nodeset)
 	if err != nil {
 		return err
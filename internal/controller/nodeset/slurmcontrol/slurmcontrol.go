@@ -184,6 +184,11 @@ func (r *realSlurmControl) UpdateNodeTopology(ctx context.Context, nodeset *slin
 
 const nodeReasonPrefix = "slurm-operator:"
 
+// nodeReasonHealthCheckPrefix is the reason prefix used when a NodeSet health
+// check drains a node. It must match the controllerbuilder
+// nodeset-healthcheck.sh script.
+const nodeReasonHealthCheckPrefix = nodeReasonPrefix + " HealthCheck"
+
 // MakeNodeDrain implements SlurmControlInterface.
 func (r *realSlurmControl) MakeNodeDrain(ctx context.Context, nodeset *slinkyv1beta1.NodeSet, pod *corev1.Pod, reason string) error {
 	logger := log.FromContext(ctx)
@@ -261,6 +266,13 @@ func (r *realSlurmControl) MakeNodeUndrain(ctx context.Context, nodeset *slinkyv
 		return nil
 	}
 
+	// Only a passing health check may undrain a node that it drained.
+	if isHealthCheckReason(slurmNode) {
+		logger.V(1).Info("Node was drained by a health check, skipping undrain request",
+			"node", slurmNode.GetKey(), "nodeReason", slurmNode.Reason)
+		return nil
+	}
+
 	// If the reason is not empty, prefix it with nodeReasonPrefix
 	prefixedReason := ""
 	if reason != "" {
@@ -526,6 +538,10 @@ func (r *realSlurmControl) CalculateNodeStatus(ctx context.Context, nodeset *sli
 				nodeState(node, slurmconditions.PodConditionUndrain))
 			status.Undrain++
 		}
+		if len(nodeset.Spec.HealthCheck.ScriptRefs) > 0 {
+			status.NodeStates[nodeName] = append(status.NodeStates[nodeName],
+				nodeHealth(node))
+		}
 	}
 
 	return status, nil
@@ -621,3 +637,26 @@ func nodeState(node slurmtypes.V0044Node, condType corev1.PodConditionType) core
 		Message: ptr.Deref(node.Reason, ""),
 	}
 }
+
+// nodeHealth reports whether the node was drained by a NodeSet health check.
+func nodeHealth(node slurmtypes.V0044Node) corev1.PodCondition {
+	if node.GetStateAsSet().Has(slurmapi.V0044NodeStateDRAIN) && isHealthCheckReason(&node) {
+		return corev1.PodCondition{
+			Type:    slurmconditions.PodConditionHealthy,
+			Status:  corev1.ConditionFalse,
+			Reason:  "HealthCheckFailed",
+			Message: ptr.Deref(node.Reason, ""),
+		}
+	}
+	return corev1.PodCondition{
+		Type:   slurmconditions.PodConditionHealthy,
+		Status: corev1.ConditionTrue,
+		Reason: "HealthCheckPassed",
+	}
+}
+
+// isHealthCheckReason reports if the node reason was set by a NodeSet health
+// check.
+func isHealthCheckReason(node *slurmtypes.V0044Node) bool {
+	return strings.HasPrefix(ptr.Deref(node.Reason, ""), nodeReasonHealthCheckPrefix)
+}
@@ -292,6 +292,27 @@ func Test_realSlurmControl_MakeNodeUndrain(t *testing.T) {
 			},
 			wantUndrain: true,
 		},
+		{
+			name: "drained by health check",
+			fields: fields{
+				node: &types.V0044Node{
+					V0044Node: api.V0044Node{
+						Name: ptr.To(nodesetutils.GetNodeName(pod)),
+						State: ptr.To([]api.V0044NodeState{
+							api.V0044NodeStateDRAIN,
+						}),
+						Reason: ptr.To(nodeReasonHealthCheckPrefix + " 10-gpu.sh failed"),
+					},
+				},
+			},
+			args: args{
+				ctx:     ctx,
+				nodeset: nodeset,
+				pod:     pod,
+				reason:  "test",
+			},
+			wantUndrain: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -2208,6 +2229,64 @@ func Test_tolerateError(t *testing.T) {
 	}
 }
 
+func Test_nodeHealth(t *testing.T) {
+	tests := []struct {
+		name string
+		node types.V0044Node
+		want corev1.PodCondition
+	}{
+		{
+			name: "healthy",
+			node: types.V0044Node{
+				V0044Node: api.V0044Node{
+					State: ptr.To([]api.V0044NodeState{api.V0044NodeStateIDLE}),
+				},
+			},
+			want: corev1.PodCondition{
+				Type:   slurmconditions.PodConditionHealthy,
+				Status: corev1.ConditionTrue,
+				Reason: "HealthCheckPassed",
+			},
+		},
+		{
+			name: "drained by admin",
+			node: types.V0044Node{
+				V0044Node: api.V0044Node{
+					State:  ptr.To([]api.V0044NodeState{api.V0044NodeStateIDLE, api.V0044NodeStateDRAIN}),
+					Reason: ptr.To("Drain by admin"),
+				},
+			},
+			want: corev1.PodCondition{
+				Type:   slurmconditions.PodConditionHealthy,
+				Status: corev1.ConditionTrue,
+				Reason: "HealthCheckPassed",
+			},
+		},
+		{
+			name: "drained by health check",
+			node: types.V0044Node{
+				V0044Node: api.V0044Node{
+					State:  ptr.To([]api.V0044NodeState{api.V0044NodeStateIDLE, api.V0044NodeStateDRAIN}),
+					Reason: ptr.To("slurm-operator: HealthCheck 10-gpu.sh failed: no devices"),
+				},
+			},
+			want: corev1.PodCondition{
+				Type:    slurmconditions.PodConditionHealthy,
+				Status:  corev1.ConditionFalse,
+				Reason:  "HealthCheckFailed",
+				Message: "slurm-operator: HealthCheck 10-gpu.sh failed: no devices",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nodeHealth(tt.node); !apiequality.Semantic.DeepEqual(got, tt.want) {
+				t.Errorf("nodeHealth() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_nodeState(t *testing.T) {
 	type args struct {
 		node  types.V0044Node
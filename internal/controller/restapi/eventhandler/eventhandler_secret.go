@@ -78,6 +78,16 @@ func (e *SecretEventHandler) enqueueRequest(
 	}
 	secretKey := client.ObjectKeyFromObject(secret)
 
+	restapiList := &slinkyv1beta1.RestApiList{}
+	if err := e.List(ctx, restapiList, client.InNamespace(secret.Namespace)); err != nil {
+		logger.Error(err, "failed to list restapi CRs")
+	}
+	for _, restapi := range restapiList.Items {
+		if ref := restapi.Spec.TLS.SecretRef; ref != nil && ref.Name == secret.Name {
+			objectutils.EnqueueRequest(q, &restapi)
+		}
+	}
+
 	controllerList := &slinkyv1beta1.ControllerList{}
 	if err := e.List(ctx, controllerList); err != nil {
 		logger.Error(err, "failed to list controller CRs")
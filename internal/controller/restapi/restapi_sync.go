@@ -36,6 +36,12 @@ func (r *RestapiReconciler) Sync(ctx context.Context, req reconcile.Request) err
 	}
 
 	syncSteps := []SyncStep{
+		{
+			Name: "TLS",
+			Sync: func(ctx context.Context, restapi *slinkyv1beta1.RestApi) error {
+				return r.syncTLS(ctx, restapi)
+			},
+		},
 		{
 			Name: "Service",
 			Sync: func(ctx context.Context, restapi *slinkyv1beta1.RestApi) error {
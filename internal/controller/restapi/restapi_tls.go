@@ -0,0 +1,133 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package restapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	builder "github.com/SlinkyProject/slurm-operator/internal/builder/restapibuilder"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/objectutils"
+)
+
+const (
+	tlsReasonGenerated    = "Generated"
+	tlsReasonUserProvided = "UserProvided"
+	tlsReasonNotFound     = "SecretNotFound"
+	tlsReasonMissingKeys  = "MissingKeys"
+)
+
+// syncTLS generates the CA and serving certificate of the restapi, and renews
+// them before they expire. The generated secret is removed when the restapi
+// does not serve HTTPS or uses its own secret, which is checked instead.
+func (r *RestapiReconciler) syncTLS(ctx context.Context, restapi *slinkyv1beta1.RestApi) error {
+	logger := log.FromContext(ctx)
+
+	if err := r.syncTLSCondition(ctx, restapi); err != nil {
+		return err
+	}
+
+	key := restapi.TLSKey()
+	existing := &corev1.Secret{}
+	if err := r.Get(ctx, key, existing); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		existing = nil
+	}
+
+	if !restapi.Spec.TLS.Enabled || restapi.Spec.TLS.SecretRef != nil {
+		if existing == nil || !metav1.IsControlledBy(existing, restapi) {
+			return nil
+		}
+		if err := objectutils.DeleteObject(r.Client, ctx, existing); err != nil {
+			return fmt.Errorf("failed to delete object (%s): %w", klog.KObj(existing), err)
+		}
+		return nil
+	}
+
+	object, err := r.builder.BuildRestapiTLS(restapi, existing)
+	if err != nil {
+		return fmt.Errorf("failed to build: %w", err)
+	}
+	if err := objectutils.SyncObject(r.Client, ctx, object, true); err != nil {
+		return fmt.Errorf("failed to sync object (%s): %w", klog.KObj(object), err)
+	}
+
+	renewal, err := builder.RestapiTLSRenewalTime(object)
+	if err != nil {
+		return fmt.Errorf("failed to get renewal time: %w", err)
+	}
+	logger.V(1).Info("Renew serving certificate before expiration", "renewal", renewal)
+	durationStore.Push(client.ObjectKeyFromObject(restapi).String(), time.Until(renewal))
+
+	return nil
+}
+
+// syncTLSCondition records whether the secret served by the TLS proxy has all
+// its keys. A user secret missing a key keeps the proxy from starting.
+func (r *RestapiReconciler) syncTLSCondition(ctx context.Context, restapi *slinkyv1beta1.RestApi) error {
+	if !restapi.Spec.TLS.Enabled {
+		meta.RemoveStatusCondition(&restapi.Status.Conditions, slinkyv1beta1.RestApiTLSReady)
+		return nil
+	}
+
+	condition := metav1.Condition{
+		Type:               slinkyv1beta1.RestApiTLSReady,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: restapi.Generation,
+		Reason:             tlsReasonGenerated,
+		Message:            "The operator generates and renews the certificates.",
+	}
+	defer func() {
+		meta.SetStatusCondition(&restapi.Status.Conditions, condition)
+	}()
+	if restapi.Spec.TLS.SecretRef == nil {
+		return nil
+	}
+
+	key := types.NamespacedName{
+		Namespace: restapi.Namespace,
+		Name:      restapi.Spec.TLS.SecretRef.Name,
+	}
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, key, secret); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = tlsReasonNotFound
+		condition.Message = fmt.Sprintf("Secret %s was not found.", key.Name)
+		return nil
+	}
+
+	missing := []string{}
+	for _, file := range []string{builder.TLSCertFile, builder.TLSKeyFile, builder.TLSCaCertFile} {
+		if len(secret.Data[file]) == 0 {
+			missing = append(missing, file)
+		}
+	}
+	if len(missing) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = tlsReasonMissingKeys
+		condition.Message = fmt.Sprintf("Secret %s is missing keys: %s.", key.Name, strings.Join(missing, ", "))
+		return nil
+	}
+
+	condition.Reason = tlsReasonUserProvided
+	condition.Message = fmt.Sprintf("Secret %s has all the keys.", key.Name)
+	return nil
+}
@@ -0,0 +1,171 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package restapi
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	builder "github.com/SlinkyProject/slurm-operator/internal/builder/restapibuilder"
+)
+
+func newTLSRestapi(tls slinkyv1beta1.RestApiTLS) *slinkyv1beta1.RestApi {
+	return &slinkyv1beta1.RestApi{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "slurm",
+			Name:      "slurm",
+		},
+		Spec: slinkyv1beta1.RestApiSpec{
+			TLS: tls,
+		},
+	}
+}
+
+func TestRestapiReconciler_syncTLS(t *testing.T) {
+	generated := newTLSRestapi(slinkyv1beta1.RestApiTLS{Enabled: true})
+	tests := []struct {
+		name       string
+		restapi    *slinkyv1beta1.RestApi
+		wantSecret bool
+	}{
+		{
+			name:       "Generated",
+			restapi:    generated,
+			wantSecret: true,
+		},
+		{
+			name:    "Disabled",
+			restapi: newTLSRestapi(slinkyv1beta1.RestApiTLS{}),
+		},
+		{
+			name: "User secret",
+			restapi: newTLSRestapi(slinkyv1beta1.RestApiTLS{
+				Enabled:   true,
+				SecretRef: &corev1.LocalObjectReference{Name: "restapi-tls"},
+			}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.TODO()
+			c := fake.NewFakeClient(tt.restapi)
+			r := NewReconciler(c)
+
+			// Create the secret first, to check it is kept or removed.
+			if err := r.syncTLS(ctx, generated); err != nil {
+				t.Fatalf("syncTLS() error = %v", err)
+			}
+			before := &corev1.Secret{}
+			if err := c.Get(ctx, generated.TLSKey(), before); err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+
+			if err := r.syncTLS(ctx, tt.restapi); err != nil {
+				t.Fatalf("syncTLS() error = %v", err)
+			}
+			after := &corev1.Secret{}
+			err := c.Get(ctx, tt.restapi.TLSKey(), after)
+			if err != nil && !apierrors.IsNotFound(err) {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if hasSecret := err == nil; hasSecret != tt.wantSecret {
+				t.Fatalf("secret exists = %v, want %v", hasSecret, tt.wantSecret)
+			}
+			if tt.wantSecret && !bytes.Equal(before.Data[builder.TLSCertFile], after.Data[builder.TLSCertFile]) {
+				t.Errorf("syncTLS() renewed a valid certificate")
+			}
+			if tt.wantSecret && durationStore.Peek(client.ObjectKeyFromObject(tt.restapi).String()) <= 0 {
+				t.Errorf("syncTLS() did not requeue for renewal")
+			}
+		})
+	}
+}
+
+func TestRestapiReconciler_syncTLSCondition(t *testing.T) {
+	userTLS := slinkyv1beta1.RestApiTLS{
+		Enabled:   true,
+		SecretRef: &corev1.LocalObjectReference{Name: "restapi-tls"},
+	}
+	newSecret := func(keys ...string) *corev1.Secret {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "slurm",
+				Name:      "restapi-tls",
+			},
+			Data: map[string][]byte{},
+		}
+		for _, key := range keys {
+			secret.Data[key] = []byte(key)
+		}
+		return secret
+	}
+	tests := []struct {
+		name       string
+		restapi    *slinkyv1beta1.RestApi
+		secret     *corev1.Secret
+		wantStatus metav1.ConditionStatus
+		wantReason string
+	}{
+		{
+			name:    "Disabled",
+			restapi: newTLSRestapi(slinkyv1beta1.RestApiTLS{}),
+		},
+		{
+			name:       "Generated",
+			restapi:    newTLSRestapi(slinkyv1beta1.RestApiTLS{Enabled: true}),
+			wantStatus: metav1.ConditionTrue,
+			wantReason: tlsReasonGenerated,
+		},
+		{
+			name:       "User secret",
+			restapi:    newTLSRestapi(userTLS),
+			secret:     newSecret(builder.TLSCertFile, builder.TLSKeyFile, builder.TLSCaCertFile),
+			wantStatus: metav1.ConditionTrue,
+			wantReason: tlsReasonUserProvided,
+		},
+		{
+			name:       "User secret not found",
+			restapi:    newTLSRestapi(userTLS),
+			wantStatus: metav1.ConditionFalse,
+			wantReason: tlsReasonNotFound,
+		},
+		{
+			name:       "User secret missing keys",
+			restapi:    newTLSRestapi(userTLS),
+			secret:     newSecret(builder.TLSCertFile, builder.TLSKeyFile),
+			wantStatus: metav1.ConditionFalse,
+			wantReason: tlsReasonMissingKeys,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objs := []runtime.Object{tt.restapi}
+			if tt.secret != nil {
+				objs = append(objs, tt.secret)
+			}
+			r := NewReconciler(fake.NewFakeClient(objs...))
+
+			if err := r.syncTLSCondition(context.TODO(), tt.restapi); err != nil {
+				t.Fatalf("syncTLSCondition() error = %v", err)
+			}
+			condition := meta.FindStatusCondition(tt.restapi.Status.Conditions, slinkyv1beta1.RestApiTLSReady)
+			switch {
+			case tt.wantReason == "" && condition != nil:
+				t.Errorf("Conditions = %v, want no %v condition", tt.restapi.Status.Conditions, slinkyv1beta1.RestApiTLSReady)
+			case tt.wantReason != "" && (condition == nil || condition.Status != tt.wantStatus || condition.Reason != tt.wantReason):
+				t.Errorf("Conditions = %v, want %v with reason %v", tt.restapi.Status.Conditions, tt.wantStatus, tt.wantReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package eventhandler
+
+import (
+	"context"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+)
+
+func NewRestapiEventHandler(reader client.Reader) *RestapiEventHandler {
+	return &RestapiEventHandler{
+		Reader: reader,
+	}
+}
+
+var _ handler.EventHandler = &RestapiEventHandler{}
+
+type RestapiEventHandler struct {
+	client.Reader
+}
+
+func (e *RestapiEventHandler) Create(
+	ctx context.Context,
+	evt event.CreateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.Object, q)
+}
+
+func (e *RestapiEventHandler) Update(
+	ctx context.Context,
+	evt event.UpdateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.ObjectOld, q)
+	e.enqueueRequest(ctx, evt.ObjectNew, q)
+}
+
+func (e *RestapiEventHandler) Delete(
+	ctx context.Context,
+	evt event.DeleteEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.Object, q)
+}
+
+func (e *RestapiEventHandler) Generic(
+	ctx context.Context,
+	evt event.GenericEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	// Intentionally blank
+}
+
+func (e *RestapiEventHandler) enqueueRequest(
+	ctx context.Context,
+	obj client.Object,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	restapi, ok := obj.(*slinkyv1beta1.RestApi)
+	if !ok {
+		return
+	}
+
+	enqueueController(q, restapi)
+}
+
+// enqueueController enqueues the Controller of the restapi, whose slurm client
+// connects to it.
+func enqueueController(
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+	restapi *slinkyv1beta1.RestApi,
+) {
+	ref := restapi.Spec.ControllerRef
+	if ref.Name == "" {
+		return
+	}
+	key := ref.NamespacedName()
+	if key.Namespace == "" {
+		key.Namespace = restapi.Namespace
+	}
+	q.Add(reconcile.Request{NamespacedName: key})
+}
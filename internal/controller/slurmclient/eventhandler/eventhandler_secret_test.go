@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package eventhandler
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/SlinkyProject/slurm-operator/internal/utils/testutils"
+)
+
+func Test_SecretEventHandler_Update(t *testing.T) {
+	controller := testutils.NewController("slurm", testutils.NewSlurmKeyRef("slurm"), testutils.NewJwtHs256KeyRef("slurm"), nil)
+	restapi := testutils.NewRestapi("slurm", controller)
+	restapi.Spec.TLS.Enabled = true
+	userRestapi := testutils.NewRestapi("user", controller)
+	userRestapi.Spec.TLS.Enabled = true
+	userRestapi.Spec.TLS.SecretRef = &corev1.LocalObjectReference{Name: "restapi-tls"}
+	plainRestapi := testutils.NewRestapi("plain", controller)
+
+	newSecret := func(name string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: restapi.Namespace,
+				Name:      name,
+			},
+		}
+	}
+	type fields struct {
+		Reader client.Reader
+	}
+	type args struct {
+		ctx context.Context
+		evt event.UpdateEvent
+		q   workqueue.TypedRateLimitingInterface[reconcile.Request]
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		want   int
+	}{
+		{
+			name: "Generated secret",
+			fields: fields{
+				Reader: fake.NewFakeClient(restapi, userRestapi, plainRestapi),
+			},
+			args: args{
+				ctx: context.TODO(),
+				evt: event.UpdateEvent{
+					ObjectNew: newSecret(restapi.TLSKey().Name),
+				},
+				q: newQueue(),
+			},
+			want: 1,
+		},
+		{
+			name: "User secret",
+			fields: fields{
+				Reader: fake.NewFakeClient(restapi, userRestapi, plainRestapi),
+			},
+			args: args{
+				ctx: context.TODO(),
+				evt: event.UpdateEvent{
+					ObjectNew: newSecret("restapi-tls"),
+				},
+				q: newQueue(),
+			},
+			want: 1,
+		},
+		{
+			name: "TLS disabled",
+			fields: fields{
+				Reader: fake.NewFakeClient(restapi, userRestapi, plainRestapi),
+			},
+			args: args{
+				ctx: context.TODO(),
+				evt: event.UpdateEvent{
+					ObjectNew: newSecret(plainRestapi.TLSKey().Name),
+				},
+				q: newQueue(),
+			},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewSecretEventHandler(tt.fields.Reader)
+			h.Update(tt.args.ctx, tt.args.evt, tt.args.q)
+			if got := tt.args.q.Len(); got != tt.want {
+				t.Errorf("SecretEventHandler.Update() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
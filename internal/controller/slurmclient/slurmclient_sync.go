@@ -40,7 +40,7 @@ func (r *SlurmClientReconciler) Sync(ctx context.Context, req reconcile.Request)
 	}
 	controllerKey := client.ObjectKeyFromObject(controller)
 
-	server, err := r.getRestApiServer(ctx, controller)
+	restapi, err := r.getRestApi(ctx, controller)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			_ = r.ClientMap.Remove(controllerKey)
@@ -49,6 +49,17 @@ func (r *SlurmClientReconciler) Sync(ctx context.Context, req reconcile.Request)
 		}
 		return err
 	}
+	server := getRestApiServer(ctx, restapi)
+
+	ca, err := r.getRestApiCA(ctx, restapi)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("Waiting for the RestApi TLS secret", "secret", restapi.TLSRef().Name)
+			durationStore.Push(controllerKey.String(), 10*time.Second)
+			return nil
+		}
+		return fmt.Errorf("failed to get RestApi CA: %w", err)
+	}
 
 	signingKey, err := r.refResolver.GetSecretKeyRef(ctx, controller.AuthJwtHs256Ref(), controller.Namespace)
 	if err != nil {
@@ -78,8 +89,9 @@ func (r *SlurmClientReconciler) Sync(ctx context.Context, req reconcile.Request)
 		durationStore.Push(controllerKey.String(), refresh)
 	}
 
-	// There is an existing client, handle in-place updates
-	if slurmClient := r.ClientMap.Get(controllerKey); slurmClient != nil {
+	// There is an existing client, handle in-place updates.
+	// The HTTP client cannot be updated, the client is replaced when the CA changes.
+	if slurmClient := r.ClientMap.Get(controllerKey); slurmClient != nil && clientCACheckSum(slurmClient) == caCheckSum(ca) {
 		slurmClient.SetServer(server)
 		slurmClient.SetToken(authToken)
 		return nil
@@ -89,6 +101,12 @@ func (r *SlurmClientReconciler) Sync(ctx context.Context, req reconcile.Request)
 		Server:    server,
 		AuthToken: authToken,
 	}
+	if len(ca) > 0 {
+		config.HTTPClient, err = newHTTPClient(ca)
+		if err != nil {
+			return fmt.Errorf("failed to create HTTP client: %w", err)
+		}
+	}
 	options := &slurmclient.ClientOptions{
 		DisableFor: []slurmobject.Object{
 			&slurmtypes.V0041ControllerPing{},
@@ -98,6 +116,13 @@ func (r *SlurmClientReconciler) Sync(ctx context.Context, req reconcile.Request)
 	if err != nil {
 		return fmt.Errorf("failed to create slurm client: %w", err)
 	}
+	if config.HTTPClient != nil {
+		slurmClient = &tlsClient{
+			Client:     slurmClient,
+			httpClient: config.HTTPClient,
+			caCheckSum: caCheckSum(ca),
+		}
+	}
 
 	if r.ClientMap.Add(controllerKey, slurmClient) {
 		logger.Info("Added slurm client", "controller", controllerKey.String())
@@ -106,22 +131,30 @@ func (r *SlurmClientReconciler) Sync(ctx context.Context, req reconcile.Request)
 	return nil
 }
 
-func (r *SlurmClientReconciler) getRestApiServer(ctx context.Context, controller *slinkyv1beta1.Controller) (string, error) {
-	logger := log.FromContext(ctx)
-
+func (r *SlurmClientReconciler) getRestApi(ctx context.Context, controller *slinkyv1beta1.Controller) (*slinkyv1beta1.RestApi, error) {
 	restapiList, err := r.refResolver.GetRestapisForController(ctx, controller)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	if len(restapiList.Items) == 0 {
-		return "", errors.New(http.StatusText(http.StatusNotFound))
+		return nil, errors.New(http.StatusText(http.StatusNotFound))
+	}
+	return &restapiList.Items[0], nil
+}
+
+func getRestApiServer(ctx context.Context, restapi *slinkyv1beta1.RestApi) string {
+	logger := log.FromContext(ctx)
+
+	scheme := "http"
+	if restapi.Spec.TLS.Enabled {
+		scheme = "https"
 	}
 
-	server := fmt.Sprintf("http://%s:%d", restapiList.Items[0].ServiceFQDNShort(), builder.SlurmrestdPort)
+	server := fmt.Sprintf("%s://%s:%d", scheme, restapi.ServiceFQDNShort(), builder.SlurmrestdPort)
 	if val := os.Getenv("DEBUG"); val == "1" {
 		logger.Info("overriding restapi URL with localhost")
-		server = fmt.Sprintf("http://localhost:%d", builder.SlurmrestdPort)
+		server = fmt.Sprintf("%s://localhost:%d", scheme, builder.SlurmrestdPort)
 	}
 
-	return server, nil
+	return server
 }
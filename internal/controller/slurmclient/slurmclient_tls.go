@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+
+	slurmclient "github.com/SlinkyProject/slurm-client/pkg/client"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	builder "github.com/SlinkyProject/slurm-operator/internal/builder/restapibuilder"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/crypto"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/slurmapi"
+)
+
+// tlsClient is a slurm client that verifies slurmrestd with a CA.
+type tlsClient struct {
+	slurmclient.Client
+
+	httpClient *http.Client
+	caCheckSum string
+}
+
+var _ slurmapi.HTTPClientGetter = &tlsClient{}
+
+// GetHTTPClient returns the HTTP client that verifies slurmrestd.
+func (c *tlsClient) GetHTTPClient() *http.Client {
+	return c.httpClient
+}
+
+// clientCACheckSum returns the checksum of the CA the slurm client verifies
+// slurmrestd with, or empty when it does not use TLS.
+func clientCACheckSum(slurmClient slurmclient.Client) string {
+	if c, ok := slurmClient.(*tlsClient); ok {
+		return c.caCheckSum
+	}
+	return ""
+}
+
+// caCheckSum returns the checksum of the CA, or empty when there is none.
+func caCheckSum(ca []byte) string {
+	if len(ca) == 0 {
+		return ""
+	}
+	return crypto.CheckSum(ca)
+}
+
+// getRestApiCA returns the CA that signed the serving certificate of the
+// restapi, or nil when it does not serve HTTPS.
+func (r *SlurmClientReconciler) getRestApiCA(ctx context.Context, restapi *slinkyv1beta1.RestApi) ([]byte, error) {
+	if !restapi.Spec.TLS.Enabled {
+		return nil, nil
+	}
+	selector := &corev1.SecretKeySelector{
+		LocalObjectReference: *restapi.TLSRef(),
+		Key:                  builder.TLSCaCertFile,
+	}
+	return r.refResolver.GetSecretKeyRef(ctx, selector, restapi.Namespace)
+}
+
+// newHTTPClient returns an HTTP client that only trusts the CA.
+func newHTTPClient(ca []byte) (*http.Client, error) {
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(ca) {
+		return nil, errors.New("no CA certificate found in PEM data")
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		RootCAs:    roots,
+		MinVersion: tls.VersionTLS12,
+	}
+	return &http.Client{Transport: transport}, nil
+}
@@ -0,0 +1,130 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmclient
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/crypto"
+)
+
+func Test_getRestApiServer(t *testing.T) {
+	newRestapi := func(tls bool) *slinkyv1beta1.RestApi {
+		return &slinkyv1beta1.RestApi{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "slurm",
+				Name:      "slurm",
+			},
+			Spec: slinkyv1beta1.RestApiSpec{
+				TLS: slinkyv1beta1.RestApiTLS{
+					Enabled: tls,
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name    string
+		restapi *slinkyv1beta1.RestApi
+		want    string
+	}{
+		{
+			name:    "HTTP",
+			restapi: newRestapi(false),
+			want:    "http://slurm-restapi.slurm:6820",
+		},
+		{
+			name:    "HTTPS",
+			restapi: newRestapi(true),
+			want:    "https://slurm-restapi.slurm:6820",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getRestApiServer(context.TODO(), tt.restapi); got != tt.want {
+				t.Errorf("getRestApiServer() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_newHTTPClient(t *testing.T) {
+	ca, err := crypto.NewCertificateAuthority("test-ca", time.Hour)
+	if err != nil {
+		t.Fatalf("NewCertificateAuthority() error = %v", err)
+	}
+	otherCa, err := crypto.NewCertificateAuthority("other-ca", time.Hour)
+	if err != nil {
+		t.Fatalf("NewCertificateAuthority() error = %v", err)
+	}
+	cert, err := crypto.NewServingCertificate(ca, "localhost", []string{"localhost"}, time.Hour)
+	if err != nil {
+		t.Fatalf("NewServingCertificate() error = %v", err)
+	}
+	pair, err := tls.X509KeyPair(cert.Cert, cert.Key)
+	if err != nil {
+		t.Fatalf("X509KeyPair() error = %v", err)
+	}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{pair}}
+	server.StartTLS()
+	defer server.Close()
+	url := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+
+	tests := []struct {
+		name    string
+		ca      []byte
+		wantErr bool
+	}{
+		{
+			name: "Trusted",
+			ca:   ca.Cert,
+		},
+		{
+			name:    "Untrusted",
+			ca:      otherCa.Cert,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpClient, err := newHTTPClient(tt.ca)
+			if err != nil {
+				t.Fatalf("newHTTPClient() error = %v", err)
+			}
+			resp, err := httpClient.Get(url)
+			if err == nil {
+				resp.Body.Close()
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := newHTTPClient([]byte("foo")); err == nil {
+		t.Errorf("newHTTPClient() error = nil, want an error for an invalid CA")
+	}
+}
+
+func Test_clientCACheckSum(t *testing.T) {
+	if got := caCheckSum(nil); got != "" {
+		t.Errorf("caCheckSum() = %v, want empty", got)
+	}
+	client := &tlsClient{caCheckSum: caCheckSum([]byte("ca"))}
+	if got, want := clientCACheckSum(client), caCheckSum([]byte("ca")); got != want {
+		t.Errorf("clientCACheckSum() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package eventhandler
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/objectutils"
+)
+
+func NewNamespaceEventHandler(reader client.Reader) *NamespaceEventHandler {
+	return &NamespaceEventHandler{
+		Reader: reader,
+	}
+}
+
+var _ handler.EventHandler = &NamespaceEventHandler{}
+
+type NamespaceEventHandler struct {
+	client.Reader
+}
+
+func (e *NamespaceEventHandler) Create(
+	ctx context.Context,
+	evt event.CreateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, q, evt.Object)
+}
+
+func (e *NamespaceEventHandler) Update(
+	ctx context.Context,
+	evt event.UpdateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	// The namespace may have been selected before its labels changed.
+	e.enqueueRequest(ctx, q, evt.ObjectOld, evt.ObjectNew)
+}
+
+func (e *NamespaceEventHandler) Delete(
+	ctx context.Context,
+	evt event.DeleteEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, q, evt.Object)
+}
+
+func (e *NamespaceEventHandler) Generic(
+	ctx context.Context,
+	evt event.GenericEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	// Intentionally blank
+}
+
+func (e *NamespaceEventHandler) enqueueRequest(
+	ctx context.Context,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+	objs ...client.Object,
+) {
+	var namespaces []*corev1.Namespace
+	for _, obj := range objs {
+		if namespace, ok := obj.(*corev1.Namespace); ok {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	enqueueAccountings(ctx, e.Reader, q, namespaces...)
+}
+
+// enqueueAccountings enqueues the Accountings whose NamespaceAccounts select
+// any of the namespaces.
+func enqueueAccountings(
+	ctx context.Context,
+	reader client.Reader,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+	namespaces ...*corev1.Namespace,
+) {
+	logger := log.FromContext(ctx)
+
+	if len(namespaces) == 0 {
+		return
+	}
+
+	accountingList := &slinkyv1beta1.AccountingList{}
+	if err := reader.List(ctx, accountingList); err != nil {
+		logger.Error(err, "failed to list accounting CRs")
+	}
+
+	for _, accounting := range accountingList.Items {
+		selector, err := accounting.NamespaceSelector()
+		if err != nil {
+			logger.Error(err, "failed to parse namespace selector", "accounting", accounting.Name)
+			continue
+		}
+		for _, namespace := range namespaces {
+			if selector.Matches(labels.Set(namespace.Labels)) {
+				objectutils.EnqueueRequest(q, &accounting)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package eventhandler
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func Test_NamespaceEventHandler_Create(t *testing.T) {
+	tenants := newAccounting("tenants", map[string]string{"slurm.net/tenant": "true"})
+	other := newAccounting("other", map[string]string{"slurm.net/other": "true"})
+	plain := newAccounting("plain", nil)
+	type fields struct {
+		Reader client.Reader
+	}
+	type args struct {
+		ctx context.Context
+		evt event.CreateEvent
+		q   workqueue.TypedRateLimitingInterface[reconcile.Request]
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		want   int
+	}{
+		{
+			name: "selected",
+			fields: fields{
+				Reader: fake.NewFakeClient(tenants, other, plain),
+			},
+			args: args{
+				ctx: context.TODO(),
+				evt: event.CreateEvent{
+					Object: newNamespace("physics", map[string]string{"slurm.net/tenant": "true"}),
+				},
+				q: newQueue(),
+			},
+			want: 1,
+		},
+		{
+			name: "not selected",
+			fields: fields{
+				Reader: fake.NewFakeClient(tenants, other, plain),
+			},
+			args: args{
+				ctx: context.TODO(),
+				evt: event.CreateEvent{
+					Object: newNamespace("kube-system", nil),
+				},
+				q: newQueue(),
+			},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewNamespaceEventHandler(tt.fields.Reader)
+			h.Create(tt.args.ctx, tt.args.evt, tt.args.q)
+			if got := tt.args.q.Len(); got != tt.want {
+				t.Errorf("NamespaceEventHandler.Create() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_NamespaceEventHandler_Update(t *testing.T) {
+	tenants := newAccounting("tenants", map[string]string{"slurm.net/tenant": "true"})
+	other := newAccounting("other", map[string]string{"slurm.net/other": "true"})
+	type fields struct {
+		Reader client.Reader
+	}
+	type args struct {
+		ctx context.Context
+		evt event.UpdateEvent
+		q   workqueue.TypedRateLimitingInterface[reconcile.Request]
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		want   int
+	}{
+		{
+			name: "label removed",
+			fields: fields{
+				Reader: fake.NewFakeClient(tenants, other),
+			},
+			args: args{
+				ctx: context.TODO(),
+				evt: event.UpdateEvent{
+					ObjectOld: newNamespace("physics", map[string]string{"slurm.net/tenant": "true"}),
+					ObjectNew: newNamespace("physics", nil),
+				},
+				q: newQueue(),
+			},
+			want: 1,
+		},
+		{
+			name: "moved between Accountings",
+			fields: fields{
+				Reader: fake.NewFakeClient(tenants, other),
+			},
+			args: args{
+				ctx: context.TODO(),
+				evt: event.UpdateEvent{
+					ObjectOld: newNamespace("physics", map[string]string{"slurm.net/tenant": "true"}),
+					ObjectNew: newNamespace("physics", map[string]string{"slurm.net/other": "true"}),
+				},
+				q: newQueue(),
+			},
+			want: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewNamespaceEventHandler(tt.fields.Reader)
+			h.Update(tt.args.ctx, tt.args.evt, tt.args.q)
+			if got := tt.args.q.Len(); got != tt.want {
+				t.Errorf("NamespaceEventHandler.Update() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package eventhandler
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func NewResourceQuotaEventHandler(reader client.Reader) *ResourceQuotaEventHandler {
+	return &ResourceQuotaEventHandler{
+		Reader: reader,
+	}
+}
+
+var _ handler.EventHandler = &ResourceQuotaEventHandler{}
+
+type ResourceQuotaEventHandler struct {
+	client.Reader
+}
+
+func (e *ResourceQuotaEventHandler) Create(
+	ctx context.Context,
+	evt event.CreateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.Object, q)
+}
+
+func (e *ResourceQuotaEventHandler) Update(
+	ctx context.Context,
+	evt event.UpdateEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.ObjectNew, q)
+}
+
+func (e *ResourceQuotaEventHandler) Delete(
+	ctx context.Context,
+	evt event.DeleteEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	e.enqueueRequest(ctx, evt.Object, q)
+}
+
+func (e *ResourceQuotaEventHandler) Generic(
+	ctx context.Context,
+	evt event.GenericEvent,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	// Intentionally blank
+}
+
+func (e *ResourceQuotaEventHandler) enqueueRequest(
+	ctx context.Context,
+	obj client.Object,
+	q workqueue.TypedRateLimitingInterface[reconcile.Request],
+) {
+	logger := log.FromContext(ctx)
+
+	quota, ok := obj.(*corev1.ResourceQuota)
+	if !ok {
+		return
+	}
+
+	namespace := &corev1.Namespace{}
+	if err := e.Get(ctx, client.ObjectKey{Name: quota.Namespace}, namespace); err != nil {
+		// A deleted namespace is handled by the NamespaceEventHandler.
+		if !apierrors.IsNotFound(err) {
+			logger.Error(err, "failed to get namespace", "namespace", quota.Namespace)
+		}
+		return
+	}
+
+	enqueueAccountings(ctx, e.Reader, q, namespace)
+}
@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package eventhandler
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func Test_ResourceQuotaEventHandler_Create(t *testing.T) {
+	tenants := newAccounting("tenants", map[string]string{"slurm.net/tenant": "true"})
+	physics := newNamespace("physics", map[string]string{"slurm.net/tenant": "true"})
+	system := newNamespace("kube-system", nil)
+	newQuota := func(namespace string) *corev1.ResourceQuota {
+		return &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      "quota",
+			},
+		}
+	}
+	type fields struct {
+		Reader client.Reader
+	}
+	type args struct {
+		ctx context.Context
+		evt event.CreateEvent
+		q   workqueue.TypedRateLimitingInterface[reconcile.Request]
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		args   args
+		want   int
+	}{
+		{
+			name: "selected namespace",
+			fields: fields{
+				Reader: fake.NewFakeClient(tenants, physics, system),
+			},
+			args: args{
+				ctx: context.TODO(),
+				evt: event.CreateEvent{
+					Object: newQuota(physics.Name),
+				},
+				q: newQueue(),
+			},
+			want: 1,
+		},
+		{
+			name: "other namespace",
+			fields: fields{
+				Reader: fake.NewFakeClient(tenants, physics, system),
+			},
+			args: args{
+				ctx: context.TODO(),
+				evt: event.CreateEvent{
+					Object: newQuota(system.Name),
+				},
+				q: newQueue(),
+			},
+			want: 0,
+		},
+		{
+			name: "missing namespace",
+			fields: fields{
+				Reader: fake.NewFakeClient(tenants),
+			},
+			args: args{
+				ctx: context.TODO(),
+				evt: event.CreateEvent{
+					Object: newQuota(physics.Name),
+				},
+				q: newQueue(),
+			},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewResourceQuotaEventHandler(tt.fields.Reader)
+			h.Create(tt.args.ctx, tt.args.evt, tt.args.q)
+			if got := tt.args.q.Len(); got != tt.want {
+				t.Errorf("ResourceQuotaEventHandler.Create() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmdb
+
+import (
+	"context"
+	"flag"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	"github.com/SlinkyProject/slurm-operator/internal/clientmap"
+	"github.com/SlinkyProject/slurm-operator/internal/controller/slurmdb/eventhandler"
+	"github.com/SlinkyProject/slurm-operator/internal/controller/slurmdb/slurmcontrol"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/durationstore"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/refresolver"
+)
+
+const (
+	NamespaceAccountControllerName = "namespaceaccount-controller"
+)
+
+func init() {
+	flag.IntVar(&maxConcurrentNamespaceAccountReconciles, "namespaceaccount-workers", maxConcurrentNamespaceAccountReconciles, "Max concurrent workers for the namespace account controller.")
+}
+
+var (
+	maxConcurrentNamespaceAccountReconciles = 1
+
+	// this is a short cut for any sub-functions to notify the reconcile how long to wait to requeue
+	namespaceAccountDurationStore = durationstore.NewDurationStore(durationstore.Less)
+)
+
+// NamespaceAccountReconciler reconciles the accounts of the namespaces
+// selected by the NamespaceAccounts of an Accounting object
+type NamespaceAccountReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	ClientMap *clientmap.ClientMap
+
+	refResolver   *refresolver.RefResolver
+	slurmControl  slurmcontrol.SlurmControlInterface
+	eventRecorder record.EventRecorderLogger
+}
+
+// +kubebuilder:rbac:groups=slinky.slurm.net,resources=accountings,verbs=get;list;watch
+// +kubebuilder:rbac:groups=slinky.slurm.net,resources=accountings/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=slinky.slurm.net,resources=controllers,verbs=get;list;watch
+// +kubebuilder:rbac:groups=slinky.slurm.net,resources=slurmaccounts,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=resourcequotas,verbs=get;list;watch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+func (r *NamespaceAccountReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, retErr error) {
+	logger := log.FromContext(ctx)
+	logger.Info("Started syncing namespace accounts", "request", req)
+
+	startTime := time.Now()
+	defer func() {
+		if retErr == nil {
+			if res.RequeueAfter > 0 {
+				logger.Info("Finished syncing namespace accounts", "duration", time.Since(startTime), "result", res)
+			} else {
+				logger.Info("Finished syncing namespace accounts", "duration", time.Since(startTime))
+			}
+		} else {
+			logger.Info("Finished syncing namespace accounts", "duration", time.Since(startTime), "error", retErr)
+		}
+		// clean the duration store
+		_ = namespaceAccountDurationStore.Pop(req.String())
+	}()
+
+	retErr = r.Sync(ctx, req)
+	res = reconcile.Result{
+		RequeueAfter: namespaceAccountDurationStore.Pop(req.String()),
+	}
+	return res, retErr
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NamespaceAccountReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(NamespaceAccountControllerName).
+		For(&slinkyv1beta1.Accounting{}).
+		Watches(&corev1.Namespace{}, eventhandler.NewNamespaceEventHandler(r.Client)).
+		Watches(&corev1.ResourceQuota{}, eventhandler.NewResourceQuotaEventHandler(r.Client)).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: maxConcurrentNamespaceAccountReconciles,
+		}).
+		Complete(r)
+}
+
+func NewNamespaceAccountReconciler(c client.Client, cm *clientmap.ClientMap) *NamespaceAccountReconciler {
+	s := c.Scheme()
+	es := corev1.EventSource{Component: NamespaceAccountControllerName}
+	return &NamespaceAccountReconciler{
+		Client: c,
+		Scheme: s,
+
+		ClientMap: cm,
+
+		refResolver:   refresolver.New(c),
+		slurmControl:  slurmcontrol.NewSlurmControl(cm),
+		eventRecorder: record.NewBroadcaster().NewRecorder(s, es),
+	}
+}
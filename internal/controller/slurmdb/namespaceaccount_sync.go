@@ -0,0 +1,513 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"k8s.io/utils/set"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	api "github.com/SlinkyProject/slurm-client/api/v0044"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/objectutils"
+)
+
+// Reasons for namespace account events
+const (
+	// NamespaceAccountCreatedReason is added to an event when the account of a
+	// namespace is created in slurmdbd.
+	NamespaceAccountCreatedReason = "NamespaceAccountCreated"
+	// NamespaceAccountUpdatedReason is added to an event when the associations
+	// of the account of a namespace are updated in slurmdbd.
+	NamespaceAccountUpdatedReason = "NamespaceAccountUpdated"
+	// NamespaceAccountRemovedReason is added to an event when the account of
+	// namespaces that are no longer selected is removed from slurmdbd.
+	NamespaceAccountRemovedReason = "NamespaceAccountRemoved"
+)
+
+// quotaTRES maps ResourceQuota resources to TRES. The first resource a
+// ResourceQuota limits is used.
+var quotaTRES = []struct {
+	tres      string
+	resources []corev1.ResourceName
+	count     func(resource.Quantity) int64
+}{
+	{
+		tres:      "cpu",
+		resources: []corev1.ResourceName{corev1.ResourceRequestsCPU, corev1.ResourceCPU, corev1.ResourceLimitsCPU},
+		// Slurm allocates whole CPUs.
+		count: func(q resource.Quantity) int64 { return q.MilliValue() / 1000 },
+	},
+	{
+		tres:      "mem",
+		resources: []corev1.ResourceName{corev1.ResourceRequestsMemory, corev1.ResourceMemory, corev1.ResourceLimitsMemory},
+		// Slurm memory is in megabytes.
+		count: func(q resource.Quantity) int64 { return q.Value() / (1 << 20) },
+	},
+	{
+		tres:      "gres/gpu",
+		resources: []corev1.ResourceName{corev1.DefaultResourceRequestsPrefix + "nvidia.com/gpu"},
+		count:     func(q resource.Quantity) int64 { return q.Value() },
+	},
+}
+
+// Sync implements control logic for synchronizing the namespace accounts of
+// an Accounting.
+func (r *NamespaceAccountReconciler) Sync(ctx context.Context, req reconcile.Request) error {
+	logger := log.FromContext(ctx)
+
+	accounting := &slinkyv1beta1.Accounting{}
+	if err := r.Get(ctx, req.NamespacedName, accounting); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("Accounting has been deleted", "request", req)
+			return nil
+		}
+		return err
+	}
+
+	if !accounting.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	status := accounting.Status.DeepCopy()
+	var errs []error
+	if err := r.syncNamespaceAccounts(ctx, accounting, status); err != nil {
+		errs = append(errs, err)
+	}
+	if err := r.syncStatus(ctx, accounting, status); err != nil {
+		errs = append(errs, err)
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// syncNamespaceAccounts makes the accounts of the selected namespaces in
+// slurmdbd match their ResourceQuotas, and removes the accounts of the
+// namespaces that are no longer selected.
+func (r *NamespaceAccountReconciler) syncNamespaceAccounts(
+	ctx context.Context,
+	accounting *slinkyv1beta1.Accounting,
+	status *slinkyv1beta1.AccountingStatus,
+) error {
+	var namespaceAccounts []slinkyv1beta1.NamespaceAccountStatus
+	if accounting.Spec.NamespaceAccounts != nil {
+		var err error
+		namespaceAccounts, err = r.namespaceAccounts(ctx, accounting)
+		if err != nil {
+			return err
+		}
+	}
+	stale := staleNamespaceAccounts(status.NamespaceAccounts, namespaceAccounts)
+	if accounting.Spec.NamespaceAccounts == nil && len(stale) == 0 {
+		status.NamespaceAccounts = nil
+		meta.RemoveStatusCondition(&status.Conditions, slinkyv1beta1.AccountingNamespaceAccountsSynced)
+		return nil
+	}
+	key := objectutils.NamespacedName(accounting).String()
+
+	ref := slinkyv1beta1.ObjectReference{
+		Namespace: accounting.Namespace,
+		Name:      accounting.Name,
+	}
+	clusters, err := resolveAccounting(ctx, r.refResolver, r.slurmControl, ref)
+	if err != nil {
+		return err
+	}
+	if clusters.controller == nil {
+		namespaceAccountDurationStore.Push(key, NoClientInterval)
+		meta.SetStatusCondition(&status.Conditions, namespaceAccountsCondition(accounting.Generation,
+			metav1.ConditionFalse, syncedReasonNoClient,
+			"No slurmrestd of the Accounting's Controllers can reach slurmdbd."))
+		return nil
+	}
+	namespaceAccountDurationStore.Push(key, SyncInterval)
+
+	failed := func(name string, err error) error {
+		r.eventRecorder.Eventf(accounting, corev1.EventTypeWarning, SyncFailedReason,
+			"Failed to sync account %s: %v", name, err)
+		meta.SetStatusCondition(&status.Conditions, namespaceAccountsCondition(accounting.Generation,
+			metav1.ConditionFalse, syncedReasonFailed, err.Error()))
+		return err
+	}
+
+	parent := ""
+	if accounting.Spec.NamespaceAccounts != nil {
+		parent = valueOrDefault(accounting.Spec.NamespaceAccounts.ParentAccount, defaultParentAccount)
+	}
+	accounts := make(map[string]map[string]int64)
+	namespaces := make(map[string][]string)
+	for _, item := range namespaceAccounts {
+		accounts[item.Account] = item.GrpTRES
+		namespaces[item.Account] = append(namespaces[item.Account], item.Namespace)
+	}
+	for _, name := range slices.Sorted(maps.Keys(accounts)) {
+		created, changes, err := r.applyNamespaceAccount(ctx, clusters, name, parent, accounts[name], namespaces[name])
+		if err != nil {
+			return failed(name, err)
+		}
+		if created {
+			r.eventRecorder.Eventf(accounting, corev1.EventTypeNormal, NamespaceAccountCreatedReason,
+				"Created account %s for namespace %s", name, strings.Join(namespaces[name], ", "))
+		}
+		if len(changes) > 0 {
+			r.eventRecorder.Eventf(accounting, corev1.EventTypeNormal, NamespaceAccountUpdatedReason,
+				"Updated account %s: %s", name, strings.Join(changes, "; "))
+		}
+	}
+
+	if len(stale) > 0 {
+		managed, err := r.slurmAccountNames(ctx, ref)
+		if err != nil {
+			return err
+		}
+		for _, name := range stale {
+			// The account is kept when a SlurmAccount manages it.
+			if managed.Has(name) {
+				continue
+			}
+			if err := removeAccount(ctx, r.slurmControl, clusters.controller, name, clusters.clusters); err != nil {
+				return failed(name, err)
+			}
+			r.eventRecorder.Eventf(accounting, corev1.EventTypeNormal, NamespaceAccountRemovedReason,
+				"Removed account %s, its namespaces are no longer selected", name)
+		}
+	}
+
+	status.NamespaceAccounts = namespaceAccounts
+	if accounting.Spec.NamespaceAccounts == nil {
+		meta.RemoveStatusCondition(&status.Conditions, slinkyv1beta1.AccountingNamespaceAccountsSynced)
+		return nil
+	}
+	meta.SetStatusCondition(&status.Conditions, namespaceAccountsCondition(accounting.Generation,
+		metav1.ConditionTrue, syncedReasonSynced, "slurmdbd matches the namespaces."))
+
+	return nil
+}
+
+// staleNamespaceAccounts returns the accounts, sorted by name, that were
+// mapped to namespaces but no longer are.
+func staleNamespaceAccounts(previous, current []slinkyv1beta1.NamespaceAccountStatus) []string {
+	stale := set.New[string]()
+	for _, item := range previous {
+		stale.Insert(item.Account)
+	}
+	for _, item := range current {
+		stale.Delete(item.Account)
+	}
+	return stale.SortedList()
+}
+
+// slurmAccountNames returns the names of the accounts that the SlurmAccounts
+// of the Accounting manage.
+func (r *NamespaceAccountReconciler) slurmAccountNames(
+	ctx context.Context,
+	ref slinkyv1beta1.ObjectReference,
+) (set.Set[string], error) {
+	list := &slinkyv1beta1.SlurmAccountList{}
+	if err := r.List(ctx, list); err != nil {
+		return nil, err
+	}
+	names := set.New[string]()
+	for i := range list.Items {
+		item := &list.Items[i]
+		if item.AccountingRef() == ref {
+			names.Insert(item.AccountName())
+		}
+	}
+	return names, nil
+}
+
+// namespaceAccounts returns the selected namespaces, sorted by name, with
+// their account and its GrpTRES.
+func (r *NamespaceAccountReconciler) namespaceAccounts(
+	ctx context.Context,
+	accounting *slinkyv1beta1.Accounting,
+) ([]slinkyv1beta1.NamespaceAccountStatus, error) {
+	selector, err := accounting.NamespaceSelector()
+	if err != nil {
+		return nil, err
+	}
+	namespaceList := &corev1.NamespaceList{}
+	if err := r.List(ctx, namespaceList, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, err
+	}
+	slices.SortFunc(namespaceList.Items, func(a, b corev1.Namespace) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	result := make([]slinkyv1beta1.NamespaceAccountStatus, 0, len(namespaceList.Items))
+	limits := make(map[string][]map[string]int64)
+	for i := range namespaceList.Items {
+		namespace := &namespaceList.Items[i]
+		quotaList := &corev1.ResourceQuotaList{}
+		if err := r.List(ctx, quotaList, client.InNamespace(namespace.Name)); err != nil {
+			return nil, err
+		}
+		account := accounting.NamespaceAccountName(namespace)
+		limits[account] = append(limits[account], namespaceTRES(quotaList.Items))
+		result = append(result, slinkyv1beta1.NamespaceAccountStatus{
+			Namespace: namespace.Name,
+			Account:   account,
+		})
+	}
+	for i := range result {
+		result[i].GrpTRES = accountTRES(limits[result[i].Account])
+	}
+
+	return result, nil
+}
+
+// applyNamespaceAccount creates the account if it is missing and makes its
+// associations on the clusters match the GrpTRES, returning the changes that
+// were made.
+func (r *NamespaceAccountReconciler) applyNamespaceAccount(
+	ctx context.Context,
+	clusters *accountingClusters,
+	name string,
+	parent string,
+	grpTRES map[string]int64,
+	namespaces []string,
+) (bool, []string, error) {
+	controller := clusters.controller
+
+	created := false
+	current, err := r.slurmControl.GetAccount(ctx, controller, name)
+	if err != nil {
+		return false, nil, err
+	}
+	if current == nil {
+		req := api.V0044OpenapiAccountsAddCondResp{
+			Account: &api.V0044AccountShort{
+				Description:  ptr.To(fmt.Sprintf("Kubernetes namespace %s", strings.Join(namespaces, ", "))),
+				Organization: ptr.To(name),
+			},
+			AssociationCondition: api.V0044AccountsAddCond{
+				Accounts: api.V0044StringList{name},
+				Clusters: ptr.To(api.V0044StringList(clusters.clusters)),
+				Association: &api.V0044AssocRecSet{
+					Parent: ptr.To(parent),
+				},
+			},
+		}
+		if err := r.slurmControl.CreateAccount(ctx, controller, req); err != nil {
+			return false, nil, err
+		}
+		created = true
+	}
+
+	assocs, err := r.slurmControl.GetAssociations(ctx, controller, api.SlurmdbV0044GetAssociationsParams{
+		Account: ptr.To(name),
+	})
+	if err != nil {
+		return false, nil, err
+	}
+	// The account association of each cluster is the one without a user.
+	currentAssocs := make(map[string]api.V0044Assoc)
+	for _, assoc := range assocs {
+		if assoc.User == "" && ptr.Deref(assoc.Account, "") == name {
+			currentAssocs[ptr.Deref(assoc.Cluster, "")] = assoc
+		}
+	}
+
+	changes := []string{}
+	var toApply []api.V0044Assoc
+	for _, cluster := range clusters.clusters {
+		var currentTRES map[string]int64
+		assoc, ok := currentAssocs[cluster]
+		switch {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("association on cluster %q is missing", cluster))
+		case ptr.Deref(assoc.ParentAccount, "") != parent:
+			currentTRES = assocGrpTRES(assoc)
+			changes = append(changes, fmt.Sprintf("parent account on cluster %q is %q, want %q",
+				cluster, ptr.Deref(assoc.ParentAccount, ""), parent))
+		case !maps.Equal(assocGrpTRES(assoc), grpTRES):
+			currentTRES = assocGrpTRES(assoc)
+			changes = append(changes, fmt.Sprintf("GrpTRES on cluster %q is %q, want %q",
+				cluster, formatTRES(currentTRES), formatTRES(grpTRES)))
+		default:
+			continue
+		}
+		assoc, err := toAccountAssoc(name, cluster, parent, tresList(grpTRES, currentTRES))
+		if err != nil {
+			return false, nil, err
+		}
+		toApply = append(toApply, assoc)
+	}
+	if err := r.slurmControl.UpdateAssociations(ctx, controller, toApply); err != nil {
+		return false, nil, err
+	}
+	if created {
+		// The associations of a new account are expected to be updated.
+		changes = nil
+	}
+
+	return created, changes, nil
+}
+
+// assocRecord mirrors the managed fields of api.V0044Assoc, whose nested
+// fields have anonymous types.
+type assocRecord struct {
+	Account       string `json:"account"`
+	Cluster       string `json:"cluster"`
+	ParentAccount string `json:"parent_account"`
+	User          string `json:"user"`
+	Max           struct {
+		Tres struct {
+			// Total is the GrpTRES of the association.
+			Total api.V0044TresList `json:"total,omitempty"`
+		} `json:"tres"`
+	} `json:"max"`
+}
+
+// toAccountAssoc returns the account association of the cluster.
+func toAccountAssoc(account, cluster, parent string, grpTRES api.V0044TresList) (api.V0044Assoc, error) {
+	record := assocRecord{
+		Account:       account,
+		Cluster:       cluster,
+		ParentAccount: parent,
+	}
+	record.Max.Tres.Total = grpTRES
+
+	var assoc api.V0044Assoc
+	data, err := json.Marshal(record)
+	if err != nil {
+		return assoc, err
+	}
+	if err := json.Unmarshal(data, &assoc); err != nil {
+		return assoc, err
+	}
+	return assoc, nil
+}
+
+// assocGrpTRES returns the GrpTRES of the association, by TRES name.
+func assocGrpTRES(assoc api.V0044Assoc) map[string]int64 {
+	if assoc.Max == nil || assoc.Max.Tres == nil || assoc.Max.Tres.Total == nil {
+		return nil
+	}
+	return tresMap(*assoc.Max.Tres.Total)
+}
+
+// namespaceTRES returns the TRES limited by the ResourceQuotas of a
+// namespace. When several ResourceQuotas limit a resource, the lowest limit
+// applies.
+func namespaceTRES(quotas []corev1.ResourceQuota) map[string]int64 {
+	tres := make(map[string]int64)
+	for _, quota := range quotas {
+		for _, item := range quotaTRES {
+			for _, name := range item.resources {
+				q, ok := quota.Spec.Hard[name]
+				if !ok {
+					continue
+				}
+				count := item.count(q)
+				if current, ok := tres[item.tres]; !ok || count < current {
+					tres[item.tres] = count
+				}
+				break
+			}
+		}
+	}
+	if len(tres) == 0 {
+		return nil
+	}
+	return tres
+}
+
+// accountTRES returns the GrpTRES of an account from the TRES limits of its
+// namespaces. A TRES is only limited when all of the namespaces limit it, in
+// which case the limits add up.
+func accountTRES(limits []map[string]int64) map[string]int64 {
+	if len(limits) == 0 {
+		return nil
+	}
+	tres := make(map[string]int64)
+	for name := range limits[0] {
+		var total int64
+		limited := true
+		for _, limit := range limits {
+			count, ok := limit[name]
+			if !ok {
+				limited = false
+				break
+			}
+			total += count
+		}
+		if limited {
+			tres[name] = total
+		}
+	}
+	if len(tres) == 0 {
+		return nil
+	}
+	return tres
+}
+
+func namespaceAccountsCondition(generation int64, status metav1.ConditionStatus, reason, message string) metav1.Condition {
+	return metav1.Condition{
+		Type:               slinkyv1beta1.AccountingNamespaceAccountsSynced,
+		Status:             status,
+		ObservedGeneration: generation,
+		Reason:             reason,
+		Message:            message,
+	}
+}
+
+// syncStatus handles determining and updating the status.
+func (r *NamespaceAccountReconciler) syncStatus(
+	ctx context.Context,
+	accounting *slinkyv1beta1.Accounting,
+	newStatus *slinkyv1beta1.AccountingStatus,
+) error {
+	logger := log.FromContext(ctx)
+
+	if apiequality.Semantic.DeepEqual(accounting.Status, *newStatus) {
+		logger.V(2).Info("Accounting Status has not changed, skipping status update",
+			"accounting", klog.KObj(accounting), "status", accounting.Status)
+		return nil
+	}
+
+	accountingKey := objectutils.NamespacedName(accounting)
+	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		toUpdate := &slinkyv1beta1.Accounting{}
+		if err := r.Get(ctx, accountingKey, toUpdate); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		// Only the namespace accounts are owned by this controller.
+		toUpdate.Status.NamespaceAccounts = newStatus.NamespaceAccounts
+		if condition := meta.FindStatusCondition(newStatus.Conditions, slinkyv1beta1.AccountingNamespaceAccountsSynced); condition != nil {
+			meta.SetStatusCondition(&toUpdate.Status.Conditions, *condition)
+		} else {
+			meta.RemoveStatusCondition(&toUpdate.Status.Conditions, slinkyv1beta1.AccountingNamespaceAccountsSynced)
+		}
+		return r.Status().Update(ctx, toUpdate)
+	}); err != nil {
+		return fmt.Errorf("error updating Accounting(%s) status: %w",
+			klog.KObj(accounting), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,216 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmdb
+
+import (
+	"testing"
+
+	api "github.com/SlinkyProject/slurm-client/api/v0044"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+)
+
+func newResourceQuota(hard corev1.ResourceList) corev1.ResourceQuota {
+	return corev1.ResourceQuota{
+		Spec: corev1.ResourceQuotaSpec{
+			Hard: hard,
+		},
+	}
+}
+
+func Test_namespaceTRES(t *testing.T) {
+	tests := []struct {
+		name   string
+		quotas []corev1.ResourceQuota
+		want   map[string]int64
+	}{
+		{
+			name: "no quota",
+			want: nil,
+		},
+		{
+			name: "unrelated resources",
+			quotas: []corev1.ResourceQuota{
+				newResourceQuota(corev1.ResourceList{
+					corev1.ResourcePods: resource.MustParse("10"),
+				}),
+			},
+			want: nil,
+		},
+		{
+			name: "requests",
+			quotas: []corev1.ResourceQuota{
+				newResourceQuota(corev1.ResourceList{
+					corev1.ResourceRequestsCPU:            resource.MustParse("10500m"),
+					corev1.ResourceRequestsMemory:         resource.MustParse("4Gi"),
+					"requests.nvidia.com/gpu":             resource.MustParse("2"),
+					corev1.ResourceLimitsCPU:              resource.MustParse("20"),
+					corev1.ResourceLimitsMemory:           resource.MustParse("8Gi"),
+					corev1.ResourceRequestsStorage:        resource.MustParse("1Ti"),
+					corev1.ResourcePersistentVolumeClaims: resource.MustParse("5"),
+				}),
+			},
+			want: map[string]int64{"cpu": 10, "mem": 4096, "gres/gpu": 2},
+		},
+		{
+			name: "limits",
+			quotas: []corev1.ResourceQuota{
+				newResourceQuota(corev1.ResourceList{
+					corev1.ResourceLimitsCPU:    resource.MustParse("20"),
+					corev1.ResourceLimitsMemory: resource.MustParse("1G"),
+				}),
+			},
+			want: map[string]int64{"cpu": 20, "mem": 953},
+		},
+		{
+			name: "lowest limit",
+			quotas: []corev1.ResourceQuota{
+				newResourceQuota(corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("8"),
+				}),
+				newResourceQuota(corev1.ResourceList{
+					corev1.ResourceRequestsCPU:    resource.MustParse("4"),
+					corev1.ResourceRequestsMemory: resource.MustParse("1Gi"),
+				}),
+			},
+			want: map[string]int64{"cpu": 4, "mem": 1024},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := namespaceTRES(tt.quotas); !equality.Semantic.DeepEqual(got, tt.want) {
+				t.Errorf("namespaceTRES() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_accountTRES(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits []map[string]int64
+		want   map[string]int64
+	}{
+		{
+			name: "no namespaces",
+			want: nil,
+		},
+		{
+			name:   "one namespace",
+			limits: []map[string]int64{{"cpu": 10, "mem": 1024}},
+			want:   map[string]int64{"cpu": 10, "mem": 1024},
+		},
+		{
+			name: "namespaces add up",
+			limits: []map[string]int64{
+				{"cpu": 10, "mem": 1024},
+				{"cpu": 5, "mem": 2048, "gres/gpu": 1},
+			},
+			want: map[string]int64{"cpu": 15, "mem": 3072},
+		},
+		{
+			name: "unlimited namespace",
+			limits: []map[string]int64{
+				{"cpu": 10},
+				nil,
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := accountTRES(tt.limits); !equality.Semantic.DeepEqual(got, tt.want) {
+				t.Errorf("accountTRES() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_staleNamespaceAccounts(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous []slinkyv1beta1.NamespaceAccountStatus
+		current  []slinkyv1beta1.NamespaceAccountStatus
+		want     []string
+	}{
+		{
+			name: "no namespaces",
+			want: []string{},
+		},
+		{
+			name: "unchanged",
+			previous: []slinkyv1beta1.NamespaceAccountStatus{
+				{Namespace: "physics", Account: "physics"},
+			},
+			current: []slinkyv1beta1.NamespaceAccountStatus{
+				{Namespace: "physics", Account: "physics"},
+			},
+			want: []string{},
+		},
+		{
+			name: "namespace deselected",
+			previous: []slinkyv1beta1.NamespaceAccountStatus{
+				{Namespace: "chemistry", Account: "chemistry"},
+				{Namespace: "physics", Account: "physics"},
+			},
+			current: []slinkyv1beta1.NamespaceAccountStatus{
+				{Namespace: "physics", Account: "physics"},
+			},
+			want: []string{"chemistry"},
+		},
+		{
+			name: "account still has a namespace",
+			previous: []slinkyv1beta1.NamespaceAccountStatus{
+				{Namespace: "physics-a", Account: "physics"},
+				{Namespace: "physics-b", Account: "physics"},
+			},
+			current: []slinkyv1beta1.NamespaceAccountStatus{
+				{Namespace: "physics-b", Account: "physics"},
+			},
+			want: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := staleNamespaceAccounts(tt.previous, tt.current); !equality.Semantic.DeepEqual(got, tt.want) {
+				t.Errorf("staleNamespaceAccounts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_toAccountAssoc(t *testing.T) {
+	grpTRES := map[string]int64{"cpu": 10, "gres/gpu": 2}
+	assoc, err := toAccountAssoc("physics", "slurm_slurm", "root", tresList(grpTRES, map[string]int64{"mem": 1024}))
+	if err != nil {
+		t.Fatalf("toAccountAssoc() error = %v", err)
+	}
+	if ptr.Deref(assoc.Account, "") != "physics" || ptr.Deref(assoc.Cluster, "") != "slurm_slurm" ||
+		ptr.Deref(assoc.ParentAccount, "") != "root" || assoc.User != "" {
+		t.Errorf("toAccountAssoc() = %+v", assoc)
+	}
+	want := api.V0044TresList{
+		{Type: "cpu", Count: ptr.To[int64](10)},
+		{Type: "gres", Name: ptr.To("gpu"), Count: ptr.To[int64](2)},
+		{Type: "mem", Count: ptr.To[int64](-1)},
+	}
+	if got := *assoc.Max.Tres.Total; !equality.Semantic.DeepEqual(got, want) {
+		t.Errorf("toAccountAssoc() GrpTRES = %v, want %v", got, want)
+	}
+
+	assoc, err = toAccountAssoc("physics", "slurm_slurm", "root", tresList(grpTRES, nil))
+	if err != nil {
+		t.Fatalf("toAccountAssoc() error = %v", err)
+	}
+	if got := assocGrpTRES(assoc); !equality.Semantic.DeepEqual(got, grpTRES) {
+		t.Errorf("assocGrpTRES() = %v, want %v", got, grpTRES)
+	}
+	if got := assocGrpTRES(api.V0044Assoc{}); got != nil {
+		t.Errorf("assocGrpTRES() = %v, want nil", got)
+	}
+}
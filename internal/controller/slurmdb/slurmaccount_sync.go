@@ -0,0 +1,358 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmdb
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"k8s.io/utils/set"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	api "github.com/SlinkyProject/slurm-client/api/v0044"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	"github.com/SlinkyProject/slurm-operator/internal/controller/slurmdb/slurmcontrol"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/objectutils"
+)
+
+const (
+	// defaultParentAccount is the parent of accounts that do not name one.
+	defaultParentAccount = "root"
+
+	syncedReasonSynced   = "Synced"
+	syncedReasonNoClient = "NoClient"
+	syncedReasonFailed   = "SyncFailed"
+)
+
+// Reasons for SlurmAccount, SlurmUser, and SlurmQOS events
+const (
+	// DriftCorrectedReason is added to an event when changes made in slurmdbd are reverted.
+	DriftCorrectedReason = "DriftCorrected"
+	// SyncFailedReason is added to an event when slurmdbd could not be updated.
+	SyncFailedReason = "SyncFailed"
+	// RemovedReason is added to an event when the object was removed from slurmdbd.
+	RemovedReason = "Removed"
+)
+
+// Sync implements control logic for synchronizing a SlurmAccount.
+func (r *SlurmAccountReconciler) Sync(ctx context.Context, req reconcile.Request) error {
+	logger := log.FromContext(ctx)
+
+	account := &slinkyv1beta1.SlurmAccount{}
+	if err := r.Get(ctx, req.NamespacedName, account); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("SlurmAccount has been deleted", "request", req)
+			return nil
+		}
+		return err
+	}
+
+	if !account.DeletionTimestamp.IsZero() {
+		return r.syncDelete(ctx, account)
+	}
+
+	if !controllerutil.ContainsFinalizer(account, slinkyv1beta1.FinalizerSlurmdb) {
+		patch := client.MergeFrom(account.DeepCopy())
+		controllerutil.AddFinalizer(account, slinkyv1beta1.FinalizerSlurmdb)
+		if err := r.Patch(ctx, account, patch); err != nil {
+			return err
+		}
+	}
+
+	status := account.Status.DeepCopy()
+	var errs []error
+	if err := r.syncAccount(ctx, account, status); err != nil {
+		errs = append(errs, err)
+	}
+	if err := r.syncStatus(ctx, account, status); err != nil {
+		errs = append(errs, err)
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// syncAccount makes the account in slurmdbd match the spec.
+func (r *SlurmAccountReconciler) syncAccount(
+	ctx context.Context,
+	account *slinkyv1beta1.SlurmAccount,
+	status *slinkyv1beta1.SlurmAccountStatus,
+) error {
+	key := account.Key().String()
+
+	accounting, err := resolveAccounting(ctx, r.refResolver, r.slurmControl, account.AccountingRef())
+	if err != nil {
+		return err
+	}
+	if accounting.controller == nil {
+		accountDurationStore.Push(key, NoClientInterval)
+		meta.SetStatusCondition(&status.Conditions, noClientCondition(account.Generation))
+		return nil
+	}
+	accountDurationStore.Push(key, SyncInterval)
+
+	clusters := accountClusters(account, accounting)
+	changes, err := r.applyAccount(ctx, accounting.controller, account, clusters, accounting.clusters)
+	if err != nil {
+		r.eventRecorder.Eventf(account, corev1.EventTypeWarning, SyncFailedReason,
+			"Failed to sync account %s: %v", account.AccountName(), err)
+		meta.SetStatusCondition(&status.Conditions, failedCondition(account.Generation, err))
+		return err
+	}
+
+	status.Drift = nil
+	if isSynced(status.Conditions, account.Generation) && len(changes) > 0 {
+		status.Drift = changes
+		r.eventRecorder.Eventf(account, corev1.EventTypeWarning, DriftCorrectedReason,
+			"Reverted changes to account %s: %s", account.AccountName(), strings.Join(changes, "; "))
+	}
+	status.Clusters = clusters
+	status.LastSyncTime = ptr.To(metav1.Now())
+	meta.SetStatusCondition(&status.Conditions, syncedCondition(account.Generation))
+
+	return nil
+}
+
+// applyAccount makes the account and its associations in slurmdbd match the
+// spec, returning the differences that were found.
+func (r *SlurmAccountReconciler) applyAccount(
+	ctx context.Context,
+	controller *slinkyv1beta1.Controller,
+	account *slinkyv1beta1.SlurmAccount,
+	clusters []string,
+	knownClusters []string,
+) ([]string, error) {
+	name := account.AccountName()
+	parent := valueOrDefault(account.Spec.ParentAccount, defaultParentAccount)
+	var fairshare *int32
+	if account.Spec.Fairshare > 0 {
+		fairshare = ptr.To(account.Spec.Fairshare)
+	}
+
+	current, err := r.slurmControl.GetAccount(ctx, controller, name)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		req := api.V0044OpenapiAccountsAddCondResp{
+			Account: &api.V0044AccountShort{
+				Description:  ptr.To(valueOrDefault(account.Spec.Description, name)),
+				Organization: ptr.To(valueOrDefault(account.Spec.Organization, name)),
+			},
+			AssociationCondition: api.V0044AccountsAddCond{
+				Accounts: api.V0044StringList{name},
+				Clusters: ptr.To(api.V0044StringList(clusters)),
+				Association: &api.V0044AssocRecSet{
+					Parent:    ptr.To(parent),
+					Fairshare: fairshare,
+				},
+			},
+		}
+		return []string{"account is missing"}, r.slurmControl.CreateAccount(ctx, controller, req)
+	}
+
+	changes := []string{}
+	toUpdate := *current
+	toUpdate.Associations = nil
+	toUpdate.Coordinators = nil
+	if account.Spec.Description != "" && current.Description != account.Spec.Description {
+		changes = append(changes, fmt.Sprintf("description is %q, want %q", current.Description, account.Spec.Description))
+		toUpdate.Description = account.Spec.Description
+	}
+	if account.Spec.Organization != "" && current.Organization != account.Spec.Organization {
+		changes = append(changes, fmt.Sprintf("organization is %q, want %q", current.Organization, account.Spec.Organization))
+		toUpdate.Organization = account.Spec.Organization
+	}
+	if len(changes) > 0 {
+		if err := r.slurmControl.UpdateAccount(ctx, controller, toUpdate); err != nil {
+			return nil, err
+		}
+	}
+
+	assocs, err := r.slurmControl.GetAssociations(ctx, controller, api.SlurmdbV0044GetAssociationsParams{
+		Account: ptr.To(name),
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The account association of each cluster is the one without a user.
+	currentAssocs := make(map[string]api.V0044Assoc)
+	for _, assoc := range assocs {
+		if assoc.User == "" && ptr.Deref(assoc.Account, "") == name {
+			currentAssocs[ptr.Deref(assoc.Cluster, "")] = assoc
+		}
+	}
+
+	var toApply []api.V0044Assoc
+	for _, cluster := range clusters {
+		assoc, ok := currentAssocs[cluster]
+		switch {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("association on cluster %q is missing", cluster))
+		case ptr.Deref(assoc.ParentAccount, "") != parent:
+			changes = append(changes, fmt.Sprintf("parent account on cluster %q is %q, want %q",
+				cluster, ptr.Deref(assoc.ParentAccount, ""), parent))
+		case fairshare != nil && ptr.Deref(assoc.SharesRaw, 0) != *fairshare:
+			changes = append(changes, fmt.Sprintf("fairshare on cluster %q is %d, want %d",
+				cluster, ptr.Deref(assoc.SharesRaw, 0), *fairshare))
+		default:
+			continue
+		}
+		toApply = append(toApply, api.V0044Assoc{
+			Account:       ptr.To(name),
+			Cluster:       ptr.To(cluster),
+			ParentAccount: ptr.To(parent),
+			SharesRaw:     fairshare,
+		})
+	}
+	if err := r.slurmControl.UpdateAssociations(ctx, controller, toApply); err != nil {
+		return nil, err
+	}
+
+	// Only associations on the clusters of the Accounting are managed, other
+	// clusters may share slurmdbd.
+	wanted := set.New(clusters...)
+	for _, cluster := range knownClusters {
+		if _, ok := currentAssocs[cluster]; !ok || wanted.Has(cluster) {
+			continue
+		}
+		changes = append(changes, fmt.Sprintf("association on cluster %q is not wanted", cluster))
+		params := api.SlurmdbV0044DeleteAssociationsParams{
+			Account: ptr.To(name),
+			Cluster: ptr.To(cluster),
+		}
+		if err := r.slurmControl.DeleteAssociations(ctx, controller, params); err != nil {
+			return nil, err
+		}
+	}
+
+	return changes, nil
+}
+
+// syncDelete removes the account associations from slurmdbd, and the account
+// once it has no associations left, before removing the finalizer.
+func (r *SlurmAccountReconciler) syncDelete(ctx context.Context, account *slinkyv1beta1.SlurmAccount) error {
+	logger := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(account, slinkyv1beta1.FinalizerSlurmdb) {
+		return nil
+	}
+
+	accounting, err := resolveAccounting(ctx, r.refResolver, r.slurmControl, account.AccountingRef())
+	if err != nil {
+		return err
+	}
+	switch {
+	case accounting.gone:
+		logger.Info("Accounting is gone, skipping removal of the account from slurmdbd",
+			"account", account.AccountName())
+	case accounting.controller == nil:
+		logger.Info("Waiting for a slurm client to remove the account from slurmdbd",
+			"account", account.AccountName())
+		accountDurationStore.Push(account.Key().String(), NoClientInterval)
+		return nil
+	default:
+		clusters := set.New(accounting.clusters...).Union(set.New(account.Spec.Clusters...)).SortedList()
+		if err := removeAccount(ctx, r.slurmControl, accounting.controller, account.AccountName(), clusters); err != nil {
+			r.eventRecorder.Eventf(account, corev1.EventTypeWarning, SyncFailedReason,
+				"Failed to remove account %s: %v", account.AccountName(), err)
+			return err
+		}
+		r.eventRecorder.Eventf(account, corev1.EventTypeNormal, RemovedReason,
+			"Removed account %s from slurmdbd", account.AccountName())
+	}
+
+	patch := client.MergeFrom(account.DeepCopy())
+	controllerutil.RemoveFinalizer(account, slinkyv1beta1.FinalizerSlurmdb)
+	return r.Patch(ctx, account, patch)
+}
+
+// removeAccount removes the associations of the account on the clusters,
+// and the account once it has no associations left.
+func removeAccount(
+	ctx context.Context,
+	slurmControl slurmcontrol.SlurmControlInterface,
+	controller *slinkyv1beta1.Controller,
+	name string,
+	clusters []string,
+) error {
+	for _, cluster := range clusters {
+		params := api.SlurmdbV0044DeleteAssociationsParams{
+			Account: ptr.To(name),
+			Cluster: ptr.To(cluster),
+		}
+		if err := slurmControl.DeleteAssociations(ctx, controller, params); err != nil {
+			return err
+		}
+	}
+
+	assocs, err := slurmControl.GetAssociations(ctx, controller, api.SlurmdbV0044GetAssociationsParams{
+		Account: ptr.To(name),
+	})
+	if err != nil {
+		return err
+	}
+	if slices.ContainsFunc(assocs, func(assoc api.V0044Assoc) bool {
+		return ptr.Deref(assoc.Account, "") == name
+	}) {
+		return nil
+	}
+
+	return slurmControl.DeleteAccount(ctx, controller, name)
+}
+
+// accountClusters returns the clusters the account is associated with.
+func accountClusters(account *slinkyv1beta1.SlurmAccount, accounting *accountingClusters) []string {
+	if len(account.Spec.Clusters) > 0 {
+		return set.New(account.Spec.Clusters...).SortedList()
+	}
+	return accounting.clusters
+}
+
+// syncStatus handles determining and updating the status.
+func (r *SlurmAccountReconciler) syncStatus(
+	ctx context.Context,
+	account *slinkyv1beta1.SlurmAccount,
+	newStatus *slinkyv1beta1.SlurmAccountStatus,
+) error {
+	logger := log.FromContext(ctx)
+
+	if apiequality.Semantic.DeepEqual(account.Status, *newStatus) {
+		logger.V(2).Info("SlurmAccount Status has not changed, skipping status update",
+			"account", klog.KObj(account), "status", account.Status)
+		return nil
+	}
+
+	accountKey := objectutils.NamespacedName(account)
+	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		toUpdate := &slinkyv1beta1.SlurmAccount{}
+		if err := r.Get(ctx, accountKey, toUpdate); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		toUpdate.Status = *newStatus
+		return r.Status().Update(ctx, toUpdate)
+	}); err != nil {
+		return fmt.Errorf("error updating SlurmAccount(%s) status: %w",
+			klog.KObj(account), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmdb
+
+import (
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+)
+
+func newSlurmAccount(clusters ...string) *slinkyv1beta1.SlurmAccount {
+	return &slinkyv1beta1.SlurmAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      "physics",
+		},
+		Spec: slinkyv1beta1.SlurmAccountSpec{
+			AccountingRef: slinkyv1beta1.ObjectReference{Name: "slurm"},
+			Clusters:      clusters,
+		},
+	}
+}
+
+func Test_accountClusters(t *testing.T) {
+	accounting := &accountingClusters{
+		clusters: []string{"default_slurm-a", "default_slurm-b"},
+	}
+	tests := []struct {
+		name    string
+		account *slinkyv1beta1.SlurmAccount
+		want    []string
+	}{
+		{
+			name:    "all clusters",
+			account: newSlurmAccount(),
+			want:    []string{"default_slurm-a", "default_slurm-b"},
+		},
+		{
+			name:    "some clusters",
+			account: newSlurmAccount("default_slurm-b", "other"),
+			want:    []string{"default_slurm-b", "other"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := accountClusters(tt.account, accounting); !equality.Semantic.DeepEqual(got, tt.want) {
+				t.Errorf("accountClusters() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_isSynced(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions []metav1.Condition
+		generation int64
+		want       bool
+	}{
+		{
+			name:       "synced",
+			conditions: []metav1.Condition{syncedCondition(2)},
+			generation: 2,
+			want:       true,
+		},
+		{
+			name:       "spec changed",
+			conditions: []metav1.Condition{syncedCondition(1)},
+			generation: 2,
+			want:       false,
+		},
+		{
+			name:       "failed",
+			conditions: []metav1.Condition{failedCondition(2, errors.New("unable to connect"))},
+			generation: 2,
+			want:       false,
+		},
+		{
+			name:       "no client",
+			conditions: []metav1.Condition{noClientCondition(2)},
+			generation: 2,
+			want:       false,
+		},
+		{
+			name:       "new",
+			generation: 1,
+			want:       false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isSynced(tt.conditions, tt.generation); got != tt.want {
+				t.Errorf("isSynced() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
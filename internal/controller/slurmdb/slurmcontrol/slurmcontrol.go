@@ -0,0 +1,415 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmcontrol
+
+import (
+	"context"
+	"net/http"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	api "github.com/SlinkyProject/slurm-client/api/v0044"
+	slurmclient "github.com/SlinkyProject/slurm-client/pkg/client"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	"github.com/SlinkyProject/slurm-operator/internal/clientmap"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/slurmapi"
+)
+
+type SlurmControlInterface interface {
+	// HasClient reports if there is a slurm client for the controller.
+	HasClient(controller *slinkyv1beta1.Controller) bool
+	// GetAccount returns the account, or nil if it does not exist.
+	GetAccount(ctx context.Context, controller *slinkyv1beta1.Controller, name string) (*api.V0044Account, error)
+	// CreateAccount adds the account with the associations of the request.
+	CreateAccount(ctx context.Context, controller *slinkyv1beta1.Controller, req api.V0044OpenapiAccountsAddCondResp) error
+	// UpdateAccount updates the description and organization of the account.
+	UpdateAccount(ctx context.Context, controller *slinkyv1beta1.Controller, account api.V0044Account) error
+	// DeleteAccount removes the account.
+	DeleteAccount(ctx context.Context, controller *slinkyv1beta1.Controller, name string) error
+	// GetUser returns the user, or nil if it does not exist.
+	GetUser(ctx context.Context, controller *slinkyv1beta1.Controller, name string) (*api.V0044User, error)
+	// CreateUser adds the user, if needed, with the associations of the request.
+	CreateUser(ctx context.Context, controller *slinkyv1beta1.Controller, req api.V0044OpenapiUsersAddCondResp) error
+	// UpdateUser updates the admin level and default account of the user.
+	UpdateUser(ctx context.Context, controller *slinkyv1beta1.Controller, user api.V0044User) error
+	// DeleteUser removes the user.
+	DeleteUser(ctx context.Context, controller *slinkyv1beta1.Controller, name string) error
+	// GetAssociations returns the associations matching the params.
+	GetAssociations(ctx context.Context, controller *slinkyv1beta1.Controller, params api.SlurmdbV0044GetAssociationsParams) ([]api.V0044Assoc, error)
+	// UpdateAssociations adds or updates the associations.
+	UpdateAssociations(ctx context.Context, controller *slinkyv1beta1.Controller, assocs []api.V0044Assoc) error
+	// DeleteAssociations removes the associations matching the params.
+	DeleteAssociations(ctx context.Context, controller *slinkyv1beta1.Controller, params api.SlurmdbV0044DeleteAssociationsParams) error
+	// GetQOS returns every QOS.
+	GetQOS(ctx context.Context, controller *slinkyv1beta1.Controller) ([]api.V0044Qos, error)
+	// UpdateQOS adds or updates the QOS.
+	UpdateQOS(ctx context.Context, controller *slinkyv1beta1.Controller, qos api.V0044Qos) error
+	// DeleteQOS removes the QOS.
+	DeleteQOS(ctx context.Context, controller *slinkyv1beta1.Controller, name string) error
+}
+
+// realSlurmControl is the default implementation of SlurmControlInterface.
+type realSlurmControl struct {
+	clientMap    *clientmap.ClientMap
+	newAPIClient slurmapi.ClientFunc
+}
+
+// HasClient implements SlurmControlInterface.
+func (r *realSlurmControl) HasClient(controller *slinkyv1beta1.Controller) bool {
+	return r.lookupClient(controller) != nil
+}
+
+// GetAccount implements SlurmControlInterface.
+func (r *realSlurmControl) GetAccount(ctx context.Context, controller *slinkyv1beta1.Controller, name string) (*api.V0044Account, error) {
+	apiClient, err := r.lookupAPIClient(ctx, controller, "GetAccount")
+	if apiClient == nil || err != nil {
+		return nil, err
+	}
+	res, err := apiClient.SlurmdbV0044GetAccountWithResponse(ctx, name, &api.SlurmdbV0044GetAccountParams{})
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode() == http.StatusNotFound {
+		return nil, nil
+	}
+	if res.JSON200 == nil {
+		var oapiErrors *api.V0044OpenapiErrors
+		if res.JSONDefault != nil {
+			oapiErrors = res.JSONDefault.Errors
+		}
+		return nil, slurmapi.CheckResponse(res.StatusCode(), oapiErrors)
+	}
+	for _, account := range res.JSON200.Accounts {
+		if account.Name == name {
+			return &account, nil
+		}
+	}
+	return nil, nil
+}
+
+// CreateAccount implements SlurmControlInterface.
+func (r *realSlurmControl) CreateAccount(ctx context.Context, controller *slinkyv1beta1.Controller, req api.V0044OpenapiAccountsAddCondResp) error {
+	apiClient, err := r.lookupAPIClient(ctx, controller, "CreateAccount")
+	if apiClient == nil || err != nil {
+		return err
+	}
+	res, err := apiClient.SlurmdbV0044PostAccountsAssociationWithResponse(ctx, req)
+	if err != nil {
+		return err
+	}
+	if res.JSON200 == nil {
+		var oapiErrors *api.V0044OpenapiErrors
+		if res.JSONDefault != nil {
+			oapiErrors = res.JSONDefault.Errors
+		}
+		return slurmapi.CheckResponse(res.StatusCode(), oapiErrors)
+	}
+	return nil
+}
+
+// UpdateAccount implements SlurmControlInterface.
+func (r *realSlurmControl) UpdateAccount(ctx context.Context, controller *slinkyv1beta1.Controller, account api.V0044Account) error {
+	apiClient, err := r.lookupAPIClient(ctx, controller, "UpdateAccount")
+	if apiClient == nil || err != nil {
+		return err
+	}
+	req := api.V0044OpenapiAccountsResp{
+		Accounts: api.V0044AccountList{account},
+	}
+	res, err := apiClient.SlurmdbV0044PostAccountsWithResponse(ctx, req)
+	if err != nil {
+		return err
+	}
+	if res.JSON200 == nil {
+		var oapiErrors *api.V0044OpenapiErrors
+		if res.JSONDefault != nil {
+			oapiErrors = res.JSONDefault.Errors
+		}
+		return slurmapi.CheckResponse(res.StatusCode(), oapiErrors)
+	}
+	return nil
+}
+
+// DeleteAccount implements SlurmControlInterface.
+func (r *realSlurmControl) DeleteAccount(ctx context.Context, controller *slinkyv1beta1.Controller, name string) error {
+	apiClient, err := r.lookupAPIClient(ctx, controller, "DeleteAccount")
+	if apiClient == nil || err != nil {
+		return err
+	}
+	res, err := apiClient.SlurmdbV0044DeleteAccountWithResponse(ctx, name)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode() == http.StatusNotFound {
+		return nil
+	}
+	if res.JSON200 == nil {
+		var oapiErrors *api.V0044OpenapiErrors
+		if res.JSONDefault != nil {
+			oapiErrors = res.JSONDefault.Errors
+		}
+		return slurmapi.CheckResponse(res.StatusCode(), oapiErrors)
+	}
+	return nil
+}
+
+// GetUser implements SlurmControlInterface.
+func (r *realSlurmControl) GetUser(ctx context.Context, controller *slinkyv1beta1.Controller, name string) (*api.V0044User, error) {
+	apiClient, err := r.lookupAPIClient(ctx, controller, "GetUser")
+	if apiClient == nil || err != nil {
+		return nil, err
+	}
+	res, err := apiClient.SlurmdbV0044GetUserWithResponse(ctx, name, &api.SlurmdbV0044GetUserParams{})
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode() == http.StatusNotFound {
+		return nil, nil
+	}
+	if res.JSON200 == nil {
+		var oapiErrors *api.V0044OpenapiErrors
+		if res.JSONDefault != nil {
+			oapiErrors = res.JSONDefault.Errors
+		}
+		return nil, slurmapi.CheckResponse(res.StatusCode(), oapiErrors)
+	}
+	for _, user := range res.JSON200.Users {
+		if user.Name == name {
+			return &user, nil
+		}
+	}
+	return nil, nil
+}
+
+// CreateUser implements SlurmControlInterface.
+func (r *realSlurmControl) CreateUser(ctx context.Context, controller *slinkyv1beta1.Controller, req api.V0044OpenapiUsersAddCondResp) error {
+	apiClient, err := r.lookupAPIClient(ctx, controller, "CreateUser")
+	if apiClient == nil || err != nil {
+		return err
+	}
+	res, err := apiClient.SlurmdbV0044PostUsersAssociationWithResponse(ctx, &api.SlurmdbV0044PostUsersAssociationParams{}, req)
+	if err != nil {
+		return err
+	}
+	if res.JSON200 == nil {
+		var oapiErrors *api.V0044OpenapiErrors
+		if res.JSONDefault != nil {
+			oapiErrors = res.JSONDefault.Errors
+		}
+		return slurmapi.CheckResponse(res.StatusCode(), oapiErrors)
+	}
+	return nil
+}
+
+// UpdateUser implements SlurmControlInterface.
+func (r *realSlurmControl) UpdateUser(ctx context.Context, controller *slinkyv1beta1.Controller, user api.V0044User) error {
+	apiClient, err := r.lookupAPIClient(ctx, controller, "UpdateUser")
+	if apiClient == nil || err != nil {
+		return err
+	}
+	req := api.V0044OpenapiUsersResp{
+		Users: api.V0044UserList{user},
+	}
+	res, err := apiClient.SlurmdbV0044PostUsersWithResponse(ctx, req)
+	if err != nil {
+		return err
+	}
+	if res.JSON200 == nil {
+		var oapiErrors *api.V0044OpenapiErrors
+		if res.JSONDefault != nil {
+			oapiErrors = res.JSONDefault.Errors
+		}
+		return slurmapi.CheckResponse(res.StatusCode(), oapiErrors)
+	}
+	return nil
+}
+
+// DeleteUser implements SlurmControlInterface.
+func (r *realSlurmControl) DeleteUser(ctx context.Context, controller *slinkyv1beta1.Controller, name string) error {
+	apiClient, err := r.lookupAPIClient(ctx, controller, "DeleteUser")
+	if apiClient == nil || err != nil {
+		return err
+	}
+	res, err := apiClient.SlurmdbV0044DeleteUserWithResponse(ctx, name)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode() == http.StatusNotFound {
+		return nil
+	}
+	if res.JSON200 == nil {
+		var oapiErrors *api.V0044OpenapiErrors
+		if res.JSONDefault != nil {
+			oapiErrors = res.JSONDefault.Errors
+		}
+		return slurmapi.CheckResponse(res.StatusCode(), oapiErrors)
+	}
+	return nil
+}
+
+// GetAssociations implements SlurmControlInterface.
+func (r *realSlurmControl) GetAssociations(ctx context.Context, controller *slinkyv1beta1.Controller, params api.SlurmdbV0044GetAssociationsParams) ([]api.V0044Assoc, error) {
+	apiClient, err := r.lookupAPIClient(ctx, controller, "GetAssociations")
+	if apiClient == nil || err != nil {
+		return nil, err
+	}
+	res, err := apiClient.SlurmdbV0044GetAssociationsWithResponse(ctx, &params)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode() == http.StatusNotFound {
+		return nil, nil
+	}
+	if res.JSON200 == nil {
+		var oapiErrors *api.V0044OpenapiErrors
+		if res.JSONDefault != nil {
+			oapiErrors = res.JSONDefault.Errors
+		}
+		return nil, slurmapi.CheckResponse(res.StatusCode(), oapiErrors)
+	}
+	return res.JSON200.Associations, nil
+}
+
+// UpdateAssociations implements SlurmControlInterface.
+func (r *realSlurmControl) UpdateAssociations(ctx context.Context, controller *slinkyv1beta1.Controller, assocs []api.V0044Assoc) error {
+	if len(assocs) == 0 {
+		return nil
+	}
+	apiClient, err := r.lookupAPIClient(ctx, controller, "UpdateAssociations")
+	if apiClient == nil || err != nil {
+		return err
+	}
+	req := api.V0044OpenapiAssocsResp{
+		Associations: assocs,
+	}
+	res, err := apiClient.SlurmdbV0044PostAssociationsWithResponse(ctx, req)
+	if err != nil {
+		return err
+	}
+	if res.JSON200 == nil {
+		var oapiErrors *api.V0044OpenapiErrors
+		if res.JSONDefault != nil {
+			oapiErrors = res.JSONDefault.Errors
+		}
+		return slurmapi.CheckResponse(res.StatusCode(), oapiErrors)
+	}
+	return nil
+}
+
+// DeleteAssociations implements SlurmControlInterface.
+func (r *realSlurmControl) DeleteAssociations(ctx context.Context, controller *slinkyv1beta1.Controller, params api.SlurmdbV0044DeleteAssociationsParams) error {
+	apiClient, err := r.lookupAPIClient(ctx, controller, "DeleteAssociations")
+	if apiClient == nil || err != nil {
+		return err
+	}
+	res, err := apiClient.SlurmdbV0044DeleteAssociationsWithResponse(ctx, &params)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode() == http.StatusNotFound {
+		return nil
+	}
+	if res.JSON200 == nil {
+		var oapiErrors *api.V0044OpenapiErrors
+		if res.JSONDefault != nil {
+			oapiErrors = res.JSONDefault.Errors
+		}
+		return slurmapi.CheckResponse(res.StatusCode(), oapiErrors)
+	}
+	return nil
+}
+
+// GetQOS implements SlurmControlInterface.
+func (r *realSlurmControl) GetQOS(ctx context.Context, controller *slinkyv1beta1.Controller) ([]api.V0044Qos, error) {
+	apiClient, err := r.lookupAPIClient(ctx, controller, "GetQOS")
+	if apiClient == nil || err != nil {
+		return nil, err
+	}
+	res, err := apiClient.SlurmdbV0044GetQosWithResponse(ctx, &api.SlurmdbV0044GetQosParams{})
+	if err != nil {
+		return nil, err
+	}
+	if res.JSON200 == nil {
+		var oapiErrors *api.V0044OpenapiErrors
+		if res.JSONDefault != nil {
+			oapiErrors = res.JSONDefault.Errors
+		}
+		return nil, slurmapi.CheckResponse(res.StatusCode(), oapiErrors)
+	}
+	return res.JSON200.Qos, nil
+}
+
+// UpdateQOS implements SlurmControlInterface.
+func (r *realSlurmControl) UpdateQOS(ctx context.Context, controller *slinkyv1beta1.Controller, qos api.V0044Qos) error {
+	apiClient, err := r.lookupAPIClient(ctx, controller, "UpdateQOS")
+	if apiClient == nil || err != nil {
+		return err
+	}
+	req := api.V0044OpenapiSlurmdbdQosResp{
+		Qos: api.V0044QosList{qos},
+	}
+	res, err := apiClient.SlurmdbV0044PostQosWithResponse(ctx, &api.SlurmdbV0044PostQosParams{}, req)
+	if err != nil {
+		return err
+	}
+	if res.JSON200 == nil {
+		var oapiErrors *api.V0044OpenapiErrors
+		if res.JSONDefault != nil {
+			oapiErrors = res.JSONDefault.Errors
+		}
+		return slurmapi.CheckResponse(res.StatusCode(), oapiErrors)
+	}
+	return nil
+}
+
+// DeleteQOS implements SlurmControlInterface.
+func (r *realSlurmControl) DeleteQOS(ctx context.Context, controller *slinkyv1beta1.Controller, name string) error {
+	apiClient, err := r.lookupAPIClient(ctx, controller, "DeleteQOS")
+	if apiClient == nil || err != nil {
+		return err
+	}
+	res, err := apiClient.SlurmdbV0044DeleteSingleQosWithResponse(ctx, name)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode() == http.StatusNotFound {
+		return nil
+	}
+	if res.JSON200 == nil {
+		var oapiErrors *api.V0044OpenapiErrors
+		if res.JSONDefault != nil {
+			oapiErrors = res.JSONDefault.Errors
+		}
+		return slurmapi.CheckResponse(res.StatusCode(), oapiErrors)
+	}
+	return nil
+}
+
+func (r *realSlurmControl) lookupClient(controller *slinkyv1beta1.Controller) slurmclient.Client {
+	return r.clientMap.Get(client.ObjectKeyFromObject(controller))
+}
+
+// lookupAPIClient returns an API client for the controller, or nil if there
+// is no slurm client for it.
+func (r *realSlurmControl) lookupAPIClient(ctx context.Context, controller *slinkyv1beta1.Controller, op string) (api.ClientWithResponsesInterface, error) {
+	logger := log.FromContext(ctx)
+
+	slurmClient := r.lookupClient(controller)
+	if slurmClient == nil {
+		logger.V(2).Info("no client for controller, cannot do " + op + "()")
+		return nil, nil
+	}
+	return r.newAPIClient(slurmClient)
+}
+
+var _ SlurmControlInterface = &realSlurmControl{}
+
+func NewSlurmControl(clientMap *clientmap.ClientMap) SlurmControlInterface {
+	return &realSlurmControl{
+		clientMap:    clientMap,
+		newAPIClient: slurmapi.NewClient,
+	}
+}
@@ -0,0 +1,371 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmcontrol
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	api "github.com/SlinkyProject/slurm-client/api/v0044"
+	"github.com/SlinkyProject/slurm-client/pkg/client"
+	apifake "github.com/SlinkyProject/slurm-client/pkg/client/api/v0044/fake"
+	apiinterceptor "github.com/SlinkyProject/slurm-client/pkg/client/api/v0044/interceptor"
+	"github.com/SlinkyProject/slurm-client/pkg/client/fake"
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	"github.com/SlinkyProject/slurm-operator/internal/clientmap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+func newController(name string) *slinkyv1beta1.Controller {
+	return &slinkyv1beta1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      name,
+		},
+	}
+}
+
+func newSlurmClientMap(controllerName string, client client.Client) *clientmap.ClientMap {
+	cm := clientmap.NewClientMap()
+	key := k8stypes.NamespacedName{
+		Namespace: corev1.NamespaceDefault,
+		Name:      controllerName,
+	}
+	cm.Add(key, client)
+	return cm
+}
+
+func newAPIClient(funcs apiinterceptor.Funcs) func(client.Client) (api.ClientWithResponsesInterface, error) {
+	return func(client.Client) (api.ClientWithResponsesInterface, error) {
+		return apifake.NewFakeClientBuilder().WithInterceptorFuncs(funcs).Build(), nil
+	}
+}
+
+func Test_realSlurmControl_HasClient(t *testing.T) {
+	controller := newController("slurm")
+	tests := []struct {
+		name      string
+		clientMap *clientmap.ClientMap
+		want      bool
+	}{
+		{
+			name:      "client",
+			clientMap: newSlurmClientMap(controller.Name, fake.NewFakeClient()),
+			want:      true,
+		},
+		{
+			name:      "no client",
+			clientMap: clientmap.NewClientMap(),
+			want:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewSlurmControl(tt.clientMap)
+			if got := r.HasClient(controller); got != tt.want {
+				t.Errorf("realSlurmControl.HasClient() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_realSlurmControl_GetAccount(t *testing.T) {
+	ctx := context.Background()
+	controller := newController("slurm")
+	tests := []struct {
+		name         string
+		clientMap    *clientmap.ClientMap
+		newAPIClient func(client.Client) (api.ClientWithResponsesInterface, error)
+		want         string
+		wantErr      bool
+	}{
+		{
+			name:      "found",
+			clientMap: newSlurmClientMap(controller.Name, fake.NewFakeClient()),
+			newAPIClient: newAPIClient(apiinterceptor.Funcs{
+				SlurmdbV0044GetAccountWithResponse: func(ctx context.Context, accountName string, params *api.SlurmdbV0044GetAccountParams, reqEditors ...api.RequestEditorFn) (*api.SlurmdbV0044GetAccountResponse, error) {
+					res := &api.SlurmdbV0044GetAccountResponse{
+						HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+						JSON200: &api.V0044OpenapiAccountsResp{
+							Accounts: api.V0044AccountList{
+								{Name: accountName, Description: "physics", Organization: "science"},
+							},
+						},
+					}
+					return res, nil
+				},
+			}),
+			want: "physics",
+		},
+		{
+			name:      "not found",
+			clientMap: newSlurmClientMap(controller.Name, fake.NewFakeClient()),
+			newAPIClient: newAPIClient(apiinterceptor.Funcs{
+				SlurmdbV0044GetAccountWithResponse: func(ctx context.Context, accountName string, params *api.SlurmdbV0044GetAccountParams, reqEditors ...api.RequestEditorFn) (*api.SlurmdbV0044GetAccountResponse, error) {
+					res := &api.SlurmdbV0044GetAccountResponse{
+						HTTPResponse: &http.Response{StatusCode: http.StatusNotFound},
+					}
+					return res, nil
+				},
+			}),
+		},
+		{
+			name:         "empty",
+			clientMap:    newSlurmClientMap(controller.Name, fake.NewFakeClient()),
+			newAPIClient: newAPIClient(apiinterceptor.Funcs{}),
+		},
+		{
+			name:         "no client",
+			clientMap:    clientmap.NewClientMap(),
+			newAPIClient: newAPIClient(apiinterceptor.Funcs{}),
+		},
+		{
+			name:      "error status",
+			clientMap: newSlurmClientMap(controller.Name, fake.NewFakeClient()),
+			newAPIClient: newAPIClient(apiinterceptor.Funcs{
+				SlurmdbV0044GetAccountWithResponse: func(ctx context.Context, accountName string, params *api.SlurmdbV0044GetAccountParams, reqEditors ...api.RequestEditorFn) (*api.SlurmdbV0044GetAccountResponse, error) {
+					res := &api.SlurmdbV0044GetAccountResponse{
+						HTTPResponse: &http.Response{StatusCode: http.StatusInternalServerError},
+						JSONDefault: &api.V0044OpenapiAccountsResp{
+							Errors: &api.V0044OpenapiErrors{
+								{Error: ptr.To("unable to connect to slurmdbd")},
+							},
+						},
+					}
+					return res, nil
+				},
+			}),
+			wantErr: true,
+		},
+		{
+			name:      "error",
+			clientMap: newSlurmClientMap(controller.Name, fake.NewFakeClient()),
+			newAPIClient: newAPIClient(apiinterceptor.Funcs{
+				SlurmdbV0044GetAccountWithResponse: func(ctx context.Context, accountName string, params *api.SlurmdbV0044GetAccountParams, reqEditors ...api.RequestEditorFn) (*api.SlurmdbV0044GetAccountResponse, error) {
+					return nil, errors.New(http.StatusText(http.StatusBadGateway))
+				},
+			}),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &realSlurmControl{
+				clientMap:    tt.clientMap,
+				newAPIClient: tt.newAPIClient,
+			}
+			got, err := r.GetAccount(ctx, controller, "physics")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("realSlurmControl.GetAccount() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			description := ""
+			if got != nil {
+				description = got.Description
+			}
+			if description != tt.want {
+				t.Errorf("realSlurmControl.GetAccount() = %v, want description %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_realSlurmControl_CreateAccount(t *testing.T) {
+	ctx := context.Background()
+	controller := newController("slurm")
+	tests := []struct {
+		name         string
+		clientMap    *clientmap.ClientMap
+		newAPIClient func(client.Client) (api.ClientWithResponsesInterface, error)
+		wantErr      bool
+	}{
+		{
+			name:         "smoke",
+			clientMap:    newSlurmClientMap(controller.Name, fake.NewFakeClient()),
+			newAPIClient: newAPIClient(apiinterceptor.Funcs{}),
+		},
+		{
+			name:         "no client",
+			clientMap:    clientmap.NewClientMap(),
+			newAPIClient: newAPIClient(apiinterceptor.Funcs{}),
+		},
+		{
+			name:      "error status",
+			clientMap: newSlurmClientMap(controller.Name, fake.NewFakeClient()),
+			newAPIClient: newAPIClient(apiinterceptor.Funcs{
+				SlurmdbV0044PostAccountsAssociationWithResponse: func(ctx context.Context, body api.V0044OpenapiAccountsAddCondResp, reqEditors ...api.RequestEditorFn) (*api.SlurmdbV0044PostAccountsAssociationResponse, error) {
+					res := &api.SlurmdbV0044PostAccountsAssociationResponse{
+						HTTPResponse: &http.Response{StatusCode: http.StatusInternalServerError},
+						JSONDefault: &api.V0044OpenapiAccountsAddCondRespStr{
+							Errors: &api.V0044OpenapiErrors{
+								{Error: ptr.To("parent account does not exist")},
+							},
+						},
+					}
+					return res, nil
+				},
+			}),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &realSlurmControl{
+				clientMap:    tt.clientMap,
+				newAPIClient: tt.newAPIClient,
+			}
+			req := api.V0044OpenapiAccountsAddCondResp{
+				AssociationCondition: api.V0044AccountsAddCond{
+					Accounts: api.V0044StringList{"physics"},
+				},
+			}
+			if err := r.CreateAccount(ctx, controller, req); (err != nil) != tt.wantErr {
+				t.Errorf("realSlurmControl.CreateAccount() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_realSlurmControl_GetAssociations(t *testing.T) {
+	ctx := context.Background()
+	controller := newController("slurm")
+	tests := []struct {
+		name         string
+		clientMap    *clientmap.ClientMap
+		newAPIClient func(client.Client) (api.ClientWithResponsesInterface, error)
+		wantCount    int
+		wantErr      bool
+	}{
+		{
+			name:      "smoke",
+			clientMap: newSlurmClientMap(controller.Name, fake.NewFakeClient()),
+			newAPIClient: newAPIClient(apiinterceptor.Funcs{
+				SlurmdbV0044GetAssociationsWithResponse: func(ctx context.Context, params *api.SlurmdbV0044GetAssociationsParams, reqEditors ...api.RequestEditorFn) (*api.SlurmdbV0044GetAssociationsResponse, error) {
+					res := &api.SlurmdbV0044GetAssociationsResponse{
+						HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+						JSON200: &api.V0044OpenapiAssocsResp{
+							Associations: api.V0044AssocList{
+								{Account: ptr.To("physics"), Cluster: ptr.To("slurm-a"), User: "alice"},
+								{Account: ptr.To("physics"), Cluster: ptr.To("slurm-b"), User: "alice"},
+							},
+						},
+					}
+					return res, nil
+				},
+			}),
+			wantCount: 2,
+		},
+		{
+			name:      "not found",
+			clientMap: newSlurmClientMap(controller.Name, fake.NewFakeClient()),
+			newAPIClient: newAPIClient(apiinterceptor.Funcs{
+				SlurmdbV0044GetAssociationsWithResponse: func(ctx context.Context, params *api.SlurmdbV0044GetAssociationsParams, reqEditors ...api.RequestEditorFn) (*api.SlurmdbV0044GetAssociationsResponse, error) {
+					res := &api.SlurmdbV0044GetAssociationsResponse{
+						HTTPResponse: &http.Response{StatusCode: http.StatusNotFound},
+					}
+					return res, nil
+				},
+			}),
+		},
+		{
+			name:         "no client",
+			clientMap:    clientmap.NewClientMap(),
+			newAPIClient: newAPIClient(apiinterceptor.Funcs{}),
+		},
+		{
+			name:      "error",
+			clientMap: newSlurmClientMap(controller.Name, fake.NewFakeClient()),
+			newAPIClient: newAPIClient(apiinterceptor.Funcs{
+				SlurmdbV0044GetAssociationsWithResponse: func(ctx context.Context, params *api.SlurmdbV0044GetAssociationsParams, reqEditors ...api.RequestEditorFn) (*api.SlurmdbV0044GetAssociationsResponse, error) {
+					return nil, errors.New(http.StatusText(http.StatusBadGateway))
+				},
+			}),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &realSlurmControl{
+				clientMap:    tt.clientMap,
+				newAPIClient: tt.newAPIClient,
+			}
+			params := api.SlurmdbV0044GetAssociationsParams{User: ptr.To("alice")}
+			got, err := r.GetAssociations(ctx, controller, params)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("realSlurmControl.GetAssociations() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if len(got) != tt.wantCount {
+				t.Errorf("realSlurmControl.GetAssociations() = %v, want count %v", got, tt.wantCount)
+			}
+		})
+	}
+}
+
+func Test_realSlurmControl_DeleteAssociations(t *testing.T) {
+	ctx := context.Background()
+	controller := newController("slurm")
+	tests := []struct {
+		name         string
+		clientMap    *clientmap.ClientMap
+		newAPIClient func(client.Client) (api.ClientWithResponsesInterface, error)
+		wantErr      bool
+	}{
+		{
+			name:         "smoke",
+			clientMap:    newSlurmClientMap(controller.Name, fake.NewFakeClient()),
+			newAPIClient: newAPIClient(apiinterceptor.Funcs{}),
+		},
+		{
+			name:      "not found",
+			clientMap: newSlurmClientMap(controller.Name, fake.NewFakeClient()),
+			newAPIClient: newAPIClient(apiinterceptor.Funcs{
+				SlurmdbV0044DeleteAssociationsWithResponse: func(ctx context.Context, params *api.SlurmdbV0044DeleteAssociationsParams, reqEditors ...api.RequestEditorFn) (*api.SlurmdbV0044DeleteAssociationsResponse, error) {
+					res := &api.SlurmdbV0044DeleteAssociationsResponse{
+						HTTPResponse: &http.Response{StatusCode: http.StatusNotFound},
+					}
+					return res, nil
+				},
+			}),
+		},
+		{
+			name:      "error status",
+			clientMap: newSlurmClientMap(controller.Name, fake.NewFakeClient()),
+			newAPIClient: newAPIClient(apiinterceptor.Funcs{
+				SlurmdbV0044DeleteAssociationsWithResponse: func(ctx context.Context, params *api.SlurmdbV0044DeleteAssociationsParams, reqEditors ...api.RequestEditorFn) (*api.SlurmdbV0044DeleteAssociationsResponse, error) {
+					res := &api.SlurmdbV0044DeleteAssociationsResponse{
+						HTTPResponse: &http.Response{StatusCode: http.StatusInternalServerError},
+						JSONDefault: &api.V0044OpenapiAssocsRemovedResp{
+							Errors: &api.V0044OpenapiErrors{
+								{Error: ptr.To("association has running jobs")},
+							},
+						},
+					}
+					return res, nil
+				},
+			}),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &realSlurmControl{
+				clientMap:    tt.clientMap,
+				newAPIClient: tt.newAPIClient,
+			}
+			params := api.SlurmdbV0044DeleteAssociationsParams{
+				User:    ptr.To("alice"),
+				Cluster: ptr.To("slurm-a"),
+			}
+			if err := r.DeleteAssociations(ctx, controller, params); (err != nil) != tt.wantErr {
+				t.Errorf("realSlurmControl.DeleteAssociations() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
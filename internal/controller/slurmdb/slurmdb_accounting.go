@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmdb
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/utils/set"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	"github.com/SlinkyProject/slurm-operator/internal/controller/slurmdb/slurmcontrol"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/refresolver"
+)
+
+// accountingClusters are the Slurm clusters that store their accounting in
+// the slurmdbd of an Accounting.
+type accountingClusters struct {
+	// controller is the Controller whose slurmrestd reaches slurmdbd, or nil
+	// if none has a slurm client.
+	controller *slinkyv1beta1.Controller
+	// clusters are the cluster names of the Controllers using the Accounting.
+	clusters []string
+	// gone reports if the Accounting, or all of its Controllers, are deleted
+	// or being deleted, so that slurmdbd is no longer reachable.
+	gone bool
+}
+
+// resolveAccounting returns the Slurm clusters using the Accounting.
+func resolveAccounting(
+	ctx context.Context,
+	refResolver *refresolver.RefResolver,
+	slurmControl slurmcontrol.SlurmControlInterface,
+	ref slinkyv1beta1.ObjectReference,
+) (*accountingClusters, error) {
+	result := &accountingClusters{}
+
+	accounting, err := refResolver.GetAccounting(ctx, ref)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			result.gone = true
+			return result, nil
+		}
+		return nil, err
+	}
+	if !accounting.DeletionTimestamp.IsZero() {
+		result.gone = true
+		return result, nil
+	}
+
+	controllerList, err := refResolver.GetControllersForAccounting(ctx, accounting)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(controllerList.Items, func(a, b slinkyv1beta1.Controller) int {
+		return strings.Compare(a.Key().String(), b.Key().String())
+	})
+
+	clusters := set.New[string]()
+	result.gone = true
+	for i := range controllerList.Items {
+		controller := &controllerList.Items[i]
+		if !controller.DeletionTimestamp.IsZero() {
+			continue
+		}
+		result.gone = false
+		clusters.Insert(controller.ClusterName())
+		if result.controller == nil && slurmControl.HasClient(controller) {
+			result.controller = controller
+		}
+	}
+	result.clusters = clusters.SortedList()
+
+	return result, nil
+}
@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmdb
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+)
+
+// isSynced reports if slurmdbd matched the spec of the generation at the
+// last sync, in which case any difference found now is drift. The
+// SlurmAccountSynced, SlurmUserSynced, and SlurmQOSSynced conditions share a
+// type.
+func isSynced(conditions []metav1.Condition, generation int64) bool {
+	condition := meta.FindStatusCondition(conditions, slinkyv1beta1.SlurmAccountSynced)
+	return condition != nil &&
+		condition.Status == metav1.ConditionTrue &&
+		condition.ObservedGeneration == generation
+}
+
+func syncedCondition(generation int64) metav1.Condition {
+	return metav1.Condition{
+		Type:               slinkyv1beta1.SlurmAccountSynced,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             syncedReasonSynced,
+		Message:            "slurmdbd matches the spec.",
+	}
+}
+
+func noClientCondition(generation int64) metav1.Condition {
+	return metav1.Condition{
+		Type:               slinkyv1beta1.SlurmAccountSynced,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: generation,
+		Reason:             syncedReasonNoClient,
+		Message:            "No slurmrestd of the Accounting's Controllers can reach slurmdbd.",
+	}
+}
+
+func failedCondition(generation int64, err error) metav1.Condition {
+	return metav1.Condition{
+		Type:               slinkyv1beta1.SlurmAccountSynced,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: generation,
+		Reason:             syncedReasonFailed,
+		Message:            err.Error(),
+	}
+}
+
+func valueOrDefault(value, def string) string {
+	if value != "" {
+		return value
+	}
+	return def
+}
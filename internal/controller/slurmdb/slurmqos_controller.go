@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmdb
+
+import (
+	"context"
+	"flag"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	"github.com/SlinkyProject/slurm-operator/internal/clientmap"
+	"github.com/SlinkyProject/slurm-operator/internal/controller/slurmdb/slurmcontrol"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/durationstore"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/refresolver"
+)
+
+const (
+	SlurmQOSControllerName = "slurmqos-controller"
+)
+
+func init() {
+	flag.IntVar(&maxConcurrentQOSReconciles, "slurmqos-workers", maxConcurrentQOSReconciles, "Max concurrent workers for SlurmQOS controller.")
+}
+
+var (
+	maxConcurrentQOSReconciles = 1
+
+	// this is a short cut for any sub-functions to notify the reconcile how long to wait to requeue
+	qosDurationStore = durationstore.NewDurationStore(durationstore.Less)
+)
+
+// SlurmQOSReconciler reconciles a SlurmQOS object
+type SlurmQOSReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	ClientMap *clientmap.ClientMap
+
+	refResolver   *refresolver.RefResolver
+	slurmControl  slurmcontrol.SlurmControlInterface
+	eventRecorder record.EventRecorderLogger
+}
+
+// +kubebuilder:rbac:groups=slinky.slurm.net,resources=slurmqoses,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=slinky.slurm.net,resources=slurmqoses/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=slinky.slurm.net,resources=slurmqoses/finalizers,verbs=update
+// +kubebuilder:rbac:groups=slinky.slurm.net,resources=accountings,verbs=get;list;watch
+// +kubebuilder:rbac:groups=slinky.slurm.net,resources=controllers,verbs=get;list;watch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+func (r *SlurmQOSReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, retErr error) {
+	logger := log.FromContext(ctx)
+	logger.Info("Started syncing SlurmQOS", "request", req)
+
+	startTime := time.Now()
+	defer func() {
+		if retErr == nil {
+			if res.RequeueAfter > 0 {
+				logger.Info("Finished syncing SlurmQOS", "duration", time.Since(startTime), "result", res)
+			} else {
+				logger.Info("Finished syncing SlurmQOS", "duration", time.Since(startTime))
+			}
+		} else {
+			logger.Info("Finished syncing SlurmQOS", "duration", time.Since(startTime), "error", retErr)
+		}
+		// clean the duration store
+		_ = qosDurationStore.Pop(req.String())
+	}()
+
+	retErr = r.Sync(ctx, req)
+	res = reconcile.Result{
+		RequeueAfter: qosDurationStore.Pop(req.String()),
+	}
+	return res, retErr
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SlurmQOSReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(SlurmQOSControllerName).
+		// Status updates would retrigger a sync against slurmdbd, drift is
+		// detected by the periodic resync instead.
+		For(&slinkyv1beta1.SlurmQOS{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: maxConcurrentQOSReconciles,
+		}).
+		Complete(r)
+}
+
+func NewSlurmQOSReconciler(c client.Client, cm *clientmap.ClientMap) *SlurmQOSReconciler {
+	s := c.Scheme()
+	es := corev1.EventSource{Component: SlurmQOSControllerName}
+	return &SlurmQOSReconciler{
+		Client: c,
+		Scheme: s,
+
+		ClientMap: cm,
+
+		refResolver:   refresolver.New(c),
+		slurmControl:  slurmcontrol.NewSlurmControl(cm),
+		eventRecorder: record.NewBroadcaster().NewRecorder(s, es),
+	}
+}
@@ -0,0 +1,312 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"k8s.io/utils/ptr"
+
+	api "github.com/SlinkyProject/slurm-client/api/v0044"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+)
+
+// qosPreemptModes maps the SlurmQOS preempt modes to the slurmrestd ones. A
+// QOS without a preempt mode of its own uses the cluster-wide PreemptMode.
+var qosPreemptModes = map[slinkyv1beta1.SlurmQOSPreemptMode]api.V0044QosPreemptMode{
+	slinkyv1beta1.SlurmQOSPreemptModeCluster: api.V0044QosPreemptModeDISABLED,
+	slinkyv1beta1.SlurmQOSPreemptModeCancel:  api.V0044QosPreemptModeCANCEL,
+	slinkyv1beta1.SlurmQOSPreemptModeRequeue: api.V0044QosPreemptModeREQUEUE,
+	slinkyv1beta1.SlurmQOSPreemptModeSuspend: api.V0044QosPreemptModeSUSPEND,
+}
+
+// qosFlags maps the SlurmQOS flags to the slurmrestd ones.
+var qosFlags = map[slinkyv1beta1.SlurmQOSFlag]api.V0044QosFlags{
+	slinkyv1beta1.SlurmQOSFlagDenyOnLimit:           api.V0044QosFlagsDENYLIMIT,
+	slinkyv1beta1.SlurmQOSFlagEnforceUsageThreshold: api.V0044QosFlagsENFORCEUSAGETHRESHOLD,
+	slinkyv1beta1.SlurmQOSFlagNoDecay:               api.V0044QosFlagsNODECAY,
+	slinkyv1beta1.SlurmQOSFlagNoReserve:             api.V0044QosFlagsNORESERVE,
+	slinkyv1beta1.SlurmQOSFlagOverPartQOS:           api.V0044QosFlagsOVERRIDEPARTITIONQOS,
+	slinkyv1beta1.SlurmQOSFlagPartitionMaxNodes:     api.V0044QosFlagsPARTITIONMAXIMUMNODE,
+	slinkyv1beta1.SlurmQOSFlagPartitionMinNodes:     api.V0044QosFlagsPARTITIONMINIMUMNODE,
+	slinkyv1beta1.SlurmQOSFlagPartitionTimeLimit:    api.V0044QosFlagsPARTITIONTIMELIMIT,
+	slinkyv1beta1.SlurmQOSFlagRequiresReservation:   api.V0044QosFlagsREQUIREDRESERVATION,
+	slinkyv1beta1.SlurmQOSFlagUsageFactorSafe:       api.V0044QosFlagsUSAGEFACTORSAFE,
+}
+
+// qosSettings are the settings of a QOS that a SlurmQOS manages.
+type qosSettings struct {
+	Description    string
+	Priority       int32
+	Preempt        []string
+	PreemptMode    api.V0044QosPreemptMode
+	GrpTRES        map[string]int64
+	MaxTRESPerUser map[string]int64
+	// MaxWall is in minutes, nil when there is no limit.
+	MaxWall     *int32
+	UsageFactor float64
+	Flags       []api.V0044QosFlags
+}
+
+// qosRecord mirrors the managed fields of api.V0044Qos, whose nested fields
+// have anonymous types.
+type qosRecord struct {
+	Name        string                       `json:"name"`
+	Description string                       `json:"description,omitempty"`
+	Flags       []api.V0044QosFlags          `json:"flags,omitempty"`
+	Priority    *api.V0044Uint32NoValStruct  `json:"priority,omitempty"`
+	UsageFactor *api.V0044Float64NoValStruct `json:"usage_factor,omitempty"`
+	Preempt     struct {
+		List []string                  `json:"list"`
+		Mode []api.V0044QosPreemptMode `json:"mode,omitempty"`
+	} `json:"preempt"`
+	Limits struct {
+		Max struct {
+			Tres struct {
+				Total api.V0044TresList `json:"total,omitempty"`
+				Per   struct {
+					User api.V0044TresList `json:"user,omitempty"`
+				} `json:"per"`
+			} `json:"tres"`
+			WallClock struct {
+				Per struct {
+					Job *api.V0044Uint32NoValStruct `json:"job,omitempty"`
+				} `json:"per"`
+			} `json:"wall_clock"`
+		} `json:"max"`
+	} `json:"limits"`
+}
+
+// desiredQOSSettings returns the settings of the SlurmQOS.
+func desiredQOSSettings(qos *slinkyv1beta1.SlurmQOS) (qosSettings, error) {
+	settings := qosSettings{
+		Description:    valueOrDefault(qos.Spec.Description, qos.QOSName()),
+		Priority:       qos.Spec.Priority,
+		Preempt:        slices.Sorted(slices.Values(qos.Spec.Preempt)),
+		PreemptMode:    qosPreemptModes[slinkyv1beta1.SlurmQOSPreemptModeCluster],
+		GrpTRES:        qos.Spec.GrpTRES,
+		MaxTRESPerUser: qos.Spec.MaxTRESPerUser,
+		UsageFactor:    1,
+	}
+	if mode, ok := qosPreemptModes[qos.Spec.PreemptMode]; ok {
+		settings.PreemptMode = mode
+	}
+	if qos.Spec.MaxWall != nil {
+		settings.MaxWall = ptr.To(int32(qos.Spec.MaxWall.Minutes()))
+	}
+	if qos.Spec.UsageFactor != "" {
+		factor, err := strconv.ParseFloat(qos.Spec.UsageFactor, 64)
+		if err != nil {
+			return qosSettings{}, fmt.Errorf("invalid usageFactor: %w", err)
+		}
+		settings.UsageFactor = factor
+	}
+	for _, flag := range qos.Spec.Flags {
+		settings.Flags = append(settings.Flags, qosFlags[flag])
+	}
+	slices.Sort(settings.Flags)
+	return settings, nil
+}
+
+// currentQOSSettings returns the settings of the QOS in slurmdbd.
+func currentQOSSettings(qos api.V0044Qos) (qosSettings, error) {
+	record := qosRecord{}
+	data, err := json.Marshal(qos)
+	if err != nil {
+		return qosSettings{}, err
+	}
+	if err := json.Unmarshal(data, &record); err != nil {
+		return qosSettings{}, err
+	}
+
+	settings := qosSettings{
+		Description:    record.Description,
+		Priority:       noValNumber(record.Priority, 0),
+		Preempt:        slices.Sorted(slices.Values(record.Preempt.List)),
+		PreemptMode:    api.V0044QosPreemptModeDISABLED,
+		GrpTRES:        tresMap(record.Limits.Max.Tres.Total),
+		MaxTRESPerUser: tresMap(record.Limits.Max.Tres.Per.User),
+		UsageFactor:    1,
+	}
+	for _, mode := range record.Preempt.Mode {
+		if mode != api.V0044QosPreemptModeGANG {
+			settings.PreemptMode = mode
+			break
+		}
+	}
+	if wall := record.Limits.Max.WallClock.Per.Job; wall != nil && ptr.Deref(wall.Set, false) && !ptr.Deref(wall.Infinite, false) {
+		settings.MaxWall = wall.Number
+	}
+	if factor := record.UsageFactor; factor != nil && ptr.Deref(factor.Set, false) {
+		settings.UsageFactor = ptr.Deref(factor.Number, 1)
+	}
+	for _, flag := range record.Flags {
+		switch flag {
+		case api.V0044QosFlagsNOTSET, api.V0044QosFlagsADD, api.V0044QosFlagsREMOVE, api.V0044QosFlagsDELETED:
+			continue
+		}
+		settings.Flags = append(settings.Flags, flag)
+	}
+	slices.Sort(settings.Flags)
+	return settings, nil
+}
+
+// diffQOSSettings returns the differences between the current and wanted
+// settings.
+func diffQOSSettings(current, want qosSettings) []string {
+	changes := []string{}
+	if current.Description != want.Description {
+		changes = append(changes, fmt.Sprintf("description is %q, want %q", current.Description, want.Description))
+	}
+	if current.Priority != want.Priority {
+		changes = append(changes, fmt.Sprintf("priority is %d, want %d", current.Priority, want.Priority))
+	}
+	if !slices.Equal(current.Preempt, want.Preempt) {
+		changes = append(changes, fmt.Sprintf("preempt is %q, want %q",
+			strings.Join(current.Preempt, ","), strings.Join(want.Preempt, ",")))
+	}
+	if current.PreemptMode != want.PreemptMode {
+		changes = append(changes, fmt.Sprintf("preempt mode is %s, want %s", current.PreemptMode, want.PreemptMode))
+	}
+	if !maps.Equal(current.GrpTRES, want.GrpTRES) {
+		changes = append(changes, fmt.Sprintf("GrpTRES is %q, want %q",
+			formatTRES(current.GrpTRES), formatTRES(want.GrpTRES)))
+	}
+	if !maps.Equal(current.MaxTRESPerUser, want.MaxTRESPerUser) {
+		changes = append(changes, fmt.Sprintf("MaxTRESPerUser is %q, want %q",
+			formatTRES(current.MaxTRESPerUser), formatTRES(want.MaxTRESPerUser)))
+	}
+	if !ptr.Equal(current.MaxWall, want.MaxWall) {
+		changes = append(changes, fmt.Sprintf("MaxWall is %s, want %s",
+			formatMinutes(current.MaxWall), formatMinutes(want.MaxWall)))
+	}
+	if current.UsageFactor != want.UsageFactor {
+		changes = append(changes, fmt.Sprintf("usage factor is %g, want %g", current.UsageFactor, want.UsageFactor))
+	}
+	if !slices.Equal(current.Flags, want.Flags) {
+		changes = append(changes, fmt.Sprintf("flags are %v, want %v", current.Flags, want.Flags))
+	}
+	return changes
+}
+
+// toQOS returns the QOS with the wanted settings. Limits that are only in the
+// current settings are cleared.
+func toQOS(name string, want qosSettings, current *qosSettings) (api.V0044Qos, error) {
+	record := qosRecord{
+		Name:        name,
+		Description: want.Description,
+		Flags:       want.Flags,
+		Priority: &api.V0044Uint32NoValStruct{
+			Set:    ptr.To(true),
+			Number: ptr.To(want.Priority),
+		},
+		UsageFactor: &api.V0044Float64NoValStruct{
+			Set:    ptr.To(true),
+			Number: ptr.To(want.UsageFactor),
+		},
+	}
+	if len(record.Flags) == 0 {
+		record.Flags = []api.V0044QosFlags{api.V0044QosFlagsNOTSET}
+	}
+	record.Preempt.List = append([]string{}, want.Preempt...)
+	record.Preempt.Mode = []api.V0044QosPreemptMode{want.PreemptMode}
+
+	var currentGrpTRES, currentMaxTRESPerUser map[string]int64
+	if current != nil {
+		currentGrpTRES = current.GrpTRES
+		currentMaxTRESPerUser = current.MaxTRESPerUser
+	}
+	record.Limits.Max.Tres.Total = tresList(want.GrpTRES, currentGrpTRES)
+	record.Limits.Max.Tres.Per.User = tresList(want.MaxTRESPerUser, currentMaxTRESPerUser)
+	if want.MaxWall != nil {
+		record.Limits.Max.WallClock.Per.Job = &api.V0044Uint32NoValStruct{
+			Set:    ptr.To(true),
+			Number: want.MaxWall,
+		}
+	} else {
+		record.Limits.Max.WallClock.Per.Job = &api.V0044Uint32NoValStruct{
+			Set:      ptr.To(true),
+			Infinite: ptr.To(true),
+		}
+	}
+
+	qos := api.V0044Qos{}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return qos, err
+	}
+	err = json.Unmarshal(data, &qos)
+	return qos, err
+}
+
+func noValNumber(value *api.V0044Uint32NoValStruct, def int32) int32 {
+	if value == nil || !ptr.Deref(value.Set, false) || ptr.Deref(value.Infinite, false) {
+		return def
+	}
+	return ptr.Deref(value.Number, def)
+}
+
+// tresMap returns the TRES counts by name (e.g. `cpu`, `gres/gpu`).
+func tresMap(list api.V0044TresList) map[string]int64 {
+	if len(list) == 0 {
+		return nil
+	}
+	tres := make(map[string]int64, len(list))
+	for _, item := range list {
+		name := item.Type
+		if n := ptr.Deref(item.Name, ""); n != "" {
+			name += "/" + n
+		}
+		tres[name] = ptr.Deref(item.Count, 0)
+	}
+	return tres
+}
+
+// tresList returns the TRES list of the wanted counts. TRES that are only
+// in the current counts are cleared.
+func tresList(want, current map[string]int64) api.V0044TresList {
+	var list api.V0044TresList
+	for _, name := range slices.Sorted(maps.Keys(want)) {
+		list = append(list, tresItem(name, want[name]))
+	}
+	for _, name := range slices.Sorted(maps.Keys(current)) {
+		if _, ok := want[name]; !ok {
+			list = append(list, tresItem(name, -1))
+		}
+	}
+	return list
+}
+
+func tresItem(name string, count int64) api.V0044Tres {
+	tresType, tresName, _ := strings.Cut(name, "/")
+	item := api.V0044Tres{
+		Type:  tresType,
+		Count: ptr.To(count),
+	}
+	if tresName != "" {
+		item.Name = ptr.To(tresName)
+	}
+	return item
+}
+
+func formatTRES(tres map[string]int64) string {
+	items := make([]string, 0, len(tres))
+	for _, name := range slices.Sorted(maps.Keys(tres)) {
+		items = append(items, fmt.Sprintf("%s=%d", name, tres[name]))
+	}
+	return strings.Join(items, ",")
+}
+
+func formatMinutes(minutes *int32) string {
+	if minutes == nil {
+		return "unlimited"
+	}
+	return fmt.Sprintf("%d minutes", *minutes)
+}
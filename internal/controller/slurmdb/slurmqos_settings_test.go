@@ -0,0 +1,176 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmdb
+
+import (
+	"testing"
+	"time"
+
+	api "github.com/SlinkyProject/slurm-client/api/v0044"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+)
+
+func newSlurmQOS(spec slinkyv1beta1.SlurmQOSSpec) *slinkyv1beta1.SlurmQOS {
+	spec.AccountingRef = slinkyv1beta1.ObjectReference{Name: "slurm"}
+	return &slinkyv1beta1.SlurmQOS{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      "high",
+		},
+		Spec: spec,
+	}
+}
+
+func Test_desiredQOSSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		qos     *slinkyv1beta1.SlurmQOS
+		want    qosSettings
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			qos:  newSlurmQOS(slinkyv1beta1.SlurmQOSSpec{}),
+			want: qosSettings{
+				Description: "high",
+				PreemptMode: api.V0044QosPreemptModeDISABLED,
+				UsageFactor: 1,
+			},
+		},
+		{
+			name: "settings",
+			qos: newSlurmQOS(slinkyv1beta1.SlurmQOSSpec{
+				Description:    "High priority",
+				Priority:       100,
+				Preempt:        []string{"normal", "low"},
+				PreemptMode:    slinkyv1beta1.SlurmQOSPreemptModeRequeue,
+				GrpTRES:        map[string]int64{"cpu": 1000, "gres/gpu": 16},
+				MaxTRESPerUser: map[string]int64{"cpu": 100},
+				MaxWall:        &metav1.Duration{Duration: 48*time.Hour + 30*time.Second},
+				UsageFactor:    "2.5",
+				Flags:          []slinkyv1beta1.SlurmQOSFlag{slinkyv1beta1.SlurmQOSFlagNoReserve, slinkyv1beta1.SlurmQOSFlagDenyOnLimit},
+			}),
+			want: qosSettings{
+				Description:    "High priority",
+				Priority:       100,
+				Preempt:        []string{"low", "normal"},
+				PreemptMode:    api.V0044QosPreemptModeREQUEUE,
+				GrpTRES:        map[string]int64{"cpu": 1000, "gres/gpu": 16},
+				MaxTRESPerUser: map[string]int64{"cpu": 100},
+				MaxWall:        ptr.To[int32](48 * 60),
+				UsageFactor:    2.5,
+				Flags:          []api.V0044QosFlags{api.V0044QosFlagsDENYLIMIT, api.V0044QosFlagsNORESERVE},
+			},
+		},
+		{
+			name:    "invalid usage factor",
+			qos:     newSlurmQOS(slinkyv1beta1.SlurmQOSSpec{UsageFactor: "fast"}),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := desiredQOSSettings(tt.qos)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("desiredQOSSettings() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !equality.Semantic.DeepEqual(got, tt.want) {
+				t.Errorf("desiredQOSSettings() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_toQOS(t *testing.T) {
+	want := qosSettings{
+		Description:    "High priority",
+		Priority:       100,
+		Preempt:        []string{"low"},
+		PreemptMode:    api.V0044QosPreemptModeCANCEL,
+		GrpTRES:        map[string]int64{"cpu": 1000, "gres/gpu": 16},
+		MaxTRESPerUser: map[string]int64{"cpu": 100},
+		MaxWall:        ptr.To[int32](60),
+		UsageFactor:    2,
+		Flags:          []api.V0044QosFlags{api.V0044QosFlagsNODECAY},
+	}
+	qos, err := toQOS("high", want, nil)
+	if err != nil {
+		t.Fatalf("toQOS() error = %v", err)
+	}
+	if ptr.Deref(qos.Name, "") != "high" {
+		t.Errorf("toQOS() name = %v, want %v", ptr.Deref(qos.Name, ""), "high")
+	}
+	got, err := currentQOSSettings(qos)
+	if err != nil {
+		t.Fatalf("currentQOSSettings() error = %v", err)
+	}
+	if changes := diffQOSSettings(got, want); len(changes) > 0 {
+		t.Errorf("toQOS() does not round trip: %v", changes)
+	}
+}
+
+func Test_toQOS_clear(t *testing.T) {
+	current := qosSettings{
+		GrpTRES: map[string]int64{"cpu": 1000, "gres/gpu": 16},
+		MaxWall: ptr.To[int32](60),
+		Flags:   []api.V0044QosFlags{api.V0044QosFlagsNODECAY},
+	}
+	want := qosSettings{
+		GrpTRES:     map[string]int64{"cpu": 500},
+		PreemptMode: api.V0044QosPreemptModeDISABLED,
+		UsageFactor: 1,
+	}
+	qos, err := toQOS("high", want, &current)
+	if err != nil {
+		t.Fatalf("toQOS() error = %v", err)
+	}
+	wantTRES := api.V0044TresList{
+		{Type: "cpu", Count: ptr.To[int64](500)},
+		{Type: "gres", Name: ptr.To("gpu"), Count: ptr.To[int64](-1)},
+	}
+	if got := *qos.Limits.Max.Tres.Total; !equality.Semantic.DeepEqual(got, wantTRES) {
+		t.Errorf("toQOS() GrpTRES = %v, want %v", got, wantTRES)
+	}
+	if got := qos.Limits.Max.WallClock.Per.Job; !ptr.Deref(got.Infinite, false) {
+		t.Errorf("toQOS() MaxWall = %v, want infinite", got)
+	}
+	if got := *qos.Flags; !equality.Semantic.DeepEqual(got, []api.V0044QosFlags{api.V0044QosFlagsNOTSET}) {
+		t.Errorf("toQOS() Flags = %v, want %v", got, api.V0044QosFlagsNOTSET)
+	}
+	if got := qos.Preempt.List; got == nil || len(*got) != 0 {
+		t.Errorf("toQOS() Preempt = %v, want empty", got)
+	}
+}
+
+func Test_diffQOSSettings(t *testing.T) {
+	base := qosSettings{
+		Description: "high",
+		Priority:    100,
+		PreemptMode: api.V0044QosPreemptModeDISABLED,
+		GrpTRES:     map[string]int64{"cpu": 1000},
+		UsageFactor: 1,
+	}
+	changed := base
+	changed.Priority = 10
+	changed.GrpTRES = map[string]int64{"cpu": 2000}
+	changed.MaxWall = ptr.To[int32](60)
+
+	if got := diffQOSSettings(base, base); len(got) != 0 {
+		t.Errorf("diffQOSSettings() = %v, want none", got)
+	}
+	want := []string{
+		"priority is 10, want 100",
+		`GrpTRES is "cpu=2000", want "cpu=1000"`,
+		"MaxWall is 60 minutes, want unlimited",
+	}
+	if got := diffQOSSettings(changed, base); !equality.Semantic.DeepEqual(got, want) {
+		t.Errorf("diffQOSSettings() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,276 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmdb
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"k8s.io/utils/set"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	api "github.com/SlinkyProject/slurm-client/api/v0044"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/objectutils"
+)
+
+// Reasons for SlurmQOS events
+const (
+	// UnmanagedQOSReason is added to an event when slurmdbd has QOS that no
+	// SlurmQOS manages.
+	UnmanagedQOSReason = "UnmanagedQOS"
+)
+
+// defaultQOSName is the QOS that slurmdbd creates for the root association.
+const defaultQOSName = "normal"
+
+// Sync implements control logic for synchronizing a SlurmQOS.
+func (r *SlurmQOSReconciler) Sync(ctx context.Context, req reconcile.Request) error {
+	logger := log.FromContext(ctx)
+
+	qos := &slinkyv1beta1.SlurmQOS{}
+	if err := r.Get(ctx, req.NamespacedName, qos); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("SlurmQOS has been deleted", "request", req)
+			return nil
+		}
+		return err
+	}
+
+	if !qos.DeletionTimestamp.IsZero() {
+		return r.syncDelete(ctx, qos)
+	}
+
+	if !controllerutil.ContainsFinalizer(qos, slinkyv1beta1.FinalizerSlurmdb) {
+		patch := client.MergeFrom(qos.DeepCopy())
+		controllerutil.AddFinalizer(qos, slinkyv1beta1.FinalizerSlurmdb)
+		if err := r.Patch(ctx, qos, patch); err != nil {
+			return err
+		}
+	}
+
+	status := qos.Status.DeepCopy()
+	var errs []error
+	if err := r.syncQOS(ctx, qos, status); err != nil {
+		errs = append(errs, err)
+	}
+	if err := r.syncStatus(ctx, qos, status); err != nil {
+		errs = append(errs, err)
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// syncQOS makes the QOS in slurmdbd match the spec.
+func (r *SlurmQOSReconciler) syncQOS(
+	ctx context.Context,
+	qos *slinkyv1beta1.SlurmQOS,
+	status *slinkyv1beta1.SlurmQOSStatus,
+) error {
+	key := qos.Key().String()
+
+	accounting, err := resolveAccounting(ctx, r.refResolver, r.slurmControl, qos.AccountingRef())
+	if err != nil {
+		return err
+	}
+	if accounting.controller == nil {
+		qosDurationStore.Push(key, NoClientInterval)
+		meta.SetStatusCondition(&status.Conditions, noClientCondition(qos.Generation))
+		return nil
+	}
+	qosDurationStore.Push(key, SyncInterval)
+
+	var changes []string
+	qosList, err := r.slurmControl.GetQOS(ctx, accounting.controller)
+	if err == nil {
+		changes, err = r.applyQOS(ctx, accounting.controller, qos, qosList)
+	}
+	if err != nil {
+		r.eventRecorder.Eventf(qos, corev1.EventTypeWarning, SyncFailedReason,
+			"Failed to sync QOS %s: %v", qos.QOSName(), err)
+		meta.SetStatusCondition(&status.Conditions, failedCondition(qos.Generation, err))
+		return err
+	}
+
+	status.Drift = nil
+	if isSynced(status.Conditions, qos.Generation) && len(changes) > 0 {
+		status.Drift = changes
+		r.eventRecorder.Eventf(qos, corev1.EventTypeWarning, DriftCorrectedReason,
+			"Reverted changes to QOS %s: %s", qos.QOSName(), strings.Join(changes, "; "))
+	}
+
+	unmanaged, err := r.unmanagedQOS(ctx, qos, qosList)
+	if err != nil {
+		return err
+	}
+	if len(unmanaged) > 0 && !slices.Equal(status.UnmanagedQOS, unmanaged) {
+		r.eventRecorder.Eventf(qos, corev1.EventTypeWarning, UnmanagedQOSReason,
+			"QOS in slurmdbd are not managed by a SlurmQOS: %s", strings.Join(unmanaged, ", "))
+	}
+	status.UnmanagedQOS = unmanaged
+	status.LastSyncTime = ptr.To(metav1.Now())
+	meta.SetStatusCondition(&status.Conditions, syncedCondition(qos.Generation))
+
+	return nil
+}
+
+// applyQOS makes the QOS in slurmdbd match the spec, returning the
+// differences that were found.
+func (r *SlurmQOSReconciler) applyQOS(
+	ctx context.Context,
+	controller *slinkyv1beta1.Controller,
+	qos *slinkyv1beta1.SlurmQOS,
+	qosList []api.V0044Qos,
+) ([]string, error) {
+	name := qos.QOSName()
+	want, err := desiredQOSSettings(qos)
+	if err != nil {
+		return nil, err
+	}
+
+	var current *qosSettings
+	changes := []string{}
+	idx := slices.IndexFunc(qosList, func(q api.V0044Qos) bool {
+		return ptr.Deref(q.Name, "") == name
+	})
+	if idx < 0 {
+		changes = append(changes, "QOS is missing")
+	} else {
+		settings, err := currentQOSSettings(qosList[idx])
+		if err != nil {
+			return nil, err
+		}
+		current = &settings
+		changes = append(changes, diffQOSSettings(settings, want)...)
+	}
+	if len(changes) == 0 {
+		return changes, nil
+	}
+
+	toUpdate, err := toQOS(name, want, current)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.slurmControl.UpdateQOS(ctx, controller, toUpdate); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// unmanagedQOS returns the QOS in slurmdbd that no SlurmQOS of the same
+// Accounting manages. The default QOS of slurmdbd is not reported.
+func (r *SlurmQOSReconciler) unmanagedQOS(
+	ctx context.Context,
+	qos *slinkyv1beta1.SlurmQOS,
+	qosList []api.V0044Qos,
+) ([]string, error) {
+	list := &slinkyv1beta1.SlurmQOSList{}
+	if err := r.List(ctx, list); err != nil {
+		return nil, err
+	}
+	managed := set.New[string]()
+	for i := range list.Items {
+		item := &list.Items[i]
+		if item.AccountingRef() == qos.AccountingRef() {
+			managed.Insert(item.QOSName())
+		}
+	}
+
+	unmanaged := set.New[string]()
+	for _, q := range qosList {
+		name := ptr.Deref(q.Name, "")
+		if name == "" || name == defaultQOSName {
+			continue
+		}
+		if !managed.Has(name) {
+			unmanaged.Insert(name)
+		}
+	}
+	return unmanaged.SortedList(), nil
+}
+
+// syncDelete removes the QOS from slurmdbd before removing the finalizer.
+func (r *SlurmQOSReconciler) syncDelete(ctx context.Context, qos *slinkyv1beta1.SlurmQOS) error {
+	logger := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(qos, slinkyv1beta1.FinalizerSlurmdb) {
+		return nil
+	}
+
+	accounting, err := resolveAccounting(ctx, r.refResolver, r.slurmControl, qos.AccountingRef())
+	if err != nil {
+		return err
+	}
+	switch {
+	case accounting.gone:
+		logger.Info("Accounting is gone, skipping removal of the QOS from slurmdbd",
+			"qos", qos.QOSName())
+	case accounting.controller == nil:
+		logger.Info("Waiting for a slurm client to remove the QOS from slurmdbd",
+			"qos", qos.QOSName())
+		qosDurationStore.Push(qos.Key().String(), NoClientInterval)
+		return nil
+	default:
+		if err := r.slurmControl.DeleteQOS(ctx, accounting.controller, qos.QOSName()); err != nil {
+			r.eventRecorder.Eventf(qos, corev1.EventTypeWarning, SyncFailedReason,
+				"Failed to remove QOS %s: %v", qos.QOSName(), err)
+			return err
+		}
+		r.eventRecorder.Eventf(qos, corev1.EventTypeNormal, RemovedReason,
+			"Removed QOS %s from slurmdbd", qos.QOSName())
+	}
+
+	patch := client.MergeFrom(qos.DeepCopy())
+	controllerutil.RemoveFinalizer(qos, slinkyv1beta1.FinalizerSlurmdb)
+	return r.Patch(ctx, qos, patch)
+}
+
+// syncStatus handles determining and updating the status.
+func (r *SlurmQOSReconciler) syncStatus(
+	ctx context.Context,
+	qos *slinkyv1beta1.SlurmQOS,
+	newStatus *slinkyv1beta1.SlurmQOSStatus,
+) error {
+	logger := log.FromContext(ctx)
+
+	if apiequality.Semantic.DeepEqual(qos.Status, *newStatus) {
+		logger.V(2).Info("SlurmQOS Status has not changed, skipping status update",
+			"qos", klog.KObj(qos), "status", qos.Status)
+		return nil
+	}
+
+	qosKey := objectutils.NamespacedName(qos)
+	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		toUpdate := &slinkyv1beta1.SlurmQOS{}
+		if err := r.Get(ctx, qosKey, toUpdate); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		toUpdate.Status = *newStatus
+		return r.Status().Update(ctx, toUpdate)
+	}); err != nil {
+		return fmt.Errorf("error updating SlurmQOS(%s) status: %w",
+			klog.KObj(qos), err)
+	}
+
+	return nil
+}
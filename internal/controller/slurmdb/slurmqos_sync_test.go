@@ -0,0 +1,45 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmdb
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	api "github.com/SlinkyProject/slurm-client/api/v0044"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+)
+
+func init() {
+	utilruntime.Must(slinkyv1beta1.AddToScheme(clientgoscheme.Scheme))
+}
+
+func TestSlurmQOSReconciler_unmanagedQOS(t *testing.T) {
+	high := newSlurmQOS(slinkyv1beta1.SlurmQOSSpec{})
+	low := newSlurmQOS(slinkyv1beta1.SlurmQOSSpec{})
+	low.Name = "low"
+	low.Spec.AccountingRef.Name = "other"
+	c := fake.NewFakeClient(high, low)
+	r := NewSlurmQOSReconciler(c, nil)
+	qosList := []api.V0044Qos{
+		{Name: ptr.To(defaultQOSName)},
+		{Name: ptr.To("high")},
+		{Name: ptr.To("low")},
+		{Name: ptr.To("scavenger")},
+	}
+	got, err := r.unmanagedQOS(context.TODO(), high, qosList)
+	if err != nil {
+		t.Fatalf("unmanagedQOS() error = %v", err)
+	}
+	if want := []string{"low", "scavenger"}; !equality.Semantic.DeepEqual(got, want) {
+		t.Errorf("unmanagedQOS() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,433 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmdb
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"k8s.io/utils/set"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	api "github.com/SlinkyProject/slurm-client/api/v0044"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/objectutils"
+)
+
+// userDefault is the anonymous type of the user defaults in the API.
+type userDefault = struct {
+	Account *string `json:"account,omitempty"`
+	Qos     *int32  `json:"qos,omitempty"`
+	Wckey   *string `json:"wckey,omitempty"`
+}
+
+// Sync implements control logic for synchronizing a SlurmUser.
+func (r *SlurmUserReconciler) Sync(ctx context.Context, req reconcile.Request) error {
+	logger := log.FromContext(ctx)
+
+	user := &slinkyv1beta1.SlurmUser{}
+	if err := r.Get(ctx, req.NamespacedName, user); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("SlurmUser has been deleted", "request", req)
+			return nil
+		}
+		return err
+	}
+
+	if !user.DeletionTimestamp.IsZero() {
+		return r.syncDelete(ctx, user)
+	}
+
+	if !controllerutil.ContainsFinalizer(user, slinkyv1beta1.FinalizerSlurmdb) {
+		patch := client.MergeFrom(user.DeepCopy())
+		controllerutil.AddFinalizer(user, slinkyv1beta1.FinalizerSlurmdb)
+		if err := r.Patch(ctx, user, patch); err != nil {
+			return err
+		}
+	}
+
+	status := user.Status.DeepCopy()
+	var errs []error
+	if err := r.syncUser(ctx, user, status); err != nil {
+		errs = append(errs, err)
+	}
+	if err := r.syncStatus(ctx, user, status); err != nil {
+		errs = append(errs, err)
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// syncUser makes the user in slurmdbd match the spec.
+func (r *SlurmUserReconciler) syncUser(
+	ctx context.Context,
+	user *slinkyv1beta1.SlurmUser,
+	status *slinkyv1beta1.SlurmUserStatus,
+) error {
+	key := user.Key().String()
+
+	accounting, err := resolveAccounting(ctx, r.refResolver, r.slurmControl, user.AccountingRef())
+	if err != nil {
+		return err
+	}
+	if accounting.controller == nil {
+		userDurationStore.Push(key, NoClientInterval)
+		meta.SetStatusCondition(&status.Conditions, noClientCondition(user.Generation))
+		return nil
+	}
+	userDurationStore.Push(key, SyncInterval)
+
+	assocs := userAssociations(user, accounting.clusters)
+	changes, err := r.applyUser(ctx, accounting.controller, user, assocs, accounting.clusters)
+	if err != nil {
+		r.eventRecorder.Eventf(user, corev1.EventTypeWarning, SyncFailedReason,
+			"Failed to sync user %s: %v", user.UserName(), err)
+		meta.SetStatusCondition(&status.Conditions, failedCondition(user.Generation, err))
+		return err
+	}
+
+	status.Drift = nil
+	if isSynced(status.Conditions, user.Generation) && len(changes) > 0 {
+		status.Drift = changes
+		r.eventRecorder.Eventf(user, corev1.EventTypeWarning, DriftCorrectedReason,
+			"Reverted changes to user %s: %s", user.UserName(), strings.Join(changes, "; "))
+	}
+	status.Associations = assocs
+	status.LastSyncTime = ptr.To(metav1.Now())
+	meta.SetStatusCondition(&status.Conditions, syncedCondition(user.Generation))
+
+	return nil
+}
+
+// applyUser makes the user and its associations in slurmdbd match the spec,
+// returning the differences that were found.
+func (r *SlurmUserReconciler) applyUser(
+	ctx context.Context,
+	controller *slinkyv1beta1.Controller,
+	user *slinkyv1beta1.SlurmUser,
+	assocs []slinkyv1beta1.SlurmUserAssociation,
+	knownClusters []string,
+) ([]string, error) {
+	name := user.UserName()
+	adminLevel := valueOrDefault(string(user.Spec.AdminLevel), string(slinkyv1beta1.SlurmAdminLevelNone))
+	var fairshare *int32
+	if user.Spec.Fairshare > 0 {
+		fairshare = ptr.To(user.Spec.Fairshare)
+	}
+
+	changes := []string{}
+	current, err := r.slurmControl.GetUser(ctx, controller, name)
+	if err != nil {
+		return nil, err
+	}
+	currentAssocs := make(map[slinkyv1beta1.SlurmUserAssociation]api.V0044Assoc)
+	if current == nil {
+		changes = append(changes, "user is missing")
+	} else {
+		list, err := r.slurmControl.GetAssociations(ctx, controller, api.SlurmdbV0044GetAssociationsParams{
+			User: ptr.To(name),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, assoc := range list {
+			if assoc.User == name {
+				currentAssocs[associationKey(assoc)] = assoc
+			}
+		}
+	}
+
+	// Adding an association adds the user if it does not exist.
+	var toApply []api.V0044Assoc
+	for _, want := range assocs {
+		assoc, ok := currentAssocs[want]
+		if !ok {
+			if current != nil {
+				changes = append(changes, fmt.Sprintf("association %s is missing", formatAssociation(want)))
+			}
+			req := api.V0044OpenapiUsersAddCondResp{
+				AssociationCondition: api.V0044UsersAddCond{
+					Users:    api.V0044StringList{name},
+					Accounts: ptr.To(api.V0044StringList{want.Account}),
+					Clusters: ptr.To(api.V0044StringList{want.Cluster}),
+					Association: &api.V0044AssocRecSet{
+						Fairshare: fairshare,
+					},
+				},
+				User: api.V0044UserShort{
+					Defaultaccount: ptr.To(user.Spec.DefaultAccount),
+					Adminlevel:     ptr.To([]api.V0044UserShortAdminlevel{api.V0044UserShortAdminlevel(adminLevel)}),
+				},
+			}
+			if want.Partition != "" {
+				req.AssociationCondition.Partitions = ptr.To(api.V0044StringList{want.Partition})
+			}
+			if err := r.slurmControl.CreateUser(ctx, controller, req); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if fairshare != nil && ptr.Deref(assoc.SharesRaw, 0) != *fairshare {
+			changes = append(changes, fmt.Sprintf("fairshare of association %s is %d, want %d",
+				formatAssociation(want), ptr.Deref(assoc.SharesRaw, 0), *fairshare))
+			toApply = append(toApply, api.V0044Assoc{
+				User:      name,
+				Account:   ptr.To(want.Account),
+				Cluster:   ptr.To(want.Cluster),
+				Partition: assoc.Partition,
+				SharesRaw: fairshare,
+			})
+		}
+	}
+	if err := r.slurmControl.UpdateAssociations(ctx, controller, toApply); err != nil {
+		return nil, err
+	}
+
+	// The default account must be associated before it is set.
+	if current != nil {
+		currentAdminLevel := userAdminLevel(current)
+		currentDefaultAccount := ""
+		if current.Default != nil {
+			currentDefaultAccount = ptr.Deref(current.Default.Account, "")
+		}
+		if currentAdminLevel != adminLevel || currentDefaultAccount != user.Spec.DefaultAccount {
+			if currentAdminLevel != adminLevel {
+				changes = append(changes, fmt.Sprintf("admin level is %q, want %q", currentAdminLevel, adminLevel))
+			}
+			if currentDefaultAccount != user.Spec.DefaultAccount {
+				changes = append(changes, fmt.Sprintf("default account is %q, want %q",
+					currentDefaultAccount, user.Spec.DefaultAccount))
+			}
+			toUpdate := api.V0044User{
+				Name:               name,
+				AdministratorLevel: ptr.To([]api.V0044UserAdministratorLevel{api.V0044UserAdministratorLevel(adminLevel)}),
+				Default: &userDefault{
+					Account: ptr.To(user.Spec.DefaultAccount),
+				},
+			}
+			if err := r.slurmControl.UpdateUser(ctx, controller, toUpdate); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Only associations on the clusters of the Accounting are managed, other
+	// clusters may share slurmdbd.
+	wanted := sets.New(assocs...)
+	known := set.New(knownClusters...)
+	unwanted := []slinkyv1beta1.SlurmUserAssociation{}
+	for key := range currentAssocs {
+		if !wanted.Has(key) && known.Has(key.Cluster) {
+			unwanted = append(unwanted, key)
+		}
+	}
+	sortAssociations(unwanted)
+	for _, key := range unwanted {
+		changes = append(changes, fmt.Sprintf("association %s is not wanted", formatAssociation(key)))
+		params := api.SlurmdbV0044DeleteAssociationsParams{
+			User:    ptr.To(name),
+			Account: ptr.To(key.Account),
+			Cluster: ptr.To(key.Cluster),
+		}
+		if id := currentAssocs[key].Id; id != nil {
+			params.Id = ptr.To(strconv.Itoa(int(*id)))
+		}
+		if err := r.slurmControl.DeleteAssociations(ctx, controller, params); err != nil {
+			return nil, err
+		}
+	}
+
+	return changes, nil
+}
+
+// syncDelete removes the user associations from slurmdbd, and the user once
+// it has no associations left, before removing the finalizer.
+func (r *SlurmUserReconciler) syncDelete(ctx context.Context, user *slinkyv1beta1.SlurmUser) error {
+	logger := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(user, slinkyv1beta1.FinalizerSlurmdb) {
+		return nil
+	}
+
+	accounting, err := resolveAccounting(ctx, r.refResolver, r.slurmControl, user.AccountingRef())
+	if err != nil {
+		return err
+	}
+	switch {
+	case accounting.gone:
+		logger.Info("Accounting is gone, skipping removal of the user from slurmdbd",
+			"user", user.UserName())
+	case accounting.controller == nil:
+		logger.Info("Waiting for a slurm client to remove the user from slurmdbd",
+			"user", user.UserName())
+		userDurationStore.Push(user.Key().String(), NoClientInterval)
+		return nil
+	default:
+		clusters := set.New(accounting.clusters...)
+		for _, assoc := range user.Spec.Associations {
+			if assoc.Cluster != "" {
+				clusters.Insert(assoc.Cluster)
+			}
+		}
+		if err := r.removeUser(ctx, accounting.controller, user.UserName(), clusters.SortedList()); err != nil {
+			r.eventRecorder.Eventf(user, corev1.EventTypeWarning, SyncFailedReason,
+				"Failed to remove user %s: %v", user.UserName(), err)
+			return err
+		}
+		r.eventRecorder.Eventf(user, corev1.EventTypeNormal, RemovedReason,
+			"Removed user %s from slurmdbd", user.UserName())
+	}
+
+	patch := client.MergeFrom(user.DeepCopy())
+	controllerutil.RemoveFinalizer(user, slinkyv1beta1.FinalizerSlurmdb)
+	return r.Patch(ctx, user, patch)
+}
+
+// removeUser removes the associations of the user on the clusters, and the
+// user once it has no associations left.
+func (r *SlurmUserReconciler) removeUser(
+	ctx context.Context,
+	controller *slinkyv1beta1.Controller,
+	name string,
+	clusters []string,
+) error {
+	for _, cluster := range clusters {
+		params := api.SlurmdbV0044DeleteAssociationsParams{
+			User:    ptr.To(name),
+			Cluster: ptr.To(cluster),
+		}
+		if err := r.slurmControl.DeleteAssociations(ctx, controller, params); err != nil {
+			return err
+		}
+	}
+
+	assocs, err := r.slurmControl.GetAssociations(ctx, controller, api.SlurmdbV0044GetAssociationsParams{
+		User: ptr.To(name),
+	})
+	if err != nil {
+		return err
+	}
+	if slices.ContainsFunc(assocs, func(assoc api.V0044Assoc) bool {
+		return assoc.User == name
+	}) {
+		return nil
+	}
+
+	return r.slurmControl.DeleteUser(ctx, controller, name)
+}
+
+// userAssociations returns the associations of the user, with the
+// associations that omit the cluster made on every cluster.
+func userAssociations(user *slinkyv1beta1.SlurmUser, clusters []string) []slinkyv1beta1.SlurmUserAssociation {
+	assocs := user.Spec.Associations
+	if len(assocs) == 0 {
+		assocs = []slinkyv1beta1.SlurmUserAssociation{
+			{Account: user.Spec.DefaultAccount},
+		}
+	}
+
+	out := sets.New[slinkyv1beta1.SlurmUserAssociation]()
+	for _, assoc := range assocs {
+		if assoc.Cluster != "" {
+			out.Insert(assoc)
+			continue
+		}
+		for _, cluster := range clusters {
+			assoc.Cluster = cluster
+			out.Insert(assoc)
+		}
+	}
+
+	list := out.UnsortedList()
+	sortAssociations(list)
+	return list
+}
+
+// associationKey returns the association of the user association.
+func associationKey(assoc api.V0044Assoc) slinkyv1beta1.SlurmUserAssociation {
+	return slinkyv1beta1.SlurmUserAssociation{
+		Account:   ptr.Deref(assoc.Account, ""),
+		Cluster:   ptr.Deref(assoc.Cluster, ""),
+		Partition: ptr.Deref(assoc.Partition, ""),
+	}
+}
+
+func formatAssociation(assoc slinkyv1beta1.SlurmUserAssociation) string {
+	s := fmt.Sprintf("cluster=%s account=%s", assoc.Cluster, assoc.Account)
+	if assoc.Partition != "" {
+		s += " partition=" + assoc.Partition
+	}
+	return s
+}
+
+func sortAssociations(assocs []slinkyv1beta1.SlurmUserAssociation) {
+	slices.SortFunc(assocs, func(a, b slinkyv1beta1.SlurmUserAssociation) int {
+		if c := strings.Compare(a.Cluster, b.Cluster); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.Account, b.Account); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Partition, b.Partition)
+	})
+}
+
+// userAdminLevel returns the admin level of the user.
+func userAdminLevel(user *api.V0044User) string {
+	levels := ptr.Deref(user.AdministratorLevel, nil)
+	if len(levels) == 0 || levels[0] == api.V0044UserAdministratorLevelNotSet {
+		return string(slinkyv1beta1.SlurmAdminLevelNone)
+	}
+	return string(levels[0])
+}
+
+// syncStatus handles determining and updating the status.
+func (r *SlurmUserReconciler) syncStatus(
+	ctx context.Context,
+	user *slinkyv1beta1.SlurmUser,
+	newStatus *slinkyv1beta1.SlurmUserStatus,
+) error {
+	logger := log.FromContext(ctx)
+
+	if apiequality.Semantic.DeepEqual(user.Status, *newStatus) {
+		logger.V(2).Info("SlurmUser Status has not changed, skipping status update",
+			"user", klog.KObj(user), "status", user.Status)
+		return nil
+	}
+
+	userKey := objectutils.NamespacedName(user)
+	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		toUpdate := &slinkyv1beta1.SlurmUser{}
+		if err := r.Get(ctx, userKey, toUpdate); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		toUpdate.Status = *newStatus
+		return r.Status().Update(ctx, toUpdate)
+	}); err != nil {
+		return fmt.Errorf("error updating SlurmUser(%s) status: %w",
+			klog.KObj(user), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmdb
+
+import (
+	"testing"
+
+	api "github.com/SlinkyProject/slurm-client/api/v0044"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+)
+
+func newSlurmUser(defaultAccount string, assocs ...slinkyv1beta1.SlurmUserAssociation) *slinkyv1beta1.SlurmUser {
+	return &slinkyv1beta1.SlurmUser{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      "alice",
+		},
+		Spec: slinkyv1beta1.SlurmUserSpec{
+			AccountingRef:  slinkyv1beta1.ObjectReference{Name: "slurm"},
+			DefaultAccount: defaultAccount,
+			Associations:   assocs,
+		},
+	}
+}
+
+func Test_userAssociations(t *testing.T) {
+	clusters := []string{"default_slurm-a", "default_slurm-b"}
+	tests := []struct {
+		name     string
+		user     *slinkyv1beta1.SlurmUser
+		clusters []string
+		want     []slinkyv1beta1.SlurmUserAssociation
+	}{
+		{
+			name:     "default account",
+			user:     newSlurmUser("physics"),
+			clusters: clusters,
+			want: []slinkyv1beta1.SlurmUserAssociation{
+				{Account: "physics", Cluster: "default_slurm-a"},
+				{Account: "physics", Cluster: "default_slurm-b"},
+			},
+		},
+		{
+			name: "associations",
+			user: newSlurmUser("physics",
+				slinkyv1beta1.SlurmUserAssociation{Account: "physics", Cluster: "default_slurm-b"},
+				slinkyv1beta1.SlurmUserAssociation{Account: "chemistry", Partition: "gpu"},
+			),
+			clusters: clusters,
+			want: []slinkyv1beta1.SlurmUserAssociation{
+				{Account: "chemistry", Cluster: "default_slurm-a", Partition: "gpu"},
+				{Account: "chemistry", Cluster: "default_slurm-b", Partition: "gpu"},
+				{Account: "physics", Cluster: "default_slurm-b"},
+			},
+		},
+		{
+			name: "duplicates",
+			user: newSlurmUser("physics",
+				slinkyv1beta1.SlurmUserAssociation{Account: "physics", Cluster: "default_slurm-a"},
+				slinkyv1beta1.SlurmUserAssociation{Account: "physics"},
+			),
+			clusters: clusters,
+			want: []slinkyv1beta1.SlurmUserAssociation{
+				{Account: "physics", Cluster: "default_slurm-a"},
+				{Account: "physics", Cluster: "default_slurm-b"},
+			},
+		},
+		{
+			name:     "no clusters",
+			user:     newSlurmUser("physics"),
+			clusters: nil,
+			want:     []slinkyv1beta1.SlurmUserAssociation{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := userAssociations(tt.user, tt.clusters)
+			if !equality.Semantic.DeepEqual(got, tt.want) {
+				t.Errorf("userAssociations() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_userAdminLevel(t *testing.T) {
+	tests := []struct {
+		name string
+		user *api.V0044User
+		want string
+	}{
+		{
+			name: "unset",
+			user: &api.V0044User{Name: "alice"},
+			want: string(slinkyv1beta1.SlurmAdminLevelNone),
+		},
+		{
+			name: "not set",
+			user: &api.V0044User{
+				Name:               "alice",
+				AdministratorLevel: ptr.To([]api.V0044UserAdministratorLevel{api.V0044UserAdministratorLevelNotSet}),
+			},
+			want: string(slinkyv1beta1.SlurmAdminLevelNone),
+		},
+		{
+			name: "operator",
+			user: &api.V0044User{
+				Name:               "alice",
+				AdministratorLevel: ptr.To([]api.V0044UserAdministratorLevel{api.V0044UserAdministratorLevelOperator}),
+			},
+			want: string(slinkyv1beta1.SlurmAdminLevelOperator),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := userAdminLevel(tt.user); got != tt.want {
+				t.Errorf("userAdminLevel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
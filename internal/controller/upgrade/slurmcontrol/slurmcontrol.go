@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmcontrol
+
+import (
+	"context"
+	"net/http"
+
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	slurmclient "github.com/SlinkyProject/slurm-client/pkg/client"
+	slurmtypes "github.com/SlinkyProject/slurm-client/pkg/types"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	"github.com/SlinkyProject/slurm-operator/internal/clientmap"
+)
+
+type SlurmControlInterface interface {
+	// HasClient reports if there is a slurm client for the controller.
+	HasClient(controller *slinkyv1beta1.Controller) bool
+	// IsControllerResponding reports if every slurmctld responds to a ping.
+	IsControllerResponding(ctx context.Context, controller *slinkyv1beta1.Controller) (bool, error)
+	// GetNodeVersions returns the slurmd version of each Slurm node, by node name.
+	GetNodeVersions(ctx context.Context, controller *slinkyv1beta1.Controller) (map[string]string, error)
+}
+
+// realSlurmControl is the default implementation of SlurmControlInterface.
+type realSlurmControl struct {
+	clientMap *clientmap.ClientMap
+}
+
+// HasClient implements SlurmControlInterface.
+func (r *realSlurmControl) HasClient(controller *slinkyv1beta1.Controller) bool {
+	return r.lookupClient(controller) != nil
+}
+
+// IsControllerResponding implements SlurmControlInterface.
+func (r *realSlurmControl) IsControllerResponding(ctx context.Context, controller *slinkyv1beta1.Controller) (bool, error) {
+	logger := log.FromContext(ctx)
+
+	slurmClient := r.lookupClient(controller)
+	if slurmClient == nil {
+		logger.V(2).Info("no client for controller, cannot do IsControllerResponding()")
+		return false, nil
+	}
+
+	pingList := &slurmtypes.V0044ControllerPingList{}
+	if err := slurmClient.List(ctx, pingList); err != nil {
+		if tolerateError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if len(pingList.Items) == 0 {
+		return false, nil
+	}
+	for _, ping := range pingList.Items {
+		if !ping.Responding {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// GetNodeVersions implements SlurmControlInterface.
+func (r *realSlurmControl) GetNodeVersions(ctx context.Context, controller *slinkyv1beta1.Controller) (map[string]string, error) {
+	logger := log.FromContext(ctx)
+
+	slurmClient := r.lookupClient(controller)
+	if slurmClient == nil {
+		logger.V(2).Info("no client for controller, cannot do GetNodeVersions()")
+		return nil, nil
+	}
+
+	nodeList := &slurmtypes.V0044NodeList{}
+	if err := slurmClient.List(ctx, nodeList); err != nil {
+		if tolerateError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	versions := make(map[string]string, len(nodeList.Items))
+	for _, node := range nodeList.Items {
+		name := ptr.Deref(node.Name, "")
+		version := ptr.Deref(node.Version, "")
+		if name == "" || version == "" {
+			continue
+		}
+		versions[name] = version
+	}
+
+	return versions, nil
+}
+
+func (r *realSlurmControl) lookupClient(controller *slinkyv1beta1.Controller) slurmclient.Client {
+	return r.clientMap.Get(client.ObjectKeyFromObject(controller))
+}
+
+var _ SlurmControlInterface = &realSlurmControl{}
+
+func NewSlurmControl(clientMap *clientmap.ClientMap) SlurmControlInterface {
+	return &realSlurmControl{
+		clientMap: clientMap,
+	}
+}
+
+func tolerateError(err error) bool {
+	if err == nil {
+		return true
+	}
+	errText := err.Error()
+	if errText == http.StatusText(http.StatusNotFound) ||
+		errText == http.StatusText(http.StatusNoContent) {
+		return true
+	}
+	return false
+}
@@ -0,0 +1,183 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmcontrol
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	api "github.com/SlinkyProject/slurm-client/api/v0044"
+	"github.com/SlinkyProject/slurm-client/pkg/client"
+	"github.com/SlinkyProject/slurm-client/pkg/client/fake"
+	"github.com/SlinkyProject/slurm-client/pkg/client/interceptor"
+	"github.com/SlinkyProject/slurm-client/pkg/object"
+	"github.com/SlinkyProject/slurm-client/pkg/types"
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	"github.com/SlinkyProject/slurm-operator/internal/clientmap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+func newController(name string) *slinkyv1beta1.Controller {
+	return &slinkyv1beta1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      name,
+		},
+	}
+}
+
+func newSlurmClientMap(controllerName string, client client.Client) *clientmap.ClientMap {
+	cm := clientmap.NewClientMap()
+	key := k8stypes.NamespacedName{
+		Namespace: corev1.NamespaceDefault,
+		Name:      controllerName,
+	}
+	cm.Add(key, client)
+	return cm
+}
+
+func newPing(hostname string, responding bool) types.V0044ControllerPing {
+	return types.V0044ControllerPing{
+		V0044ControllerPing: api.V0044ControllerPing{
+			Hostname:   ptr.To(hostname),
+			Responding: responding,
+		},
+	}
+}
+
+func Test_realSlurmControl_IsControllerResponding(t *testing.T) {
+	ctx := context.Background()
+	controller := newController("slurm")
+	tests := []struct {
+		name      string
+		clientMap *clientmap.ClientMap
+		want      bool
+		wantErr   bool
+	}{
+		{
+			name: "responding",
+			clientMap: newSlurmClientMap(controller.Name,
+				fake.NewClientBuilder().WithLists(&types.V0044ControllerPingList{
+					Items: []types.V0044ControllerPing{newPing("slurm-controller-0", true)},
+				}).Build()),
+			want: true,
+		},
+		{
+			name: "not responding",
+			clientMap: newSlurmClientMap(controller.Name,
+				fake.NewClientBuilder().WithLists(&types.V0044ControllerPingList{
+					Items: []types.V0044ControllerPing{
+						newPing("slurm-controller-0", true),
+						newPing("slurm-controller-1", false),
+					},
+				}).Build()),
+			want: false,
+		},
+		{
+			name:      "no pings",
+			clientMap: newSlurmClientMap(controller.Name, fake.NewFakeClient()),
+			want:      false,
+		},
+		{
+			name:      "no client",
+			clientMap: clientmap.NewClientMap(),
+			want:      false,
+		},
+		{
+			name: "error",
+			clientMap: newSlurmClientMap(controller.Name,
+				fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+					List: func(ctx context.Context, list object.ObjectList, opts ...client.ListOption) error {
+						return errors.New(http.StatusText(http.StatusInternalServerError))
+					},
+				}).Build()),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewSlurmControl(tt.clientMap)
+			got, err := r.IsControllerResponding(ctx, controller)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("realSlurmControl.IsControllerResponding() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("realSlurmControl.IsControllerResponding() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_realSlurmControl_GetNodeVersions(t *testing.T) {
+	ctx := context.Background()
+	controller := newController("slurm")
+	nodeList := &types.V0044NodeList{
+		Items: []types.V0044Node{
+			{V0044Node: api.V0044Node{Name: ptr.To("node-0"), Version: ptr.To("25.05.3")}},
+			{V0044Node: api.V0044Node{Name: ptr.To("node-1"), Version: ptr.To("25.11.0")}},
+			{V0044Node: api.V0044Node{Name: ptr.To("node-2")}},
+		},
+	}
+	tests := []struct {
+		name      string
+		clientMap *clientmap.ClientMap
+		want      map[string]string
+		wantErr   bool
+	}{
+		{
+			name: "smoke",
+			clientMap: newSlurmClientMap(controller.Name,
+				fake.NewClientBuilder().WithLists(nodeList).Build()),
+			want: map[string]string{
+				"node-0": "25.05.3",
+				"node-1": "25.11.0",
+			},
+		},
+		{
+			name:      "no client",
+			clientMap: clientmap.NewClientMap(),
+			want:      nil,
+		},
+		{
+			name: "not found",
+			clientMap: newSlurmClientMap(controller.Name,
+				fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+					List: func(ctx context.Context, list object.ObjectList, opts ...client.ListOption) error {
+						return errors.New(http.StatusText(http.StatusNotFound))
+					},
+				}).Build()),
+			want: nil,
+		},
+		{
+			name: "error",
+			clientMap: newSlurmClientMap(controller.Name,
+				fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+					List: func(ctx context.Context, list object.ObjectList, opts ...client.ListOption) error {
+						return errors.New(http.StatusText(http.StatusInternalServerError))
+					},
+				}).Build()),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewSlurmControl(tt.clientMap)
+			got, err := r.GetNodeVersions(ctx, controller)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("realSlurmControl.GetNodeVersions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !equality.Semantic.DeepEqual(got, tt.want) {
+				t.Errorf("realSlurmControl.GetNodeVersions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,521 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package upgrade
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	nodesetutils "github.com/SlinkyProject/slurm-operator/internal/controller/nodeset/utils"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/objectutils"
+)
+
+const (
+	progressingReasonComplete = "Complete"
+
+	supportedReasonSupported          = "Supported"
+	supportedReasonUnsupported        = "UnsupportedVersion"
+	supportedReasonUnknownVersion     = "UnknownVersion"
+	supportedReasonControllerNotFound = "ControllerNotFound"
+	supportedReasonInvalidImage       = "InvalidImage"
+)
+
+// Reasons for Upgrade events
+const (
+	// UpgradingReason is added to an event when a component is given the new image.
+	UpgradingReason = "Upgrading"
+	// UpgradedReason is added to an event when every component was upgraded.
+	UpgradedReason = "Upgraded"
+	// UnsupportedUpgradeReason is added to an event when Slurm does not support the version change.
+	UnsupportedUpgradeReason = "UnsupportedUpgrade"
+)
+
+// component is a CR whose container image is changed by the upgrade.
+type component struct {
+	kind   string
+	object client.Object
+	// image points at the container image within object.
+	image *string
+	// override is the image requested by the Upgrade, if any.
+	override string
+	// ready reports if the component rolled out the image and is healthy.
+	ready func(ctx context.Context, image string) (bool, string, error)
+}
+
+// targetImage returns the image the component is upgraded to.
+func (c *component) targetImage(version string) (string, error) {
+	if c.override != "" {
+		return c.override, nil
+	}
+	if *c.image == "" {
+		return "", fmt.Errorf("%s(%s) has no image to derive from, set the image in the Upgrade",
+			c.kind, klog.KObj(c.object))
+	}
+	image, err := upgradeImage(*c.image, version)
+	if err != nil {
+		return "", fmt.Errorf("%s(%s): %w, set the image in the Upgrade",
+			c.kind, klog.KObj(c.object), err)
+	}
+	return image, nil
+}
+
+// tier is a set of components that are upgraded together. Slurm requires
+// slurmdbd be upgraded first, then slurmctld, then slurmd and the clients.
+type tier struct {
+	phase      slinkyv1beta1.UpgradePhase
+	components []component
+}
+
+// Sync implements control logic for synchronizing a Upgrade.
+func (r *UpgradeReconciler) Sync(ctx context.Context, req reconcile.Request) error {
+	logger := log.FromContext(ctx)
+
+	upgrade := &slinkyv1beta1.Upgrade{}
+	if err := r.Get(ctx, req.NamespacedName, upgrade); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("Upgrade has been deleted", "request", req)
+			return nil
+		}
+		return err
+	}
+
+	if !upgrade.DeletionTimestamp.IsZero() {
+		return nil
+	}
+	if upgrade.Status.Phase == slinkyv1beta1.UpgradePhaseComplete {
+		return nil
+	}
+
+	status := upgrade.Status.DeepCopy()
+	var errs []error
+	if err := r.syncUpgrade(ctx, upgrade, status); err != nil {
+		errs = append(errs, err)
+	}
+	if err := r.syncStatus(ctx, upgrade, status); err != nil {
+		errs = append(errs, err)
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// syncUpgrade advances the upgrade by one tier at a time, only moving to the
+// next tier once every component of the current tier is healthy.
+func (r *UpgradeReconciler) syncUpgrade(
+	ctx context.Context,
+	upgrade *slinkyv1beta1.Upgrade,
+	status *slinkyv1beta1.UpgradeStatus,
+) error {
+	logger := log.FromContext(ctx)
+	version := upgrade.Spec.SlurmVersion
+
+	controllerRef := upgrade.ControllerRef()
+	controller, err := r.refResolver.GetController(ctx, controllerRef)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			setFailed(upgrade, status, supportedReasonControllerNotFound,
+				fmt.Sprintf("Controller(%s) was not found.", controllerRef.NamespacedName()))
+			return nil
+		}
+		return err
+	}
+
+	tiers, err := r.buildTiers(ctx, upgrade, controller)
+	if err != nil {
+		return err
+	}
+
+	if status.FromVersion == "" {
+		fromVersion, err := r.runningVersion(ctx, controller, tiers)
+		if err != nil {
+			return err
+		}
+		if fromVersion == "" {
+			setFailed(upgrade, status, supportedReasonUnknownVersion,
+				"Unable to determine the running Slurm version from the component images or Slurm nodes.")
+			return nil
+		}
+		status.FromVersion = fromVersion
+	}
+
+	if err := checkUpgradePath(status.FromVersion, version); err != nil {
+		if status.Phase != slinkyv1beta1.UpgradePhaseFailed {
+			r.eventRecorder.Eventf(upgrade, corev1.EventTypeWarning, UnsupportedUpgradeReason,
+				"Refusing to upgrade: %v", err)
+		}
+		setFailed(upgrade, status, supportedReasonUnsupported, fmt.Sprintf("Refusing to upgrade: %v.", err))
+		return nil
+	}
+
+	images := make([][]string, len(tiers))
+	for i, t := range tiers {
+		for _, c := range t.components {
+			image, err := c.targetImage(version)
+			if err != nil {
+				setFailed(upgrade, status, supportedReasonInvalidImage, err.Error())
+				return nil
+			}
+			images[i] = append(images[i], image)
+		}
+	}
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               slinkyv1beta1.UpgradeSupported,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: upgrade.Generation,
+		Reason:             supportedReasonSupported,
+		Message:            fmt.Sprintf("Slurm supports upgrading from %s to %s.", status.FromVersion, version),
+	})
+
+	status.Components = status.Components[:0]
+	for i, t := range tiers {
+		for j := range t.components {
+			c := &t.components[j]
+			if *c.image == images[i][j] {
+				continue
+			}
+			logger.Info("Upgrading component", "kind", c.kind, "object", klog.KObj(c.object), "image", images[i][j])
+			patch := client.MergeFrom(c.object.DeepCopyObject().(client.Object))
+			*c.image = images[i][j]
+			if err := r.Patch(ctx, c.object, patch); err != nil {
+				return fmt.Errorf("failed to patch %s(%s) image: %w", c.kind, klog.KObj(c.object), err)
+			}
+			r.eventRecorder.Eventf(upgrade, corev1.EventTypeNormal, UpgradingReason,
+				"Upgrading %s(%s) to image %s", c.kind, klog.KObj(c.object), images[i][j])
+		}
+
+		tierReady := true
+		for j, c := range t.components {
+			ready, message, err := c.ready(ctx, images[i][j])
+			if err != nil {
+				return fmt.Errorf("failed to check %s(%s) health: %w", c.kind, klog.KObj(c.object), err)
+			}
+			tierReady = tierReady && ready
+			status.Components = append(status.Components, slinkyv1beta1.UpgradeComponentStatus{
+				Kind:    c.kind,
+				Name:    c.object.GetName(),
+				Image:   images[i][j],
+				Ready:   ready,
+				Message: message,
+			})
+		}
+		if tierReady {
+			continue
+		}
+
+		// Later tiers must not change until this one is healthy.
+		for k := i + 1; k < len(tiers); k++ {
+			for j, c := range tiers[k].components {
+				status.Components = append(status.Components, slinkyv1beta1.UpgradeComponentStatus{
+					Kind:    c.kind,
+					Name:    c.object.GetName(),
+					Image:   images[k][j],
+					Message: fmt.Sprintf("Waiting for the %s tier to be upgraded.", t.phase),
+				})
+			}
+		}
+		status.Phase = t.phase
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+			Type:               slinkyv1beta1.UpgradeProgressing,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: upgrade.Generation,
+			Reason:             string(t.phase),
+			Message:            fmt.Sprintf("Waiting for the %s tier to be healthy.", t.phase),
+		})
+		durationStore.Push(objectutils.KeyFunc(upgrade), ProgressInterval)
+		return nil
+	}
+
+	r.eventRecorder.Eventf(upgrade, corev1.EventTypeNormal, UpgradedReason,
+		"Upgraded from %s to %s", status.FromVersion, version)
+	status.Phase = slinkyv1beta1.UpgradePhaseComplete
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               slinkyv1beta1.UpgradeProgressing,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: upgrade.Generation,
+		Reason:             progressingReasonComplete,
+		Message:            fmt.Sprintf("Every component runs Slurm %s.", version),
+	})
+
+	return nil
+}
+
+func setFailed(upgrade *slinkyv1beta1.Upgrade, status *slinkyv1beta1.UpgradeStatus, reason, message string) {
+	status.Phase = slinkyv1beta1.UpgradePhaseFailed
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               slinkyv1beta1.UpgradeSupported,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: upgrade.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+	meta.RemoveStatusCondition(&status.Conditions, slinkyv1beta1.UpgradeProgressing)
+}
+
+// buildTiers returns the components of the Slurm cluster in upgrade order.
+func (r *UpgradeReconciler) buildTiers(
+	ctx context.Context,
+	upgrade *slinkyv1beta1.Upgrade,
+	controller *slinkyv1beta1.Controller,
+) ([]tier, error) {
+	images := upgrade.Spec.Images
+
+	accountingTier := tier{phase: slinkyv1beta1.UpgradePhaseAccounting}
+	if controller.Spec.AccountingRef.Name != "" {
+		accounting, err := r.refResolver.GetAccounting(ctx, controller.Spec.AccountingRef)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		if err == nil && !accounting.Spec.External {
+			accountingTier.components = append(accountingTier.components, component{
+				kind:     slinkyv1beta1.AccountingKind,
+				object:   accounting,
+				image:    &accounting.Spec.Slurmdbd.Image,
+				override: images.Slurmdbd,
+				ready: func(ctx context.Context, image string) (bool, string, error) {
+					return r.statefulSetReady(ctx, accounting.Key(), image)
+				},
+			})
+		}
+	}
+
+	controllerTier := tier{phase: slinkyv1beta1.UpgradePhaseController}
+	if !controller.Spec.External {
+		controllerTier.components = append(controllerTier.components, component{
+			kind:     slinkyv1beta1.ControllerKind,
+			object:   controller,
+			image:    &controller.Spec.Slurmctld.Image,
+			override: images.Slurmctld,
+			ready: func(ctx context.Context, image string) (bool, string, error) {
+				return r.controllerReady(ctx, controller, image)
+			},
+		})
+	}
+
+	nodesTier := tier{phase: slinkyv1beta1.UpgradePhaseNodes}
+	nodesetList, err := r.refResolver.GetNodeSetsForController(ctx, controller)
+	if err != nil {
+		return nil, err
+	}
+	for i := range nodesetList.Items {
+		nodeset := &nodesetList.Items[i]
+		nodesTier.components = append(nodesTier.components, component{
+			kind:     slinkyv1beta1.NodeSetKind,
+			object:   nodeset,
+			image:    &nodeset.Spec.Slurmd.Image,
+			override: images.Slurmd,
+			ready: func(ctx context.Context, image string) (bool, string, error) {
+				return r.nodesetReady(ctx, controller, nodeset, upgrade.Spec.SlurmVersion)
+			},
+		})
+	}
+	loginsetList, err := r.refResolver.GetLoginSetsForController(ctx, controller)
+	if err != nil {
+		return nil, err
+	}
+	for i := range loginsetList.Items {
+		loginset := &loginsetList.Items[i]
+		nodesTier.components = append(nodesTier.components, component{
+			kind:     slinkyv1beta1.LoginSetKind,
+			object:   loginset,
+			image:    &loginset.Spec.Login.Image,
+			override: images.Login,
+			ready: func(ctx context.Context, image string) (bool, string, error) {
+				return r.deploymentReady(ctx, loginset.Key(), image)
+			},
+		})
+	}
+	restapiList, err := r.refResolver.GetRestapisForController(ctx, controller)
+	if err != nil {
+		return nil, err
+	}
+	for i := range restapiList.Items {
+		restapi := &restapiList.Items[i]
+		nodesTier.components = append(nodesTier.components, component{
+			kind:     slinkyv1beta1.RestApiKind,
+			object:   restapi,
+			image:    &restapi.Spec.Slurmrestd.Image,
+			override: images.Slurmrestd,
+			ready: func(ctx context.Context, image string) (bool, string, error) {
+				return r.deploymentReady(ctx, restapi.Key(), image)
+			},
+		})
+	}
+
+	return []tier{accountingTier, controllerTier, nodesTier}, nil
+}
+
+// runningVersion returns the oldest Slurm version found in the component
+// images and reported by the Slurm nodes.
+func (r *UpgradeReconciler) runningVersion(
+	ctx context.Context,
+	controller *slinkyv1beta1.Controller,
+	tiers []tier,
+) (string, error) {
+	var versions []string
+	for _, t := range tiers {
+		for _, c := range t.components {
+			versions = append(versions, imageVersion(*c.image))
+		}
+	}
+	nodeVersions, err := r.slurmControl.GetNodeVersions(ctx, controller)
+	if err != nil {
+		return "", err
+	}
+	for _, version := range nodeVersions {
+		versions = append(versions, version)
+	}
+
+	oldest := ""
+	for _, version := range versions {
+		if majorRelease(version) == "" {
+			continue
+		}
+		if oldest == "" || compareVersions(version, oldest) < 0 {
+			oldest = version
+		}
+	}
+	return oldest, nil
+}
+
+func (r *UpgradeReconciler) statefulSetReady(ctx context.Context, key types.NamespacedName, image string) (bool, string, error) {
+	sts := &appsv1.StatefulSet{}
+	if err := r.Get(ctx, key, sts); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, "Waiting for the StatefulSet to be created.", nil
+		}
+		return false, "", err
+	}
+	replicas := ptr.Deref(sts.Spec.Replicas, 1)
+	switch {
+	case !hasImage(&sts.Spec.Template.Spec, image):
+		return false, "Waiting for the image to be applied to the StatefulSet.", nil
+	case sts.Status.ObservedGeneration < sts.Generation:
+		return false, "Waiting for the StatefulSet rollout to start.", nil
+	case sts.Status.UpdatedReplicas != replicas:
+		return false, fmt.Sprintf("%d of %d pods updated.", sts.Status.UpdatedReplicas, replicas), nil
+	case sts.Status.ReadyReplicas != replicas:
+		return false, fmt.Sprintf("%d of %d pods ready.", sts.Status.ReadyReplicas, replicas), nil
+	}
+	return true, "", nil
+}
+
+func (r *UpgradeReconciler) deploymentReady(ctx context.Context, key types.NamespacedName, image string) (bool, string, error) {
+	deployment := &appsv1.Deployment{}
+	if err := r.Get(ctx, key, deployment); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, "Waiting for the Deployment to be created.", nil
+		}
+		return false, "", err
+	}
+	replicas := ptr.Deref(deployment.Spec.Replicas, 1)
+	switch {
+	case !hasImage(&deployment.Spec.Template.Spec, image):
+		return false, "Waiting for the image to be applied to the Deployment.", nil
+	case deployment.Status.ObservedGeneration < deployment.Generation:
+		return false, "Waiting for the Deployment rollout to start.", nil
+	case deployment.Status.UpdatedReplicas != replicas:
+		return false, fmt.Sprintf("%d of %d pods updated.", deployment.Status.UpdatedReplicas, replicas), nil
+	case deployment.Status.Replicas != replicas:
+		return false, "Waiting for old pods to terminate.", nil
+	case deployment.Status.AvailableReplicas != replicas:
+		return false, fmt.Sprintf("%d of %d pods available.", deployment.Status.AvailableReplicas, replicas), nil
+	}
+	return true, "", nil
+}
+
+// controllerReady checks the slurmctld StatefulSet rolled out and, when a
+// Slurm client is available, that slurmctld responds to pings.
+func (r *UpgradeReconciler) controllerReady(ctx context.Context, controller *slinkyv1beta1.Controller, image string) (bool, string, error) {
+	ready, message, err := r.statefulSetReady(ctx, controller.Key(), image)
+	if err != nil || !ready {
+		return ready, message, err
+	}
+	if !r.slurmControl.HasClient(controller) {
+		return true, "", nil
+	}
+	responding, err := r.slurmControl.IsControllerResponding(ctx, controller)
+	if err != nil {
+		return false, "", err
+	}
+	if !responding {
+		return false, "Waiting for slurmctld to respond to pings.", nil
+	}
+	return true, "", nil
+}
+
+// nodesetReady checks the NodeSet rolled out and, when a Slurm client is
+// available, that every NodeSet pod registered with the new slurmd version.
+func (r *UpgradeReconciler) nodesetReady(
+	ctx context.Context,
+	controller *slinkyv1beta1.Controller,
+	nodeset *slinkyv1beta1.NodeSet,
+	version string,
+) (bool, string, error) {
+	status := nodeset.Status
+	switch {
+	case status.ObservedGeneration < nodeset.Generation, status.Selector == "":
+		return false, "Waiting for the NodeSet rollout to start.", nil
+	case status.UpdatedReplicas != status.Replicas:
+		return false, fmt.Sprintf("%d of %d pods updated.", status.UpdatedReplicas, status.Replicas), nil
+	case status.ReadyReplicas != status.Replicas:
+		return false, fmt.Sprintf("%d of %d pods ready.", status.ReadyReplicas, status.Replicas), nil
+	}
+	if !r.slurmControl.HasClient(controller) {
+		return true, "", nil
+	}
+
+	nodeVersions, err := r.slurmControl.GetNodeVersions(ctx, controller)
+	if err != nil {
+		return false, "", err
+	}
+	selector, err := labels.Parse(status.Selector)
+	if err != nil {
+		return false, "", err
+	}
+	podList := &corev1.PodList{}
+	if err := r.List(ctx, podList, client.InNamespace(nodeset.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return false, "", err
+	}
+	upgraded := 0
+	for i := range podList.Items {
+		nodeName := nodesetutils.GetNodeName(&podList.Items[i])
+		if versionMatches(nodeVersions[nodeName], version) {
+			upgraded++
+		}
+	}
+	if upgraded != len(podList.Items) {
+		return false, fmt.Sprintf("%d of %d Slurm nodes registered with version %s.",
+			upgraded, len(podList.Items), version), nil
+	}
+	return true, "", nil
+}
+
+// hasImage reports if a container of the pod uses the image.
+func hasImage(podSpec *corev1.PodSpec, image string) bool {
+	for _, c := range podSpec.Containers {
+		if c.Image == image {
+			return true
+		}
+	}
+	for _, c := range podSpec.InitContainers {
+		if c.Image == image {
+			return true
+		}
+	}
+	return false
+}
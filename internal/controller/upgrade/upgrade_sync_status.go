@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package upgrade
+
+import (
+	"context"
+	"fmt"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/objectutils"
+)
+
+// syncStatus handles determining and updating the status.
+func (r *UpgradeReconciler) syncStatus(
+	ctx context.Context,
+	upgrade *slinkyv1beta1.Upgrade,
+	newStatus *slinkyv1beta1.UpgradeStatus,
+) error {
+	logger := log.FromContext(ctx)
+
+	if apiequality.Semantic.DeepEqual(upgrade.Status, *newStatus) {
+		logger.V(2).Info("Upgrade Status has not changed, skipping status update",
+			"upgrade", klog.KObj(upgrade), "status", upgrade.Status)
+		return nil
+	}
+
+	if err := r.updateStatus(ctx, upgrade, newStatus); err != nil {
+		return fmt.Errorf("error updating Upgrade(%s) status: %w",
+			klog.KObj(upgrade), err)
+	}
+
+	return nil
+}
+
+func (r *UpgradeReconciler) updateStatus(
+	ctx context.Context,
+	upgrade *slinkyv1beta1.Upgrade,
+	newStatus *slinkyv1beta1.UpgradeStatus,
+) error {
+	logger := log.FromContext(ctx)
+	upgradeKey := objectutils.NamespacedName(upgrade)
+
+	logger.V(1).Info("Pending Upgrade Status update",
+		"upgrade", klog.KObj(upgrade), "newStatus", newStatus)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		toUpdate := &slinkyv1beta1.Upgrade{}
+		if err := r.Get(ctx, upgradeKey, toUpdate); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		toUpdate.Status = *newStatus
+		return r.Status().Update(ctx, toUpdate)
+	})
+}
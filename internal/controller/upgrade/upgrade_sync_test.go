@@ -0,0 +1,301 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package upgrade
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/SlinkyProject/slurm-client/api/v0044"
+	slurmfake "github.com/SlinkyProject/slurm-client/pkg/client/fake"
+	slurmtypes "github.com/SlinkyProject/slurm-client/pkg/types"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+	"github.com/SlinkyProject/slurm-operator/internal/clientmap"
+	"github.com/SlinkyProject/slurm-operator/internal/controller/upgrade/slurmcontrol"
+	"github.com/SlinkyProject/slurm-operator/internal/utils/refresolver"
+)
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(slinkyv1beta1.AddToScheme(scheme))
+}
+
+const (
+	oldVersion = "25.05"
+	newVersion = "25.11"
+)
+
+func newUpgrade(version string) *slinkyv1beta1.Upgrade {
+	return &slinkyv1beta1.Upgrade{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      "upgrade",
+		},
+		Spec: slinkyv1beta1.UpgradeSpec{
+			ControllerRef: slinkyv1beta1.ObjectReference{Name: "slurm"},
+			SlurmVersion:  version,
+		},
+	}
+}
+
+func image(name, version string) string {
+	return "ghcr.io/slinkyproject/" + name + ":" + version + "-ubuntu24.04"
+}
+
+func newCluster(version string) (*slinkyv1beta1.Controller, *slinkyv1beta1.Accounting, *slinkyv1beta1.NodeSet) {
+	accounting := &slinkyv1beta1.Accounting{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      "slurm",
+		},
+	}
+	accounting.Spec.Slurmdbd.Image = image("slurmdbd", version)
+	controller := &slinkyv1beta1.Controller{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      "slurm",
+		},
+		Spec: slinkyv1beta1.ControllerSpec{
+			AccountingRef: slinkyv1beta1.ObjectReference{
+				Namespace: corev1.NamespaceDefault,
+				Name:      accounting.Name,
+			},
+		},
+	}
+	controller.Spec.Slurmctld.Image = image("slurmctld", version)
+	nodeset := &slinkyv1beta1.NodeSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      "slurm-compute",
+		},
+		Spec: slinkyv1beta1.NodeSetSpec{
+			ControllerRef: slinkyv1beta1.ObjectReference{
+				Namespace: corev1.NamespaceDefault,
+				Name:      controller.Name,
+			},
+		},
+		Status: slinkyv1beta1.NodeSetStatus{
+			Replicas:        1,
+			UpdatedReplicas: 1,
+			ReadyReplicas:   1,
+			Selector:        "app=slurmd",
+		},
+	}
+	nodeset.Spec.Slurmd.Image = image("slurmd", version)
+	return controller, accounting, nodeset
+}
+
+func newStatefulSet(key client.ObjectKey, image string, ready bool) *appsv1.StatefulSet {
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: ptr.To[int32](1),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Image: image}},
+				},
+			},
+		},
+	}
+	if ready {
+		sts.Status.UpdatedReplicas = 1
+		sts.Status.ReadyReplicas = 1
+	}
+	return sts
+}
+
+func newReconciler(c client.Client, cm *clientmap.ClientMap) *UpgradeReconciler {
+	return &UpgradeReconciler{
+		Client:        c,
+		refResolver:   refresolver.New(c),
+		slurmControl:  slurmcontrol.NewSlurmControl(cm),
+		eventRecorder: record.NewFakeRecorder(10),
+	}
+}
+
+func TestUpgradeReconciler_syncUpgrade(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name            string
+		upgrade         *slinkyv1beta1.Upgrade
+		objects         func(controller *slinkyv1beta1.Controller, accounting *slinkyv1beta1.Accounting) []client.Object
+		nodeVersion     string
+		wantPhase       slinkyv1beta1.UpgradePhase
+		wantSlurmdbd    string
+		wantSlurmctld   string
+		wantSlurmd      string
+		wantSupported   metav1.ConditionStatus
+		wantFromVersion string
+	}{
+		{
+			name:    "accounting first",
+			upgrade: newUpgrade(newVersion),
+			objects: func(controller *slinkyv1beta1.Controller, accounting *slinkyv1beta1.Accounting) []client.Object {
+				return []client.Object{
+					newStatefulSet(accounting.Key(), image("slurmdbd", oldVersion), true),
+				}
+			},
+			wantPhase:       slinkyv1beta1.UpgradePhaseAccounting,
+			wantSlurmdbd:    image("slurmdbd", newVersion),
+			wantSlurmctld:   image("slurmctld", oldVersion),
+			wantSlurmd:      image("slurmd", oldVersion),
+			wantSupported:   metav1.ConditionTrue,
+			wantFromVersion: oldVersion,
+		},
+		{
+			name:    "controller after accounting",
+			upgrade: newUpgrade(newVersion),
+			objects: func(controller *slinkyv1beta1.Controller, accounting *slinkyv1beta1.Accounting) []client.Object {
+				return []client.Object{
+					newStatefulSet(accounting.Key(), image("slurmdbd", newVersion), true),
+					newStatefulSet(controller.Key(), image("slurmctld", oldVersion), true),
+				}
+			},
+			wantPhase:       slinkyv1beta1.UpgradePhaseController,
+			wantSlurmdbd:    image("slurmdbd", newVersion),
+			wantSlurmctld:   image("slurmctld", newVersion),
+			wantSlurmd:      image("slurmd", oldVersion),
+			wantSupported:   metav1.ConditionTrue,
+			wantFromVersion: oldVersion,
+		},
+		{
+			name:    "nodes wait for slurmd version",
+			upgrade: newUpgrade(newVersion),
+			objects: func(controller *slinkyv1beta1.Controller, accounting *slinkyv1beta1.Accounting) []client.Object {
+				return []client.Object{
+					newStatefulSet(accounting.Key(), image("slurmdbd", newVersion), true),
+					newStatefulSet(controller.Key(), image("slurmctld", newVersion), true),
+				}
+			},
+			nodeVersion:     "25.05.3",
+			wantPhase:       slinkyv1beta1.UpgradePhaseNodes,
+			wantSlurmdbd:    image("slurmdbd", newVersion),
+			wantSlurmctld:   image("slurmctld", newVersion),
+			wantSlurmd:      image("slurmd", newVersion),
+			wantSupported:   metav1.ConditionTrue,
+			wantFromVersion: oldVersion,
+		},
+		{
+			name:    "complete",
+			upgrade: newUpgrade(newVersion),
+			objects: func(controller *slinkyv1beta1.Controller, accounting *slinkyv1beta1.Accounting) []client.Object {
+				return []client.Object{
+					newStatefulSet(accounting.Key(), image("slurmdbd", newVersion), true),
+					newStatefulSet(controller.Key(), image("slurmctld", newVersion), true),
+				}
+			},
+			nodeVersion:     "25.11.0",
+			wantPhase:       slinkyv1beta1.UpgradePhaseComplete,
+			wantSlurmdbd:    image("slurmdbd", newVersion),
+			wantSlurmctld:   image("slurmctld", newVersion),
+			wantSlurmd:      image("slurmd", newVersion),
+			wantSupported:   metav1.ConditionTrue,
+			wantFromVersion: oldVersion,
+		},
+		{
+			name:    "unsupported skip",
+			upgrade: newUpgrade("26.11"),
+			objects: func(controller *slinkyv1beta1.Controller, accounting *slinkyv1beta1.Accounting) []client.Object {
+				return nil
+			},
+			wantPhase:       slinkyv1beta1.UpgradePhaseFailed,
+			wantSlurmdbd:    image("slurmdbd", oldVersion),
+			wantSlurmctld:   image("slurmctld", oldVersion),
+			wantSlurmd:      image("slurmd", oldVersion),
+			wantSupported:   metav1.ConditionFalse,
+			wantFromVersion: oldVersion,
+		},
+		{
+			name:    "downgrade",
+			upgrade: newUpgrade("24.11"),
+			objects: func(controller *slinkyv1beta1.Controller, accounting *slinkyv1beta1.Accounting) []client.Object {
+				return nil
+			},
+			wantPhase:       slinkyv1beta1.UpgradePhaseFailed,
+			wantSlurmdbd:    image("slurmdbd", oldVersion),
+			wantSlurmctld:   image("slurmctld", oldVersion),
+			wantSlurmd:      image("slurmd", oldVersion),
+			wantSupported:   metav1.ConditionFalse,
+			wantFromVersion: oldVersion,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller, accounting, nodeset := newCluster(oldVersion)
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: corev1.NamespaceDefault,
+					Name:      "slurm-compute-0",
+					Labels:    map[string]string{"app": "slurmd"},
+				},
+			}
+			objects := append(tt.objects(controller, accounting), controller, accounting, nodeset, pod, tt.upgrade)
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+			cm := clientmap.NewClientMap()
+			if tt.nodeVersion != "" {
+				cm.Add(client.ObjectKeyFromObject(controller), slurmfake.NewClientBuilder().WithLists(
+					&slurmtypes.V0044ControllerPingList{
+						Items: []slurmtypes.V0044ControllerPing{
+							{V0044ControllerPing: api.V0044ControllerPing{Hostname: ptr.To("slurm-controller-0"), Responding: true}},
+						},
+					},
+					&slurmtypes.V0044NodeList{
+						Items: []slurmtypes.V0044Node{
+							{V0044Node: api.V0044Node{Name: ptr.To(pod.Name), Version: ptr.To(tt.nodeVersion)}},
+						},
+					},
+				).Build())
+			}
+			r := newReconciler(c, cm)
+
+			status := tt.upgrade.Status.DeepCopy()
+			if err := r.syncUpgrade(ctx, tt.upgrade, status); err != nil {
+				t.Fatalf("syncUpgrade() error = %v", err)
+			}
+			if status.Phase != tt.wantPhase {
+				t.Errorf("Status.Phase = %v, want %v", status.Phase, tt.wantPhase)
+			}
+			if status.FromVersion != tt.wantFromVersion {
+				t.Errorf("Status.FromVersion = %v, want %v", status.FromVersion, tt.wantFromVersion)
+			}
+			condition := meta.FindStatusCondition(status.Conditions, slinkyv1beta1.UpgradeSupported)
+			if condition == nil || condition.Status != tt.wantSupported {
+				t.Errorf("Supported condition = %v, want status %v", condition, tt.wantSupported)
+			}
+
+			gotAccounting := &slinkyv1beta1.Accounting{}
+			gotController := &slinkyv1beta1.Controller{}
+			gotNodeSet := &slinkyv1beta1.NodeSet{}
+			_ = c.Get(ctx, client.ObjectKeyFromObject(accounting), gotAccounting)
+			_ = c.Get(ctx, client.ObjectKeyFromObject(controller), gotController)
+			_ = c.Get(ctx, client.ObjectKeyFromObject(nodeset), gotNodeSet)
+			if got := gotAccounting.Spec.Slurmdbd.Image; got != tt.wantSlurmdbd {
+				t.Errorf("Accounting image = %v, want %v", got, tt.wantSlurmdbd)
+			}
+			if got := gotController.Spec.Slurmctld.Image; got != tt.wantSlurmctld {
+				t.Errorf("Controller image = %v, want %v", got, tt.wantSlurmctld)
+			}
+			if got := gotNodeSet.Spec.Slurmd.Image; got != tt.wantSlurmd {
+				t.Errorf("NodeSet image = %v, want %v", got, tt.wantSlurmd)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package upgrade
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MaxReleaseSkew is the number of major Slurm releases that one upgrade
+	// can advance by.
+	MaxReleaseSkew = 2
+)
+
+var (
+	// legacyReleases are the major Slurm releases before the six month
+	// release cadence started with 24.05.
+	legacyReleases = []string{
+		"17.02",
+		"17.11",
+		"18.08",
+		"19.05",
+		"20.02",
+		"20.11",
+		"21.08",
+		"22.05",
+		"23.02",
+		"23.11",
+	}
+
+	versionRegex = regexp.MustCompile(`^([0-9]{2})\.([0-9]{2})(\.[0-9]+)?`)
+)
+
+// majorRelease returns the major Slurm release (e.g. `25.05`) of a version
+// string (e.g. `25.05.3`), or an empty string if it is not a Slurm version.
+func majorRelease(version string) string {
+	m := versionRegex.FindStringSubmatch(version)
+	if m == nil {
+		return ""
+	}
+	return m[1] + "." + m[2]
+}
+
+// releaseIndex returns the position of the major release in the sequence of
+// Slurm releases, or -1 if it is not a Slurm release.
+func releaseIndex(release string) int {
+	for i, r := range legacyReleases {
+		if r == release {
+			return i
+		}
+	}
+	m := versionRegex.FindStringSubmatch(release)
+	if m == nil {
+		return -1
+	}
+	year, _ := strconv.Atoi(m[1])
+	month := m[2]
+	if year < 24 {
+		return -1
+	}
+	index := len(legacyReleases) + (year-24)*2
+	switch month {
+	case "05":
+		return index
+	case "11":
+		return index + 1
+	default:
+		return -1
+	}
+}
+
+// checkUpgradePath returns an error if Slurm does not support upgrading
+// directly from one version to the other.
+func checkUpgradePath(from, to string) error {
+	fromRelease, toRelease := majorRelease(from), majorRelease(to)
+	fromIndex, toIndex := releaseIndex(fromRelease), releaseIndex(toRelease)
+	switch {
+	case fromIndex < 0:
+		return fmt.Errorf("current version (%s) is not a known Slurm release", from)
+	case toIndex < 0:
+		return fmt.Errorf("version (%s) is not a known Slurm release", to)
+	case toIndex < fromIndex:
+		return fmt.Errorf("downgrading from %s to %s is not supported", fromRelease, toRelease)
+	case toIndex-fromIndex > MaxReleaseSkew:
+		return fmt.Errorf("%s is %d releases newer than %s, at most %d are supported",
+			toRelease, toIndex-fromIndex, fromRelease, MaxReleaseSkew)
+	}
+	return nil
+}
+
+// compareVersions orders two Slurm versions, returning -1, 0, or 1. A version
+// without a maintenance number (e.g. `25.05`) sorts before any maintenance
+// release of it.
+func compareVersions(a, b string) int {
+	pa, pb := versionParts(a), versionParts(b)
+	for i := range pa {
+		switch {
+		case pa[i] < pb[i]:
+			return -1
+		case pa[i] > pb[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+func versionParts(version string) [3]int {
+	var parts [3]int
+	m := versionRegex.FindStringSubmatch(version)
+	if m == nil {
+		return parts
+	}
+	parts[0], _ = strconv.Atoi(m[1])
+	parts[1], _ = strconv.Atoi(m[2])
+	parts[2], _ = strconv.Atoi(strings.TrimPrefix(m[3], "."))
+	return parts
+}
+
+// versionMatches reports if a running version satisfies the target version.
+// A target without a maintenance number matches any maintenance release.
+func versionMatches(running, target string) bool {
+	if majorRelease(running) != majorRelease(target) {
+		return false
+	}
+	if majorRelease(target) == target {
+		return true
+	}
+	return compareVersions(running, target) == 0
+}
+
+// imageVersion returns the Slurm version at the start of the image tag, or an
+// empty string if the image tag does not start with a Slurm version.
+func imageVersion(image string) string {
+	return versionRegex.FindString(imageTag(image))
+}
+
+// upgradeImage replaces the Slurm version at the start of the image tag with
+// the version.
+func upgradeImage(image, version string) (string, error) {
+	if strings.Contains(image, "@") {
+		return "", fmt.Errorf("image (%s) is pinned by digest", image)
+	}
+	tag := imageTag(image)
+	current := versionRegex.FindString(tag)
+	if current == "" {
+		return "", fmt.Errorf("image (%s) tag does not start with a Slurm version", image)
+	}
+	repo := strings.TrimSuffix(image, ":"+tag)
+	return repo + ":" + version + strings.TrimPrefix(tag, current), nil
+}
+
+// imageTag returns the tag of the image reference, ignoring registry ports and
+// digests.
+func imageTag(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	i := strings.LastIndex(image, ":")
+	if i < 0 || strings.Contains(image[i:], "/") {
+		return ""
+	}
+	return image[i+1:]
+}
@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package upgrade
+
+import (
+	"testing"
+)
+
+func Test_releaseIndex(t *testing.T) {
+	tests := []struct {
+		release string
+		want    int
+	}{
+		{release: "17.02", want: 0},
+		{release: "23.11", want: 9},
+		{release: "24.05", want: 10},
+		{release: "24.11", want: 11},
+		{release: "25.05", want: 12},
+		{release: "27.11", want: 17},
+		{release: "23.05", want: -1},
+		{release: "25.08", want: -1},
+		{release: "foo", want: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.release, func(t *testing.T) {
+			if got := releaseIndex(tt.release); got != tt.want {
+				t.Errorf("releaseIndex() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_checkUpgradePath(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		wantErr bool
+	}{
+		{name: "maintenance release", from: "25.05.1", to: "25.05.3"},
+		{name: "next release", from: "25.05", to: "25.11"},
+		{name: "two releases", from: "24.11.5", to: "25.11"},
+		{name: "across cadence change", from: "23.02", to: "24.05"},
+		{name: "three releases", from: "24.05", to: "25.11", wantErr: true},
+		{name: "downgrade", from: "25.11", to: "25.05", wantErr: true},
+		{name: "unknown from", from: "latest", to: "25.11", wantErr: true},
+		{name: "unknown to", from: "25.05", to: "25.08", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkUpgradePath(tt.from, tt.to); (err != nil) != tt.wantErr {
+				t.Errorf("checkUpgradePath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_versionMatches(t *testing.T) {
+	tests := []struct {
+		running string
+		target  string
+		want    bool
+	}{
+		{running: "25.11.0", target: "25.11", want: true},
+		{running: "25.11.2", target: "25.11.2", want: true},
+		{running: "25.11.1", target: "25.11.2", want: false},
+		{running: "25.05.3", target: "25.11", want: false},
+		{running: "", target: "25.11", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.running+"-"+tt.target, func(t *testing.T) {
+			if got := versionMatches(tt.running, tt.target); got != tt.want {
+				t.Errorf("versionMatches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_upgradeImage(t *testing.T) {
+	tests := []struct {
+		name    string
+		image   string
+		version string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "os suffix",
+			image:   "ghcr.io/slinkyproject/slurmctld:25.05-ubuntu24.04",
+			version: "25.11",
+			want:    "ghcr.io/slinkyproject/slurmctld:25.11-ubuntu24.04",
+		},
+		{
+			name:    "maintenance release",
+			image:   "ghcr.io/slinkyproject/slurmd:25.05.3-rockylinux9",
+			version: "25.11.1",
+			want:    "ghcr.io/slinkyproject/slurmd:25.11.1-rockylinux9",
+		},
+		{
+			name:    "registry port",
+			image:   "registry:5000/slurmdbd:24.11",
+			version: "25.05",
+			want:    "registry:5000/slurmdbd:25.05",
+		},
+		{
+			name:    "no version",
+			image:   "ghcr.io/slinkyproject/slurmctld:latest",
+			version: "25.11",
+			wantErr: true,
+		},
+		{
+			name:    "no tag",
+			image:   "registry:5000/slurmctld",
+			version: "25.11",
+			wantErr: true,
+		},
+		{
+			name:    "digest",
+			image:   "ghcr.io/slinkyproject/slurmctld:25.05@sha256:0123",
+			version: "25.11",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := upgradeImage(tt.image, tt.version)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("upgradeImage() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("upgradeImage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
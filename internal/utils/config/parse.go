@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Parameter is a single key-value pair from a configuration line.
+type Parameter struct {
+	Key   string
+	Value string
+}
+
+// Line is the ordered list of parameters found on one configuration line.
+type Line []Parameter
+
+// Get returns the value of the first parameter whose key matches,
+// ignoring case (Slurm keys are case insensitive).
+func (l Line) Get(key string) (string, bool) {
+	for _, param := range l {
+		if strings.EqualFold(param.Key, key) {
+			return param.Value, true
+		}
+	}
+	return "", false
+}
+
+// Parse parses Slurm style configuration (e.g. slurm.conf) into lines of
+// key-value parameters. Comments, blank lines, and surrounding quotes are
+// dropped; lines ending in a backslash are joined with the next line.
+func Parse(conf string) []Line {
+	lines := []Line{}
+	pending := ""
+	for _, raw := range strings.Split(conf, "\n") {
+		text := stripComment(raw)
+		text = strings.TrimRightFunc(text, unicode.IsSpace)
+		if trimmed, ok := strings.CutSuffix(text, "\\"); ok {
+			pending += trimmed + " "
+			continue
+		}
+		text = pending + text
+		pending = ""
+		if line := parseLine(text); len(line) > 0 {
+			lines = append(lines, line)
+		}
+	}
+	if line := parseLine(pending); len(line) > 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// stripComment removes everything after an unquoted '#'.
+func stripComment(text string) string {
+	quoted := false
+	for i, r := range text {
+		switch r {
+		case '"':
+			quoted = !quoted
+		case '#':
+			if !quoted {
+				return text[:i]
+			}
+		}
+	}
+	return text
+}
+
+// parseLine splits the text on unquoted whitespace into parameters.
+func parseLine(text string) Line {
+	line := Line{}
+	quoted := false
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		if r == '"' {
+			quoted = !quoted
+		}
+		return !quoted && unicode.IsSpace(r)
+	})
+	for _, field := range fields {
+		key, value, _ := strings.Cut(field, "=")
+		line = append(line, Parameter{
+			Key:   key,
+			Value: strings.Trim(value, `"`),
+		})
+	}
+	return line
+}
@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+)
+
+func TestParse(t *testing.T) {
+	type args struct {
+		conf string
+	}
+	tests := []struct {
+		name string
+		args args
+		want []Line
+	}{
+		{
+			name: "empty",
+			args: args{
+				conf: "",
+			},
+			want: []Line{},
+		},
+		{
+			name: "comments and blank lines",
+			args: args{
+				conf: "# comment\n\n   \n#\n",
+			},
+			want: []Line{},
+		},
+		{
+			name: "single key",
+			args: args{
+				conf: "ClusterName=slurm # trailing comment\n",
+			},
+			want: []Line{
+				{{Key: "ClusterName", Value: "slurm"}},
+			},
+		},
+		{
+			name: "multiple keys",
+			args: args{
+				conf: "PartitionName=foo Nodes=foo,bar Default=YES\n",
+			},
+			want: []Line{
+				{
+					{Key: "PartitionName", Value: "foo"},
+					{Key: "Nodes", Value: "foo,bar"},
+					{Key: "Default", Value: "YES"},
+				},
+			},
+		},
+		{
+			name: "quoted values",
+			args: args{
+				conf: `NodeName=foo Reason="down # for maintenance"` + "\n",
+			},
+			want: []Line{
+				{
+					{Key: "NodeName", Value: "foo"},
+					{Key: "Reason", Value: "down # for maintenance"},
+				},
+			},
+		},
+		{
+			name: "line continuation",
+			args: args{
+				conf: "PartitionName=foo \\\n  Nodes=foo\nInclude /etc/slurm/extra.conf",
+			},
+			want: []Line{
+				{
+					{Key: "PartitionName", Value: "foo"},
+					{Key: "Nodes", Value: "foo"},
+				},
+				{
+					{Key: "Include", Value: ""},
+					{Key: "/etc/slurm/extra.conf", Value: ""},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Parse(tt.args.conf)
+			if !apiequality.Semantic.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLine_Get(t *testing.T) {
+	line := Line{
+		{Key: "PartitionName", Value: "foo"},
+		{Key: "Nodes", Value: "foo"},
+	}
+	type args struct {
+		key string
+	}
+	tests := []struct {
+		name   string
+		args   args
+		want   string
+		wantOk bool
+	}{
+		{
+			name:   "exact",
+			args:   args{key: "Nodes"},
+			want:   "foo",
+			wantOk: true,
+		},
+		{
+			name:   "case insensitive",
+			args:   args{key: "partitionname"},
+			want:   "foo",
+			wantOk: true,
+		},
+		{
+			name:   "missing",
+			args:   args{key: "Default"},
+			want:   "",
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := line.Get(tt.args.key)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("Line.Get() = (%v, %v), want (%v, %v)", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+const (
+	pemTypeCertificate = "CERTIFICATE"
+	pemTypePrivateKey  = "PRIVATE KEY"
+)
+
+// Certificate is an X.509 certificate and its private key, in PEM format.
+type Certificate struct {
+	Cert []byte
+	Key  []byte
+}
+
+// NewCertificateAuthority creates a self-signed CA certificate.
+func NewCertificateAuthority(commonName string, validity time.Duration) (*Certificate, error) {
+	template := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: commonName},
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	return newCertificate(template, validity, nil, nil)
+}
+
+// NewServingCertificate creates a TLS server certificate for the DNS names,
+// signed by the CA.
+func NewServingCertificate(ca *Certificate, commonName string, dnsNames []string, validity time.Duration) (*Certificate, error) {
+	if ca == nil {
+		return nil, errors.New("CA is nil")
+	}
+	caPair, err := tls.X509KeyPair(ca.Cert, ca.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load CA: %w", err)
+	}
+	caCert, err := x509.ParseCertificate(caPair.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CA certificate: %w", err)
+	}
+	template := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: commonName},
+		DNSNames:    dnsNames,
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	return newCertificate(template, validity, caCert, caPair.PrivateKey)
+}
+
+// newCertificate creates an ECDSA key and a certificate for it, signed by the
+// parent. The certificate is self-signed when parent is nil.
+func newCertificate(template *x509.Certificate, validity time.Duration, parent *x509.Certificate, parentKey any) (*Certificate, error) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate ECDSA key pair: %w", err)
+	}
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+
+	now := time.Now()
+	template.SerialNumber = serialNumber
+	// Tolerate clock skew between the operator and the clients.
+	template.NotBefore = now.Add(-5 * time.Minute)
+	template.NotAfter = now.Add(validity)
+
+	if parent == nil {
+		parent = template
+		parentKey = privateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, privateKey.Public(), parentKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create certificate: %w", err)
+	}
+	keyDer, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal private key: %w", err)
+	}
+
+	out := &Certificate{
+		Cert: pem.EncodeToMemory(&pem.Block{Type: pemTypeCertificate, Bytes: der}),
+		Key:  pem.EncodeToMemory(&pem.Block{Type: pemTypePrivateKey, Bytes: keyDer}),
+	}
+	return out, nil
+}
+
+// ParseCertificate parses the first certificate in PEM format.
+func ParseCertificate(certPEM []byte) (*x509.Certificate, error) {
+	for {
+		var block *pem.Block
+		block, certPEM = pem.Decode(certPEM)
+		if block == nil {
+			return nil, errors.New("no certificate found in PEM data")
+		}
+		if block.Type == pemTypeCertificate {
+			return x509.ParseCertificate(block.Bytes)
+		}
+	}
+}
+
+// RenewalTime returns when the certificate should be renewed, after two thirds
+// of its validity have passed.
+func RenewalTime(cert *x509.Certificate) time.Time {
+	validity := cert.NotAfter.Sub(cert.NotBefore)
+	return cert.NotBefore.Add(validity * 2 / 3)
+}
+
+// VerifyCertificateAuthority checks that the CA certificate matches its key,
+// and does not need renewal.
+func VerifyCertificateAuthority(ca *Certificate, now time.Time) error {
+	if _, err := tls.X509KeyPair(ca.Cert, ca.Key); err != nil {
+		return fmt.Errorf("invalid key pair: %w", err)
+	}
+	cert, err := ParseCertificate(ca.Cert)
+	if err != nil {
+		return fmt.Errorf("failed to parse certificate: %w", err)
+	}
+	if !cert.IsCA {
+		return errors.New("certificate is not a CA")
+	}
+	if !now.Before(RenewalTime(cert)) {
+		return fmt.Errorf("certificate is due for renewal since %s", RenewalTime(cert).Format(time.RFC3339))
+	}
+	return nil
+}
+
+// VerifyServingCertificate checks that the certificate matches its key, is
+// signed by the CA, is valid for the DNS names, and does not need renewal.
+func VerifyServingCertificate(caPEM []byte, cert *Certificate, dnsNames []string, now time.Time) error {
+	if _, err := tls.X509KeyPair(cert.Cert, cert.Key); err != nil {
+		return fmt.Errorf("invalid key pair: %w", err)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caPEM) {
+		return errors.New("no CA certificate found in PEM data")
+	}
+	leaf, err := ParseCertificate(cert.Cert)
+	if err != nil {
+		return fmt.Errorf("failed to parse certificate: %w", err)
+	}
+	for _, dnsName := range dnsNames {
+		opts := x509.VerifyOptions{
+			DNSName:     dnsName,
+			Roots:       roots,
+			CurrentTime: now,
+			KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		}
+		if _, err := leaf.Verify(opts); err != nil {
+			return err
+		}
+	}
+	if !now.Before(RenewalTime(leaf)) {
+		return fmt.Errorf("certificate is due for renewal since %s", RenewalTime(leaf).Format(time.RFC3339))
+	}
+	return nil
+}
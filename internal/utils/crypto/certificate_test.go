@@ -0,0 +1,206 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package crypto
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewCertificateAuthority(t *testing.T) {
+	ca, err := NewCertificateAuthority("test-ca", time.Hour)
+	if err != nil {
+		t.Fatalf("NewCertificateAuthority() error = %v", err)
+	}
+	cert, err := ParseCertificate(ca.Cert)
+	if err != nil {
+		t.Fatalf("ParseCertificate() error = %v", err)
+	}
+	if !cert.IsCA {
+		t.Errorf("IsCA = %v, want true", cert.IsCA)
+	}
+	if cert.Subject.CommonName != "test-ca" {
+		t.Errorf("CommonName = %v, want %v", cert.Subject.CommonName, "test-ca")
+	}
+}
+
+func TestVerifyServingCertificate(t *testing.T) {
+	dnsNames := []string{"slurm-restapi", "slurm-restapi.slurm"}
+
+	ca, err := NewCertificateAuthority("test-ca", time.Hour)
+	if err != nil {
+		t.Fatalf("NewCertificateAuthority() error = %v", err)
+	}
+	otherCa, err := NewCertificateAuthority("other-ca", time.Hour)
+	if err != nil {
+		t.Fatalf("NewCertificateAuthority() error = %v", err)
+	}
+	cert, err := NewServingCertificate(ca, "slurm-restapi", dnsNames, time.Hour)
+	if err != nil {
+		t.Fatalf("NewServingCertificate() error = %v", err)
+	}
+	otherCert, err := NewServingCertificate(ca, "slurm-restapi", dnsNames, time.Hour)
+	if err != nil {
+		t.Fatalf("NewServingCertificate() error = %v", err)
+	}
+
+	type args struct {
+		caPEM    []byte
+		cert     *Certificate
+		dnsNames []string
+		now      time.Time
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Valid",
+			args: args{
+				caPEM:    ca.Cert,
+				cert:     cert,
+				dnsNames: dnsNames,
+				now:      time.Now(),
+			},
+		},
+		{
+			name: "Other CA",
+			args: args{
+				caPEM:    otherCa.Cert,
+				cert:     cert,
+				dnsNames: dnsNames,
+				now:      time.Now(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "Missing DNS name",
+			args: args{
+				caPEM:    ca.Cert,
+				cert:     cert,
+				dnsNames: append(dnsNames, "slurm-restapi.slurm.svc"),
+				now:      time.Now(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "Mismatched key",
+			args: args{
+				caPEM:    ca.Cert,
+				cert:     &Certificate{Cert: cert.Cert, Key: otherCert.Key},
+				dnsNames: dnsNames,
+				now:      time.Now(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "Due for renewal",
+			args: args{
+				caPEM:    ca.Cert,
+				cert:     cert,
+				dnsNames: dnsNames,
+				now:      time.Now().Add(45 * time.Minute),
+			},
+			wantErr: true,
+		},
+		{
+			name: "Not a CA",
+			args: args{
+				caPEM:    []byte("foo"),
+				cert:     cert,
+				dnsNames: dnsNames,
+				now:      time.Now(),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyServingCertificate(tt.args.caPEM, tt.args.cert, tt.args.dnsNames, tt.args.now)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifyServingCertificate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRenewalTime(t *testing.T) {
+	ca, err := NewCertificateAuthority("test-ca", 3*time.Hour)
+	if err != nil {
+		t.Fatalf("NewCertificateAuthority() error = %v", err)
+	}
+	cert, err := ParseCertificate(ca.Cert)
+	if err != nil {
+		t.Fatalf("ParseCertificate() error = %v", err)
+	}
+	got := RenewalTime(cert)
+	if !got.After(cert.NotBefore) || !got.Before(cert.NotAfter) {
+		t.Errorf("RenewalTime() = %v, want between %v and %v", got, cert.NotBefore, cert.NotAfter)
+	}
+	if remaining := cert.NotAfter.Sub(got); remaining < time.Hour {
+		t.Errorf("RenewalTime() leaves %v, want at least %v", remaining, time.Hour)
+	}
+}
+
+func TestVerifyCertificateAuthority(t *testing.T) {
+	ca, err := NewCertificateAuthority("test-ca", time.Hour)
+	if err != nil {
+		t.Fatalf("NewCertificateAuthority() error = %v", err)
+	}
+	cert, err := NewServingCertificate(ca, "slurm-restapi", []string{"slurm-restapi"}, time.Hour)
+	if err != nil {
+		t.Fatalf("NewServingCertificate() error = %v", err)
+	}
+
+	type args struct {
+		ca  *Certificate
+		now time.Time
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "Valid",
+			args: args{
+				ca:  ca,
+				now: time.Now(),
+			},
+		},
+		{
+			name: "Not a CA",
+			args: args{
+				ca:  cert,
+				now: time.Now(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "Mismatched key",
+			args: args{
+				ca:  &Certificate{Cert: ca.Cert, Key: cert.Key},
+				now: time.Now(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "Due for renewal",
+			args: args{
+				ca:  ca,
+				now: time.Now().Add(45 * time.Minute),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyCertificateAuthority(tt.args.ca, tt.args.now)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifyCertificateAuthority() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -30,6 +30,8 @@ func DeleteObject(c client.Client, ctx context.Context, newObj client.Object) er
 		oldObj = &corev1.Secret{}
 	case *corev1.Service:
 		oldObj = &corev1.Service{}
+	case *corev1.PersistentVolumeClaim:
+		oldObj = &corev1.PersistentVolumeClaim{}
 	case *appsv1.Deployment:
 		oldObj = &appsv1.Deployment{}
 	case *appsv1.StatefulSet:
@@ -32,6 +32,8 @@ func SyncObject(c client.Client, ctx context.Context, newObj client.Object, shou
 		oldObj = &corev1.Secret{}
 	case *corev1.Service:
 		oldObj = &corev1.Service{}
+	case *corev1.PersistentVolumeClaim:
+		oldObj = &corev1.PersistentVolumeClaim{}
 	case *appsv1.Deployment:
 		oldObj = &appsv1.Deployment{}
 	case *appsv1.StatefulSet:
@@ -105,6 +107,13 @@ func SyncObject(c client.Client, ctx context.Context, newObj client.Object, shou
 		obj.Annotations = structutils.MergeMaps(obj.Annotations, o.Annotations)
 		obj.Labels = structutils.MergeMaps(obj.Labels, o.Labels)
 		obj.Spec = o.Spec
+	case *corev1.PersistentVolumeClaim:
+		obj := oldObj.(*corev1.PersistentVolumeClaim)
+		patch = client.MergeFrom(obj.DeepCopy())
+		obj.Annotations = structutils.MergeMaps(obj.Annotations, o.Annotations)
+		obj.Labels = structutils.MergeMaps(obj.Labels, o.Labels)
+		// NOTE: only the requested resources of a bound claim may change.
+		obj.Spec.Resources.Requests = o.Spec.Resources.Requests
 	case *appsv1.Deployment:
 		obj := oldObj.(*appsv1.Deployment)
 		patch = client.MergeFrom(obj.DeepCopy())
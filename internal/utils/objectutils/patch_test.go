@@ -179,6 +179,38 @@ func TestSyncObject(t *testing.T) {
 				shouldUpdate: true,
 			},
 		},
+		{
+			name: "Create PersistentVolumeClaim",
+			args: args{
+				c:   fake.NewFakeClient(),
+				ctx: context.TODO(),
+				newObj: &corev1.PersistentVolumeClaim{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "foo",
+					},
+				},
+				shouldUpdate: true,
+			},
+		},
+		{
+			name: "Update PersistentVolumeClaim",
+			args: args{
+				c: fake.NewClientBuilder().WithObjects(
+					&corev1.PersistentVolumeClaim{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "foo",
+						},
+					},
+				).Build(),
+				ctx: context.TODO(),
+				newObj: &corev1.PersistentVolumeClaim{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "foo",
+					},
+				},
+				shouldUpdate: true,
+			},
+		},
 		{
 			name: "Create Deployment",
 			args: args{
@@ -7,7 +7,9 @@ import (
 	"context"
 	"fmt"
 
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -107,6 +109,47 @@ func (r *RefResolver) GetControllersForAccounting(ctx context.Context, accountin
 	return out, nil
 }
 
+func (r *RefResolver) GetHorizontalPodAutoscalersForNodeSet(ctx context.Context, nodeset *slinkyv1beta1.NodeSet) (*autoscalingv2.HorizontalPodAutoscalerList, error) {
+	list := &autoscalingv2.HorizontalPodAutoscalerList{}
+	if err := r.reader.List(ctx, list, client.InNamespace(nodeset.Namespace)); err != nil {
+		return nil, err
+	}
+
+	out := &autoscalingv2.HorizontalPodAutoscalerList{}
+	for _, item := range list.Items {
+		if isNodeSetScaleTarget(item.Spec.ScaleTargetRef) && item.Spec.ScaleTargetRef.Name == nodeset.Name {
+			out.Items = append(out.Items, item)
+		}
+	}
+
+	return out, nil
+}
+
+func (r *RefResolver) GetNodeSetForHorizontalPodAutoscaler(ctx context.Context, hpa *autoscalingv2.HorizontalPodAutoscaler) (*slinkyv1beta1.NodeSet, error) {
+	if !isNodeSetScaleTarget(hpa.Spec.ScaleTargetRef) {
+		return nil, fmt.Errorf("HorizontalPodAutoscaler does not scale a NodeSet")
+	}
+
+	obj := &slinkyv1beta1.NodeSet{}
+	key := types.NamespacedName{
+		Name:      hpa.Spec.ScaleTargetRef.Name,
+		Namespace: hpa.Namespace,
+	}
+	if err := r.reader.Get(ctx, key, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// isNodeSetScaleTarget reports whether the scale target refers to a NodeSet.
+func isNodeSetScaleTarget(ref autoscalingv2.CrossVersionObjectReference) bool {
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return false
+	}
+	return gv.Group == slinkyv1beta1.GroupVersion.Group && ref.Kind == slinkyv1beta1.NodeSetKind
+}
+
 func (r *RefResolver) GetSecretKeyRef(ctx context.Context, selector *corev1.SecretKeySelector, namespace string) ([]byte, error) {
 	secret := &corev1.Secret{}
 	key := types.NamespacedName{
@@ -9,6 +9,7 @@ import (
 
 	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
 	"github.com/SlinkyProject/slurm-operator/internal/utils/objectutils"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -538,6 +539,171 @@ func TestRefResolver_GetControllersForAccounting(t *testing.T) {
 	}
 }
 
+func newHorizontalPodAutoscaler(name, apiVersion, kind, target string) *autoscalingv2.HorizontalPodAutoscaler {
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: apiVersion,
+				Kind:       kind,
+				Name:       target,
+			},
+			MaxReplicas: 10,
+		},
+	}
+}
+
+func TestRefResolver_GetHorizontalPodAutoscalersForNodeSet(t *testing.T) {
+	type fields struct {
+		reader client.Reader
+	}
+	type args struct {
+		ctx     context.Context
+		nodeset *slinkyv1beta1.NodeSet
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "empty",
+			fields: fields{
+				reader: fake.NewClientBuilder().
+					WithScheme(scheme).
+					Build(),
+			},
+			args: args{
+				ctx: context.TODO(),
+				nodeset: &slinkyv1beta1.NodeSet{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "slurm",
+						Namespace: metav1.NamespaceDefault,
+					},
+				},
+			},
+			want: 0,
+		},
+		{
+			name: "found",
+			fields: fields{
+				reader: fake.NewClientBuilder().
+					WithScheme(scheme).
+					WithObjects(newHorizontalPodAutoscaler("foo", slinkyv1beta1.NodeSetAPIVersion, slinkyv1beta1.NodeSetKind, "slurm")).
+					WithObjects(newHorizontalPodAutoscaler("bar", slinkyv1beta1.NodeSetAPIVersion, slinkyv1beta1.NodeSetKind, "slurm1")).
+					WithObjects(newHorizontalPodAutoscaler("baz", "apps/v1", "StatefulSet", "slurm")).
+					Build(),
+			},
+			args: args{
+				ctx: context.TODO(),
+				nodeset: &slinkyv1beta1.NodeSet{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "slurm",
+						Namespace: metav1.NamespaceDefault,
+					},
+				},
+			},
+			want: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(tt.fields.reader)
+			got, err := r.GetHorizontalPodAutoscalersForNodeSet(tt.args.ctx, tt.args.nodeset)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RefResolver.GetHorizontalPodAutoscalersForNodeSet() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if len(got.Items) != tt.want {
+				t.Errorf("RefResolver.GetHorizontalPodAutoscalersForNodeSet() = %v, want %v", len(got.Items), tt.want)
+			}
+		})
+	}
+}
+
+func TestRefResolver_GetNodeSetForHorizontalPodAutoscaler(t *testing.T) {
+	nodeset := &slinkyv1beta1.NodeSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "slurm",
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+	type fields struct {
+		reader client.Reader
+	}
+	type args struct {
+		ctx context.Context
+		hpa *autoscalingv2.HorizontalPodAutoscaler
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "found",
+			fields: fields{
+				reader: fake.NewClientBuilder().
+					WithScheme(scheme).
+					WithObjects(nodeset.DeepCopy()).
+					Build(),
+			},
+			args: args{
+				ctx: context.TODO(),
+				hpa: newHorizontalPodAutoscaler("foo", slinkyv1beta1.NodeSetAPIVersion, slinkyv1beta1.NodeSetKind, "slurm"),
+			},
+			want: "slurm",
+		},
+		{
+			name: "not found",
+			fields: fields{
+				reader: fake.NewClientBuilder().
+					WithScheme(scheme).
+					Build(),
+			},
+			args: args{
+				ctx: context.TODO(),
+				hpa: newHorizontalPodAutoscaler("foo", slinkyv1beta1.NodeSetAPIVersion, slinkyv1beta1.NodeSetKind, "slurm"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "not a NodeSet",
+			fields: fields{
+				reader: fake.NewClientBuilder().
+					WithScheme(scheme).
+					WithObjects(nodeset.DeepCopy()).
+					Build(),
+			},
+			args: args{
+				ctx: context.TODO(),
+				hpa: newHorizontalPodAutoscaler("foo", "apps/v1", "StatefulSet", "slurm"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(tt.fields.reader)
+			got, err := r.GetNodeSetForHorizontalPodAutoscaler(tt.args.ctx, tt.args.hpa)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RefResolver.GetNodeSetForHorizontalPodAutoscaler() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got.Name != tt.want {
+				t.Errorf("RefResolver.GetNodeSetForHorizontalPodAutoscaler() = %v, want %v", got.Name, tt.want)
+			}
+		})
+	}
+}
+
 func TestRefResolver_GetSecretKeyRef(t *testing.T) {
 	type fields struct {
 		reader client.Reader
@@ -0,0 +1,53 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package slurmapi provides access to the slurmrestd endpoints that have no
+// typed object in the slurm client (e.g. licenses and the slurmdbd API).
+package slurmapi
+
+import (
+	"errors"
+	"net/http"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/utils/ptr"
+
+	api "github.com/SlinkyProject/slurm-client/api/v0044"
+	slurmclient "github.com/SlinkyProject/slurm-client/pkg/client"
+	"github.com/SlinkyProject/slurm-client/pkg/client/api/v0044"
+)
+
+// ClientFunc returns an API client for the slurm client.
+type ClientFunc func(slurmClient slurmclient.Client) (api.ClientWithResponsesInterface, error)
+
+// HTTPClientGetter is implemented by slurm clients that were created with
+// their own HTTP client (e.g. to verify the CA of slurmrestd).
+type HTTPClientGetter interface {
+	GetHTTPClient() *http.Client
+}
+
+// NewClient returns an API client using the server, auth token, and HTTP
+// client of the slurm client, which are kept up to date by the SlurmClient
+// controller.
+func NewClient(slurmClient slurmclient.Client) (api.ClientWithResponsesInterface, error) {
+	var httpClient *http.Client
+	if getter, ok := slurmClient.(HTTPClientGetter); ok {
+		httpClient = getter.GetHTTPClient()
+	}
+	return v0044.NewSlurmClient(slurmClient.GetServer(), slurmClient.GetToken(), httpClient)
+}
+
+// CheckResponse returns an error if the response status is not OK, including
+// the errors reported by slurmrestd.
+func CheckResponse(statusCode int, oapiErrors *api.V0044OpenapiErrors) error {
+	if statusCode == http.StatusOK {
+		return nil
+	}
+	errs := []error{errors.New(http.StatusText(statusCode))}
+	for _, oapiErr := range ptr.Deref(oapiErrors, api.V0044OpenapiErrors{}) {
+		if oapiErr.Error != nil {
+			errs = append(errs, errors.New(*oapiErr.Error))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
@@ -0,0 +1,51 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package slurmapi
+
+import (
+	"net/http"
+	"testing"
+
+	"k8s.io/utils/ptr"
+
+	api "github.com/SlinkyProject/slurm-client/api/v0044"
+)
+
+func TestCheckResponse(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		oapiErrors *api.V0044OpenapiErrors
+		want       string
+	}{
+		{
+			name:       "ok",
+			statusCode: http.StatusOK,
+			oapiErrors: &api.V0044OpenapiErrors{{Error: ptr.To("ignored")}},
+		},
+		{
+			name:       "status",
+			statusCode: http.StatusInternalServerError,
+			want:       "Internal Server Error",
+		},
+		{
+			name:       "errors",
+			statusCode: http.StatusBadRequest,
+			oapiErrors: &api.V0044OpenapiErrors{{Error: ptr.To("Invalid account")}, {}},
+			want:       "[Bad Request, Invalid account]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckResponse(tt.statusCode, tt.oapiErrors)
+			got := ""
+			if err != nil {
+				got = err.Error()
+			}
+			if got != tt.want {
+				t.Errorf("CheckResponse() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
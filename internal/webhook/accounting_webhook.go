@@ -5,7 +5,10 @@ package webhook
 
 import (
 	"context"
+	"fmt"
+	"slices"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
@@ -44,7 +47,9 @@ func (r *AccountingSetWebhook) ValidateCreate(ctx context.Context, obj runtime.O
 	accounting := obj.(*slinkyv1beta1.Accounting)
 	accountinglog.Info("validate create", "accounting", klog.KObj(accounting))
 
-	return nil, nil
+	warns, errs := validateAccounting(accounting)
+
+	return warns, utilerrors.NewAggregate(errs)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
@@ -70,5 +75,33 @@ func validateAccounting(obj *slinkyv1beta1.Accounting) (admission.Warnings, []er
 	var warns admission.Warnings
 	var errs []error
 
+	if _, err := obj.NamespaceSelector(); err != nil {
+		errs = append(errs, fmt.Errorf("`Accounting.Spec.NamespaceAccounts.NamespaceSelector` is not valid: %w", err))
+	}
+
+	if tls := obj.Spec.StorageConfig.TLS; tls != nil {
+		if (tls.CertKeyRef == nil) != (tls.KeyKeyRef == nil) {
+			errs = append(errs, fmt.Errorf("`Accounting.Spec.StorageConfig.TLS.CertKeyRef` and `Accounting.Spec.StorageConfig.TLS.KeyKeyRef` must be set together"))
+		}
+	}
+
+	archive := obj.Spec.Archive
+	if archive.ExistingClaim != "" && archive.VolumeClaim != nil {
+		errs = append(errs, fmt.Errorf("`Accounting.Spec.Archive.ExistingClaim` and `Accounting.Spec.Archive.VolumeClaim` are mutually exclusive"))
+	}
+	if obj.ArchiveEnabled() && obj.ArchiveClaimName() == "" && !obj.Spec.External {
+		errs = append(errs, fmt.Errorf("`Accounting.Spec.Archive` requires either `ExistingClaim` or `VolumeClaim`"))
+	}
+	// The primary and backup slurmdbd mount the same archive claim, and may
+	// run on different nodes.
+	if obj.Spec.HighAvailability && !obj.Spec.External {
+		switch {
+		case archive.ExistingClaim != "":
+			warns = append(warns, "`Accounting.Spec.Archive.ExistingClaim` must be `ReadWriteMany` with `Accounting.Spec.HighAvailability`")
+		case archive.VolumeClaim != nil && !slices.Contains(archive.VolumeClaim.AccessModes, corev1.ReadWriteMany):
+			errs = append(errs, fmt.Errorf("`Accounting.Spec.Archive.VolumeClaim.AccessModes` must include `ReadWriteMany` with `Accounting.Spec.HighAvailability`"))
+		}
+	}
+
 	return warns, errs
 }
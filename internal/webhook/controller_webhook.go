@@ -7,15 +7,20 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"regexp"
 	"slices"
+	"strings"
+	"unicode"
 
+	luaparse "github.com/yuin/gopher-lua/parse"
 	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -138,6 +143,33 @@ func (r *ControllerWebhook) validateController(ctx context.Context, obj *slinkyv
 		"topology.yaml",
 	}
 
+	if ref := obj.Spec.JobSubmitScriptRef; ref != nil {
+		configMap := &corev1.ConfigMap{}
+		if err := r.Get(ctx, obj.JobSubmitScriptKey(), configMap); err != nil {
+			errs = append(errs, err)
+		} else if script, ok := configMap.Data[ref.Key]; !ok {
+			errs = append(errs, fmt.Errorf("`Controller.Spec.JobSubmitScriptRef` key %q was not found in ConfigMap %s", ref.Key, ref.Name))
+		} else if err := validateLua(script); err != nil {
+			if isNewerLuaSyntax(err) {
+				warns = append(warns, fmt.Sprintf("`Controller.Spec.JobSubmitScriptRef` could not be checked, it may use syntax newer than Lua 5.1: %v", err))
+			} else {
+				errs = append(errs, fmt.Errorf("`Controller.Spec.JobSubmitScriptRef` is not valid Lua: %w", err))
+			}
+		}
+	}
+
+	errs = append(errs, validateSpank("Controller.Spec.Spank", obj.Spec.Spank)...)
+
+	cgroupWarns, cgroupErrs := validateControllerCgroup(obj)
+	warns = append(warns, cgroupWarns...)
+	errs = append(errs, cgroupErrs...)
+
+	schedulingWarns, schedulingErrs := validateControllerScheduling(obj)
+	warns = append(warns, schedulingWarns...)
+	errs = append(errs, schedulingErrs...)
+
+	errs = append(errs, validateControllerLicenses(obj)...)
+
 	refs := obj.Spec.ConfigFileRefs
 	for _, ref := range refs {
 		configMap := &corev1.ConfigMap{}
@@ -152,6 +184,15 @@ func (r *ControllerWebhook) validateController(ctx context.Context, obj *slinkyv
 		configFiles := structutils.Keys(configMap.Data)
 		controllerlog.V(1).Info("configMap files", "files", configFiles)
 		for _, file := range configFiles {
+			if file == "cgroup.conf" && !apiequality.Semantic.DeepEqual(obj.Spec.Cgroup, slinkyv1beta1.ControllerCgroup{}) {
+				warns = append(warns, fmt.Sprintf("the configFile takes precedence over `Controller.Spec.Cgroup`, which is ignored: %s", file))
+			}
+			if file == "plugstack.conf" && len(obj.Spec.Spank.Plugins) > 0 {
+				warns = append(warns, fmt.Sprintf("the configFile takes precedence over `Controller.Spec.Spank`, which is ignored: %s", file))
+			}
+			if file == "job_submit.lua" && obj.Spec.JobSubmitScriptRef != nil {
+				errs = append(errs, fmt.Errorf("the configFile conflicts with `Controller.Spec.JobSubmitScriptRef`: %s", file))
+			}
 			if slices.Contains(denyConfigFiles, file) {
 				errs = append(errs, fmt.Errorf("the configFile is reserved for slurm-operator use: %s", file))
 			} else if !slices.Contains(knownConfigFiles, file) {
@@ -162,3 +203,176 @@ func (r *ControllerWebhook) validateController(ctx context.Context, obj *slinkyv
 
 	return warns, errs
 }
+
+// validateControllerCgroup validates the combination of `cgroup.conf` settings.
+// Ref: https://slurm.schedmd.com/cgroup.conf.html
+func validateControllerCgroup(obj *slinkyv1beta1.Controller) (admission.Warnings, []error) {
+	var warns admission.Warnings
+	var errs []error
+
+	cgroup := obj.Spec.Cgroup
+	if cgroup.Plugin == slinkyv1beta1.CgroupPluginDisabled {
+		constrained := map[string]*bool{
+			"ConstrainCores":     cgroup.ConstrainCores,
+			"ConstrainRAMSpace":  cgroup.ConstrainRAMSpace,
+			"ConstrainDevices":   cgroup.ConstrainDevices,
+			"ConstrainSwapSpace": cgroup.ConstrainSwapSpace,
+		}
+		for _, name := range slices.Sorted(maps.Keys(constrained)) {
+			if ptr.Deref(constrained[name], false) {
+				errs = append(errs, fmt.Errorf("`Controller.Spec.Cgroup.%s` requires cgroups, but `Controller.Spec.Cgroup.Plugin` is %s",
+					name, cgroup.Plugin))
+			}
+		}
+	}
+	if cgroup.AllowedRAMSpace != nil && !ptr.Deref(cgroup.ConstrainRAMSpace, false) {
+		warns = append(warns, "`Controller.Spec.Cgroup.AllowedRAMSpace` has no effect unless `Controller.Spec.Cgroup.ConstrainRAMSpace` is enabled")
+	}
+
+	return warns, errs
+}
+
+// slurmTimeRegex matches the Slurm time formats "minutes", "minutes:seconds",
+// "hours:minutes:seconds", "days-hours", "days-hours:minutes" and
+// "days-hours:minutes:seconds".
+var slurmTimeRegex = regexp.MustCompile(`^([0-9]+-)?[0-9]+(:[0-9]+){0,2}$`)
+
+// validateControllerScheduling validates the combination of scheduler,
+// priority, and preemption settings.
+// Ref: https://slurm.schedmd.com/slurm.conf.html
+func validateControllerScheduling(obj *slinkyv1beta1.Controller) (admission.Warnings, []error) {
+	var warns admission.Warnings
+	var errs []error
+
+	scheduling := obj.Spec.Scheduling
+	for i, param := range scheduling.SchedulerParameters {
+		if param == "" || strings.ContainsFunc(param, unicode.IsSpace) || strings.Contains(param, ",") {
+			errs = append(errs, fmt.Errorf("`Controller.Spec.Scheduling.SchedulerParameters[%d]` must be non-empty and not contain whitespace or commas. Got: %q", i, param))
+		}
+		if scheduling.SchedulerType == slinkyv1beta1.SchedulerTypeBuiltin && strings.HasPrefix(param, "bf_") {
+			warns = append(warns, fmt.Sprintf("`Controller.Spec.Scheduling.SchedulerParameters[%d]` has no effect unless `Controller.Spec.Scheduling.SchedulerType` is %s: %s",
+				i, slinkyv1beta1.SchedulerTypeBackfill, param))
+		}
+	}
+
+	priority := scheduling.Priority
+	if priority.Type == slinkyv1beta1.PriorityTypeBasic {
+		if priority.DecayHalfLife != "" || !apiequality.Semantic.DeepEqual(priority.Weights, slinkyv1beta1.PriorityWeights{}) {
+			errs = append(errs, fmt.Errorf("`Controller.Spec.Scheduling.Priority.DecayHalfLife` and `Controller.Spec.Scheduling.Priority.Weights` require %s, but `Controller.Spec.Scheduling.Priority.Type` is %s",
+				slinkyv1beta1.PriorityTypeMultifactor, priority.Type))
+		}
+	}
+	if priority.DecayHalfLife != "" && !slurmTimeRegex.MatchString(priority.DecayHalfLife) {
+		errs = append(errs, fmt.Errorf("`Controller.Spec.Scheduling.Priority.DecayHalfLife` must be in Slurm time format (e.g. `7-0`). Got: %q", priority.DecayHalfLife))
+	}
+	for _, name := range slices.Sorted(maps.Keys(priority.Weights.TRES)) {
+		if name == "" || strings.ContainsFunc(name, unicode.IsSpace) || strings.ContainsAny(name, ",=") {
+			errs = append(errs, fmt.Errorf("`Controller.Spec.Scheduling.Priority.Weights.TRES` key must be non-empty and not contain whitespace, commas, or equal signs. Got: %q", name))
+		}
+		if weight := priority.Weights.TRES[name]; weight < 0 {
+			errs = append(errs, fmt.Errorf("`Controller.Spec.Scheduling.Priority.Weights.TRES[%s]` must not be negative. Got: %d", name, weight))
+		}
+	}
+
+	preempt := scheduling.Preempt
+	modes := make(map[slinkyv1beta1.PreemptMode]bool, len(preempt.Mode))
+	for _, mode := range preempt.Mode {
+		if modes[mode] {
+			errs = append(errs, fmt.Errorf("`Controller.Spec.Scheduling.Preempt.Mode` contains %s more than once", mode))
+		}
+		modes[mode] = true
+	}
+	if modes[slinkyv1beta1.PreemptModeOff] && len(modes) > 1 {
+		errs = append(errs, fmt.Errorf("`Controller.Spec.Scheduling.Preempt.Mode` %s cannot be combined with other modes",
+			slinkyv1beta1.PreemptModeOff))
+	}
+	actions := []string{}
+	for _, mode := range []slinkyv1beta1.PreemptMode{slinkyv1beta1.PreemptModeCancel, slinkyv1beta1.PreemptModeRequeue, slinkyv1beta1.PreemptModeSuspend} {
+		if modes[mode] {
+			actions = append(actions, string(mode))
+		}
+	}
+	if len(actions) > 1 {
+		errs = append(errs, fmt.Errorf("`Controller.Spec.Scheduling.Preempt.Mode` can only contain one of %s, %s, or %s. Got: %s",
+			slinkyv1beta1.PreemptModeCancel, slinkyv1beta1.PreemptModeRequeue, slinkyv1beta1.PreemptModeSuspend, strings.Join(actions, ",")))
+	}
+	if modes[slinkyv1beta1.PreemptModeSuspend] && !modes[slinkyv1beta1.PreemptModeGang] {
+		errs = append(errs, fmt.Errorf("`Controller.Spec.Scheduling.Preempt.Mode` %s requires %s",
+			slinkyv1beta1.PreemptModeSuspend, slinkyv1beta1.PreemptModeGang))
+	}
+	if modes[slinkyv1beta1.PreemptModeWithin] && preempt.Type != slinkyv1beta1.PreemptTypeQOS {
+		errs = append(errs, fmt.Errorf("`Controller.Spec.Scheduling.Preempt.Mode` %s requires %s, but `Controller.Spec.Scheduling.Preempt.Type` is %q",
+			slinkyv1beta1.PreemptModeWithin, slinkyv1beta1.PreemptTypeQOS, preempt.Type))
+	}
+	preemptEnabled := preempt.Type == slinkyv1beta1.PreemptTypePartitionPrio || preempt.Type == slinkyv1beta1.PreemptTypeQOS
+	if !preemptEnabled && len(actions) > 0 {
+		errs = append(errs, fmt.Errorf("`Controller.Spec.Scheduling.Preempt.Mode` %s requires a preemption plugin, but `Controller.Spec.Scheduling.Preempt.Type` is %q",
+			strings.Join(actions, ","), preempt.Type))
+	}
+	if preemptEnabled && (len(modes) == 0 || modes[slinkyv1beta1.PreemptModeOff]) {
+		warns = append(warns, fmt.Sprintf("`Controller.Spec.Scheduling.Preempt.Type` is %s, but no jobs are preempted unless `Controller.Spec.Scheduling.Preempt.Mode` is set to %s, %s, or %s",
+			preempt.Type, slinkyv1beta1.PreemptModeCancel, slinkyv1beta1.PreemptModeRequeue, slinkyv1beta1.PreemptModeSuspend))
+	}
+
+	return warns, errs
+}
+
+// validateControllerLicenses validates that remote licenses are unique and can
+// be served by slurmdbd.
+// Ref: https://slurm.schedmd.com/licenses.html
+func validateControllerLicenses(obj *slinkyv1beta1.Controller) []error {
+	var errs []error
+
+	if len(obj.Spec.RemoteLicenses) > 0 && obj.Spec.AccountingRef.Name == "" {
+		errs = append(errs, errors.New("`Controller.Spec.RemoteLicenses` requires accounting, but `Controller.Spec.AccountingRef` is not set"))
+	}
+
+	remote := make(map[string]bool, len(obj.Spec.RemoteLicenses))
+	for i, license := range obj.Spec.RemoteLicenses {
+		name := license.LicenseName()
+		if remote[name] {
+			errs = append(errs, fmt.Errorf("`Controller.Spec.RemoteLicenses[%d]` is a duplicate of license %q", i, name))
+		}
+		remote[name] = true
+	}
+
+	return errs
+}
+
+// validateSpank validates SPANK plugins, which are rendered as space
+// separated `plugstack.conf` lines.
+// Ref: https://slurm.schedmd.com/spank.html#SECTION_CONFIGURATION
+func validateSpank(field string, spank slinkyv1beta1.Spank) []error {
+	var errs []error
+	for i, plugin := range spank.Plugins {
+		if strings.ContainsFunc(plugin.Path, unicode.IsSpace) {
+			errs = append(errs, fmt.Errorf("`%s.Plugins[%d].Path` must not contain whitespace. Got: %q", field, i, plugin.Path))
+		}
+		for j, arg := range plugin.Args {
+			if arg == "" || strings.ContainsFunc(arg, unicode.IsSpace) {
+				errs = append(errs, fmt.Errorf("`%s.Plugins[%d].Args[%d]` must be non-empty and not contain whitespace. Got: %q", field, i, j, arg))
+			}
+		}
+	}
+	return errs
+}
+
+// validateLua checks the syntax of a Lua script with the Lua 5.1 grammar.
+// Slurm embeds Lua 5.3 or later, so an error may come from newer syntax, see
+// isNewerLuaSyntax.
+func validateLua(script string) error {
+	_, err := luaparse.Parse(strings.NewReader(script), "job_submit.lua")
+	return err
+}
+
+// luaNewerSyntaxError matches the parse errors of Lua 5.1 at the tokens that
+// start the syntax added by later versions: integer division (`//`), bitwise
+// operators (`&`, `|`, `~`, `<<`, `>>`), variable attributes (`<const>`), and
+// hexadecimal floats.
+var luaNewerSyntaxError = regexp.MustCompile(`near '(/|&|\||~|<|>)'|at EOF:\s+parse error`)
+
+// isNewerLuaSyntax reports if the error of validateLua may come from syntax
+// that Lua 5.1 lacks, so the script is not rejected.
+func isNewerLuaSyntax(err error) bool {
+	return luaNewerSyntaxError.MatchString(err.Error())
+}
@@ -5,6 +5,14 @@ package webhook
 
 import (
 	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
 )
 
 var _ = Describe("Controller Webhook", func() {
@@ -16,5 +24,202 @@ var _ = Describe("Controller Webhook", func() {
 		It("Should admit if all required fields are provided", func() {
 			// TODO(user): Add your logic here
 		})
+
+		It("Should admit cgroup constraints", func() {
+			controller := &slinkyv1beta1.Controller{}
+			controller.Spec.Cgroup = slinkyv1beta1.ControllerCgroup{
+				Plugin:            slinkyv1beta1.CgroupPluginV2,
+				ConstrainCores:    ptr.To(true),
+				ConstrainRAMSpace: ptr.To(true),
+				AllowedRAMSpace:   ptr.To[int32](90),
+			}
+			warns, errs := validateControllerCgroup(controller)
+			Expect(warns).To(BeEmpty())
+			Expect(errs).To(BeEmpty())
+		})
+
+		It("Should deny cgroup constraints with cgroups disabled", func() {
+			controller := &slinkyv1beta1.Controller{}
+			controller.Spec.Cgroup = slinkyv1beta1.ControllerCgroup{
+				Plugin:           slinkyv1beta1.CgroupPluginDisabled,
+				ConstrainCores:   ptr.To(true),
+				ConstrainDevices: ptr.To(true),
+			}
+			_, errs := validateControllerCgroup(controller)
+			Expect(errs).To(HaveLen(2))
+		})
+
+		It("Should warn about ineffective cgroup settings", func() {
+			controller := &slinkyv1beta1.Controller{}
+			controller.Spec.Cgroup.AllowedRAMSpace = ptr.To[int32](90)
+			warns, errs := validateControllerCgroup(controller)
+			Expect(warns).To(HaveLen(1))
+			Expect(errs).To(BeEmpty())
+
+			controller.Spec.ConfigFileRefs = []slinkyv1beta1.ObjectReference{{Name: "config"}}
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "config"},
+				Data:       map[string]string{"cgroup.conf": ""},
+			}
+			r := &ControllerWebhook{Client: fake.NewFakeClient(configMap)}
+			warns, errs = r.validateController(ctx, controller)
+			Expect(warns).To(ContainElement(ContainSubstring("`Controller.Spec.Cgroup`, which is ignored")))
+			Expect(errs).To(BeEmpty())
+		})
+
+		It("Should deny an invalid job submit script", func() {
+			Expect(validateLua("function slurm_job_submit(job_desc, part_list, submit_uid)\n\treturn slurm.SUCCESS\nend\n")).To(Succeed())
+			Expect(validateLua("function slurm_job_submit(\n")).NotTo(Succeed())
+
+			controller := &slinkyv1beta1.Controller{}
+			controller.Spec.JobSubmitScriptRef = &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "job-submit"},
+				Key:                  "job_submit.lua",
+			}
+			r := &ControllerWebhook{Client: fake.NewFakeClient()}
+			_, errs := r.validateController(ctx, controller)
+			Expect(errs).To(HaveLen(1))
+
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "job-submit"},
+				Data:       map[string]string{"other.lua": ""},
+			}
+			r = &ControllerWebhook{Client: fake.NewFakeClient(configMap)}
+			_, errs = r.validateController(ctx, controller)
+			Expect(errs).To(HaveLen(1))
+		})
+
+		It("Should deny job_submit.lua from both a script ref and a configFile", func() {
+			controller := &slinkyv1beta1.Controller{}
+			controller.Spec.JobSubmitScriptRef = &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "job-submit"},
+				Key:                  "job_submit.lua",
+			}
+			controller.Spec.ConfigFileRefs = []slinkyv1beta1.ObjectReference{{Name: "config"}}
+			scriptMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "job-submit"},
+				Data:       map[string]string{"job_submit.lua": ""},
+			}
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "config"},
+				Data:       map[string]string{"job_submit.lua": ""},
+			}
+			r := &ControllerWebhook{Client: fake.NewFakeClient(scriptMap, configMap)}
+			_, errs := r.validateController(ctx, controller)
+			Expect(errs).To(HaveLen(1))
+		})
+
+		It("Should deny invalid SPANK plugins", func() {
+			spank := slinkyv1beta1.Spank{
+				Plugins: []slinkyv1beta1.SpankPlugin{
+					{Path: "/usr/lib64/slurm/spank_pyxis.so", Optional: true, Args: []string{"runtime_path=/run/pyxis"}},
+				},
+			}
+			Expect(validateSpank("Controller.Spec.Spank", spank)).To(BeEmpty())
+
+			spank.Plugins = []slinkyv1beta1.SpankPlugin{
+				{Path: "spank pyxis.so", Args: []string{"", "runtime_path = /run/pyxis"}},
+			}
+			Expect(validateSpank("Controller.Spec.Spank", spank)).To(HaveLen(3))
+		})
+
+		It("Should warn about SPANK plugins ignored for a configFile", func() {
+			controller := &slinkyv1beta1.Controller{}
+			controller.Spec.Spank.Plugins = []slinkyv1beta1.SpankPlugin{{Path: "spank_pyxis.so"}}
+			controller.Spec.ConfigFileRefs = []slinkyv1beta1.ObjectReference{{Name: "config"}}
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "config"},
+				Data:       map[string]string{"plugstack.conf": ""},
+			}
+			r := &ControllerWebhook{Client: fake.NewFakeClient(configMap)}
+			warns, errs := r.validateController(ctx, controller)
+			Expect(warns).To(ContainElement(ContainSubstring("`Controller.Spec.Spank`, which is ignored")))
+			Expect(errs).To(BeEmpty())
+		})
+
+		It("Should admit scheduling settings", func() {
+			controller := &slinkyv1beta1.Controller{}
+			controller.Spec.Scheduling = slinkyv1beta1.ControllerScheduling{
+				SchedulerType:       slinkyv1beta1.SchedulerTypeBackfill,
+				SchedulerParameters: []string{"bf_continue", "bf_window=1440"},
+				Priority: slinkyv1beta1.ControllerPriority{
+					Type:          slinkyv1beta1.PriorityTypeMultifactor,
+					DecayHalfLife: "7-0",
+					Weights: slinkyv1beta1.PriorityWeights{
+						Age:  ptr.To[int64](1000),
+						TRES: map[string]int64{"GRES/gpu": 2000},
+					},
+				},
+				Preempt: slinkyv1beta1.ControllerPreempt{
+					Type: slinkyv1beta1.PreemptTypeQOS,
+					Mode: []slinkyv1beta1.PreemptMode{slinkyv1beta1.PreemptModeRequeue, slinkyv1beta1.PreemptModeWithin},
+				},
+			}
+			warns, errs := validateControllerScheduling(controller)
+			Expect(warns).To(BeEmpty())
+			Expect(errs).To(BeEmpty())
+		})
+
+		It("Should deny invalid scheduler and priority settings", func() {
+			controller := &slinkyv1beta1.Controller{}
+			controller.Spec.Scheduling.SchedulerParameters = []string{"bf_continue,bf_window=1440"}
+			_, errs := validateControllerScheduling(controller)
+			Expect(errs).To(HaveLen(1))
+
+			controller = &slinkyv1beta1.Controller{}
+			controller.Spec.Scheduling.Priority = slinkyv1beta1.ControllerPriority{
+				Type:    slinkyv1beta1.PriorityTypeBasic,
+				Weights: slinkyv1beta1.PriorityWeights{Age: ptr.To[int64](1000)},
+			}
+			_, errs = validateControllerScheduling(controller)
+			Expect(errs).To(HaveLen(1))
+
+			controller = &slinkyv1beta1.Controller{}
+			controller.Spec.Scheduling.Priority.DecayHalfLife = "7 days"
+			_, errs = validateControllerScheduling(controller)
+			Expect(errs).To(HaveLen(1))
+
+			controller = &slinkyv1beta1.Controller{}
+			controller.Spec.Scheduling.Priority.Weights.TRES = map[string]int64{"CPU=1": 1000, "Mem": -1}
+			_, errs = validateControllerScheduling(controller)
+			Expect(errs).To(HaveLen(2))
+		})
+
+		It("Should deny invalid preemption settings", func() {
+			preempts := []slinkyv1beta1.ControllerPreempt{
+				{Mode: []slinkyv1beta1.PreemptMode{slinkyv1beta1.PreemptModeOff, slinkyv1beta1.PreemptModeGang}},
+				{Type: slinkyv1beta1.PreemptTypePartitionPrio, Mode: []slinkyv1beta1.PreemptMode{slinkyv1beta1.PreemptModeCancel, slinkyv1beta1.PreemptModeRequeue}},
+				{Type: slinkyv1beta1.PreemptTypePartitionPrio, Mode: []slinkyv1beta1.PreemptMode{slinkyv1beta1.PreemptModeSuspend}},
+				{Type: slinkyv1beta1.PreemptTypePartitionPrio, Mode: []slinkyv1beta1.PreemptMode{slinkyv1beta1.PreemptModeRequeue, slinkyv1beta1.PreemptModeWithin}},
+				{Mode: []slinkyv1beta1.PreemptMode{slinkyv1beta1.PreemptModeRequeue}},
+			}
+			for _, preempt := range preempts {
+				controller := &slinkyv1beta1.Controller{}
+				controller.Spec.Scheduling.Preempt = preempt
+				_, errs := validateControllerScheduling(controller)
+				Expect(errs).To(HaveLen(1), "preempt: %v", preempt)
+			}
+		})
+
+		It("Should warn about ineffective scheduling settings", func() {
+			controller := &slinkyv1beta1.Controller{}
+			controller.Spec.Scheduling.SchedulerType = slinkyv1beta1.SchedulerTypeBuiltin
+			controller.Spec.Scheduling.SchedulerParameters = []string{"bf_continue"}
+			warns, errs := validateControllerScheduling(controller)
+			Expect(warns).To(HaveLen(1))
+			Expect(errs).To(BeEmpty())
+
+			controller = &slinkyv1beta1.Controller{}
+			controller.Spec.Scheduling.Preempt.Type = slinkyv1beta1.PreemptTypeQOS
+			warns, errs = validateControllerScheduling(controller)
+			Expect(warns).To(HaveLen(1))
+			Expect(errs).To(BeEmpty())
+		})
+
+		It("Should warn about Lua syntax newer than 5.1", func() {
+			Expect(isNewerLuaSyntax(validateLua("local half = 7 // 2\n"))).To(BeTrue())
+			Expect(isNewerLuaSyntax(validateLua("local mask = 7 & 2\n"))).To(BeTrue())
+			Expect(isNewerLuaSyntax(validateLua("function slurm_job_submit(\n"))).To(BeFalse())
+		})
 	})
 })
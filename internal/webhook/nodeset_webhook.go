@@ -5,11 +5,16 @@ package webhook
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
+	"strings"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/set"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -103,5 +108,99 @@ func validateNodeSet(obj *slinkyv1beta1.NodeSet) (admission.Warnings, []error) {
 		}
 	}
 
+	resourceWarns, resourceErrs := validateNodeSetResources(obj)
+	warns = append(warns, resourceWarns...)
+	errs = append(errs, resourceErrs...)
+
+	errs = append(errs, validateSpank("NodeSet.Spec.Spank", obj.Spec.Spank)...)
+
+	gresWarns, gresErrs := validateNodeSetGres(obj)
+	warns = append(warns, gresWarns...)
+	errs = append(errs, gresErrs...)
+
+	return warns, errs
+}
+
+// validateNodeSetResources validates the resource overheads against the
+// slurmd container's resource limits, from which the Slurm node is derived.
+func validateNodeSetResources(obj *slinkyv1beta1.NodeSet) (admission.Warnings, []error) {
+	var warns admission.Warnings
+	var errs []error
+
+	limits := obj.Spec.Slurmd.Resources.Limits
+	overhead := obj.Spec.ResourceOverhead
+
+	cpu, hasCPU := limits[corev1.ResourceCPU]
+	if hasCPU && cpu.MilliValue()%1000 != 0 {
+		warns = append(warns, fmt.Sprintf("`NodeSet.Spec.Slurmd.Resources.Limits.cpu` is not a whole number of CPUs. Got: %v. Slurm will report the CPUs of the Kubernetes node", cpu.String()))
+	}
+	if overhead.CoreSpecCount > 0 && hasCPU && int64(overhead.CoreSpecCount) >= cpu.Value() {
+		errs = append(errs, fmt.Errorf("`NodeSet.Spec.ResourceOverhead.CoreSpecCount` must be less than the CPU limit. Got: %v. CPU limit: %v",
+			overhead.CoreSpecCount, cpu.String()))
+	}
+
+	memory, hasMemory := limits[corev1.ResourceMemory]
+	if overhead.MemSpecLimit != nil && hasMemory && overhead.MemSpecLimit.Cmp(memory) >= 0 {
+		errs = append(errs, fmt.Errorf("`NodeSet.Spec.ResourceOverhead.MemSpecLimit` must be less than the memory limit. Got: %v. Memory limit: %v",
+			overhead.MemSpecLimit.String(), memory.String()))
+	}
+
+	return warns, errs
+}
+
+var gresCountRegex = regexp.MustCompile(`^[0-9]+[KMGTP]?$`)
+
+// validateNodeSetGres validates the combination of GRES on a NodeSet.
+// Ref: https://slurm.schedmd.com/gres.conf.html
+func validateNodeSetGres(obj *slinkyv1beta1.NodeSet) (admission.Warnings, []error) {
+	var warns admission.Warnings
+	var errs []error
+
+	if len(obj.Spec.Gres) == 0 {
+		return warns, errs
+	}
+
+	if obj.Spec.Template.PodSpecWrapper.HostNetwork {
+		warns = append(warns, "`NodeSet.Spec.Gres` is scoped by Slurm node name, which is the Kubernetes node name when `hostNetwork` is used. The GRES will not apply.")
+	}
+
+	names := set.New[string]()
+	gresKeys := set.New[string]()
+	autoDetect := slinkyv1beta1.GresAutoDetect("")
+	for i, gres := range obj.Spec.Gres {
+		names.Insert(gres.Name)
+		key := gres.Name + ":" + gres.Type
+		if gresKeys.Has(key) {
+			errs = append(errs, fmt.Errorf("`NodeSet.Spec.Gres[%d]` is a duplicate of name %q and type %q", i, gres.Name, gres.Type))
+		}
+		gresKeys.Insert(key)
+		if gres.Count != "" && !gresCountRegex.MatchString(gres.Count) {
+			errs = append(errs, fmt.Errorf("`NodeSet.Spec.Gres[%d].Count` is not valid. Got: %v. Expected a number with an optional suffix of K, M, G, T, or P",
+				i, gres.Count))
+		}
+		if gres.File != "" && !strings.HasPrefix(gres.File, "/") {
+			errs = append(errs, fmt.Errorf("`NodeSet.Spec.Gres[%d].File` must be an absolute path. Got: %v", i, gres.File))
+		}
+		if gres.AutoDetect != "" {
+			if autoDetect != "" && autoDetect != gres.AutoDetect {
+				errs = append(errs, fmt.Errorf("`NodeSet.Spec.Gres[%d].AutoDetect` conflicts with another GRES. Got: %v and %v. AutoDetect applies to the whole node",
+					i, autoDetect, gres.AutoDetect))
+			}
+			autoDetect = gres.AutoDetect
+		}
+		if gres.Name == "mps" && gres.Count == "" {
+			warns = append(warns, fmt.Sprintf("`NodeSet.Spec.Gres[%d].Count` is empty, the mps count will be derived from the gpu count", i))
+		}
+	}
+
+	for _, name := range []string{"mps", "shard"} {
+		if names.Has(name) && !names.Has("gpu") {
+			errs = append(errs, fmt.Errorf("`NodeSet.Spec.Gres` with name %q requires a GRES with name \"gpu\"", name))
+		}
+	}
+	if names.Has("mps") && names.Has("shard") {
+		errs = append(errs, errors.New("`NodeSet.Spec.Gres` cannot have both \"mps\" and \"shard\" on the same node"))
+	}
+
 	return warns, errs
 }
@@ -5,6 +5,13 @@ package webhook
 
 import (
 	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
 )
 
 var _ = Describe("NodeSet Webhook", func() {
@@ -16,5 +23,116 @@ var _ = Describe("NodeSet Webhook", func() {
 		It("Should admit if all required fields are provided", func() {
 			// TODO(user): Add your logic here
 		})
+
+		It("Should admit GPUs with shards", func() {
+			nodeset := &slinkyv1beta1.NodeSet{
+				Spec: slinkyv1beta1.NodeSetSpec{
+					Gres: []slinkyv1beta1.NodeSetGres{
+						{Name: "gpu", Type: "a100", File: "/dev/nvidia[0-3]"},
+						{Name: "shard", Count: "8"},
+					},
+				},
+			}
+			warns, errs := validateNodeSetGres(nodeset)
+			Expect(warns).To(BeEmpty())
+			Expect(errs).To(BeEmpty())
+		})
+
+		It("Should deny invalid GRES", func() {
+			nodeset := &slinkyv1beta1.NodeSet{
+				Spec: slinkyv1beta1.NodeSetSpec{
+					Gres: []slinkyv1beta1.NodeSetGres{
+						{Name: "gpu", Type: "a100", Count: "4X", File: "dev/nvidia0"},
+						{Name: "gpu", Type: "a100"},
+					},
+				},
+			}
+			_, errs := validateNodeSetGres(nodeset)
+			Expect(errs).To(HaveLen(3))
+		})
+
+		It("Should deny conflicting GRES", func() {
+			nodeset := &slinkyv1beta1.NodeSet{}
+			nodeset.Spec.Gres = []slinkyv1beta1.NodeSetGres{
+				{Name: "gpu", Type: "a100", AutoDetect: slinkyv1beta1.GresAutoDetectNvml},
+				{Name: "gpu", Type: "mi300", AutoDetect: slinkyv1beta1.GresAutoDetectRsmi},
+			}
+			_, errs := validateNodeSetGres(nodeset)
+			Expect(errs).To(HaveLen(1))
+
+			nodeset.Spec.Gres = []slinkyv1beta1.NodeSetGres{
+				{Name: "shard", Count: "8"},
+			}
+			_, errs = validateNodeSetGres(nodeset)
+			Expect(errs).To(HaveLen(1))
+
+			nodeset.Spec.Gres = []slinkyv1beta1.NodeSetGres{
+				{Name: "gpu"},
+				{Name: "mps", Count: "100"},
+				{Name: "shard", Count: "8"},
+			}
+			_, errs = validateNodeSetGres(nodeset)
+			Expect(errs).To(HaveLen(1))
+		})
+
+		It("Should warn about GRES that may not apply", func() {
+			nodeset := &slinkyv1beta1.NodeSet{}
+			nodeset.Spec.Template.PodSpecWrapper.HostNetwork = true
+			nodeset.Spec.Gres = []slinkyv1beta1.NodeSetGres{
+				{Name: "gpu"},
+				{Name: "mps"},
+			}
+			warns, errs := validateNodeSetGres(nodeset)
+			Expect(warns).To(HaveLen(2))
+			Expect(errs).To(BeEmpty())
+		})
+
+		It("Should admit a resource overhead within the limits", func() {
+			nodeset := &slinkyv1beta1.NodeSet{}
+			nodeset.Spec.Slurmd.Resources.Limits = corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("8"),
+				corev1.ResourceMemory: resource.MustParse("32Gi"),
+			}
+			nodeset.Spec.ResourceOverhead = slinkyv1beta1.NodeSetResourceOverhead{
+				CoreSpecCount: 1,
+				MemSpecLimit:  ptr.To(resource.MustParse("2Gi")),
+			}
+			warns, errs := validateNodeSetResources(nodeset)
+			Expect(warns).To(BeEmpty())
+			Expect(errs).To(BeEmpty())
+		})
+
+		It("Should deny a resource overhead exceeding the limits", func() {
+			nodeset := &slinkyv1beta1.NodeSet{}
+			nodeset.Spec.Slurmd.Resources.Limits = corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("2"),
+				corev1.ResourceMemory: resource.MustParse("4Gi"),
+			}
+			nodeset.Spec.ResourceOverhead = slinkyv1beta1.NodeSetResourceOverhead{
+				CoreSpecCount: 2,
+				MemSpecLimit:  ptr.To(resource.MustParse("4Gi")),
+			}
+			_, errs := validateNodeSetResources(nodeset)
+			Expect(errs).To(HaveLen(2))
+		})
+
+		It("Should warn about a fractional CPU limit", func() {
+			nodeset := &slinkyv1beta1.NodeSet{}
+			nodeset.Spec.Slurmd.Resources.Limits = corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("1500m"),
+			}
+			warns, errs := validateNodeSetResources(nodeset)
+			Expect(warns).To(HaveLen(1))
+			Expect(errs).To(BeEmpty())
+		})
+
+		It("Should deny invalid SPANK plugins", func() {
+			nodeset := &slinkyv1beta1.NodeSet{}
+			nodeset.Spec.Spank.Plugins = []slinkyv1beta1.SpankPlugin{
+				{Path: "spank_pyxis.so", Args: []string{"runtime_path = /run/pyxis"}},
+			}
+			_, errs := validateNodeSet(nodeset)
+			Expect(errs).To(HaveLen(1))
+		})
 	})
 })
@@ -5,6 +5,7 @@ package webhook
 
 import (
 	"context"
+	"errors"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
@@ -72,5 +73,16 @@ func validateRestapi(obj *slinkyv1beta1.RestApi) (admission.Warnings, []error) {
 	var warns admission.Warnings
 	var errs []error
 
+	tls := obj.Spec.TLS
+	if tls.Enabled && tls.Proxy.Image == "" {
+		errs = append(errs, errors.New("`RestApi.Spec.TLS.Proxy.Image` must be set when TLS is enabled"))
+	}
+	if tls.SecretRef != nil && tls.SecretRef.Name == "" {
+		errs = append(errs, errors.New("`RestApi.Spec.TLS.SecretRef.Name` must not be empty"))
+	}
+	if !tls.Enabled && tls.SecretRef != nil {
+		warns = append(warns, "`RestApi.Spec.TLS.SecretRef` is ignored unless TLS is enabled")
+	}
+
 	return warns, errs
 }
@@ -0,0 +1,160 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	slinkyv1beta1 "github.com/SlinkyProject/slurm-operator/api/v1beta1"
+)
+
+type SlurmQOSWebhook struct {
+	client.Client
+}
+
+// log is for logging in this package.
+var slurmqoslog = logf.Log.WithName("slurmqos-resource")
+
+// SetupWebhookWithManager will setup the manager to manage the webhooks
+func (r *SlurmQOSWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&slinkyv1beta1.SlurmQOS{}).
+		WithValidator(r).
+		Complete()
+}
+
+// NOTE: The 'path' attribute must follow a specific pattern and should not be modified directly here.
+// Modifying the path for an invalid path can cause API server errors; failing to locate the webhook.
+// +kubebuilder:webhook:path=/validate-slinky-slurm-net-v1beta1-slurmqos,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,sideEffects=None,groups=slinky.slurm.net,resources=slurmqoses,verbs=create;update,versions=v1beta1,name=slurmqos-v1beta1.kb.io,admissionReviewVersions=v1beta1
+// +kubebuilder:rbac:groups=slinky.slurm.net,resources=slurmqoses,verbs=get;list;watch
+
+var _ webhook.CustomValidator = &SlurmQOSWebhook{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *SlurmQOSWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	qos := obj.(*slinkyv1beta1.SlurmQOS)
+	slurmqoslog.Info("validate create", "slurmqos", klog.KObj(qos))
+
+	warns, errs := r.validateSlurmQOS(ctx, qos)
+
+	return warns, utilerrors.NewAggregate(errs)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *SlurmQOSWebhook) ValidateUpdate(ctx context.Context, oldObj runtime.Object, newObj runtime.Object) (admission.Warnings, error) {
+	newQOS := newObj.(*slinkyv1beta1.SlurmQOS)
+	_ = oldObj.(*slinkyv1beta1.SlurmQOS)
+	slurmqoslog.Info("validate update", "newSlurmQOS", klog.KObj(newQOS))
+
+	warns, errs := r.validateSlurmQOS(ctx, newQOS)
+
+	return warns, utilerrors.NewAggregate(errs)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *SlurmQOSWebhook) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	qos := obj.(*slinkyv1beta1.SlurmQOS)
+	slurmqoslog.Info("validate delete", "slurmqos", klog.KObj(qos))
+
+	return nil, nil
+}
+
+func (r *SlurmQOSWebhook) validateSlurmQOS(ctx context.Context, obj *slinkyv1beta1.SlurmQOS) (admission.Warnings, []error) {
+	var warns admission.Warnings
+	var errs []error
+
+	errs = append(errs, validateTRES("SlurmQOS.Spec.GrpTRES", obj.Spec.GrpTRES)...)
+	errs = append(errs, validateTRES("SlurmQOS.Spec.MaxTRESPerUser", obj.Spec.MaxTRESPerUser)...)
+	if obj.Spec.MaxWall != nil && obj.Spec.MaxWall.Duration < time.Minute {
+		errs = append(errs, fmt.Errorf("`SlurmQOS.Spec.MaxWall` must be at least one minute. Got: %s", obj.Spec.MaxWall.Duration))
+	}
+
+	// The QOS of the other SlurmQOS of the same Accounting.
+	list := &slinkyv1beta1.SlurmQOSList{}
+	if err := r.List(ctx, list); err != nil {
+		errs = append(errs, err)
+		return warns, errs
+	}
+	name := obj.QOSName()
+	preempts := map[string][]string{
+		name: obj.Spec.Preempt,
+	}
+	for i := range list.Items {
+		item := &list.Items[i]
+		if item.Key() == obj.Key() || item.AccountingRef() != obj.AccountingRef() {
+			continue
+		}
+		if item.QOSName() == name {
+			errs = append(errs, fmt.Errorf("QOS %q is already managed by SlurmQOS %s", name, klog.KObj(item)))
+			continue
+		}
+		preempts[item.QOSName()] = item.Spec.Preempt
+	}
+	if cycle := findPreemptCycle(preempts, name); cycle != nil {
+		errs = append(errs, fmt.Errorf("`SlurmQOS.Spec.Preempt` creates a preemption cycle: %s", strings.Join(cycle, " -> ")))
+	}
+
+	return warns, errs
+}
+
+// validateTRES validates TRES limits, which are rendered as comma separated
+// `name=count` pairs.
+func validateTRES(field string, tres map[string]int64) []error {
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(tres)) {
+		if name == "" || strings.ContainsFunc(name, unicode.IsSpace) || strings.ContainsAny(name, ",=") {
+			errs = append(errs, fmt.Errorf("`%s` key must be non-empty and not contain whitespace, commas, or equal signs. Got: %q", field, name))
+		}
+		if count := tres[name]; count < 0 {
+			errs = append(errs, fmt.Errorf("`%s[%s]` must not be negative. Got: %d", field, name, count))
+		}
+	}
+	return errs
+}
+
+// findPreemptCycle returns a path of QOS from start that preempt each other
+// back to start, or nil if there is none. Only cycles through start are
+// found, as the other QOS were validated when they were admitted.
+func findPreemptCycle(preempts map[string][]string, start string) []string {
+	visited := map[string]bool{start: true}
+	path := []string{}
+	var visit func(name string) bool
+	visit = func(name string) bool {
+		path = append(path, name)
+		for _, next := range preempts[name] {
+			if next == start {
+				path = append(path, next)
+				return true
+			}
+			if visited[next] {
+				continue
+			}
+			visited[next] = true
+			if visit(next) {
+				return true
+			}
+		}
+		path = path[:len(path)-1]
+		return false
+	}
+	if visit(start) {
+		return path
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: Copyright (C) SchedMD LLC.
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SlurmQOS Webhook", func() {
+	Context("When creating SlurmQOS under Validating Webhook", func() {
+		It("Should deny a preemption cycle", func() {
+			preempts := map[string][]string{
+				"high":   {"normal"},
+				"normal": {"low"},
+				"low":    {"high"},
+			}
+			Expect(findPreemptCycle(preempts, "high")).To(Equal([]string{"high", "normal", "low", "high"}))
+		})
+
+		It("Should deny a QOS preempting itself", func() {
+			preempts := map[string][]string{
+				"high": {"high"},
+			}
+			Expect(findPreemptCycle(preempts, "high")).To(Equal([]string{"high", "high"}))
+		})
+
+		It("Should admit a preemption hierarchy", func() {
+			preempts := map[string][]string{
+				"high":   {"normal", "low"},
+				"normal": {"low"},
+				"low":    {"scavenger"},
+			}
+			Expect(findPreemptCycle(preempts, "high")).To(BeNil())
+		})
+
+		It("Should deny invalid TRES limits", func() {
+			Expect(validateTRES("SlurmQOS.Spec.GrpTRES", map[string]int64{"cpu": 100, "gres/gpu": 8})).To(BeEmpty())
+			Expect(validateTRES("SlurmQOS.Spec.GrpTRES", map[string]int64{"cpu,mem": 100})).To(HaveLen(1))
+			Expect(validateTRES("SlurmQOS.Spec.GrpTRES", map[string]int64{"cpu": -1})).To(HaveLen(1))
+		})
+	})
+})
@@ -116,6 +116,11 @@ var _ = BeforeSuite(func() {
 	err = (&RestapiWebhook{}).SetupWebhookWithManager(mgr)
 	Expect(err).NotTo(HaveOccurred())
 
+	err = (&SlurmQOSWebhook{
+		Client: mgr.GetClient(),
+	}).SetupWebhookWithManager(mgr)
+	Expect(err).NotTo(HaveOccurred())
+
 	err = (&TokenWebhook{}).SetupWebhookWithManager(mgr)
 	Expect(err).NotTo(HaveOccurred())
 
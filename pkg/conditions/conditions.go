@@ -29,6 +29,9 @@ const (
 	PodConditionMaintenance   corev1.PodConditionType = StatePrefix + "Maintenance"
 	PodConditionNotResponding corev1.PodConditionType = StatePrefix + "NotResponding"
 	PodConditionUndrain       corev1.PodConditionType = StatePrefix + "Undrain"
+
+	// PodConditionHealthy reports the result of the NodeSet health checks.
+	PodConditionHealthy corev1.PodConditionType = StatePrefix + "Healthy"
 )
 
 func IsConditionTrue(status *corev1.PodStatus, condType corev1.PodConditionType) bool {